@@ -23,7 +23,9 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/service"
+	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/errorreporterextension"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/version"
 )
 
@@ -39,7 +41,18 @@ func main() {
 		Version:     version.Version,
 	}
 
-	if err := run(service.AppSettings{BuildInfo: info, Factories: factories}); err != nil {
+	// Let a configured error_reporter extension see fatal errors, internal error logs and
+	// panics of the main goroutine, i.e. while components are built, started and shut down.
+	// Panics in the goroutines of components are not recovered and crash the collector unreported.
+	defer errorreporterextension.Recover()
+	params := service.AppSettings{
+		BuildInfo:      info,
+		Factories:      factories,
+		LoggingOptions: []zap.Option{zap.WrapCore(errorreporterextension.WrapCore)},
+	}
+
+	if err := run(params); err != nil {
+		errorreporterextension.ReportError(err)
 		log.Fatal(err)
 	}
 }
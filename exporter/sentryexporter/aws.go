@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// AWS SDK attributes of spans. The aws.service, aws.operation, aws.table.name and aws.bucket.name
+// attributes are those of older instrumentations.
+// See https://github.com/open-telemetry/semantic-conventions/blob/v1.23.0/docs/cloud-providers/aws-sdk.md.
+const (
+	awsAPISystem = "aws-api"
+
+	awsServiceAttribute        = "aws.service"
+	awsOperationAttribute      = "aws.operation"
+	awsRegionAttribute         = "aws.region"
+	awsDynamoDBTablesAttribute = "aws.dynamodb.table_names"
+	awsTableNameAttribute      = "aws.table.name"
+	awsS3BucketAttribute       = "aws.s3.bucket"
+	awsBucketNameAttribute     = "aws.bucket.name"
+
+	// awsSDKOp is the op of AWS SDK spans, which are HTTP requests to the AWS APIs.
+	awsSDKOp = "http.client"
+)
+
+// awsTags maps AWS SDK attributes to the tags searched and grouped by in Sentry. The first
+// attribute set for a tag is used.
+var awsTags = []struct {
+	attribute string
+	tag       string
+}{
+	{awsRegionAttribute, "aws.region"},
+	{awsDynamoDBTablesAttribute, "aws.table"},
+	{awsTableNameAttribute, "aws.table"},
+	{awsS3BucketAttribute, "aws.bucket"},
+	{awsBucketNameAttribute, "aws.bucket"},
+}
+
+// isAWSSDKSpan returns whether a span is a request of an AWS SDK, with rpc.system aws-api.
+func isAWSSDKSpan(attrs pdata.AttributeMap) bool {
+	system, ok := attrs.Get(conventions.AttributeRPCSystem)
+	return ok && system.StringVal() == awsAPISystem
+}
+
+// awsSDKSpanDescriptors returns the op and the description, <service>.<operation>, of an AWS SDK
+// span. The service and operation are the rpc.service and rpc.method, or else the aws.service and
+// aws.operation, e.g. DynamoDB.GetItem. It returns false if the span is not an AWS SDK span.
+func awsSDKSpanDescriptors(name string, attrs pdata.AttributeMap, in *interner) (op string, description string, ok bool) {
+	if !isAWSSDKSpan(attrs) {
+		return "", "", false
+	}
+
+	service := firstStringAttribute(attrs, conventions.AttributeRPCService, awsServiceAttribute)
+	operation := firstStringAttribute(attrs, conventions.AttributeRPCMethod, awsOperationAttribute)
+	if service == "" || operation == "" {
+		return awsSDKOp, name, true
+	}
+	return awsSDKOp, in.joinWith(service, '.', operation), true
+}
+
+// addAWSTags sets the region, table and bucket tags of an AWS SDK span from its attributes.
+func addAWSTags(attrs pdata.AttributeMap, tags map[string]string) {
+	if !isAWSSDKSpan(attrs) {
+		return
+	}
+	for _, m := range awsTags {
+		if _, ok := tags[m.tag]; ok {
+			continue
+		}
+		attr, ok := attrs.Get(m.attribute)
+		if !ok {
+			continue
+		}
+		switch attr.Type() {
+		case pdata.AttributeValueTypeString:
+			if attr.StringVal() != "" {
+				tags[m.tag] = attr.StringVal()
+			}
+		case pdata.AttributeValueTypeArray:
+			values := attr.ArrayVal()
+			names := make([]string, 0, values.Len())
+			for i := 0; i < values.Len(); i++ {
+				if v := values.At(i); v.Type() == pdata.AttributeValueTypeString {
+					names = append(names, v.StringVal())
+				}
+			}
+			if len(names) > 0 {
+				tags[m.tag] = strings.Join(names, ",")
+			}
+		}
+	}
+}
+
+// firstStringAttribute returns the first non-empty string attribute of keys, or an empty string.
+func firstStringAttribute(attrs pdata.AttributeMap, keys ...string) string {
+	for _, key := range keys {
+		if attr, ok := attrs.Get(key); ok && attr.Type() == pdata.AttributeValueTypeString && attr.StringVal() != "" {
+			return attr.StringVal()
+		}
+	}
+	return ""
+}
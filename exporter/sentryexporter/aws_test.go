@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestFillSentrySpanAWSSDK(t *testing.T) {
+	span := pdata.NewSpan()
+	span.SetName("DynamoDB.BatchGetItem")
+	span.SetKind(pdata.SpanKindClient)
+	span.Attributes().InsertString("rpc.system", "aws-api")
+	span.Attributes().InsertString("rpc.service", "DynamoDB")
+	span.Attributes().InsertString("rpc.method", "BatchGetItem")
+	span.Attributes().InsertString("aws.region", "eu-west-1")
+	tables := pdata.NewAttributeValueArray()
+	tables.ArrayVal().Append(pdata.NewAttributeValueString("orders"))
+	tables.ArrayVal().Append(pdata.NewAttributeValueString("customers"))
+	span.Attributes().Insert("aws.dynamodb.table_names", tables)
+	library := pdata.NewInstrumentationLibrary()
+
+	var sentrySpan sentry.Span
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "http.client", sentrySpan.Op)
+	assert.Equal(t, "DynamoDB.BatchGetItem", sentrySpan.Description)
+	assert.Equal(t, "eu-west-1", sentrySpan.Tags["aws.region"])
+	assert.Equal(t, "orders,customers", sentrySpan.Tags["aws.table"])
+	assert.NotContains(t, sentrySpan.Tags, "aws.bucket")
+
+	span = pdata.NewSpan()
+	span.Attributes().InsertString("rpc.system", "aws-api")
+	span.Attributes().InsertString("aws.bucket.name", "invoices")
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "invoices", sentrySpan.Tags["aws.bucket"])
+
+	// Spans of other systems are not tagged.
+	span.Attributes().UpdateString("rpc.system", "grpc")
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.NotContains(t, sentrySpan.Tags, "aws.bucket")
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"sort"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	// spanBreadcrumbsKey is the key of the breadcrumbs of spans. Until they are collected on
+	// their transaction, breadcrumbs are kept under this key in the data of spans, as
+	// sentry-go spans have no field for them.
+	spanBreadcrumbsKey = "_breadcrumbs"
+
+	// defaultMaxBreadcrumbs is the default maximum number of breadcrumbs of a transaction.
+	defaultMaxBreadcrumbs = 100
+)
+
+// spanBreadcrumbs converts the events of a span to breadcrumbs, except exceptions, metrics and
+// measurements that are sent otherwise. It returns nil if the span has no such events.
+func spanBreadcrumbs(span pdata.Span) []*sentry.Breadcrumb {
+	var breadcrumbs []*sentry.Breadcrumb
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		switch event.Name() {
+		case conventions.AttributeExceptionEventName, metricEventName, measurementEventName:
+			continue
+		}
+
+		breadcrumb := &sentry.Breadcrumb{
+			Type:      "default",
+			Category:  event.Name(),
+			Timestamp: unixNanoToTime(event.Timestamp()),
+			Data:      generateDataFromAttributes(event.Attributes()),
+		}
+		breadcrumbs = append(breadcrumbs, breadcrumb)
+	}
+	return breadcrumbs
+}
+
+// collectBreadcrumbs moves the breadcrumbs of the spans of each transaction to the transaction,
+// which already has those of its root span. They are sorted by timestamp, and only the latest
+// max are kept.
+func collectBreadcrumbs(transactions []*sentry.Event, max int) {
+	for _, transaction := range transactions {
+		for _, span := range transaction.Spans {
+			breadcrumbs, ok := span.Data[spanBreadcrumbsKey].([]*sentry.Breadcrumb)
+			if !ok {
+				continue
+			}
+			transaction.Breadcrumbs = append(transaction.Breadcrumbs, breadcrumbs...)
+			delete(span.Data, spanBreadcrumbsKey)
+			if len(span.Data) == 0 {
+				span.Data = nil
+			}
+		}
+
+		sort.SliceStable(transaction.Breadcrumbs, func(i, j int) bool {
+			return transaction.Breadcrumbs[i].Timestamp.Before(transaction.Breadcrumbs[j].Timestamp)
+		})
+		if len(transaction.Breadcrumbs) > max {
+			transaction.Breadcrumbs = transaction.Breadcrumbs[len(transaction.Breadcrumbs)-max:]
+		}
+		if len(transaction.Breadcrumbs) == 0 {
+			transaction.Breadcrumbs = nil
+		}
+	}
+}
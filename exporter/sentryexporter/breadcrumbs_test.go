@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+var breadcrumbsTestTime = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// addSpanEvent adds an event to the span, at the given offset from breadcrumbsTestTime.
+func addSpanEvent(span pdata.Span, name string, offset time.Duration) pdata.SpanEvent {
+	event := span.Events().AppendEmpty()
+	event.SetName(name)
+	event.SetTimestamp(pdata.TimestampFromTime(breadcrumbsTestTime.Add(offset)))
+	return event
+}
+
+func TestSpanBreadcrumbs(t *testing.T) {
+	span := pdata.NewSpan()
+	assert.Nil(t, spanBreadcrumbs(span))
+
+	event := addSpanEvent(span, "cache.miss", time.Second)
+	event.Attributes().InsertString("cache.key", "user:42")
+	event.Attributes().InsertInt("attempt", 2)
+	event.Attributes().InsertBool("retry", true)
+	event.Attributes().Insert("tags", pdata.NewAttributeValueArray())
+	addSpanEvent(span, conventions.AttributeExceptionEventName, 0)
+	addSpanEvent(span, metricEventName, 0)
+
+	breadcrumbs := spanBreadcrumbs(span)
+	require.Len(t, breadcrumbs, 1)
+	assert.Equal(t, &sentry.Breadcrumb{
+		Type:      "default",
+		Category:  "cache.miss",
+		Timestamp: breadcrumbsTestTime.Add(time.Second),
+		Data:      map[string]interface{}{"cache.key": "user:42", "attempt": int64(2), "retry": true},
+	}, breadcrumbs[0])
+}
+
+func TestCollectBreadcrumbs(t *testing.T) {
+	breadcrumb := func(offset time.Duration) *sentry.Breadcrumb {
+		return &sentry.Breadcrumb{Category: offset.String(), Timestamp: breadcrumbsTestTime.Add(offset)}
+	}
+	child := &sentry.Span{Data: map[string]interface{}{
+		spanBreadcrumbsKey: []*sentry.Breadcrumb{breadcrumb(1 * time.Second), breadcrumb(3 * time.Second)},
+	}}
+	other := &sentry.Span{Data: map[string]interface{}{
+		spanBreadcrumbsKey: []*sentry.Breadcrumb{breadcrumb(4 * time.Second)},
+		metricsSummaryKey:  metricsSummary{},
+	}}
+	transaction := &sentry.Event{
+		Breadcrumbs: []*sentry.Breadcrumb{breadcrumb(2 * time.Second), breadcrumb(0)},
+		Spans:       []*sentry.Span{child, other, {}},
+	}
+
+	collectBreadcrumbs([]*sentry.Event{transaction}, 3)
+
+	var categories []string
+	for _, b := range transaction.Breadcrumbs {
+		categories = append(categories, b.Category)
+	}
+	assert.Equal(t, []string{"2s", "3s", "4s"}, categories)
+	assert.Nil(t, child.Data)
+	assert.Equal(t, map[string]interface{}{metricsSummaryKey: metricsSummary{}}, other.Data)
+
+	disabled := &sentry.Event{Breadcrumbs: []*sentry.Breadcrumb{breadcrumb(0)}}
+	collectBreadcrumbs([]*sentry.Event{disabled}, 0)
+	assert.Nil(t, disabled.Breadcrumbs)
+}
+
+func TestPushTraceDataBreadcrumbs(t *testing.T) {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	root := spans.AppendEmpty()
+	root.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	root.SetSpanID(pdata.NewSpanID([8]byte{1}))
+	addSpanEvent(root, "request.received", 0)
+	child := spans.AppendEmpty()
+	child.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	child.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	child.SetParentSpanID(pdata.NewSpanID([8]byte{1}))
+	addSpanEvent(child, "cache.miss", time.Second)
+
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport, maxBreadcrumbs: defaultMaxBreadcrumbs}
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+
+	payloads := transactionPayloads(t, transport.envelopes)
+	require.Len(t, payloads, 1)
+	payload := payloads["0100000000000000"]
+	breadcrumbs := payload["breadcrumbs"].([]interface{})
+	require.Len(t, breadcrumbs, 2)
+	assert.Equal(t, "request.received", breadcrumbs[0].(map[string]interface{})["category"])
+	assert.Equal(t, "cache.miss", breadcrumbs[1].(map[string]interface{})["category"])
+	assert.NotContains(t, payload["spans"].([]interface{})[0], "data")
+}
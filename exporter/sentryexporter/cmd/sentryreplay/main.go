@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Program sentryreplay re-sends envelopes written by the file mode of the Sentry exporter.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/sentryexporter"
+)
+
+func main() {
+	dsn := flag.String("dsn", "", "DSN of the Sentry project to send the envelopes to")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -dsn DSN FILE|DIRECTORY...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *dsn == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		panic(fmt.Sprintf("failed to obtain logger: %v", err))
+	}
+
+	sent, err := sentryexporter.Replay(context.Background(), *dsn, flag.Args())
+	if err != nil {
+		logger.Error("failed to replay envelopes", zap.Int("sent", sent), zap.Error(err))
+		os.Exit(1)
+	}
+	logger.Info("replayed envelopes", zap.Int("sent", sent))
+}
@@ -14,11 +14,159 @@
 
 package sentryexporter
 
-import "go.opentelemetry.io/collector/config"
+import (
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
 
 // Config defines the configuration for the Sentry Exporter.
 type Config struct {
-	config.ExporterSettings `mapstructure:",squash"`
+	config.ExporterSettings        `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
 	// DSN to report transaction to Sentry. If the DSN is not set, no trace will be sent to Sentry.
 	DSN string `mapstructure:"dsn"`
+	// Release is the release of all events, overriding the one of the service.name and
+	// service.version resource attributes.
+	Release string `mapstructure:"release"`
+	// DefaultRelease is the release of the events without service.version resource attribute.
+	DefaultRelease string `mapstructure:"default_release"`
+	// Environment is the environment of all events, overriding the deployment.environment resource
+	// attribute.
+	Environment string `mapstructure:"environment"`
+	// DefaultEnvironment is the environment of the events without deployment.environment resource
+	// attribute.
+	DefaultEnvironment string `mapstructure:"default_environment"`
+	// Dist is the distribution of the release of the events without sentry.dist attribute, such as
+	// a build number.
+	Dist string `mapstructure:"dist"`
+	// Tags are added to all transactions and events, unless they have a tag with the same key.
+	Tags map[string]string `mapstructure:"tags"`
+	// Storage is the ID of an envelope storage extension. If set, envelopes that cannot be
+	// delivered are spooled to it and sent again later.
+	Storage string `mapstructure:"storage"`
+	// File configures writing envelopes to local files instead of sending them to Sentry.
+	File FileSettings `mapstructure:"file"`
+	// Occurrences configures issue occurrences created from matching spans and log records.
+	Occurrences OccurrencesSettings `mapstructure:"occurrences"`
+	// Logs configures sending log records as Sentry events.
+	Logs LogsSettings `mapstructure:"logs"`
+	// Pending configures keeping envelopes that cannot be delivered in memory, when no storage is set.
+	Pending PendingSettings `mapstructure:"pending"`
+	// MaxBreadcrumbs is the maximum number of breadcrumbs of a transaction, converted from the events
+	// of its spans. The latest are kept. 0 disables breadcrumbs.
+	MaxBreadcrumbs int `mapstructure:"max_breadcrumbs"`
+	// ErrorsOnly sends error events for the exceptions and failed spans, but no transactions.
+	ErrorsOnly bool `mapstructure:"errors_only"`
+	// InferHTTPStatus sets the status of spans whose status is unset, and that have a 5xx HTTP status
+	// code, to a failure status such as "internal_error".
+	InferHTTPStatus bool `mapstructure:"infer_http_status"`
+	// ScrubSQL normalizes the statements of SQL database spans, which are their descriptions, by
+	// replacing literals with placeholders and collapsing lists of values.
+	ScrubSQL bool `mapstructure:"scrub_sql"`
+	// MeasurementPrefixes are the prefixes of the int and double span attributes sent as the
+	// measurements of transactions, named without the prefix.
+	MeasurementPrefixes []string `mapstructure:"measurement_prefixes"`
+	// MaxTagKeyLength is the maximum length in characters of the keys of tags, longer keys are
+	// truncated. 0 disables truncation.
+	MaxTagKeyLength int `mapstructure:"max_tag_key_length"`
+	// MaxTagValueLength is the maximum length in characters of the values of tags, longer values are
+	// truncated and annotated. 0 disables truncation.
+	MaxTagValueLength int `mapstructure:"max_tag_value_length"`
+	// LibraryTags tags spans with the name and version of their instrumentation library, as
+	// library_name and library_version.
+	LibraryTags bool `mapstructure:"library_tags"`
+	// IncludeTags are glob patterns, such as "http.*", of the tags of transactions, spans and events
+	// that are sent. All tags are sent if empty.
+	IncludeTags []string `mapstructure:"include_tags"`
+	// ExcludeTags are glob patterns of the tags that are never sent.
+	ExcludeTags []string `mapstructure:"exclude_tags"`
+	// SpanData configures sending span attributes in the data of spans rather than as tags.
+	SpanData SpanDataSettings `mapstructure:"span_data"`
+	// SemanticConventions recognizes the attributes of the stabilized HTTP semantic conventions, such
+	// as http.request.method and url.full, in addition to their legacy names.
+	SemanticConventions bool `mapstructure:"semantic_conventions"`
+	// ConversionWorkers is the maximum number of ResourceSpans of a batch converted concurrently.
+	// 0 uses the number of CPUs.
+	ConversionWorkers int `mapstructure:"conversion_workers"`
+	// SendWorkers is the maximum number of envelopes of a batch sent concurrently. 1 or less sends
+	// them in turn.
+	SendWorkers int `mapstructure:"send_workers"`
+}
+
+// FileSettings defines where and how envelopes are written to local files.
+type FileSettings struct {
+	// Directory is the directory the files are written to. File mode is enabled if it is set.
+	Directory string `mapstructure:"directory"`
+	// MaxSizeMiB is the size in MiB after which a new file is started. 0 means no limit.
+	MaxSizeMiB int `mapstructure:"max_size_mib"`
+	// MaxFiles is the maximum number of files kept, the oldest are removed. 0 means no limit.
+	MaxFiles int `mapstructure:"max_files"`
+	// Compress enables gzip compression of the files.
+	Compress bool `mapstructure:"compress"`
+}
+
+// LogsSettings defines which log records are sent as Sentry events, and which are attached to
+// them as breadcrumbs.
+type LogsSettings struct {
+	// MinLevel is the lowest Sentry level of the log records sent, one of "debug", "info",
+	// "warning", "error" and "fatal". Records below it are dropped. Empty sends all log records.
+	MinLevel string `mapstructure:"min_level"`
+	// ErrorLevel is the lowest Sentry level of the log records sent as events. Records below it
+	// are attached as breadcrumbs to the later events of their resource and trace. Empty sends all
+	// log records as events.
+	ErrorLevel string `mapstructure:"error_level"`
+}
+
+// SpanDataSettings defines which span attributes are sent in the data of spans, and which as tags.
+type SpanDataSettings struct {
+	// Enabled sends span attributes in the data of spans, whose values are not limited to strings.
+	// Only the allowlisted attributes are also sent as tags, which Sentry indexes.
+	Enabled bool `mapstructure:"enabled"`
+	// Tags are the attributes sent as tags. Defaults to HTTP, database, RPC and messaging
+	// attributes with few values, such as http.method and db.system.
+	Tags []string `mapstructure:"tags"`
+}
+
+// PendingSettings defines how many envelopes that cannot be delivered are kept in memory to be
+// sent again later.
+type PendingSettings struct {
+	// MaxMemoryMiB is the approximate memory in MiB the pending envelopes may use, the oldest are
+	// evicted first. 0 disables keeping envelopes, they are dropped.
+	MaxMemoryMiB int `mapstructure:"max_memory_mib"`
+}
+
+// OccurrencesSettings defines where issue occurrences are sent, and the rules creating them.
+type OccurrencesSettings struct {
+	// URL of the issue occurrence endpoint. Defaults to the one of the Sentry host of the DSN.
+	URL string `mapstructure:"url"`
+	// AuthToken is the Sentry auth token used to authenticate against the endpoint.
+	AuthToken string `mapstructure:"auth_token"`
+	// Rules create an occurrence for each span or log record they match.
+	Rules []OccurrenceRule `mapstructure:"rules"`
+}
+
+// OccurrenceRule matches spans or log records, and describes the occurrences created for them.
+type OccurrenceRule struct {
+	// Signal is either "spans" or "logs".
+	Signal string `mapstructure:"signal"`
+	// Name is a regular expression matched against span names or log bodies. Empty matches all.
+	Name string `mapstructure:"name"`
+	// Attributes must all be set to the given values, on the record or its resource.
+	Attributes map[string]string `mapstructure:"attributes"`
+
+	// Type is the ID of the Sentry issue type of the occurrences.
+	Type int `mapstructure:"type"`
+	// IssueTitle is the title of the issue.
+	IssueTitle string `mapstructure:"issue_title"`
+	// Subtitle is the subtitle of the issue.
+	Subtitle string `mapstructure:"subtitle"`
+	// Level is the level of the occurrences, "warning" by default.
+	Level string `mapstructure:"level"`
+	// Fingerprint lists attributes whose values group occurrences into distinct issues, in
+	// addition to the type and title.
+	Fingerprint []string `mapstructure:"fingerprint"`
+	// Evidence lists attributes shown as evidence on the issue.
+	Evidence []string `mapstructure:"evidence"`
 }
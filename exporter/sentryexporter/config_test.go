@@ -17,12 +17,14 @@ package sentryexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -44,6 +46,117 @@ func TestLoadConfig(t *testing.T) {
 	e1 := cfg.Exporters[config.NewIDWithName(typeStr, "2")]
 	assert.Equal(t, e1, &Config{
 		ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "2")),
+		TimeoutSettings:  exporterhelper.TimeoutSettings{Timeout: 10 * time.Second},
+		QueueSettings: exporterhelper.QueueSettings{
+			Enabled:      true,
+			NumConsumers: 2,
+			QueueSize:    10,
+		},
+		RetrySettings: exporterhelper.RetrySettings{
+			Enabled:         true,
+			InitialInterval: 10 * time.Second,
+			MaxInterval:     time.Minute,
+			MaxElapsedTime:  10 * time.Minute,
+		},
+		DSN:                "https://key@host/path/42",
+		Release:            "checkout@1.2.3",
+		DefaultEnvironment: "production",
+		Dist:               "42",
+		Tags:               map[string]string{"region": "eu-west-1", "team": "checkout"},
+		Storage:            "envelope_storage",
+		File: FileSettings{
+			MaxSizeMiB: 100,
+			Compress:   true,
+		},
+		Logs:                LogsSettings{MinLevel: "info", ErrorLevel: "warn"},
+		MaxBreadcrumbs:      50,
+		MaxTagKeyLength:     32,
+		MaxTagValueLength:   100,
+		InferHTTPStatus:     false,
+		LibraryTags:         false,
+		ScrubSQL:            true,
+		MeasurementPrefixes: []string{"measurement.", "webvitals."},
+		ExcludeTags:         []string{"http.user_agent", "*.id"},
+		SpanData:            SpanDataSettings{Enabled: true, Tags: []string{"http.method", "http.route"}},
+		SemanticConventions: true,
+		ConversionWorkers:   4,
+		SendWorkers:         2,
+	})
+
+	e2 := cfg.Exporters[config.NewIDWithName(typeStr, "file")]
+	assert.Equal(t, e2, &Config{
+		ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "file")),
+		TimeoutSettings:  exporterhelper.DefaultTimeoutSettings(),
+		QueueSettings:    exporterhelper.DefaultQueueSettings(),
+		RetrySettings:    exporterhelper.DefaultRetrySettings(),
+		File: FileSettings{
+			Directory:  "/var/lib/otelcol/envelopes",
+			MaxSizeMiB: 10,
+			MaxFiles:   50,
+			Compress:   false,
+		},
+		MaxBreadcrumbs:      100,
+		MaxTagKeyLength:     32,
+		MaxTagValueLength:   200,
+		InferHTTPStatus:     true,
+		LibraryTags:         true,
+		MeasurementPrefixes: []string{"measurement."},
+		SendWorkers:         8,
+	})
+
+	e4 := cfg.Exporters[config.NewIDWithName(typeStr, "pending")]
+	assert.Equal(t, e4, &Config{
+		ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "pending")),
+		TimeoutSettings:  exporterhelper.DefaultTimeoutSettings(),
+		QueueSettings:    exporterhelper.DefaultQueueSettings(),
+		RetrySettings:    exporterhelper.DefaultRetrySettings(),
+		DSN:              "https://key@host/path/42",
+		File: FileSettings{
+			MaxSizeMiB: 100,
+			Compress:   true,
+		},
+		Pending:             PendingSettings{MaxMemoryMiB: 64},
+		MaxBreadcrumbs:      100,
+		MaxTagKeyLength:     32,
+		MaxTagValueLength:   200,
+		InferHTTPStatus:     true,
+		LibraryTags:         true,
+		MeasurementPrefixes: []string{"measurement."},
+		SendWorkers:         8,
+	})
+
+	e3 := cfg.Exporters[config.NewIDWithName(typeStr, "occurrences")]
+	assert.Equal(t, e3, &Config{
+		ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "occurrences")),
+		TimeoutSettings:  exporterhelper.DefaultTimeoutSettings(),
+		QueueSettings:    exporterhelper.DefaultQueueSettings(),
+		RetrySettings:    exporterhelper.DefaultRetrySettings(),
 		DSN:              "https://key@host/path/42",
+		ErrorsOnly:       true,
+		File: FileSettings{
+			MaxSizeMiB: 100,
+			Compress:   true,
+		},
+		Occurrences: OccurrencesSettings{
+			AuthToken: "token",
+			Rules: []OccurrenceRule{
+				{
+					Signal:      "spans",
+					Name:        "^GET /checkout$",
+					Attributes:  map[string]string{"slo.breached": "true"},
+					Type:        9001,
+					IssueTitle:  "Checkout SLO breached",
+					Fingerprint: []string{"service.name"},
+					Evidence:    []string{"http.route"},
+				},
+			},
+		},
+		MaxBreadcrumbs:      100,
+		MaxTagKeyLength:     32,
+		MaxTagValueLength:   200,
+		InferHTTPStatus:     true,
+		LibraryTags:         true,
+		MeasurementPrefixes: []string{"measurement."},
+		SendWorkers:         8,
 	})
 }
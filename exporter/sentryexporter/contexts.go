@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// cloudContextType is the type of the cloud context, named like the one of Sentry SDKs.
+const cloudContextType = "cloud_resource"
+
+// attributeContexts maps attributes, usually of resources, to the keys of Sentry contexts. The
+// first attribute set for a key is used.
+// See https://develop.sentry.dev/sdk/event-payloads/contexts/.
+var attributeContexts = []struct {
+	attribute string
+	context   string
+	key       string
+}{
+	{"os.name", "os", "name"},
+	{conventions.AttributeOSType, "os", "name"},
+	{"os.version", "os", "version"},
+	{"os.build_id", "os", "build"},
+	{conventions.AttributeOSDescription, "os", "raw_description"},
+	{"process.runtime.name", "runtime", "name"},
+	{"process.runtime.version", "runtime", "version"},
+	{"process.runtime.description", "runtime", "raw_description"},
+	{"device.id", "device", "id"},
+	{"device.manufacturer", "device", "manufacturer"},
+	{"device.model.name", "device", "model"},
+	{"device.model.identifier", "device", "model_id"},
+	{"host.arch", "device", "arch"},
+	{conventions.AttributeCloudProvider, "cloud", conventions.AttributeCloudProvider},
+	{conventions.AttributeCloudAccount, "cloud", conventions.AttributeCloudAccount},
+	{conventions.AttributeCloudRegion, "cloud", conventions.AttributeCloudRegion},
+	{conventions.AttributeCloudAvailabilityZone, "cloud", conventions.AttributeCloudAvailabilityZone},
+	{conventions.AttributeCloudPlatform, "cloud", conventions.AttributeCloudPlatform},
+	{conventions.AttributeHostID, "cloud", conventions.AttributeHostID},
+	{conventions.AttributeHostType, "cloud", conventions.AttributeHostType},
+}
+
+// kubernetesAttributePrefix is the prefix of the Kubernetes attributes sent in the kubernetes context.
+const kubernetesAttributePrefix = "k8s."
+
+// kubernetesTags maps Kubernetes attributes to the tags searched and grouped by in Sentry. The
+// first attribute set for a tag is used, so that the workload is the deployment, stateful set,
+// daemon set or job of a pod, whichever is set.
+var kubernetesTags = []struct {
+	attribute string
+	tag       string
+}{
+	{conventions.AttributeK8sCluster, "k8s.cluster"},
+	{conventions.AttributeK8sNamespace, "k8s.namespace"},
+	{conventions.AttributeK8sDeployment, "k8s.workload"},
+	{conventions.AttributeK8sStatefulSet, "k8s.workload"},
+	{conventions.AttributeK8sDaemonSet, "k8s.workload"},
+	{conventions.AttributeK8sCronJob, "k8s.workload"},
+	{conventions.AttributeK8sJob, "k8s.workload"},
+	{conventions.AttributeK8sPod, "k8s.pod"},
+	{conventions.AttributeK8sNodeName, "k8s.node"},
+	{conventions.AttributeK8sContainer, "k8s.container"},
+}
+
+// setContextsFromTags sets the os, runtime, device, cloud, kubernetes and faas contexts and the
+// server name of an event from the attributes in its tags, and removes those attributes from the tags.
+func setContextsFromTags(event *sentry.Event, tags map[string]string) {
+	setKubernetesFromTags(event, tags)
+	setFaaSFromTags(event, tags)
+
+	for _, m := range attributeContexts {
+		value, ok := tags[m.attribute]
+		if !ok {
+			continue
+		}
+		delete(tags, m.attribute)
+
+		context, _ := event.Contexts[m.context].(map[string]interface{})
+		if context == nil {
+			context = make(map[string]interface{})
+			if m.context == "cloud" {
+				context["type"] = cloudContextType
+			}
+			event.Contexts[m.context] = context
+		}
+		if _, ok := context[m.key]; !ok {
+			context[m.key] = value
+		}
+	}
+
+	if hostName, ok := tags[conventions.AttributeHostName]; ok {
+		event.ServerName = hostName
+		delete(tags, conventions.AttributeHostName)
+	}
+}
+
+// setKubernetesFromTags moves the k8s.* attributes in the tags of an event to its kubernetes
+// context, keyed without the prefix and with underscores, e.g. pod_name for k8s.pod.name, and sets
+// the Kubernetes tags from them.
+func setKubernetesFromTags(event *sentry.Event, tags map[string]string) {
+	context := make(map[string]interface{})
+	for k, v := range tags {
+		if !strings.HasPrefix(k, kubernetesAttributePrefix) {
+			continue
+		}
+		context[strings.ReplaceAll(strings.TrimPrefix(k, kubernetesAttributePrefix), ".", "_")] = v
+		delete(tags, k)
+	}
+	if len(context) == 0 {
+		return
+	}
+	event.Contexts["kubernetes"] = context
+
+	for _, m := range kubernetesTags {
+		key := strings.ReplaceAll(strings.TrimPrefix(m.attribute, kubernetesAttributePrefix), ".", "_")
+		if value, ok := context[key].(string); ok {
+			if _, ok := tags[m.tag]; !ok {
+				tags[m.tag] = value
+			}
+		}
+	}
+}
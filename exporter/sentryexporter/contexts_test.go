@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetContextsFromTags(t *testing.T) {
+	tags := map[string]string{
+		"service.name":                "checkout",
+		"host.name":                   "checkout-1",
+		"host.arch":                   "arm64",
+		"host.type":                   "m6g.large",
+		"os.type":                     "linux",
+		"os.name":                     "Ubuntu",
+		"os.version":                  "20.04",
+		"process.runtime.name":        "OpenJDK Runtime Environment",
+		"process.runtime.version":     "14.0.2",
+		"process.runtime.description": "AdoptOpenJDK 14.0.2+12",
+		"device.model.identifier":     "iPhone13,2",
+		"device.manufacturer":         "Apple",
+		"cloud.provider":              "aws",
+		"cloud.availability_zone":     "eu-west-1a",
+	}
+	event := sentry.NewEvent()
+	setContextsFromTags(event, tags)
+
+	assert.Equal(t, map[string]string{"service.name": "checkout"}, tags)
+	assert.Equal(t, "checkout-1", event.ServerName)
+	assert.Equal(t, map[string]interface{}{
+		"os": map[string]interface{}{"name": "Ubuntu", "version": "20.04"},
+		"runtime": map[string]interface{}{
+			"name":            "OpenJDK Runtime Environment",
+			"version":         "14.0.2",
+			"raw_description": "AdoptOpenJDK 14.0.2+12",
+		},
+		"device": map[string]interface{}{"arch": "arm64", "model_id": "iPhone13,2", "manufacturer": "Apple"},
+		"cloud": map[string]interface{}{
+			"type":                    "cloud_resource",
+			"cloud.provider":          "aws",
+			"cloud.availability_zone": "eu-west-1a",
+			"host.type":               "m6g.large",
+		},
+	}, event.Contexts)
+}
+
+func TestSetKubernetesFromTags(t *testing.T) {
+	tags := map[string]string{
+		"service.name":         "checkout",
+		"k8s.cluster.name":     "prod-eu",
+		"k8s.namespace.name":   "shop",
+		"k8s.deployment.name":  "checkout",
+		"k8s.replicaset.name":  "checkout-7d9f8b6c5",
+		"k8s.pod.name":         "checkout-7d9f8b6c5-x2x4q",
+		"k8s.pod.uid":          "1ae2c8f6-2b54-4f65-a8b6-5a5a3f7c9e01",
+		"k8s.node.name":        "node-3",
+		"k8s.container.name":   "app",
+		"k8s.statefulset.name": "ignored-workload",
+	}
+	event := sentry.NewEvent()
+	setContextsFromTags(event, tags)
+
+	assert.Equal(t, map[string]string{
+		"service.name":  "checkout",
+		"k8s.cluster":   "prod-eu",
+		"k8s.namespace": "shop",
+		"k8s.workload":  "checkout",
+		"k8s.pod":       "checkout-7d9f8b6c5-x2x4q",
+		"k8s.node":      "node-3",
+		"k8s.container": "app",
+	}, tags)
+	assert.Equal(t, map[string]interface{}{
+		"cluster_name":     "prod-eu",
+		"namespace_name":   "shop",
+		"deployment_name":  "checkout",
+		"replicaset_name":  "checkout-7d9f8b6c5",
+		"pod_name":         "checkout-7d9f8b6c5-x2x4q",
+		"pod_uid":          "1ae2c8f6-2b54-4f65-a8b6-5a5a3f7c9e01",
+		"node_name":        "node-3",
+		"container_name":   "app",
+		"statefulset_name": "ignored-workload",
+	}, event.Contexts["kubernetes"])
+}
+
+func TestSetKubernetesFromTagsWithoutAttributes(t *testing.T) {
+	event := sentry.NewEvent()
+	setContextsFromTags(event, map[string]string{"service.name": "checkout"})
+	assert.NotContains(t, event.Contexts, "kubernetes")
+}
+
+func TestSetFaaSFromTags(t *testing.T) {
+	tags := map[string]string{
+		"service.name":   "thumbnails",
+		"faas.name":      "resize-image",
+		"faas.version":   "7",
+		"faas.trigger":   "datasource",
+		"faas.coldstart": "true",
+		"faas.execution": "af9d5aa4-a685-4c5f-a22b-444f80b3cc28",
+		"cloud.provider": "aws",
+		"cloud.region":   "eu-west-1",
+	}
+	event := sentry.NewEvent()
+	setContextsFromTags(event, tags)
+
+	assert.Equal(t, map[string]string{
+		"service.name":   "thumbnails",
+		"faas.trigger":   "datasource",
+		"faas.coldstart": "true",
+		"faas.region":    "eu-west-1",
+	}, tags)
+	assert.Equal(t, map[string]interface{}{
+		"name":      "resize-image",
+		"version":   "7",
+		"trigger":   "datasource",
+		"coldstart": "true",
+		"execution": "af9d5aa4-a685-4c5f-a22b-444f80b3cc28",
+		"region":    "eu-west-1",
+	}, event.Contexts["faas"])
+	assert.Equal(t, map[string]interface{}{
+		"type":           "cloud_resource",
+		"cloud.provider": "aws",
+		"cloud.region":   "eu-west-1",
+	}, event.Contexts["cloud"])
+
+	// The region of the invoked function takes precedence.
+	tags = map[string]string{
+		"faas.invoked_name":   "resize-image",
+		"faas.invoked_region": "us-east-1",
+		"cloud.region":        "eu-west-1",
+	}
+	event = sentry.NewEvent()
+	setContextsFromTags(event, tags)
+	assert.Equal(t, map[string]string{"faas.invoked_name": "resize-image", "faas.region": "us-east-1"}, tags)
+
+	event = sentry.NewEvent()
+	setContextsFromTags(event, map[string]string{"cloud.region": "eu-west-1"})
+	assert.NotContains(t, event.Contexts, "faas")
+}
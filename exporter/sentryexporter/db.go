@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	// dbTypeAttribute is the deprecated attribute of the kind of database of a span, such as sql.
+	dbTypeAttribute = "db.type"
+
+	// dbOp is the op of database spans of unknown systems.
+	dbOp = "db"
+	// dbSQLQueryOp is the op of spans of SQL databases, used by Sentry's queries insights.
+	dbSQLQueryOp = "db.sql.query"
+)
+
+// dbOps maps db.system values, and sql for db.type, to the ops of Sentry span naming conventions.
+// See https://develop.sentry.dev/sdk/performance/span-operations/#database.
+var dbOps = map[string]string{
+	"sql":           dbSQLQueryOp,
+	"other_sql":     dbSQLQueryOp,
+	"mssql":         dbSQLQueryOp,
+	"mysql":         dbSQLQueryOp,
+	"mariadb":       dbSQLQueryOp,
+	"oracle":        dbSQLQueryOp,
+	"db2":           dbSQLQueryOp,
+	"postgresql":    dbSQLQueryOp,
+	"redshift":      dbSQLQueryOp,
+	"cockroachdb":   dbSQLQueryOp,
+	"sqlite":        dbSQLQueryOp,
+	"h2":            dbSQLQueryOp,
+	"hsqldb":        dbSQLQueryOp,
+	"derby":         dbSQLQueryOp,
+	"hive":          dbSQLQueryOp,
+	"hanadb":        dbSQLQueryOp,
+	"clickhouse":    dbSQLQueryOp,
+	"spanner":       dbSQLQueryOp,
+	"sybase":        dbSQLQueryOp,
+	"teradata":      dbSQLQueryOp,
+	"vertica":       dbSQLQueryOp,
+	"informix":      dbSQLQueryOp,
+	"firebird":      dbSQLQueryOp,
+	"redis":         "db.redis",
+	"memcached":     "db.memcached",
+	"mongodb":       "db.mongodb",
+	"cassandra":     "db.cassandra",
+	"couchdb":       "db.couchdb",
+	"couchbase":     "db.couchbase",
+	"cosmosdb":      "db.cosmosdb",
+	"dynamodb":      "db.dynamodb",
+	"elasticsearch": "db.elasticsearch",
+	"hbase":         "db.hbase",
+	"neo4j":         "db.neo4j",
+	"geode":         "db.geode",
+}
+
+// dbSpanOp returns the op of a database span from its db.system, or else db.type, attribute. It
+// returns false if the span is not a database span.
+func dbSpanOp(attrs pdata.AttributeMap) (string, bool) {
+	system, ok := attrs.Get(conventions.AttributeDBSystem)
+	if !ok {
+		if system, ok = attrs.Get(dbTypeAttribute); !ok {
+			return "", false
+		}
+	}
+	if op, ok := dbOps[system.StringVal()]; ok {
+		return op, true
+	}
+	return dbOp, true
+}
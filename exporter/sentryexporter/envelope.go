@@ -0,0 +1,237 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const (
+	envelopeItemTypeEvent       = "event"
+	envelopeItemTypeTransaction = "transaction"
+	envelopeItemTypeSessions    = "sessions"
+	envelopeItemTypeStatsd      = "statsd"
+	envelopeContentType         = "application/x-sentry-envelope"
+)
+
+// envelopeHeader is the first line of a Sentry envelope.
+// See https://develop.sentry.dev/sdk/envelopes/ for details about the format.
+type envelopeHeader struct {
+	EventID sentry.EventID `json:"event_id,omitempty"`
+	SentAt  time.Time      `json:"sent_at"`
+}
+
+// envelopeItemHeader precedes the payload of each item in an envelope.
+type envelopeItemHeader struct {
+	Type   string `json:"type"`
+	Length int    `json:"length"`
+}
+
+// eventPayload has the fields of sentry.Event, but not its methods.
+type eventPayload sentry.Event
+
+// errorEventPayload is the payload of an event item. It adds the exception mechanism, that the
+// sentry-go types are missing, to the event.
+type errorEventPayload struct {
+	*eventPayload
+	Timestamp json.RawMessage        `json:"timestamp,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+	User      *userPayload           `json:"user,omitempty"`
+	Exception []exceptionPayload     `json:"exception,omitempty"`
+	Meta      map[string]interface{} `json:"_meta,omitempty"`
+
+	// The fields below are only sent for transactions. They shadow those of the event to
+	// be omitted.
+	Type           json.RawMessage `json:"type,omitempty"`
+	StartTimestamp json.RawMessage `json:"start_timestamp,omitempty"`
+	Spans          json.RawMessage `json:"spans,omitempty"`
+}
+
+type exceptionPayload struct {
+	sentry.Exception
+	Mechanism *exceptionMechanism `json:"mechanism,omitempty"`
+}
+
+func newErrorEventPayload(event *sentry.Event) (*errorEventPayload, error) {
+	p := &errorEventPayload{eventPayload: (*eventPayload)(event), Extra: event.Extra}
+	if !event.Timestamp.IsZero() {
+		timestamp, err := json.Marshal(event.Timestamp.UTC())
+		if err != nil {
+			return nil, err
+		}
+		p.Timestamp = timestamp
+	}
+
+	mechanism, _ := event.Extra[exceptionMechanismKey].(*exceptionMechanism)
+	if mechanism != nil {
+		p.Extra = make(map[string]interface{}, len(event.Extra)-1)
+		for k, v := range event.Extra {
+			if k != exceptionMechanismKey {
+				p.Extra[k] = v
+			}
+		}
+	}
+	p.User, p.Extra = newUserPayload(event.User, p.Extra)
+	p.Extra, p.Meta = extractMeta(p.Extra)
+	for _, exception := range event.Exception {
+		p.Exception = append(p.Exception, exceptionPayload{Exception: exception, Mechanism: mechanism})
+	}
+	return p, nil
+}
+
+// transactionPayload is the payload of a transaction item. It adds the fields that the
+// sentry-go types are missing to the transaction event.
+type transactionPayload struct {
+	*eventPayload
+	Extra           map[string]interface{} `json:"extra,omitempty"`
+	User            *userPayload           `json:"user,omitempty"`
+	Spans           []*spanPayload         `json:"spans,omitempty"`
+	MetricsSummary  metricsSummary         `json:"_metrics_summary,omitempty"`
+	TransactionInfo *transactionInfo       `json:"transaction_info,omitempty"`
+	Measurements    map[string]measurement `json:"measurements,omitempty"`
+	Meta            map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type spanPayload struct {
+	*sentry.Span
+	Data           map[string]interface{} `json:"data,omitempty"`
+	MetricsSummary metricsSummary         `json:"_metrics_summary,omitempty"`
+	Links          []spanLink             `json:"links,omitempty"`
+}
+
+func newTransactionPayload(transaction *sentry.Event) *transactionPayload {
+	p := &transactionPayload{eventPayload: (*eventPayload)(transaction)}
+	p.Extra, p.MetricsSummary = extractMetricsSummary(transaction.Extra)
+	p.User, p.Extra = newUserPayload(transaction.User, p.Extra)
+	p.Extra, p.Measurements = extractMeasurements(p.Extra)
+	p.Extra, p.Meta = extractMeta(p.Extra)
+	var source string
+	if p.Extra, source = extractTransactionSource(p.Extra); source != "" {
+		p.TransactionInfo = &transactionInfo{Source: source}
+	}
+
+	p.Spans = make([]*spanPayload, 0, len(transaction.Spans))
+	for _, span := range transaction.Spans {
+		sp := &spanPayload{Span: span}
+		sp.Data, sp.MetricsSummary = extractMetricsSummary(span.Data)
+		sp.Data, sp.Links = extractSpanLinks(sp.Data)
+		sp.Data, _ = extractTransactionSource(sp.Data)
+		sp.Data, _ = extractMeasurements(sp.Data)
+		p.Spans = append(p.Spans, sp)
+	}
+	return p
+}
+
+// sessionAggregatesPayload is the payload of a sessions item.
+type sessionAggregatesPayload struct {
+	Attrs      sessionAttributes  `json:"attrs"`
+	Aggregates []sessionAggregate `json:"aggregates"`
+}
+
+type sessionAttributes struct {
+	Release     string `json:"release"`
+	Environment string `json:"environment,omitempty"`
+}
+
+type sessionAggregate struct {
+	Started  time.Time `json:"started"`
+	Exited   int       `json:"exited,omitempty"`
+	Errored  int       `json:"errored,omitempty"`
+	Abnormal int       `json:"abnormal,omitempty"`
+	Crashed  int       `json:"crashed,omitempty"`
+}
+
+// transactionToEnvelope encodes a transaction event as a Sentry envelope with a single item.
+// An event ID is assigned to the transaction if it does not have one yet.
+func transactionToEnvelope(transaction *sentry.Event, sentAt time.Time) ([]byte, error) {
+	if transaction.EventID == "" {
+		transaction.EventID = newEventID()
+	}
+
+	payload, err := json.Marshal(newTransactionPayload(transaction))
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeEnvelope(envelopeHeader{EventID: transaction.EventID, SentAt: sentAt.UTC()}, envelopeItemTypeTransaction, payload)
+}
+
+// eventToEnvelope encodes an error or message event as a Sentry envelope with a single item.
+// An event ID is assigned to the event if it does not have one yet.
+func eventToEnvelope(event *sentry.Event, sentAt time.Time) ([]byte, error) {
+	if event.EventID == "" {
+		event.EventID = newEventID()
+	}
+
+	p, err := newErrorEventPayload(event)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeEnvelope(envelopeHeader{EventID: event.EventID, SentAt: sentAt.UTC()}, envelopeItemTypeEvent, payload)
+}
+
+// sessionsToEnvelope encodes the session aggregates of a release as a Sentry envelope with a single item.
+func sessionsToEnvelope(aggregates SessionAggregates, sentAt time.Time) ([]byte, error) {
+	p := sessionAggregatesPayload{
+		Attrs: sessionAttributes{Release: aggregates.Release, Environment: aggregates.Environment},
+	}
+	for _, b := range aggregates.Buckets {
+		p.Aggregates = append(p.Aggregates, sessionAggregate{
+			Started:  b.Started.UTC(),
+			Exited:   b.Exited,
+			Errored:  b.Errored,
+			Abnormal: b.Abnormal,
+			Crashed:  b.Crashed,
+		})
+	}
+
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeEnvelope(envelopeHeader{SentAt: sentAt.UTC()}, envelopeItemTypeSessions, payload)
+}
+
+// statsdToEnvelope encodes metrics in the statsd format of Sentry as an envelope with a single item.
+func statsdToEnvelope(lines []string, sentAt time.Time) ([]byte, error) {
+	return encodeEnvelope(envelopeHeader{SentAt: sentAt.UTC()}, envelopeItemTypeStatsd, []byte(strings.Join(lines, "\n")))
+}
+
+// encodeEnvelope encodes an envelope with a single item.
+func encodeEnvelope(header envelopeHeader, itemType string, payload []byte) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	if err := enc.Encode(header); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(envelopeItemHeader{Type: itemType, Length: len(payload)}); err != nil {
+		return nil, err
+	}
+	b.Write(payload)
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
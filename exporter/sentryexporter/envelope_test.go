@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionToEnvelope(t *testing.T) {
+	transaction := sentry.NewEvent()
+	transaction.Type = "transaction"
+	transaction.Transaction = "GET /users"
+
+	sentAt := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	envelope, err := transactionToEnvelope(transaction, sentAt)
+	require.NoError(t, err)
+	require.NotEmpty(t, transaction.EventID)
+
+	lines := bytes.Split(bytes.TrimSuffix(envelope, []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+
+	var header envelopeHeader
+	require.NoError(t, json.Unmarshal(lines[0], &header))
+	assert.Equal(t, transaction.EventID, header.EventID)
+	assert.Equal(t, sentAt, header.SentAt)
+
+	var itemHeader envelopeItemHeader
+	require.NoError(t, json.Unmarshal(lines[1], &itemHeader))
+	assert.Equal(t, envelopeItemTypeTransaction, itemHeader.Type)
+	assert.Equal(t, len(lines[2]), itemHeader.Length)
+
+	var payload sentry.Event
+	require.NoError(t, json.Unmarshal(lines[2], &payload))
+	assert.Equal(t, transaction.EventID, payload.EventID)
+	assert.Equal(t, "GET /users", payload.Transaction)
+}
+
+func TestTransactionToEnvelopeKeepsEventID(t *testing.T) {
+	transaction := sentry.NewEvent()
+	transaction.EventID = "0123456789abcdef0123456789abcdef"
+
+	_, err := transactionToEnvelope(transaction, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, sentry.EventID("0123456789abcdef0123456789abcdef"), transaction.EventID)
+}
+
+func TestSessionsToEnvelope(t *testing.T) {
+	started := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	envelope, err := sessionsToEnvelope(SessionAggregates{
+		Release:     "checkout@1.2.3",
+		Environment: "production",
+		Buckets:     []SessionBucket{{Started: started, Exited: 10, Errored: 2}},
+	}, started.Add(time.Minute))
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSuffix(envelope, []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"sent_at":"2021-05-01T12:01:00Z"}`, string(lines[0]))
+	assert.JSONEq(t, fmt.Sprintf(`{"type":"sessions","length":%d}`, len(lines[2])), string(lines[1]))
+	assert.JSONEq(t, `{
+		"attrs": {"release": "checkout@1.2.3", "environment": "production"},
+		"aggregates": [{"started": "2021-05-01T12:00:00Z", "exited": 10, "errored": 2}]
+	}`, string(lines[2]))
+}
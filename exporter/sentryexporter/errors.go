@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	// exceptionMechanismKey is the key of the mechanism of the exception of error events. Until
+	// they are encoded, mechanisms are kept under this key in the extra data of events, as the
+	// sentry-go types have no field for them.
+	exceptionMechanismKey = "_exception_mechanism"
+
+	// exceptionMechanismType is the mechanism type of the exceptions recorded on spans.
+	exceptionMechanismType = "otel"
+
+	attributeExceptionEscaped = "exception.escaped"
+)
+
+// exceptionMechanism describes how an exception was captured.
+// See https://develop.sentry.dev/sdk/event-payloads/exception/#exception-mechanism.
+type exceptionMechanism struct {
+	Type string `json:"type"`
+	// Handled is false for exceptions that escaped the span, and not set if it is not known.
+	Handled *bool `json:"handled,omitempty"`
+}
+
+// spanErrorEvents returns an error event for each exception event of the spans of the traces.
+// If fromStatus is set, failed spans without exception events get an error event too.
+func spanErrorEvents(td pdata.Traces, fromStatus bool) []*sentry.Event {
+	var events []*sentry.Event
+	resourceSpans := td.ResourceSpans()
+	for i := 0; i < resourceSpans.Len(); i++ {
+		rs := resourceSpans.At(i)
+		var resourceTags map[string]string
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				hasException := false
+				spanEvents := span.Events()
+				for l := 0; l < spanEvents.Len(); l++ {
+					spanEvent := spanEvents.At(l)
+					if spanEvent.Name() != conventions.AttributeExceptionEventName {
+						continue
+					}
+					if resourceTags == nil {
+						resourceTags = generateTagsFromResource(rs.Resource())
+					}
+					events = append(events, errorEventFromException(span, spanEvent, resourceTags))
+					hasException = true
+				}
+
+				if fromStatus && !hasException && span.Status().Code() == pdata.StatusCodeError {
+					if resourceTags == nil {
+						resourceTags = generateTagsFromResource(rs.Resource())
+					}
+					events = append(events, errorEventFromStatus(span, resourceTags))
+				}
+			}
+		}
+	}
+	return events
+}
+
+// errorEventFromException converts an exception event of a span to a Sentry error event, tagged
+// like the span and linked to it by the trace context.
+func errorEventFromException(span pdata.Span, spanEvent pdata.SpanEvent, resourceTags map[string]string) *sentry.Event {
+	attrs := spanEvent.Attributes()
+
+	exception := sentry.Exception{}
+	if v, ok := attrs.Get(conventions.AttributeExceptionType); ok {
+		exception.Type = v.StringVal()
+	}
+	if v, ok := attrs.Get(conventions.AttributeExceptionMessage); ok {
+		exception.Value = v.StringVal()
+	}
+
+	mechanism := &exceptionMechanism{Type: exceptionMechanismType}
+	if v, ok := attrs.Get(attributeExceptionEscaped); ok && v.Type() == pdata.AttributeValueTypeBool {
+		handled := !v.BoolVal()
+		mechanism.Handled = &handled
+	}
+
+	event := newSpanErrorEvent(span, resourceTags)
+	event.Exception = []sentry.Exception{exception}
+	event.Extra[exceptionMechanismKey] = mechanism
+	if v, ok := attrs.Get(conventions.AttributeExceptionStacktrace); ok {
+		event.Extra[conventions.AttributeExceptionStacktrace] = v.StringVal()
+	}
+	if spanEvent.Timestamp() != 0 {
+		event.Timestamp = unixNanoToTime(spanEvent.Timestamp())
+	}
+
+	return event
+}
+
+// errorEventFromStatus converts a failed span to a Sentry error event, whose message is the status
+// message of the span, or its name.
+func errorEventFromStatus(span pdata.Span, resourceTags map[string]string) *sentry.Event {
+	event := newSpanErrorEvent(span, resourceTags)
+	event.Message = event.Tags[statusMessageTag]
+	if event.Message == "" {
+		event.Message = span.Name()
+	}
+	event.Transaction = span.Name()
+	return event
+}
+
+// spanStatusMessage returns the status message of a span, or its otel.status_description.
+func spanStatusMessage(span pdata.Span) string {
+	if message := span.Status().Message(); message != "" {
+		return message
+	}
+	return statusDescription(span.Attributes())
+}
+
+// newSpanErrorEvent returns an error event of a span, tagged like the span and linked to it by the
+// trace context, at the end of the span.
+func newSpanErrorEvent(span pdata.Span, resourceTags map[string]string) *sentry.Event {
+	event := sentry.NewEvent()
+	event.EventID = newEventID()
+	event.Level = sentry.LevelError
+	event.Platform = "other"
+	event.Sdk.Name = otelSentryExporterName
+	event.Sdk.Version = otelSentryExporterVersion
+	event.Tags = mergeTags(resourceTags, generateTagsFromAttributes(span.Attributes()))
+	applyTagOverrides(event.Tags)
+	// The status message is tagged like on spans.
+	delete(event.Tags, statusDescriptionAttribute)
+	if message := spanStatusMessage(span); message != "" {
+		event.Tags[statusMessageTag] = message
+	}
+	setUserFromTags(event, event.Tags)
+	setContextsFromTags(event, event.Tags)
+	setReleaseFromTags(event, event.Tags)
+	event.Contexts["trace"] = map[string]string{
+		"trace_id": span.TraceID().HexString(),
+		"span_id":  span.SpanID().HexString(),
+	}
+
+	event.Timestamp = unixNanoToTime(span.EndTimestamp())
+	if span.EndTimestamp() == 0 {
+		event.Timestamp = time.Now().UTC()
+	}
+	return event
+}
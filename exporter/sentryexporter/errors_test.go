@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// newExceptionTraces returns traces with a span recording an exception, escaped or not if set.
+func newExceptionTraces(escaped *bool) pdata.Traces {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	span := rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("POST /charge")
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.Attributes().InsertString("http.route", "/charge")
+
+	event := span.Events().AppendEmpty()
+	event.SetName(conventions.AttributeExceptionEventName)
+	event.SetTimestamp(pdata.TimestampFromTime(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)))
+	event.Attributes().InsertString(conventions.AttributeExceptionType, "CardError")
+	event.Attributes().InsertString(conventions.AttributeExceptionMessage, "card declined")
+	event.Attributes().InsertString(conventions.AttributeExceptionStacktrace, "at charge (charge.js:12)")
+	if escaped != nil {
+		event.Attributes().InsertBool(attributeExceptionEscaped, *escaped)
+	}
+	span.Events().AppendEmpty().SetName("retry")
+	return td
+}
+
+func TestSpanErrorEvents(t *testing.T) {
+	events := spanErrorEvents(newExceptionTraces(nil), false)
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, "error", string(event.Level))
+	require.Len(t, event.Exception, 1)
+	assert.Equal(t, "CardError", event.Exception[0].Type)
+	assert.Equal(t, "card declined", event.Exception[0].Value)
+	assert.Equal(t, "at charge (charge.js:12)", event.Extra[conventions.AttributeExceptionStacktrace])
+	assert.Equal(t, map[string]string{"service.name": "checkout", "http.route": "/charge"}, event.Tags)
+	assert.Equal(t, map[string]string{"trace_id": "0102030405060708090a0b0c0d0e0f10", "span_id": "0102030405060708"}, event.Contexts["trace"])
+	assert.Equal(t, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), event.Timestamp)
+}
+
+func TestErrorEventMechanism(t *testing.T) {
+	escaped, caught := true, false
+	tests := []struct {
+		name      string
+		escaped   *bool
+		mechanism map[string]interface{}
+	}{
+		{"escaped", &escaped, map[string]interface{}{"type": "otel", "handled": false}},
+		{"caught", &caught, map[string]interface{}{"type": "otel", "handled": true}},
+		{"unknown", nil, map[string]interface{}{"type": "otel"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			events := spanErrorEvents(newExceptionTraces(test.escaped), false)
+			require.Len(t, events, 1)
+			envelope, err := eventToEnvelope(events[0], time.Now())
+			require.NoError(t, err)
+
+			lines := bytes.Split(bytes.TrimSuffix(envelope, []byte("\n")), []byte("\n"))
+			require.Len(t, lines, 3)
+			var payload struct {
+				Exception []struct {
+					Type      string                 `json:"type"`
+					Mechanism map[string]interface{} `json:"mechanism"`
+				} `json:"exception"`
+				Extra map[string]interface{} `json:"extra"`
+			}
+			require.NoError(t, json.Unmarshal(lines[2], &payload))
+			require.Len(t, payload.Exception, 1)
+			assert.Equal(t, "CardError", payload.Exception[0].Type)
+			assert.Equal(t, test.mechanism, payload.Exception[0].Mechanism)
+			assert.NotContains(t, payload.Extra, exceptionMechanismKey)
+			assert.NotContains(t, string(lines[2]), "start_timestamp")
+		})
+	}
+}
+
+func TestPushTraceDataErrorEvents(t *testing.T) {
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport}
+
+	require.NoError(t, s.pushTraceData(context.Background(), newExceptionTraces(nil)))
+	var types []string
+	for _, envelope := range transport.envelopes {
+		lines := bytes.SplitN(envelope, []byte("\n"), 3)
+		require.Len(t, lines, 3)
+		var header envelopeItemHeader
+		require.NoError(t, json.Unmarshal(lines[1], &header))
+		types = append(types, header.Type)
+	}
+	assert.Equal(t, []string{envelopeItemTypeTransaction, envelopeItemTypeEvent}, types)
+}
+
+func TestSpanErrorEventsFromStatus(t *testing.T) {
+	td := newExceptionTraces(nil)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.At(0).Status().SetCode(pdata.StatusCodeError)
+	failed := spans.AppendEmpty()
+	failed.SetName("SELECT orders")
+	failed.Status().SetCode(pdata.StatusCodeError)
+	failed.Status().SetMessage("connection refused")
+	failed.SetEndTimestamp(pdata.TimestampFromTime(time.Date(2021, 6, 1, 0, 0, 1, 0, time.UTC)))
+	unnamed := spans.AppendEmpty()
+	unnamed.SetName("GET /orders")
+	unnamed.Status().SetCode(pdata.StatusCodeError)
+	spans.AppendEmpty().SetName("ok")
+
+	assert.Len(t, spanErrorEvents(td, false), 1)
+
+	events := spanErrorEvents(td, true)
+	require.Len(t, events, 3)
+	assert.Equal(t, "CardError", events[0].Exception[0].Type)
+	assert.Equal(t, "connection refused", events[1].Message)
+	assert.Equal(t, "SELECT orders", events[1].Transaction)
+	assert.Empty(t, events[1].Exception)
+	assert.Equal(t, time.Date(2021, 6, 1, 0, 0, 1, 0, time.UTC), events[1].Timestamp)
+	assert.Equal(t, "GET /orders", events[2].Message)
+}
+
+func TestSpanErrorEventsStatusDescription(t *testing.T) {
+	td := pdata.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("SELECT orders")
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Attributes().InsertString("otel.status_description", "connection refused")
+
+	events := spanErrorEvents(td, true)
+	require.Len(t, events, 1)
+	assert.Equal(t, "connection refused", events[0].Message)
+	assert.Equal(t, "connection refused", events[0].Tags["status_message"])
+	assert.NotContains(t, events[0].Tags, "otel.status_description")
+}
+
+func TestPushTraceDataErrorsOnly(t *testing.T) {
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport, errorsOnly: true}
+
+	require.NoError(t, s.pushTraceData(context.Background(), newExceptionTraces(nil)))
+	require.Len(t, transport.envelopes, 1)
+	lines := bytes.SplitN(transport.envelopes[0], []byte("\n"), 3)
+	require.Len(t, lines, 3)
+	var header envelopeItemHeader
+	require.NoError(t, json.Unmarshal(lines[1], &header))
+	assert.Equal(t, envelopeItemTypeEvent, header.Type)
+}
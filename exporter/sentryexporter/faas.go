@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// FaaS attributes of spans and resources.
+// See https://github.com/open-telemetry/opentelemetry-specification/blob/5b78ee1/specification/trace/semantic_conventions/faas.md.
+const (
+	faasAttributePrefix        = "faas."
+	faasTriggerAttribute       = "faas.trigger"
+	faasColdstartAttribute     = "faas.coldstart"
+	faasInvokedNameAttribute   = "faas.invoked_name"
+	faasInvokedRegionAttribute = "faas.invoked_region"
+
+	// faasInvokeOp is the op of the client spans invoking a function.
+	faasInvokeOp = "function"
+)
+
+// faasTags maps FaaS attributes to the tags searched and grouped by in Sentry. The first attribute
+// set for a tag is used, so that the region is the one of the invoked function, if any, or else
+// the one the function runs in.
+var faasTags = []struct {
+	attribute string
+	tag       string
+}{
+	{faasTriggerAttribute, "faas.trigger"},
+	{faasColdstartAttribute, "faas.coldstart"},
+	{faasInvokedNameAttribute, "faas.invoked_name"},
+	{faasInvokedRegionAttribute, "faas.region"},
+	{conventions.AttributeCloudRegion, "faas.region"},
+}
+
+// faasSpanDescriptors returns the op and the description of a FaaS span. Spans triggering a
+// function have their faas.trigger as op, and spans invoking a function are named after the
+// faas.invoked_name. It returns false if the span has neither attribute.
+func faasSpanDescriptors(name string, attrs pdata.AttributeMap) (op string, description string, ok bool) {
+	if trigger, ok := attrs.Get(faasTriggerAttribute); ok {
+		return trigger.StringVal(), name, true
+	}
+	if invokedName, ok := attrs.Get(faasInvokedNameAttribute); ok && invokedName.StringVal() != "" {
+		return faasInvokeOp, invokedName.StringVal(), true
+	}
+	return "", "", false
+}
+
+// setFaaSFromTags moves the faas.* attributes in the tags of an event to its faas context, keyed
+// without the prefix and with underscores, e.g. invoked_name for faas.invoked_name, and sets the
+// FaaS tags from them. The cloud.region of functions is their region in the context.
+func setFaaSFromTags(event *sentry.Event, tags map[string]string) {
+	values := make(map[string]string, len(faasTags))
+	for _, m := range faasTags {
+		if value, ok := tags[m.attribute]; ok {
+			if _, ok := values[m.tag]; !ok {
+				values[m.tag] = value
+			}
+		}
+	}
+
+	context := make(map[string]interface{})
+	for k, v := range tags {
+		if !strings.HasPrefix(k, faasAttributePrefix) {
+			continue
+		}
+		context[strings.ReplaceAll(strings.TrimPrefix(k, faasAttributePrefix), ".", "_")] = v
+		delete(tags, k)
+	}
+	if len(context) == 0 {
+		return
+	}
+	if region, ok := tags[conventions.AttributeCloudRegion]; ok {
+		context["region"] = region
+	}
+	event.Contexts["faas"] = context
+
+	for tag, value := range values {
+		if _, ok := tags[tag]; !ok {
+			tags[tag] = value
+		}
+	}
+}
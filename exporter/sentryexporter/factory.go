@@ -17,7 +17,9 @@ package sentryexporter
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -27,18 +29,41 @@ const (
 	typeStr = "sentry"
 )
 
+var once sync.Once
+
 // NewFactory creates a factory for Sentry exporter.
 func NewFactory() component.ExporterFactory {
+	once.Do(func() {
+		// TODO: as with other -contrib factories registering metrics, this is causing the error being ignored
+		_ = view.Register(MetricViews()...)
+	})
+
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
 		exporterhelper.WithTraces(createTracesExporter),
+		exporterhelper.WithMetrics(createMetricsExporter),
+		exporterhelper.WithLogs(createLogsExporter),
 	)
 }
 
 func createDefaultConfig() config.Exporter {
 	return &Config{
 		ExporterSettings: config.NewExporterSettings(config.NewID(typeStr)),
+		TimeoutSettings:  exporterhelper.DefaultTimeoutSettings(),
+		QueueSettings:    exporterhelper.DefaultQueueSettings(),
+		RetrySettings:    exporterhelper.DefaultRetrySettings(),
+		File: FileSettings{
+			MaxSizeMiB: 100,
+			Compress:   true,
+		},
+		MaxBreadcrumbs:      defaultMaxBreadcrumbs,
+		MaxTagKeyLength:     defaultMaxTagKeyLength,
+		MaxTagValueLength:   defaultMaxTagValueLength,
+		InferHTTPStatus:     true,
+		LibraryTags:         true,
+		MeasurementPrefixes: []string{defaultMeasurementPrefix},
+		SendWorkers:         defaultSendWorkers,
 	}
 }
 
@@ -56,3 +81,41 @@ func createTracesExporter(
 	exp, err := CreateSentryExporter(sentryConfig, params)
 	return exp, err
 }
+
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	config config.Exporter,
+) (component.LogsExporter, error) {
+	sentryConfig, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("unexpected config type: %T", config)
+	}
+
+	return CreateSentryLogsExporter(sentryConfig, params)
+}
+
+func createMetricsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	config config.Exporter,
+) (component.MetricsExporter, error) {
+	sentryConfig, ok := config.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("unexpected config type: %T", config)
+	}
+
+	return CreateSentryMetricsExporter(sentryConfig, params)
+}
+
+// exporterOptions returns the options of the exporter helper for the exporter s, which queue pushes
+// and retry the failed ones.
+func exporterOptions(config *Config, s *SentryExporter) []exporterhelper.Option {
+	return []exporterhelper.Option{
+		exporterhelper.WithStart(s.start),
+		exporterhelper.WithShutdown(s.shutdown),
+		exporterhelper.WithTimeout(config.TimeoutSettings),
+		exporterhelper.WithQueue(config.QueueSettings),
+		exporterhelper.WithRetry(config.RetrySettings),
+	}
+}
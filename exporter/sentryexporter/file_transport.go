@@ -0,0 +1,239 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	envelopeFilePrefix     = "envelopes-"
+	envelopeFileSuffix     = ".envelopes"
+	envelopeFileGzipSuffix = ".envelopes.gz"
+	envelopeFileTimeFormat = "20060102T150405.000000000Z"
+)
+
+// fileTransport writes envelopes to local files instead of sending them to Sentry.
+//
+// A file is a sequence of envelopes, each preceded by a line with its length in bytes.
+// Files are rotated when they reach the configured size, and optionally compressed.
+type fileTransport struct {
+	directory string
+	maxSize   int64
+	maxFiles  int
+	compress  bool
+
+	mu   sync.Mutex
+	file *os.File
+	gzip *gzip.Writer
+	buf  *bufio.Writer
+	size int64
+	now  func() time.Time
+}
+
+func newFileTransport(settings FileSettings) (*fileTransport, error) {
+	if err := os.MkdirAll(settings.Directory, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create envelope directory: %w", err)
+	}
+
+	return &fileTransport{
+		directory: settings.Directory,
+		maxSize:   int64(settings.MaxSizeMiB) * 1024 * 1024,
+		maxFiles:  settings.MaxFiles,
+		compress:  settings.Compress,
+		now:       time.Now,
+	}, nil
+}
+
+// SendEnvelope appends an envelope to the current file.
+func (t *fileTransport) SendEnvelope(_ context.Context, envelope []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file != nil && t.maxSize > 0 && t.size+int64(len(envelope)) > t.maxSize {
+		if err := t.closeFile(); err != nil {
+			return err
+		}
+	}
+	if t.file == nil {
+		if err := t.openFile(); err != nil {
+			return err
+		}
+	}
+
+	n, err := fmt.Fprintf(t.buf, "%d\n", len(envelope))
+	if err != nil {
+		return err
+	}
+	if _, err = t.buf.Write(envelope); err != nil {
+		return err
+	}
+	t.size += int64(n + len(envelope))
+
+	// Flush every envelope, so that they are not lost if the collector stops unexpectedly.
+	if err = t.buf.Flush(); err != nil {
+		return err
+	}
+	if t.gzip != nil {
+		return t.gzip.Flush()
+	}
+	return nil
+}
+
+// Close closes the current file.
+func (t *fileTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+	return t.closeFile()
+}
+
+func (t *fileTransport) openFile() error {
+	suffix := envelopeFileSuffix
+	if t.compress {
+		suffix = envelopeFileGzipSuffix
+	}
+	name := envelopeFilePrefix + t.now().UTC().Format(envelopeFileTimeFormat) + suffix
+
+	file, err := os.OpenFile(filepath.Join(t.directory, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create envelope file: %w", err)
+	}
+
+	t.file = file
+	t.size = 0
+	var w io.Writer = file
+	if t.compress {
+		t.gzip = gzip.NewWriter(file)
+		w = t.gzip
+	}
+	t.buf = bufio.NewWriter(w)
+
+	return t.removeOldFiles()
+}
+
+func (t *fileTransport) closeFile() error {
+	err := t.buf.Flush()
+	if t.gzip != nil {
+		if gzErr := t.gzip.Close(); err == nil {
+			err = gzErr
+		}
+	}
+	if closeErr := t.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	t.file, t.gzip, t.buf = nil, nil, nil
+	return err
+}
+
+// removeOldFiles removes the oldest files if there are more than maxFiles.
+func (t *fileTransport) removeOldFiles() error {
+	if t.maxFiles <= 0 {
+		return nil
+	}
+
+	files, err := listEnvelopeFiles(t.directory)
+	if err != nil {
+		return err
+	}
+	for len(files) > t.maxFiles {
+		if err = os.Remove(files[0]); err != nil {
+			return fmt.Errorf("failed to remove old envelope file: %w", err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// listEnvelopeFiles returns the envelope files in a directory, oldest first.
+func listEnvelopeFiles(directory string) ([]string, error) {
+	entries, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, envelopeFilePrefix) ||
+			!(strings.HasSuffix(name, envelopeFileSuffix) || strings.HasSuffix(name, envelopeFileGzipSuffix)) {
+			continue
+		}
+		files = append(files, filepath.Join(directory, name))
+	}
+	// The timestamp in the names sorts lexicographically.
+	sort.Strings(files)
+	return files, nil
+}
+
+// readEnvelopeFile calls fn for each envelope in a file written by the file transport.
+func readEnvelopeFile(path string, fn func(envelope []byte) error) error {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if errors.Is(err, io.EOF) && line == "" {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: truncated envelope length: %w", path, err)
+		}
+
+		length, err := strconv.Atoi(strings.TrimSuffix(line, "\n"))
+		if err != nil || length < 0 {
+			return fmt.Errorf("%s: invalid envelope length %q", path, strings.TrimSpace(line))
+		}
+
+		envelope := make([]byte, length)
+		if _, err = io.ReadFull(br, envelope); err != nil {
+			return fmt.Errorf("%s: truncated envelope: %w", path, err)
+		}
+		if err = fn(envelope); err != nil {
+			return err
+		}
+	}
+}
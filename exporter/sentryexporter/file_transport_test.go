@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileTransport(t *testing.T, settings FileSettings) *fileTransport {
+	settings.Directory = t.TempDir()
+	transport, err := newFileTransport(settings)
+	require.NoError(t, err)
+
+	// Give each file a distinct timestamp.
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	transport.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return transport
+}
+
+func readAllEnvelopes(t *testing.T, files []string) []string {
+	var envelopes []string
+	for _, file := range files {
+		require.NoError(t, readEnvelopeFile(file, func(envelope []byte) error {
+			envelopes = append(envelopes, string(envelope))
+			return nil
+		}))
+	}
+	return envelopes
+}
+
+func TestFileTransportRoundTrip(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		transport := newTestFileTransport(t, FileSettings{Compress: compress})
+
+		envelopes := []string{"{}\n{\"type\":\"transaction\"}\n{}\n", "second\nwith\nnewlines", ""}
+		for _, envelope := range envelopes {
+			require.NoError(t, transport.SendEnvelope(context.Background(), []byte(envelope)))
+		}
+		require.NoError(t, transport.Close())
+
+		files, err := listEnvelopeFiles(transport.directory)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, compress, strings.HasSuffix(files[0], ".gz"))
+		assert.Equal(t, envelopes, readAllEnvelopes(t, files))
+	}
+}
+
+func TestFileTransportRotation(t *testing.T) {
+	transport := newTestFileTransport(t, FileSettings{MaxFiles: 2, Compress: true})
+	transport.maxSize = 10
+
+	for _, envelope := range []string{"envelope1", "envelope2", "envelope3"} {
+		require.NoError(t, transport.SendEnvelope(context.Background(), []byte(envelope)))
+	}
+	require.NoError(t, transport.Close())
+
+	files, err := listEnvelopeFiles(transport.directory)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, []string{"envelope2", "envelope3"}, readAllEnvelopes(t, files))
+}
+
+func TestListEnvelopeFilesIgnoresOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"envelopes-2.envelopes", "envelopes-1.envelopes.gz", "other.envelopes", "envelopes-3.txt"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+
+	files, err := listEnvelopeFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "envelopes-1.envelopes.gz"),
+		filepath.Join(dir, "envelopes-2.envelopes"),
+	}, files)
+}
+
+func TestReadEnvelopeFileInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"length.envelopes":    "abc\n{}",
+		"truncated.envelopes": "10\n{}",
+		"newline.envelopes":   "10",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		assert.Error(t, readEnvelopeFile(path, func([]byte) error { return nil }), name)
+	}
+}
+
+func TestSentryExporterFileMode(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.File.Directory = t.TempDir()
+
+	exporter, err := newSentryExporter(cfg, nil)
+	require.NoError(t, err)
+	_, ok := exporter.transport.(*fileTransport)
+	assert.True(t, ok)
+}
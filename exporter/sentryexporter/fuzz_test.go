@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package sentryexporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// The fuzz tests need the native fuzzing of Go 1.18. Without -fuzz, `go test` runs them on their
+// seed corpus like regular tests.
+
+// fuzzSpan returns a span with the fuzzed name, timestamps and attribute.
+func fuzzSpan(name, key, value string, start, end int64) pdata.Span {
+	span := pdata.NewSpan()
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.SetName(name)
+	span.SetStartTimestamp(pdata.Timestamp(start))
+	span.SetEndTimestamp(pdata.Timestamp(end))
+	span.Status().SetMessage(value)
+	span.Attributes().InsertString(key, value)
+	span.Attributes().InsertString(conventions.AttributeDBSystem, "postgresql")
+	span.Attributes().InsertString(conventions.AttributeDBStatement, value)
+	return span
+}
+
+func FuzzTransactionToEnvelope(f *testing.F) {
+	f.Add("GET /users", "http.route", "/users/{id}", int64(1622548800000000000), int64(1622548800500000000))
+	f.Add("", "", "", int64(0), int64(0))
+	f.Add("名前 🚀", "clé", "line\nbreak\r\n\x00", int64(math.MinInt64), int64(math.MaxInt64))
+	f.Add("\xff\xfe", "\xc3\x28", "\xed\xa0\x80", int64(-1), int64(1))
+	f.Add(strings.Repeat("a", 1<<16), "long", strings.Repeat(" ", 1<<12), int64(1), int64(-1))
+
+	f.Fuzz(func(t *testing.T, name, key, value string, start, end int64) {
+		library := pdata.NewInstrumentationLibrary()
+		library.SetName(name)
+		library.SetVersion(value)
+		span := convertToSentrySpan(fuzzSpan(name, key, value, start, end), library, map[string]string{key: name})
+		transaction := transactionFromSpan(span)
+
+		envelope, err := transactionToEnvelope(transaction, time.Now())
+		if err != nil {
+			// Only timestamps JSON cannot represent are rejected.
+			if span.StartTimestamp.Year() >= 0 && span.StartTimestamp.Year() <= 9999 && span.EndTimestamp.Year() >= 0 && span.EndTimestamp.Year() <= 9999 {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+
+		// Newlines separate the items, so the payload must not contain any.
+		lines := bytes.Split(bytes.TrimSuffix(envelope, []byte("\n")), []byte("\n"))
+		if len(lines) != 3 {
+			t.Fatalf("envelope has %d lines: %q", len(lines), envelope)
+		}
+		var itemHeader envelopeItemHeader
+		if err = json.Unmarshal(lines[1], &itemHeader); err != nil {
+			t.Fatalf("invalid item header: %v", err)
+		}
+		if itemHeader.Length != len(lines[2]) {
+			t.Fatalf("item length is %d, payload has %d bytes", itemHeader.Length, len(lines[2]))
+		}
+		if !utf8.Valid(lines[2]) {
+			t.Fatalf("payload is not valid UTF-8: %q", lines[2])
+		}
+
+		var payload struct {
+			Transaction string            `json:"transaction"`
+			Tags        map[string]string `json:"tags"`
+		}
+		if err = json.Unmarshal(lines[2], &payload); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		// Invalid UTF-8 is replaced, valid strings are kept as is.
+		if utf8.ValidString(value) && payload.Transaction != value {
+			t.Fatalf("transaction is %q, want %q", payload.Transaction, value)
+		}
+		if utf8.ValidString(value) && payload.Tags["status_message"] != value {
+			t.Fatalf("status_message tag is %q, want %q", payload.Tags["status_message"], value)
+		}
+	})
+}
+
+func FuzzGenerateTagsFromAttributes(f *testing.F) {
+	f.Add("key", "value", int64(42), 3.14, true)
+	f.Add("", "", int64(math.MinInt64), math.Inf(-1), false)
+	f.Add("\xff", "\x00\xff\xfe", int64(math.MaxInt64), math.NaN(), true)
+	f.Add("é", strings.Repeat("🚀", 1<<12), int64(-1), math.SmallestNonzeroFloat64, false)
+
+	f.Fuzz(func(t *testing.T, key, s string, i int64, d float64, b bool) {
+		attrs := pdata.NewAttributeMap()
+		attrs.InsertString(key+".string", s)
+		attrs.InsertInt(key+".int", i)
+		attrs.InsertDouble(key+".double", d)
+		attrs.InsertBool(key+".bool", b)
+		attrs.Insert(key+".map", pdata.NewAttributeValueMap())
+
+		tags := generateTagsFromAttributes(attrs)
+		if len(tags) != 4 {
+			t.Fatalf("got %d tags, want 4: %v", len(tags), tags)
+		}
+		if tags[key+".string"] != s {
+			t.Fatalf("string tag is %q, want %q", tags[key+".string"], s)
+		}
+		if v, err := strconv.ParseInt(tags[key+".int"], 10, 64); err != nil || v != i {
+			t.Fatalf("int tag is %q, want %d", tags[key+".int"], i)
+		}
+		if v, err := strconv.ParseFloat(tags[key+".double"], 64); err != nil || (v != d && !(math.IsNaN(v) && math.IsNaN(d))) {
+			t.Fatalf("double tag is %q, want %v", tags[key+".double"], d)
+		}
+		if tags[key+".bool"] != strconv.FormatBool(b) {
+			t.Fatalf("bool tag is %q, want %v", tags[key+".bool"], b)
+		}
+	})
+}
+
+func FuzzGenerateSpanDescriptors(f *testing.F) {
+	f.Add("GET /users", "GET", "", "", int32(pdata.SpanKindServer))
+	f.Add("query", "", "SELECT 1", "", int32(pdata.SpanKindClient))
+	f.Add("handler", "", "", "http", int32(-1))
+	f.Add("\xff", "\x00", "\xfe", "‮", int32(math.MaxInt32))
+
+	f.Fuzz(func(t *testing.T, name, method, statement, trigger string, kind int32) {
+		attrs := pdata.NewAttributeMap()
+		if method != "" {
+			attrs.InsertString(conventions.AttributeHTTPMethod, method)
+		}
+		if statement != "" {
+			attrs.InsertString(conventions.AttributeDBSystem, "postgresql")
+			attrs.InsertString(conventions.AttributeDBStatement, statement)
+		}
+		if trigger != "" {
+			attrs.InsertString("faas.trigger", trigger)
+		}
+
+		op, description := generateSpanDescriptors(name, attrs, pdata.SpanKind(kind), nil)
+		switch {
+		case method != "":
+			if pdata.SpanKind(kind) == pdata.SpanKindServer {
+				name = parameterizeURLPath(name)
+			}
+			if description != method+" "+name || !strings.HasPrefix(op, "http") {
+				t.Fatalf("op %q and description %q of http span", op, description)
+			}
+		case statement != "":
+			if op != "db.sql.query" || description != statement {
+				t.Fatalf("op %q and description %q of db span", op, description)
+			}
+		case trigger != "":
+			if op != trigger || description != name {
+				t.Fatalf("op %q and description %q of faas span", op, description)
+			}
+		default:
+			if op != "" || description != name {
+				t.Fatalf("op %q and description %q of span", op, description)
+			}
+		}
+	})
+}
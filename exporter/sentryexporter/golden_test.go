@@ -0,0 +1,260 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the conversion tests")
+
+// TestGoldenConversion converts the OTLP JSON traces of testdata/golden/*.json and compares the
+// envelopes sent to Sentry with the .golden file of the same name. Run the tests with -update to
+// write the golden files from the current conversion.
+func TestGoldenConversion(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "golden", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		input := input
+		name := strings.TrimSuffix(filepath.Base(input), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(input)
+			require.NoError(t, err)
+			td, err := tracesFromOTLPJSON(data)
+			require.NoError(t, err)
+
+			transport := &mockTransport{}
+			s := &SentryExporter{transport: transport}
+			require.NoError(t, s.pushTraceData(context.Background(), td))
+			got := goldenEnvelopes(t, transport.envelopes)
+
+			golden := strings.TrimSuffix(input, ".json") + ".golden"
+			if *update {
+				require.NoError(t, ioutil.WriteFile(golden, got, 0600))
+				return
+			}
+			want, err := ioutil.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+var sentAtPattern = regexp.MustCompile(`"sent_at":"[^"]*"`)
+
+// goldenEnvelopes returns envelopes in a stable form for golden files: with zeroed event ids and
+// sent_at times, and each line indented.
+func goldenEnvelopes(t *testing.T, envelopes [][]byte) []byte {
+	var b bytes.Buffer
+	for _, envelope := range envelopes {
+		var header envelopeHeader
+		lines := bytes.Split(bytes.TrimSuffix(envelope, []byte("\n")), []byte("\n"))
+		require.Len(t, lines, 3)
+		require.NoError(t, json.Unmarshal(lines[0], &header))
+
+		zeroID := []byte(strings.Repeat("0", len(header.EventID)))
+		lines[0] = sentAtPattern.ReplaceAll(lines[0], []byte(`"sent_at":"1970-01-01T00:00:00Z"`))
+		for _, line := range lines {
+			line = bytes.ReplaceAll(line, []byte(header.EventID), zeroID)
+			require.NoError(t, json.Indent(&b, line, "", "  "))
+			b.WriteByte('\n')
+		}
+	}
+	return b.Bytes()
+}
+
+// The OTLP JSON encoding of traces, as sent to the OTLP/HTTP receiver: trace and span ids are
+// hex strings, 64-bit integers may be quoted and enums are numbers.
+type otlpJSONTraces struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []otlpJSONKeyValue `json:"attributes"`
+		} `json:"resource"`
+		InstrumentationLibrarySpans []struct {
+			InstrumentationLibrary struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"instrumentationLibrary"`
+			Spans []otlpJSONSpan `json:"spans"`
+		} `json:"instrumentationLibrarySpans"`
+	} `json:"resourceSpans"`
+}
+
+type otlpJSONSpan struct {
+	TraceID           string             `json:"traceId"`
+	SpanID            string             `json:"spanId"`
+	ParentSpanID      string             `json:"parentSpanId"`
+	Name              string             `json:"name"`
+	Kind              int32              `json:"kind"`
+	StartTimeUnixNano otlpJSONInt        `json:"startTimeUnixNano"`
+	EndTimeUnixNano   otlpJSONInt        `json:"endTimeUnixNano"`
+	Attributes        []otlpJSONKeyValue `json:"attributes"`
+	Events            []struct {
+		TimeUnixNano otlpJSONInt        `json:"timeUnixNano"`
+		Name         string             `json:"name"`
+		Attributes   []otlpJSONKeyValue `json:"attributes"`
+	} `json:"events"`
+	Status struct {
+		Code    int32  `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+type otlpJSONKeyValue struct {
+	Key   string           `json:"key"`
+	Value otlpJSONAnyValue `json:"value"`
+}
+
+type otlpJSONAnyValue struct {
+	StringValue *string      `json:"stringValue"`
+	BoolValue   *bool        `json:"boolValue"`
+	IntValue    *otlpJSONInt `json:"intValue"`
+	DoubleValue *float64     `json:"doubleValue"`
+	ArrayValue  *struct {
+		Values []otlpJSONAnyValue `json:"values"`
+	} `json:"arrayValue"`
+	KvlistValue *struct {
+		Values []otlpJSONKeyValue `json:"values"`
+	} `json:"kvlistValue"`
+}
+
+// otlpJSONInt is a 64-bit integer encoded as a JSON number or string.
+type otlpJSONInt int64
+
+func (i *otlpJSONInt) UnmarshalJSON(data []byte) error {
+	v, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	*i = otlpJSONInt(v)
+	return err
+}
+
+// tracesFromOTLPJSON decodes OTLP JSON traces.
+func tracesFromOTLPJSON(data []byte) (pdata.Traces, error) {
+	var in otlpJSONTraces
+	if err := json.Unmarshal(data, &in); err != nil {
+		return pdata.Traces{}, err
+	}
+
+	td := pdata.NewTraces()
+	for _, inRs := range in.ResourceSpans {
+		rs := td.ResourceSpans().AppendEmpty()
+		fillOTLPJSONAttributes(rs.Resource().Attributes(), inRs.Resource.Attributes)
+		for _, inIls := range inRs.InstrumentationLibrarySpans {
+			ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+			ils.InstrumentationLibrary().SetName(inIls.InstrumentationLibrary.Name)
+			ils.InstrumentationLibrary().SetVersion(inIls.InstrumentationLibrary.Version)
+			for _, inSpan := range inIls.Spans {
+				if err := fillOTLPJSONSpan(ils.Spans().AppendEmpty(), inSpan); err != nil {
+					return pdata.Traces{}, err
+				}
+			}
+		}
+	}
+	return td, nil
+}
+
+func fillOTLPJSONSpan(span pdata.Span, in otlpJSONSpan) error {
+	var traceID [16]byte
+	if err := decodeOTLPJSONID(traceID[:], in.TraceID); err != nil {
+		return err
+	}
+	var spanID, parentSpanID [8]byte
+	if err := decodeOTLPJSONID(spanID[:], in.SpanID); err != nil {
+		return err
+	}
+	if err := decodeOTLPJSONID(parentSpanID[:], in.ParentSpanID); err != nil {
+		return err
+	}
+
+	span.SetTraceID(pdata.NewTraceID(traceID))
+	span.SetSpanID(pdata.NewSpanID(spanID))
+	span.SetParentSpanID(pdata.NewSpanID(parentSpanID))
+	span.SetName(in.Name)
+	span.SetKind(pdata.SpanKind(in.Kind))
+	span.SetStartTimestamp(pdata.Timestamp(in.StartTimeUnixNano))
+	span.SetEndTimestamp(pdata.Timestamp(in.EndTimeUnixNano))
+	fillOTLPJSONAttributes(span.Attributes(), in.Attributes)
+	for _, inEvent := range in.Events {
+		event := span.Events().AppendEmpty()
+		event.SetTimestamp(pdata.Timestamp(inEvent.TimeUnixNano))
+		event.SetName(inEvent.Name)
+		fillOTLPJSONAttributes(event.Attributes(), inEvent.Attributes)
+	}
+	span.Status().SetCode(pdata.StatusCode(in.Status.Code))
+	span.Status().SetMessage(in.Status.Message)
+	return nil
+}
+
+// decodeOTLPJSONID decodes a hex trace or span id into id, leaving it empty if s is.
+func decodeOTLPJSONID(id []byte, s string) error {
+	if s == "" {
+		return nil
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(id) {
+		return strconv.ErrSyntax
+	}
+	copy(id, b)
+	return nil
+}
+
+func fillOTLPJSONAttributes(attrs pdata.AttributeMap, in []otlpJSONKeyValue) {
+	for _, kv := range in {
+		attrs.Insert(kv.Key, otlpJSONAttributeValue(kv.Value))
+	}
+}
+
+func otlpJSONAttributeValue(in otlpJSONAnyValue) pdata.AttributeValue {
+	switch {
+	case in.StringValue != nil:
+		return pdata.NewAttributeValueString(*in.StringValue)
+	case in.BoolValue != nil:
+		return pdata.NewAttributeValueBool(*in.BoolValue)
+	case in.IntValue != nil:
+		return pdata.NewAttributeValueInt(int64(*in.IntValue))
+	case in.DoubleValue != nil:
+		return pdata.NewAttributeValueDouble(*in.DoubleValue)
+	case in.ArrayValue != nil:
+		v := pdata.NewAttributeValueArray()
+		for _, value := range in.ArrayValue.Values {
+			v.ArrayVal().Append(otlpJSONAttributeValue(value))
+		}
+		return v
+	case in.KvlistValue != nil:
+		v := pdata.NewAttributeValueMap()
+		fillOTLPJSONAttributes(v.MapVal(), in.KvlistValue.Values)
+		return v
+	default:
+		return pdata.NewAttributeValueNull()
+	}
+}
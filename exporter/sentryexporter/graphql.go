@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// GraphQL attributes of spans.
+// See https://github.com/open-telemetry/semantic-conventions/blob/v1.23.0/docs/database/graphql.md.
+const (
+	graphqlOperationTypeAttribute = "graphql.operation.type"
+	graphqlOperationNameAttribute = "graphql.operation.name"
+
+	// graphqlOp is the op of GraphQL spans without operation type.
+	graphqlOp = "graphql"
+)
+
+// graphqlOps are the ops of the GraphQL operation types.
+var graphqlOps = map[string]string{
+	"query":        "graphql.query",
+	"mutation":     "graphql.mutation",
+	"subscription": "graphql.subscription",
+}
+
+// graphqlSpanDescriptors returns the op, graphql.<graphql.operation.type>, and the description,
+// the graphql.operation.name or else the span name, of a GraphQL span. The graphql.document is
+// never used, as it may be large and contain personal data. It returns false if the span has
+// neither attribute.
+func graphqlSpanDescriptors(name string, attrs pdata.AttributeMap) (op string, description string, ok bool) {
+	operationType, hasType := attrs.Get(graphqlOperationTypeAttribute)
+	operationName, hasName := attrs.Get(graphqlOperationNameAttribute)
+	if !hasType && !hasName {
+		return "", "", false
+	}
+
+	op = graphqlOp
+	if hasType {
+		if typeOp, ok := graphqlOps[operationType.StringVal()]; ok {
+			op = typeOp
+		}
+	}
+	description = name
+	if hasName && operationName.StringVal() != "" {
+		description = operationName.StringVal()
+	}
+	return op, description, true
+}
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"encoding/hex"
+	"strconv"
+)
+
+// maxInternedStrings bounds the number of strings kept by an interner. Once full, it starts over,
+// so that unique values such as timestamps in attributes do not grow it without limit.
+const maxInternedStrings = 4096
+
+// interner deduplicates the strings built while converting spans, such as trace ids, formatted
+// attribute values and span descriptions. Equal strings share their backing memory and are only
+// allocated the first time they are seen. A nil interner allocates every string. It is not safe
+// for concurrent use.
+type interner struct {
+	strings map[string]string
+	buf     []byte
+}
+
+// intern returns a string with the content of b.
+func (in *interner) intern(b []byte) string {
+	if in == nil {
+		return string(b)
+	}
+	// The conversion in the map index does not allocate.
+	if s, ok := in.strings[string(b)]; ok {
+		return s
+	}
+	if in.strings == nil || len(in.strings) >= maxInternedStrings {
+		in.strings = make(map[string]string)
+	}
+	s := string(b)
+	in.strings[s] = s
+	return s
+}
+
+// hex returns the hex encoding of id.
+func (in *interner) hex(id []byte) string {
+	if in == nil {
+		return hex.EncodeToString(id)
+	}
+	n := hex.EncodedLen(len(id))
+	if cap(in.buf) < n {
+		in.buf = make([]byte, n)
+	}
+	in.buf = in.buf[:n]
+	hex.Encode(in.buf, id)
+	return in.intern(in.buf)
+}
+
+func (in *interner) formatInt(v int64) string {
+	if in == nil {
+		return strconv.FormatInt(v, 10)
+	}
+	in.buf = strconv.AppendInt(in.buf[:0], v, 10)
+	return in.intern(in.buf)
+}
+
+func (in *interner) formatFloat(v float64) string {
+	if in == nil {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	in.buf = strconv.AppendFloat(in.buf[:0], v, 'g', -1, 64)
+	return in.intern(in.buf)
+}
+
+// join returns a and b separated by a space.
+func (in *interner) join(a, b string) string {
+	return in.joinWith(a, ' ', b)
+}
+
+// joinWith returns a and b separated by sep.
+func (in *interner) joinWith(a string, sep byte, b string) string {
+	if in == nil {
+		return a + string(sep) + b
+	}
+	in.buf = append(append(append(in.buf[:0], a...), sep), b...)
+	return in.intern(in.buf)
+}
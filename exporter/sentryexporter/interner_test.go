@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterner(t *testing.T) {
+	id := []byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03}
+	for _, in := range []*interner{nil, {}} {
+		assert.Equal(t, "5b8efff798038103", in.hex(id))
+		assert.Equal(t, "-1234567", in.formatInt(-1234567))
+		assert.Equal(t, "0.5", in.formatFloat(0.5))
+		assert.Equal(t, "GET /api/users/{user_id}", in.join("GET", "/api/users/{user_id}"))
+	}
+}
+
+func TestInternerReusesStrings(t *testing.T) {
+	in := &interner{}
+	id := []byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03}
+	in.hex(id)
+	in.formatInt(1234567)
+	in.join("GET", "/api/checkout")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		in.hex(id)
+		in.formatInt(1234567)
+		in.join("GET", "/api/checkout")
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestInternerStartsOverWhenFull(t *testing.T) {
+	in := &interner{}
+	for i := 0; i < maxInternedStrings; i++ {
+		in.formatInt(int64(i))
+	}
+	assert.Len(t, in.strings, maxInternedStrings)
+
+	assert.Equal(t, "-1", in.formatInt(-1))
+	assert.Len(t, in.strings, 1)
+}
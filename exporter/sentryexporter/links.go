@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// spanLinksKey is the key of the links of spans. Until they are encoded, links are kept under
+// this key in the data of spans, as sentry-go spans have no field for them.
+const spanLinksKey = "_links"
+
+// spanLink links a span to a span of the same or another trace, such as the producer of the
+// message it consumes.
+type spanLink struct {
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// traceContextPayload is the trace context of a transaction with the links and the data, such as
+// the status message, of its root span.
+type traceContextPayload struct {
+	sentry.TraceContext
+	Links []spanLink             `json:"links,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+}
+
+// spanLinks returns the links of a span, or nil if it has none.
+func spanLinks(span pdata.Span) []spanLink {
+	links := span.Links()
+	if links.Len() == 0 {
+		return nil
+	}
+
+	result := make([]spanLink, 0, links.Len())
+	for i := 0; i < links.Len(); i++ {
+		link := links.At(i)
+		result = append(result, spanLink{
+			TraceID:    link.TraceID().HexString(),
+			SpanID:     link.SpanID().HexString(),
+			Attributes: generateDataFromAttributes(link.Attributes()),
+		})
+	}
+	return result
+}
+
+// extractSpanLinks returns a copy of data without the links, and the links.
+func extractSpanLinks(data map[string]interface{}) (map[string]interface{}, []spanLink) {
+	links, ok := data[spanLinksKey].([]spanLink)
+	if !ok {
+		return data, nil
+	}
+
+	rest := make(map[string]interface{}, len(data)-1)
+	for k, v := range data {
+		if k != spanLinksKey {
+			rest[k] = v
+		}
+	}
+	return rest, links
+}
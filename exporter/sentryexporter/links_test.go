@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSpanLinks(t *testing.T) {
+	span := pdata.NewSpan()
+	assert.Nil(t, spanLinks(span))
+
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	link.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	link.Attributes().InsertString("messaging.operation", "publish")
+	span.Links().AppendEmpty().SetTraceID(pdata.NewTraceID([16]byte{2}))
+
+	assert.Equal(t, []spanLink{
+		{
+			TraceID:    "0102030405060708090a0b0c0d0e0f10",
+			SpanID:     "0102030405060708",
+			Attributes: map[string]interface{}{"messaging.operation": "publish"},
+		},
+		{TraceID: "02000000000000000000000000000000", SpanID: ""},
+	}, spanLinks(span))
+}
+
+func TestPushTraceDataLinks(t *testing.T) {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	root := spans.AppendEmpty()
+	root.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	root.SetSpanID(pdata.NewSpanID([8]byte{1}))
+	rootLink := root.Links().AppendEmpty()
+	rootLink.SetTraceID(pdata.NewTraceID([16]byte{2}))
+	rootLink.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	child := spans.AppendEmpty()
+	child.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	child.SetSpanID(pdata.NewSpanID([8]byte{3}))
+	child.SetParentSpanID(pdata.NewSpanID([8]byte{1}))
+	childLink := child.Links().AppendEmpty()
+	childLink.SetTraceID(pdata.NewTraceID([16]byte{4}))
+	childLink.SetSpanID(pdata.NewSpanID([8]byte{4}))
+	childLink.Attributes().InsertInt("batch.index", 3)
+
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport}
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+
+	payloads := transactionPayloads(t, transport.envelopes)
+	require.Len(t, payloads, 1)
+	payload := payloads["0100000000000000"]
+
+	trace := payload["contexts"].(map[string]interface{})["trace"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"trace_id": "02000000000000000000000000000000", "span_id": "0200000000000000"},
+	}, trace["links"])
+
+	span := payload["spans"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"trace_id":   "04000000000000000000000000000000",
+			"span_id":    "0400000000000000",
+			"attributes": map[string]interface{}{"batch.index": float64(3)},
+		},
+	}, span["links"])
+	assert.NotContains(t, span, "data")
+}
@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// logLevels lists the Sentry levels of log events from the lowest to the highest.
+var logLevels = []sentry.Level{
+	sentry.LevelDebug,
+	sentry.LevelInfo,
+	sentry.LevelWarning,
+	sentry.LevelError,
+	sentry.LevelFatal,
+}
+
+// logLevelRank returns the position of level in logLevels, or -1 if it is not a Sentry level.
+// The OpenTelemetry "warn" is accepted for warning.
+func logLevelRank(level sentry.Level) int {
+	if level == "warn" {
+		level = sentry.LevelWarning
+	}
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// severityToLevel maps the severity of a log record to a Sentry level. The severity number is
+// used if it is set, the severity text otherwise. Records without a known severity are info.
+func severityToLevel(number pdata.SeverityNumber, text string) sentry.Level {
+	switch {
+	case number >= pdata.SeverityNumberFATAL:
+		return sentry.LevelFatal
+	case number >= pdata.SeverityNumberERROR:
+		return sentry.LevelError
+	case number >= pdata.SeverityNumberWARN:
+		return sentry.LevelWarning
+	case number >= pdata.SeverityNumberINFO:
+		return sentry.LevelInfo
+	case number >= pdata.SeverityNumberTRACE:
+		return sentry.LevelDebug
+	}
+
+	switch strings.ToLower(text) {
+	case "fatal", "critical", "emergency", "alert", "panic":
+		return sentry.LevelFatal
+	case "error", "err":
+		return sentry.LevelError
+	case "warn", "warning":
+		return sentry.LevelWarning
+	case "debug", "trace":
+		return sentry.LevelDebug
+	}
+	return sentry.LevelInfo
+}
+
+// maxLogBreadcrumbs is the maximum number of breadcrumbs attached to an event, the latest are kept.
+const maxLogBreadcrumbs = 100
+
+// logBreadcrumb is a log record below the error level, attached as a breadcrumb to the later
+// events of its resource and trace.
+type logBreadcrumb struct {
+	traceID    string
+	breadcrumb *sentry.Breadcrumb
+}
+
+// logEvents converts the log records of at least the error level to Sentry events. Records
+// between the minimum and the error level are attached to them as breadcrumbs.
+func (s *SentryExporter) logEvents(ld pdata.Logs, now time.Time) []*sentry.Event {
+	var events []*sentry.Event
+	resourceLogs := ld.ResourceLogs()
+	for i := 0; i < resourceLogs.Len(); i++ {
+		rl := resourceLogs.At(i)
+		resourceTags := generateTagsFromResource(rl.Resource())
+
+		var resourceEvents []*sentry.Event
+		var breadcrumbs []logBreadcrumb
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			library := ills.At(j).InstrumentationLibrary()
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				record := logs.At(k)
+				level := severityToLevel(record.SeverityNumber(), record.SeverityText())
+				rank := logLevelRank(level)
+				switch {
+				case rank < s.minLogLevel:
+				case rank < s.errorLogLevel:
+					breadcrumbs = append(breadcrumbs, breadcrumbFromLogRecord(record, library, level, now))
+				default:
+					resourceEvents = append(resourceEvents, eventFromLogRecord(record, library, resourceTags, level, now))
+				}
+			}
+		}
+
+		attachBreadcrumbs(resourceEvents, breadcrumbs)
+		events = append(events, resourceEvents...)
+	}
+	return events
+}
+
+// breadcrumbFromLogRecord converts a log record to a Sentry breadcrumb.
+func breadcrumbFromLogRecord(record pdata.LogRecord, library pdata.InstrumentationLibrary, level sentry.Level, now time.Time) logBreadcrumb {
+	breadcrumb := &sentry.Breadcrumb{
+		Type:      "default",
+		Category:  library.Name(),
+		Level:     level,
+		Message:   logBodyString(record.Body()),
+		Timestamp: now,
+	}
+	if record.Timestamp() != 0 {
+		breadcrumb.Timestamp = unixNanoToTime(record.Timestamp())
+	}
+	if tags := generateTagsFromAttributes(record.Attributes()); len(tags) > 0 {
+		breadcrumb.Data = make(map[string]interface{}, len(tags))
+		for k, v := range tags {
+			breadcrumb.Data[k] = v
+		}
+	}
+
+	var traceID string
+	if !record.TraceID().IsEmpty() {
+		traceID = record.TraceID().HexString()
+	}
+	return logBreadcrumb{traceID: traceID, breadcrumb: breadcrumb}
+}
+
+// attachBreadcrumbs adds to each event the breadcrumbs that happened before it. Events with a
+// trace context only get the breadcrumbs of their trace.
+func attachBreadcrumbs(events []*sentry.Event, breadcrumbs []logBreadcrumb) {
+	if len(events) == 0 || len(breadcrumbs) == 0 {
+		return
+	}
+
+	sort.SliceStable(breadcrumbs, func(i, j int) bool {
+		return breadcrumbs[i].breadcrumb.Timestamp.Before(breadcrumbs[j].breadcrumb.Timestamp)
+	})
+
+	for _, event := range events {
+		trace, _ := event.Contexts["trace"].(map[string]string)
+		traceID := trace["trace_id"]
+		for _, b := range breadcrumbs {
+			if b.breadcrumb.Timestamp.After(event.Timestamp) {
+				break
+			}
+			if traceID != "" && b.traceID != traceID {
+				continue
+			}
+			event.Breadcrumbs = append(event.Breadcrumbs, b.breadcrumb)
+		}
+		if n := len(event.Breadcrumbs); n > maxLogBreadcrumbs {
+			event.Breadcrumbs = event.Breadcrumbs[n-maxLogBreadcrumbs:]
+		}
+	}
+}
+
+// eventFromLogRecord converts a log record to a Sentry event. The record attributes are added
+// to the resource tags, and the trace context links the event to the span of the record.
+func eventFromLogRecord(
+	record pdata.LogRecord,
+	library pdata.InstrumentationLibrary,
+	resourceTags map[string]string,
+	level sentry.Level,
+	now time.Time,
+) *sentry.Event {
+	event := sentry.NewEvent()
+	event.EventID = newEventID()
+	event.Level = level
+	event.Message = logBodyString(record.Body())
+	event.Logger = library.Name()
+	event.Platform = "other"
+	event.Sdk.Name = otelSentryExporterName
+	event.Sdk.Version = otelSentryExporterVersion
+	event.Tags = mergeTags(resourceTags, generateTagsFromAttributes(record.Attributes()))
+	setUserFromTags(event, event.Tags)
+	setContextsFromTags(event, event.Tags)
+	setReleaseFromTags(event, event.Tags)
+
+	event.Timestamp = now
+	if record.Timestamp() != 0 {
+		event.Timestamp = unixNanoToTime(record.Timestamp())
+	}
+
+	if traceID := record.TraceID(); !traceID.IsEmpty() {
+		trace := map[string]string{"trace_id": traceID.HexString()}
+		if spanID := record.SpanID(); !spanID.IsEmpty() {
+			trace["span_id"] = spanID.HexString()
+		}
+		event.Contexts["trace"] = trace
+	}
+
+	return event
+}
+
+// sendEvents sends each event to Sentry in its own envelope. It returns the number of envelopes
+// sent, and the errors of the others.
+func (s *SentryExporter) sendEvents(ctx context.Context, events []*sentry.Event) (int, []error) {
+	var errs []error
+	envelopes := make([][]byte, 0, len(events))
+	sentAt := time.Now()
+	for _, event := range events {
+		s.release.apply(event)
+		s.tagFilter.apply(event)
+		s.staticTags.apply(event)
+		s.tagLimits.apply(event)
+		envelope, err := eventToEnvelope(event, sentAt)
+		if err != nil {
+			errs = append(errs, consumererror.Permanent(err))
+			continue
+		}
+		envelopes = append(envelopes, envelope)
+	}
+	sent, sendErrs := s.sendEnvelopes(ctx, envelopes)
+	return sent, append(errs, sendErrs...)
+}
+
+// pushLogData sends the log records as Sentry events, and the occurrences of the log rules.
+func (s *SentryExporter) pushLogData(ctx context.Context, ld pdata.Logs) error {
+	now := time.Now()
+	sent, errs := s.sendEvents(ctx, s.logEvents(ld, now))
+	n, occurrenceErrs := s.sendOccurrences(ctx, s.logOccurrences(ld, now))
+	return s.pushError(sent+n, append(errs, occurrenceErrs...))
+}
@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestSeverityToLevel(t *testing.T) {
+	tests := []struct {
+		number pdata.SeverityNumber
+		text   string
+		want   sentry.Level
+	}{
+		{pdata.SeverityNumberTRACE2, "", sentry.LevelDebug},
+		{pdata.SeverityNumberDEBUG, "", sentry.LevelDebug},
+		{pdata.SeverityNumberINFO4, "", sentry.LevelInfo},
+		{pdata.SeverityNumberWARN, "ERROR", sentry.LevelWarning},
+		{pdata.SeverityNumberERROR3, "", sentry.LevelError},
+		{pdata.SeverityNumberFATAL, "", sentry.LevelFatal},
+		{pdata.SeverityNumberUNDEFINED, "Warning", sentry.LevelWarning},
+		{pdata.SeverityNumberUNDEFINED, "critical", sentry.LevelFatal},
+		{pdata.SeverityNumberUNDEFINED, "notice", sentry.LevelInfo},
+		{pdata.SeverityNumberUNDEFINED, "", sentry.LevelInfo},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, severityToLevel(test.number, test.text), "%v %q", test.number, test.text)
+	}
+}
+
+func newLogsExporter(t *testing.T, logs LogsSettings) (*SentryExporter, *mockTransport) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DSN = "https://key@sentry.example.com/42"
+	cfg.Logs = logs
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	transport := &mockTransport{}
+	s.transport = transport
+	return s, transport
+}
+
+func TestPushLogData(t *testing.T) {
+	ld := pdata.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "checkout")
+	ill := rl.InstrumentationLibraryLogs().AppendEmpty()
+	ill.InstrumentationLibrary().SetName("checkout.payments")
+
+	record := ill.Logs().AppendEmpty()
+	record.SetSeverityNumber(pdata.SeverityNumberERROR)
+	record.SetTimestamp(pdata.TimestampFromTime(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)))
+	record.Body().SetStringVal("payment declined")
+	record.Attributes().InsertString("payment.provider", "stripe")
+	record.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	record.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+
+	s, transport := newLogsExporter(t, LogsSettings{})
+	require.NoError(t, s.pushLogData(context.Background(), ld))
+	require.Len(t, transport.envelopes, 1)
+
+	lines := bytes.Split(bytes.TrimSuffix(transport.envelopes[0], []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Contains(t, string(lines[1]), `"type":"event"`)
+
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[2], &event))
+	assert.NotEmpty(t, event["event_id"])
+	assert.Equal(t, "error", event["level"])
+	assert.Equal(t, "payment declined", event["message"])
+	assert.Equal(t, "checkout.payments", event["logger"])
+	assert.Equal(t, "2021-06-01T00:00:00Z", event["timestamp"])
+	assert.Equal(t, map[string]interface{}{"service.name": "checkout", "payment.provider": "stripe"}, event["tags"])
+	assert.Equal(t, map[string]interface{}{
+		"trace": map[string]interface{}{"trace_id": "0102030405060708090a0b0c0d0e0f10", "span_id": "0102030405060708"},
+	}, event["contexts"])
+	assert.NotContains(t, event, "type")
+	assert.NotContains(t, event, "start_timestamp")
+}
+
+func TestPushLogDataMinLevel(t *testing.T) {
+	ld := pdata.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+	for _, severity := range []string{"DEBUG", "INFO", "WARN", "ERROR"} {
+		record := logs.AppendEmpty()
+		record.SetSeverityText(severity)
+		record.Body().SetStringVal(severity)
+	}
+
+	s, transport := newLogsExporter(t, LogsSettings{MinLevel: "warning"})
+	require.NoError(t, s.pushLogData(context.Background(), ld))
+	assert.Len(t, transport.envelopes, 2)
+
+	s, transport = newLogsExporter(t, LogsSettings{})
+	require.NoError(t, s.pushLogData(context.Background(), ld))
+	assert.Len(t, transport.envelopes, 4)
+}
+
+func TestPushLogDataBreadcrumbs(t *testing.T) {
+	traceA := pdata.NewTraceID([16]byte{1})
+	traceB := pdata.NewTraceID([16]byte{2})
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	ld := pdata.NewLogs()
+	ill := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty()
+	ill.InstrumentationLibrary().SetName("checkout")
+	logs := ill.Logs()
+	for i, r := range []struct {
+		severity pdata.SeverityNumber
+		traceID  pdata.TraceID
+		body     string
+	}{
+		{pdata.SeverityNumberDEBUG, traceA, "cache lookup"},
+		{pdata.SeverityNumberINFO, traceA, "charging card"},
+		{pdata.SeverityNumberINFO, traceB, "listing products"},
+		{pdata.SeverityNumberWARN, traceA, "card declined"},
+		{pdata.SeverityNumberINFO, traceA, "sending email"},
+	} {
+		record := logs.AppendEmpty()
+		record.SetSeverityNumber(r.severity)
+		record.SetTraceID(r.traceID)
+		record.SetTimestamp(pdata.TimestampFromTime(start.Add(time.Duration(i) * time.Second)))
+		record.Body().SetStringVal(r.body)
+	}
+	logs.At(1).Attributes().InsertString("payment.provider", "stripe")
+
+	s, transport := newLogsExporter(t, LogsSettings{MinLevel: "info", ErrorLevel: "warn"})
+	require.NoError(t, s.pushLogData(context.Background(), ld))
+	require.Len(t, transport.envelopes, 1)
+
+	lines := bytes.Split(bytes.TrimSuffix(transport.envelopes[0], []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+	var event struct {
+		Message     string `json:"message"`
+		Breadcrumbs []struct {
+			Type     string                 `json:"type"`
+			Category string                 `json:"category"`
+			Level    string                 `json:"level"`
+			Message  string                 `json:"message"`
+			Data     map[string]interface{} `json:"data"`
+		} `json:"breadcrumbs"`
+	}
+	require.NoError(t, json.Unmarshal(lines[2], &event))
+	assert.Equal(t, "card declined", event.Message)
+	require.Len(t, event.Breadcrumbs, 1)
+	assert.Equal(t, "default", event.Breadcrumbs[0].Type)
+	assert.Equal(t, "checkout", event.Breadcrumbs[0].Category)
+	assert.Equal(t, "info", event.Breadcrumbs[0].Level)
+	assert.Equal(t, "charging card", event.Breadcrumbs[0].Message)
+	assert.Equal(t, map[string]interface{}{"payment.provider": "stripe"}, event.Breadcrumbs[0].Data)
+}
+
+func TestAttachBreadcrumbsLimit(t *testing.T) {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	var breadcrumbs []logBreadcrumb
+	for i := maxLogBreadcrumbs + 10; i > 0; i-- {
+		breadcrumbs = append(breadcrumbs, logBreadcrumb{
+			breadcrumb: &sentry.Breadcrumb{Timestamp: start.Add(time.Duration(i) * time.Millisecond)},
+		})
+	}
+
+	event := sentry.NewEvent()
+	event.Timestamp = start.Add(time.Second)
+	attachBreadcrumbs([]*sentry.Event{event}, breadcrumbs)
+
+	require.Len(t, event.Breadcrumbs, maxLogBreadcrumbs)
+	assert.Equal(t, start.Add(11*time.Millisecond), event.Breadcrumbs[0].Timestamp)
+	assert.Equal(t, start.Add(time.Duration(maxLogBreadcrumbs+10)*time.Millisecond), event.Breadcrumbs[maxLogBreadcrumbs-1].Timestamp)
+}
+
+func TestNewSentryExporterInvalidLogLevels(t *testing.T) {
+	for _, logs := range []LogsSettings{{MinLevel: "critical"}, {ErrorLevel: "notice"}} {
+		cfg := createDefaultConfig().(*Config)
+		cfg.DSN = "https://key@sentry.example.com/42"
+		cfg.Logs = logs
+		_, err := newSentryExporter(cfg, zap.NewNop())
+		assert.Error(t, err)
+	}
+}
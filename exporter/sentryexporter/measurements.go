@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// measurementsKey is the key of the measurements of spans and transactions. Until they are
+	// encoded, measurements are kept under this key in the data of spans and the extra data of
+	// transactions, as the sentry-go types have no field for them.
+	measurementsKey = "_measurements"
+
+	// defaultMeasurementPrefix is the default prefix of the attributes sent as measurements.
+	defaultMeasurementPrefix = "measurement."
+
+	// Span events named measurementEventName record a measurement, with an optional unit.
+	measurementEventName           = "measurement"
+	measurementEventNameAttribute  = "measurement.name"
+	measurementEventValueAttribute = "measurement.value"
+	measurementEventUnitAttribute  = "measurement.unit"
+)
+
+// measurement is a measurement of a transaction, such as a Web Vital.
+// See https://develop.sentry.dev/sdk/event-payloads/transaction/#measurements.
+type measurement struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty"`
+}
+
+// measurementUnits are the units of the Web Vitals measured by Sentry SDKs, for measurements
+// without unit.
+var measurementUnits = map[string]string{
+	"fp":   "millisecond",
+	"fcp":  "millisecond",
+	"lcp":  "millisecond",
+	"fid":  "millisecond",
+	"inp":  "millisecond",
+	"ttfb": "millisecond",
+	"cls":  "none",
+}
+
+// spanMeasurements returns the measurements of a span, from its int and double attributes
+// starting with one of the prefixes, named without the prefix, and from its measurement events.
+// The attributes are removed from the tags of the span. It returns nil if the span has no
+// measurements.
+func spanMeasurements(span pdata.Span, prefixes []string, tags map[string]string) map[string]measurement {
+	var measurements map[string]measurement
+	add := func(name string, value float64, unit string) {
+		if measurements == nil {
+			measurements = make(map[string]measurement)
+		}
+		if unit == "" {
+			unit = measurementUnits[name]
+		}
+		measurements[name] = measurement{Value: value, Unit: unit}
+	}
+
+	if len(prefixes) > 0 {
+		span.Attributes().Range(func(key string, attr pdata.AttributeValue) bool {
+			value, ok := numericAttributeValue(attr)
+			if !ok {
+				return true
+			}
+			for _, prefix := range prefixes {
+				if name := strings.TrimPrefix(key, prefix); name != key && name != "" {
+					add(name, value, "")
+					delete(tags, key)
+					break
+				}
+			}
+			return true
+		})
+	}
+
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.Name() != measurementEventName {
+			continue
+		}
+
+		attrs := event.Attributes()
+		name, ok := attrs.Get(measurementEventNameAttribute)
+		if !ok || name.Type() != pdata.AttributeValueTypeString || name.StringVal() == "" {
+			continue
+		}
+		v, ok := attrs.Get(measurementEventValueAttribute)
+		if !ok {
+			continue
+		}
+		value, ok := numericAttributeValue(v)
+		if !ok {
+			continue
+		}
+		var unit string
+		if u, ok := attrs.Get(measurementEventUnitAttribute); ok && u.Type() == pdata.AttributeValueTypeString {
+			unit = u.StringVal()
+		}
+		add(name.StringVal(), value, unit)
+	}
+	return measurements
+}
+
+// numericAttributeValue returns the value of an int or double attribute.
+func numericAttributeValue(attr pdata.AttributeValue) (float64, bool) {
+	switch attr.Type() {
+	case pdata.AttributeValueTypeDouble:
+		return attr.DoubleVal(), true
+	case pdata.AttributeValueTypeInt:
+		return float64(attr.IntVal()), true
+	default:
+		return 0, false
+	}
+}
+
+// extractMeasurements returns a copy of data without the measurements, and the measurements.
+func extractMeasurements(data map[string]interface{}) (map[string]interface{}, map[string]measurement) {
+	measurements, ok := data[measurementsKey].(map[string]measurement)
+	if !ok {
+		return data, nil
+	}
+
+	rest := make(map[string]interface{}, len(data)-1)
+	for k, v := range data {
+		if k != measurementsKey {
+			rest[k] = v
+		}
+	}
+	return rest, measurements
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSpanMeasurements(t *testing.T) {
+	span := pdata.NewSpan()
+	span.Attributes().InsertDouble("measurement.fcp", 1234.5)
+	span.Attributes().InsertInt("app.frames_slow", 3)
+	span.Attributes().InsertDouble("measurement.cls", 0.1)
+	span.Attributes().InsertString("measurement.connection", "4g")
+	span.Attributes().InsertInt("measurement.", 1)
+	span.Attributes().InsertInt("http.status_code", 200)
+
+	event := span.Events().AppendEmpty()
+	event.SetName("measurement")
+	event.Attributes().InsertString("measurement.name", "cart.size")
+	event.Attributes().InsertInt("measurement.value", 2)
+	event.Attributes().InsertString("measurement.unit", "none")
+	invalid := span.Events().AppendEmpty()
+	invalid.SetName("measurement")
+	invalid.Attributes().InsertString("measurement.name", "missing.value")
+
+	tags := map[string]string{
+		"measurement.fcp":        "1234.5",
+		"app.frames_slow":        "3",
+		"measurement.cls":        "0.1",
+		"measurement.connection": "4g",
+		"http.status_code":       "200",
+	}
+	measurements := spanMeasurements(span, []string{"measurement.", "app."}, tags)
+	assert.Equal(t, map[string]measurement{
+		"fcp":         {Value: 1234.5, Unit: "millisecond"},
+		"cls":         {Value: 0.1, Unit: "none"},
+		"frames_slow": {Value: 3},
+		"cart.size":   {Value: 2, Unit: "none"},
+	}, measurements)
+	assert.Equal(t, map[string]string{"measurement.connection": "4g", "http.status_code": "200"}, tags)
+
+	assert.Nil(t, spanMeasurements(pdata.NewSpan(), []string{"measurement."}, map[string]string{}))
+}
+
+func TestTransactionMeasurements(t *testing.T) {
+	root := pdata.NewSpan()
+	root.SetName("/checkout")
+	root.Attributes().InsertDouble("measurement.lcp", 2500)
+	child := pdata.NewSpan()
+	child.Attributes().InsertDouble("measurement.lcp", 100)
+	child.Events().AppendEmpty().SetName("measurement")
+
+	options := conversionOptions{measurementPrefixes: []string{defaultMeasurementPrefix}}
+	library := pdata.NewInstrumentationLibrary()
+	rootSpan, childSpan := &sentry.Span{}, &sentry.Span{}
+	fillSentrySpan(rootSpan, root, library, map[string]string{}, make(map[string]string), nil, options)
+	fillSentrySpan(childSpan, child, library, map[string]string{}, make(map[string]string), nil, options)
+	assert.NotContains(t, rootSpan.Tags, "measurement.lcp")
+
+	transaction := transactionFromSpan(rootSpan)
+	transaction.Spans = append(transaction.Spans, childSpan)
+	assert.Empty(t, transaction.Breadcrumbs)
+
+	payload, err := json.Marshal(newTransactionPayload(transaction))
+	require.NoError(t, err)
+	var decoded struct {
+		Measurements map[string]measurement   `json:"measurements"`
+		Extra        map[string]interface{}   `json:"extra"`
+		Spans        []map[string]interface{} `json:"spans"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	assert.Equal(t, map[string]measurement{"lcp": {Value: 2500, Unit: "millisecond"}}, decoded.Measurements)
+	assert.Empty(t, decoded.Extra)
+	require.Len(t, decoded.Spans, 1)
+	assert.NotContains(t, decoded.Spans[0], "data")
+}
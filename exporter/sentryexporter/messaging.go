@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	// messagingSystemAttribute identifies messaging spans.
+	messagingSystemAttribute = "messaging.system"
+	// messagingDestinationNameAttribute is the destination of messaging spans in newer semantic
+	// conventions, replacing messaging.destination.
+	messagingDestinationNameAttribute = "messaging.destination.name"
+
+	// Ops of messaging spans, used by Sentry's queues insights.
+	// See https://develop.sentry.dev/sdk/performance/modules/queues/.
+	queuePublishOp = "queue.publish"
+	queueProcessOp = "queue.process"
+	queueReceiveOp = "queue.receive"
+	messageOp      = "message"
+)
+
+// messagingSpanDescriptors returns the op and description of a messaging span. The op is derived
+// from messaging.operation, or else the span kind, and the description is the destination of the
+// span, or its name without destination.
+func messagingSpanDescriptors(name string, attrs pdata.AttributeMap, spanKind pdata.SpanKind) (op string, description string) {
+	var operation string
+	if attr, ok := attrs.Get(conventions.AttributeMessagingOperation); ok {
+		operation = attr.StringVal()
+	}
+	switch {
+	case operation == "publish" || operation == "send" || operation == "create":
+		op = queuePublishOp
+	case operation == "receive":
+		op = queueReceiveOp
+	case operation == "process":
+		op = queueProcessOp
+	case spanKind == pdata.SpanKindProducer:
+		op = queuePublishOp
+	case spanKind == pdata.SpanKindConsumer:
+		op = queueProcessOp
+	default:
+		op = messageOp
+	}
+
+	for _, key := range []string{messagingDestinationNameAttribute, conventions.AttributeMessagingDestination} {
+		if destination, ok := attrs.Get(key); ok && destination.StringVal() != "" {
+			return op, destination.StringVal()
+		}
+	}
+	return op, name
+}
@@ -0,0 +1,416 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// Types of Sentry metrics, as encoded in statsd lines.
+	sentryMetricCounter      = "c"
+	sentryMetricGauge        = "g"
+	sentryMetricDistribution = "d"
+
+	// maxDistributionValues is the maximum number of values a histogram point is approximated with.
+	maxDistributionValues = 1000
+	// maxStatsdPayloadSize is the size in bytes after which statsd lines are sent in another envelope.
+	maxStatsdPayloadSize = 1 << 20
+	// metricSeriesTTL is the time after which the previous point of a cumulative series is forgotten.
+	metricSeriesTTL = 10 * time.Minute
+)
+
+// metricUnits maps UCUM units used by OpenTelemetry to Sentry units.
+var metricUnits = map[string]string{
+	"ns":   "nanosecond",
+	"us":   "microsecond",
+	"ms":   "millisecond",
+	"s":    "second",
+	"min":  "minute",
+	"h":    "hour",
+	"d":    "day",
+	"By":   "byte",
+	"KBy":  "kilobyte",
+	"KiBy": "kibibyte",
+	"MBy":  "megabyte",
+	"MiBy": "mebibyte",
+	"GBy":  "gigabyte",
+	"GiBy": "gibibyte",
+	"1":    "ratio",
+	"%":    "percent",
+}
+
+// metricsConverter converts OpenTelemetry metrics to Sentry metrics. Sentry counters and
+// distributions are deltas, so it keeps the previous point of cumulative series.
+type metricsConverter struct {
+	mu     sync.Mutex
+	series map[string]*metricSeries
+}
+
+// metricSeries is the previous point of a cumulative sum or histogram.
+type metricSeries struct {
+	start  pdata.Timestamp
+	value  float64
+	counts []uint64
+	seen   time.Time
+}
+
+func newMetricsConverter() *metricsConverter {
+	return &metricsConverter{series: make(map[string]*metricSeries)}
+}
+
+// convert returns the statsd lines of the metrics. Points of cumulative series are only
+// converted once their previous point is known.
+func (c *metricsConverter) convert(md pdata.Metrics, now time.Time) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var lines []string
+	resourceMetrics := md.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+		resourceTags := generateTagsFromResource(rm.Resource())
+
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				lines = c.appendMetric(lines, metrics.At(k), resourceTags, now)
+			}
+		}
+	}
+
+	for key, s := range c.series {
+		if now.Sub(s.seen) > metricSeriesTTL {
+			delete(c.series, key)
+		}
+	}
+	return lines
+}
+
+func (c *metricsConverter) appendMetric(lines []string, metric pdata.Metric, resourceTags map[string]string, now time.Time) []string {
+	name := sentryMetricName(metric.Name()) + "@" + sentryMetricUnit(metric.Unit())
+
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		points := metric.IntGauge().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			lines = appendStatsdLine(lines, name, sentryMetricGauge, []float64{float64(p.Value())}, statsdTags(resourceTags, p.LabelsMap()), p.Timestamp(), now)
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		points := metric.DoubleGauge().DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			lines = appendStatsdLine(lines, name, sentryMetricGauge, []float64{p.Value()}, statsdTags(resourceTags, p.LabelsMap()), p.Timestamp(), now)
+		}
+	case pdata.MetricDataTypeIntSum:
+		sum := metric.IntSum()
+		points := sum.DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			lines = c.appendSum(lines, name, sum.IsMonotonic(), sum.AggregationTemporality(), float64(p.Value()),
+				statsdTags(resourceTags, p.LabelsMap()), p.StartTimestamp(), p.Timestamp(), now)
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		sum := metric.DoubleSum()
+		points := sum.DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			lines = c.appendSum(lines, name, sum.IsMonotonic(), sum.AggregationTemporality(), p.Value(),
+				statsdTags(resourceTags, p.LabelsMap()), p.StartTimestamp(), p.Timestamp(), now)
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		histogram := metric.IntHistogram()
+		points := histogram.DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			lines = c.appendHistogram(lines, name, histogram.AggregationTemporality(), float64(p.Sum()), p.BucketCounts(), p.ExplicitBounds(),
+				statsdTags(resourceTags, p.LabelsMap()), p.StartTimestamp(), p.Timestamp(), now)
+		}
+	case pdata.MetricDataTypeHistogram:
+		histogram := metric.Histogram()
+		points := histogram.DataPoints()
+		for i := 0; i < points.Len(); i++ {
+			p := points.At(i)
+			lines = c.appendHistogram(lines, name, histogram.AggregationTemporality(), p.Sum(), p.BucketCounts(), p.ExplicitBounds(),
+				statsdTags(resourceTags, p.LabelsMap()), p.StartTimestamp(), p.Timestamp(), now)
+		}
+	}
+	return lines
+}
+
+// appendSum appends a monotonic sum as a counter of its increase, and other sums as gauges.
+func (c *metricsConverter) appendSum(
+	lines []string,
+	name string,
+	monotonic bool,
+	temporality pdata.AggregationTemporality,
+	value float64,
+	tags string,
+	start, timestamp pdata.Timestamp,
+	now time.Time,
+) []string {
+	if !monotonic {
+		return appendStatsdLine(lines, name, sentryMetricGauge, []float64{value}, tags, timestamp, now)
+	}
+	if temporality == pdata.AggregationTemporalityDelta {
+		return appendStatsdLine(lines, name, sentryMetricCounter, []float64{value}, tags, timestamp, now)
+	}
+
+	key := sentryMetricCounter + name + tags
+	prev := c.series[key]
+	c.series[key] = &metricSeries{start: start, value: value, seen: now}
+	if prev == nil {
+		return lines
+	}
+	delta := value - prev.value
+	if start != prev.start || delta < 0 {
+		// The series was reset, its value is the increase since.
+		delta = value
+	}
+	return appendStatsdLine(lines, name, sentryMetricCounter, []float64{delta}, tags, timestamp, now)
+}
+
+// appendHistogram appends a histogram as a distribution. As the values are not known, each one
+// is approximated by the middle of its bucket.
+func (c *metricsConverter) appendHistogram(
+	lines []string,
+	name string,
+	temporality pdata.AggregationTemporality,
+	sum float64,
+	counts []uint64,
+	bounds []float64,
+	tags string,
+	start, timestamp pdata.Timestamp,
+	now time.Time,
+) []string {
+	if temporality != pdata.AggregationTemporalityDelta {
+		key := sentryMetricDistribution + name + tags
+		prev := c.series[key]
+		c.series[key] = &metricSeries{start: start, value: sum, counts: counts, seen: now}
+		if prev == nil {
+			return lines
+		}
+		if start == prev.start && len(counts) == len(prev.counts) {
+			if deltas, ok := subtractCounts(counts, prev.counts); ok {
+				counts = deltas
+				sum -= prev.value
+			}
+		}
+	}
+
+	values := distributionValues(sum, counts, bounds)
+	if len(values) == 0 {
+		return lines
+	}
+	return appendStatsdLine(lines, name, sentryMetricDistribution, values, tags, timestamp, now)
+}
+
+// subtractCounts returns the difference of the bucket counts, and false if a count decreased.
+func subtractCounts(counts, prev []uint64) ([]uint64, bool) {
+	deltas := make([]uint64, len(counts))
+	for i := range counts {
+		if counts[i] < prev[i] {
+			return nil, false
+		}
+		deltas[i] = counts[i] - prev[i]
+	}
+	return deltas, true
+}
+
+// distributionValues approximates the values of histogram buckets. Buckets without a lower or
+// upper bound use the bound they have, and histograms without bounds the mean. At most
+// maxDistributionValues values are returned, in proportion to the bucket counts.
+func distributionValues(sum float64, counts []uint64, bounds []float64) []float64 {
+	var total uint64
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var values []float64
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		var value float64
+		switch {
+		case len(bounds) == 0 || len(counts) != len(bounds)+1:
+			value = sum / float64(total)
+		case i == 0:
+			value = bounds[0]
+		case i == len(bounds):
+			value = bounds[i-1]
+		default:
+			value = (bounds[i-1] + bounds[i]) / 2
+		}
+
+		n := count
+		if total > maxDistributionValues {
+			n = uint64(math.Round(float64(count) * maxDistributionValues / float64(total)))
+		}
+		for ; n > 0; n-- {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// appendStatsdLine appends a metric in the statsd format of Sentry:
+// <name>@<unit>:<value>[:<value>...]|<type>[|#<tags>]|T<timestamp>.
+func appendStatsdLine(lines []string, name, metricType string, values []float64, tags string, timestamp pdata.Timestamp, now time.Time) []string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return lines
+		}
+		b.WriteByte(':')
+		b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	b.WriteByte('|')
+	b.WriteString(metricType)
+	if tags != "" {
+		b.WriteString("|#")
+		b.WriteString(tags)
+	}
+
+	t := now
+	if timestamp != 0 {
+		t = unixNanoToTime(timestamp)
+	}
+	b.WriteString("|T")
+	b.WriteString(strconv.FormatInt(t.Unix(), 10))
+
+	return append(lines, b.String())
+}
+
+// statsdTags encodes the resource tags and the labels of a point, sorted by key.
+func statsdTags(resourceTags map[string]string, labels pdata.StringMap) string {
+	tags := make(map[string]string, len(resourceTags)+labels.Len())
+	for k, v := range resourceTags {
+		tags[sanitizeMetricTagKey(k)] = v
+	}
+	labels.Range(func(k, v string) bool {
+		tags[sanitizeMetricTagKey(k)] = v
+		return true
+	})
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(k)
+		b.WriteByte(':')
+		b.WriteString(metricTagValueReplacer.Replace(tags[k]))
+	}
+	return b.String()
+}
+
+// metricTagValueReplacer escapes the characters of tag values that are part of the statsd format.
+var metricTagValueReplacer = strings.NewReplacer(
+	"\\", `\\`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"|", `\u{7c}`,
+	",", `\u{2c}`,
+)
+
+// sentryMetricName replaces the characters Sentry does not accept in metric names with underscores.
+func sentryMetricName(name string) string {
+	return sanitizeMetricString(name, func(r rune) bool {
+		return isMetricAlphanumeric(r) || r == '_' || r == '-' || r == '.'
+	})
+}
+
+// sentryMetricUnit returns the Sentry unit of an OpenTelemetry unit. Annotations such as
+// "{requests}" and empty units are "none".
+func sentryMetricUnit(unit string) string {
+	if u, ok := metricUnits[unit]; ok {
+		return u
+	}
+	if unit == "" || strings.HasPrefix(unit, "{") {
+		return "none"
+	}
+	return sanitizeMetricString(unit, func(r rune) bool {
+		return isMetricAlphanumeric(r) || r == '_'
+	})
+}
+
+func sanitizeMetricTagKey(key string) string {
+	return sanitizeMetricString(key, func(r rune) bool {
+		return isMetricAlphanumeric(r) || r == '_' || r == '-' || r == '.' || r == '/'
+	})
+}
+
+func sanitizeMetricString(s string, valid func(r rune) bool) string {
+	return strings.Map(func(r rune) rune {
+		if valid(r) {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+func isMetricAlphanumeric(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// pushMetricsData converts the metrics to Sentry metrics, and sends them in statsd envelopes of
+// at most maxStatsdPayloadSize bytes.
+func (s *SentryExporter) pushMetricsData(ctx context.Context, md pdata.Metrics) error {
+	now := time.Now()
+	lines := s.metrics.convert(md, now)
+
+	var errs []error
+	var envelopes [][]byte
+	for len(lines) > 0 {
+		size, n := 0, 0
+		for n < len(lines) && (n == 0 || size+len(lines[n])+1 <= maxStatsdPayloadSize) {
+			size += len(lines[n]) + 1
+			n++
+		}
+
+		envelope, err := statsdToEnvelope(lines[:n], now)
+		if err != nil {
+			errs = append(errs, consumererror.Permanent(err))
+		} else {
+			envelopes = append(envelopes, envelope)
+		}
+		lines = lines[n:]
+	}
+	sent, sendErrs := s.sendEnvelopes(ctx, envelopes)
+	return s.pushError(sent, append(errs, sendErrs...))
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// metricsSummaryKey is the key of the metrics summary of spans and transactions. Until
+	// they are encoded, summaries are kept under this key in the data of spans and the extra
+	// data of transactions, as the sentry-go types have no field for them.
+	metricsSummaryKey = "_metrics_summary"
+
+	// metricsSummaryAttribute is a span attribute holding a metrics summary in Sentry's format.
+	metricsSummaryAttribute = "sentry.metrics_summary"
+
+	// Span events named metricEventName record a metric value. Their other attributes are
+	// used as the tags of the metric.
+	metricEventName           = "metric"
+	metricEventMRIAttribute   = "metric.mri"
+	metricEventValueAttribute = "metric.value"
+)
+
+// metricsSummary summarizes the metrics emitted during a span, by metric resource
+// identifier (MRI, e.g. "d:custom/cart.size@none") and set of tags.
+type metricsSummary map[string][]*metricSummary
+
+type metricSummary struct {
+	Min   float64           `json:"min"`
+	Max   float64           `json:"max"`
+	Sum   float64           `json:"sum"`
+	Count int64             `json:"count"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+// spanMetricsSummary returns the metrics summary of a span, from the metricsSummaryAttribute
+// and the metric events of the span. It returns nil if the span has no metrics.
+func spanMetricsSummary(span pdata.Span) metricsSummary {
+	summary := metricsSummary{}
+
+	if attr, ok := span.Attributes().Get(metricsSummaryAttribute); ok && attr.Type() == pdata.AttributeValueTypeString {
+		var parsed metricsSummary
+		// Summaries that cannot be parsed are dropped, the span itself is still valid.
+		if err := json.Unmarshal([]byte(attr.StringVal()), &parsed); err == nil {
+			for mri, summaries := range parsed {
+				for _, s := range summaries {
+					if s != nil {
+						summary.merge(mri, s)
+					}
+				}
+			}
+		}
+	}
+
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.Name() != metricEventName {
+			continue
+		}
+
+		attrs := event.Attributes()
+		mri, ok := attrs.Get(metricEventMRIAttribute)
+		if !ok || mri.Type() != pdata.AttributeValueTypeString || mri.StringVal() == "" {
+			continue
+		}
+		v, ok := attrs.Get(metricEventValueAttribute)
+		if !ok {
+			continue
+		}
+		var value float64
+		switch v.Type() {
+		case pdata.AttributeValueTypeDouble:
+			value = v.DoubleVal()
+		case pdata.AttributeValueTypeInt:
+			value = float64(v.IntVal())
+		default:
+			continue
+		}
+
+		tags := generateTagsFromAttributes(attrs)
+		delete(tags, metricEventMRIAttribute)
+		delete(tags, metricEventValueAttribute)
+		if len(tags) == 0 {
+			tags = nil
+		}
+		summary.merge(mri.StringVal(), &metricSummary{Min: value, Max: value, Sum: value, Count: 1, Tags: tags})
+	}
+
+	if len(summary) == 0 {
+		return nil
+	}
+	return summary
+}
+
+// merge adds a summary to the summary of the same metric and tags.
+func (m metricsSummary) merge(mri string, s *metricSummary) {
+	for _, existing := range m[mri] {
+		if reflect.DeepEqual(existing.Tags, s.Tags) {
+			if s.Min < existing.Min {
+				existing.Min = s.Min
+			}
+			if s.Max > existing.Max {
+				existing.Max = s.Max
+			}
+			existing.Sum += s.Sum
+			existing.Count += s.Count
+			return
+		}
+	}
+	m[mri] = append(m[mri], &metricSummary{Min: s.Min, Max: s.Max, Sum: s.Sum, Count: s.Count, Tags: s.Tags})
+}
+
+// extractMetricsSummary returns a copy of data without the metrics summary, and the summary.
+func extractMetricsSummary(data map[string]interface{}) (map[string]interface{}, metricsSummary) {
+	summary, ok := data[metricsSummaryKey].(metricsSummary)
+	if !ok {
+		return data, nil
+	}
+
+	rest := make(map[string]interface{}, len(data)-1)
+	for k, v := range data {
+		if k != metricsSummaryKey {
+			rest[k] = v
+		}
+	}
+	return rest, summary
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func addMetricEvent(span pdata.Span, mri string, value pdata.AttributeValue, tags map[string]string) {
+	event := span.Events().AppendEmpty()
+	event.SetName(metricEventName)
+	event.Attributes().InsertString(metricEventMRIAttribute, mri)
+	event.Attributes().Insert(metricEventValueAttribute, value)
+	for k, v := range tags {
+		event.Attributes().InsertString(k, v)
+	}
+}
+
+func TestSpanMetricsSummary(t *testing.T) {
+	span := pdata.NewSpan()
+	assert.Nil(t, spanMetricsSummary(span))
+
+	span.Attributes().InsertString(metricsSummaryAttribute,
+		`{"c:custom/checkout.attempts@none":[{"min":1,"max":1,"sum":2,"count":2,"tags":{"region":"eu"}}]}`)
+	addMetricEvent(span, "c:custom/checkout.attempts@none", pdata.NewAttributeValueInt(1), map[string]string{"region": "eu"})
+	addMetricEvent(span, "d:custom/cart.size@none", pdata.NewAttributeValueDouble(3), nil)
+	addMetricEvent(span, "d:custom/cart.size@none", pdata.NewAttributeValueDouble(7.5), nil)
+	addMetricEvent(span, "d:custom/cart.size@none", pdata.NewAttributeValueInt(5), map[string]string{"region": "us"})
+	// Events without a numeric value or an MRI are ignored.
+	addMetricEvent(span, "d:custom/cart.size@none", pdata.NewAttributeValueString("5"), nil)
+	addMetricEvent(span, "", pdata.NewAttributeValueInt(5), nil)
+	span.Events().AppendEmpty().SetName("exception")
+
+	assert.Equal(t, metricsSummary{
+		"c:custom/checkout.attempts@none": {
+			{Min: 1, Max: 1, Sum: 3, Count: 3, Tags: map[string]string{"region": "eu"}},
+		},
+		"d:custom/cart.size@none": {
+			{Min: 3, Max: 7.5, Sum: 10.5, Count: 2},
+			{Min: 5, Max: 5, Sum: 5, Count: 1, Tags: map[string]string{"region": "us"}},
+		},
+	}, spanMetricsSummary(span))
+}
+
+func TestSpanMetricsSummaryInvalidAttribute(t *testing.T) {
+	span := pdata.NewSpan()
+	span.Attributes().InsertString(metricsSummaryAttribute, "not json")
+	assert.Nil(t, spanMetricsSummary(span))
+}
+
+func TestMetricsSummaryInTransactionEnvelope(t *testing.T) {
+	traces := pdata.NewTraces()
+	spans := traces.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+
+	root := spans.AppendEmpty()
+	root.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	root.SetSpanID(pdata.NewSpanID([8]byte{1}))
+	root.SetName("GET /checkout")
+	root.Attributes().InsertString(metricsSummaryAttribute, `{"c:custom/requests@none":[{"min":1,"max":1,"sum":1,"count":1}]}`)
+
+	child := spans.AppendEmpty()
+	child.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	child.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	child.SetParentSpanID(pdata.NewSpanID([8]byte{1}))
+	child.SetName("compute cart")
+	addMetricEvent(child, "d:custom/cart.size@none", pdata.NewAttributeValueInt(4), nil)
+
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport}
+	require.NoError(t, s.pushTraceData(context.Background(), traces))
+	require.Len(t, transport.envelopes, 1)
+
+	lines := bytes.Split(bytes.TrimSuffix(transport.envelopes[0], []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+
+	var payload struct {
+		Tags           map[string]string      `json:"tags"`
+		Extra          map[string]interface{} `json:"extra"`
+		MetricsSummary metricsSummary         `json:"_metrics_summary"`
+		Spans          []struct {
+			Data           map[string]interface{} `json:"data"`
+			MetricsSummary metricsSummary         `json:"_metrics_summary"`
+		} `json:"spans"`
+	}
+	require.NoError(t, json.Unmarshal(lines[2], &payload))
+
+	assert.NotContains(t, payload.Tags, metricsSummaryAttribute)
+	assert.Nil(t, payload.Extra)
+	assert.Equal(t, metricsSummary{"c:custom/requests@none": {{Min: 1, Max: 1, Sum: 1, Count: 1}}}, payload.MetricsSummary)
+	require.Len(t, payload.Spans, 1)
+	assert.Nil(t, payload.Spans[0].Data)
+	assert.Equal(t, metricsSummary{"d:custom/cart.size@none": {{Min: 4, Max: 4, Sum: 4, Count: 1}}}, payload.Spans[0].MetricsSummary)
+}
+
+func TestTransactionPayloadKeepsOtherData(t *testing.T) {
+	transaction := transactionFromSpan(convertToSentrySpan(pdata.NewSpan(), pdata.NewInstrumentationLibrary(), nil))
+	transaction.Extra["key"] = "value"
+
+	envelope, err := transactionToEnvelope(transaction, time.Now())
+	require.NoError(t, err)
+	assert.Contains(t, string(envelope), `"extra":{"key":"value"}`)
+	assert.NotContains(t, string(envelope), metricsSummaryKey)
+}
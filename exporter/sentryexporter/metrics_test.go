@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+var metricsTestTime = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestMetrics returns metrics of a checkout service, with a single metric of the data type.
+func newTestMetrics(name, unit string, dataType pdata.MetricDataType) (pdata.Metrics, pdata.Metric) {
+	md := pdata.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("service.name", "checkout")
+	metric := rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName(name)
+	metric.SetUnit(unit)
+	metric.SetDataType(dataType)
+	return md, metric
+}
+
+func TestMetricsConverterGauge(t *testing.T) {
+	md, metric := newTestMetrics("process.memory", "By", pdata.MetricDataTypeIntGauge)
+	p := metric.IntGauge().DataPoints().AppendEmpty()
+	p.SetValue(1024)
+	p.SetTimestamp(pdata.TimestampFromTime(metricsTestTime))
+	p.LabelsMap().Insert("type", "heap|stack")
+
+	lines := newMetricsConverter().convert(md, metricsTestTime)
+	assert.Equal(t, []string{`process.memory@byte:1024|g|#service.name:checkout,type:heap\u{7c}stack|T1622505600`}, lines)
+}
+
+func TestMetricsConverterCumulativeSum(t *testing.T) {
+	c := newMetricsConverter()
+	start := pdata.TimestampFromTime(metricsTestTime)
+	convert := func(start pdata.Timestamp, value float64) []string {
+		md, metric := newTestMetrics("http.requests", "{requests}", pdata.MetricDataTypeDoubleSum)
+		sum := metric.DoubleSum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		p := sum.DataPoints().AppendEmpty()
+		p.SetStartTimestamp(start)
+		p.SetTimestamp(pdata.TimestampFromTime(metricsTestTime.Add(time.Minute)))
+		p.SetValue(value)
+		return c.convert(md, metricsTestTime)
+	}
+
+	assert.Empty(t, convert(start, 10))
+	assert.Equal(t, []string{"http.requests@none:5|c|#service.name:checkout|T1622505660"}, convert(start, 15))
+	// The value decreased, the series was reset.
+	assert.Equal(t, []string{"http.requests@none:3|c|#service.name:checkout|T1622505660"}, convert(start, 3))
+	// The start changed, the series was restarted.
+	assert.Equal(t, []string{"http.requests@none:7|c|#service.name:checkout|T1622505660"}, convert(start+1, 7))
+}
+
+func TestMetricsConverterSums(t *testing.T) {
+	md, metric := newTestMetrics("queue.size", "", pdata.MetricDataTypeIntSum)
+	metric.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	metric.IntSum().DataPoints().AppendEmpty().SetValue(-4)
+
+	delta := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().AppendEmpty()
+	delta.SetName("jobs done")
+	delta.SetDataType(pdata.MetricDataTypeIntSum)
+	delta.IntSum().SetIsMonotonic(true)
+	delta.IntSum().SetAggregationTemporality(pdata.AggregationTemporalityDelta)
+	delta.IntSum().DataPoints().AppendEmpty().SetValue(2)
+
+	lines := newMetricsConverter().convert(md, metricsTestTime)
+	assert.Equal(t, []string{
+		"queue.size@none:-4|g|#service.name:checkout|T1622505600",
+		"jobs_done@none:2|c|#service.name:checkout|T1622505600",
+	}, lines)
+}
+
+func TestMetricsConverterHistogram(t *testing.T) {
+	c := newMetricsConverter()
+	convert := func(counts []uint64, sum float64) []string {
+		md, metric := newTestMetrics("http.duration", "ms", pdata.MetricDataTypeHistogram)
+		metric.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		p := metric.Histogram().DataPoints().AppendEmpty()
+		p.SetExplicitBounds([]float64{10, 100})
+		p.SetBucketCounts(counts)
+		p.SetSum(sum)
+		p.SetTimestamp(pdata.TimestampFromTime(metricsTestTime))
+		return c.convert(md, metricsTestTime)
+	}
+
+	assert.Empty(t, convert([]uint64{1, 1, 0}, 60))
+	assert.Equal(t, []string{"http.duration@millisecond:10:10:55:100|d|#service.name:checkout|T1622505600"},
+		convert([]uint64{3, 2, 1}, 300))
+}
+
+func TestDistributionValues(t *testing.T) {
+	assert.Equal(t, []float64{2.5, 2.5}, distributionValues(5, []uint64{2}, nil))
+	assert.Nil(t, distributionValues(0, []uint64{0, 0}, []float64{1}))
+
+	values := distributionValues(0, []uint64{1000, 3000, 0}, []float64{1, 3})
+	assert.Len(t, values, maxDistributionValues)
+	assert.Equal(t, float64(1), values[0])
+	assert.Equal(t, float64(1), values[249])
+	assert.Equal(t, float64(2), values[250])
+}
+
+func TestSentryMetricUnit(t *testing.T) {
+	assert.Equal(t, "second", sentryMetricUnit("s"))
+	assert.Equal(t, "ratio", sentryMetricUnit("1"))
+	assert.Equal(t, "none", sentryMetricUnit(""))
+	assert.Equal(t, "none", sentryMetricUnit("{packets}"))
+	assert.Equal(t, "m_s", sentryMetricUnit("m/s"))
+}
+
+func TestPushMetricsData(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DSN = "https://key@sentry.example.com/42"
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	transport := &mockTransport{}
+	s.transport = transport
+
+	md, metric := newTestMetrics("cpu.utilization", "1", pdata.MetricDataTypeDoubleGauge)
+	points := metric.DoubleGauge().DataPoints()
+	for i := 0; i < 2; i++ {
+		p := points.AppendEmpty()
+		p.SetValue(0.5)
+		p.LabelsMap().Insert("cpu", strings.Repeat("x", maxStatsdPayloadSize/2))
+		p.LabelsMap().Insert("index", string(rune('0'+i)))
+	}
+
+	require.NoError(t, s.pushMetricsData(context.Background(), md))
+	require.Len(t, transport.envelopes, 2)
+	for _, envelope := range transport.envelopes {
+		lines := bytes.SplitN(envelope, []byte("\n"), 3)
+		require.Len(t, lines, 3)
+		assert.Contains(t, string(lines[1]), `"type":"statsd"`)
+		assert.True(t, bytes.HasPrefix(lines[2], []byte("cpu.utilization@ratio:0.5|g|#cpu:x")))
+	}
+}
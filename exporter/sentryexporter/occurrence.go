@@ -0,0 +1,369 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	occurrenceSignalSpans = "spans"
+	occurrenceSignalLogs  = "logs"
+
+	defaultOccurrenceLevel = "warning"
+	occurrenceAPIPath      = "/api/0/issue-occurrence/"
+)
+
+var occurrenceLevels = map[string]bool{
+	"debug":   true,
+	"info":    true,
+	"warning": true,
+	"error":   true,
+	"fatal":   true,
+}
+
+// occurrenceRule is an OccurrenceRule ready for matching.
+type occurrenceRule struct {
+	OccurrenceRule
+	name *regexp.Regexp
+}
+
+func newOccurrenceRules(rules []OccurrenceRule) ([]*occurrenceRule, error) {
+	compiled := make([]*occurrenceRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Signal != occurrenceSignalSpans && rule.Signal != occurrenceSignalLogs {
+			return nil, fmt.Errorf("occurrence rule %d: 'signal' must be %q or %q", i, occurrenceSignalSpans, occurrenceSignalLogs)
+		}
+		if rule.Type <= 0 {
+			return nil, fmt.Errorf("occurrence rule %d: 'type' must be a Sentry issue type ID", i)
+		}
+		if rule.IssueTitle == "" {
+			return nil, fmt.Errorf("occurrence rule %d: 'issue_title' is required", i)
+		}
+		if rule.Level == "" {
+			rule.Level = defaultOccurrenceLevel
+		}
+		if !occurrenceLevels[rule.Level] {
+			return nil, fmt.Errorf("occurrence rule %d: invalid 'level' %q", i, rule.Level)
+		}
+
+		r := &occurrenceRule{OccurrenceRule: rule}
+		if rule.Name != "" {
+			name, err := regexp.Compile(rule.Name)
+			if err != nil {
+				return nil, fmt.Errorf("occurrence rule %d: invalid 'name': %w", i, err)
+			}
+			r.name = name
+		}
+		compiled = append(compiled, r)
+	}
+	return compiled, nil
+}
+
+// matches returns whether a span name or log body and the attributes match the rule.
+func (r *occurrenceRule) matches(name string, attrs map[string]string) bool {
+	if r.name != nil && !r.name.MatchString(name) {
+		return false
+	}
+	for k, v := range r.Attributes {
+		if attrs[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// occurrence is the payload of the issue platform.
+// See https://develop.sentry.dev/backend/issue-platform/ for details.
+type occurrence struct {
+	ID              string               `json:"id"`
+	ProjectID       int                  `json:"project_id"`
+	EventID         sentry.EventID       `json:"event_id"`
+	Fingerprint     []string             `json:"fingerprint"`
+	IssueTitle      string               `json:"issue_title"`
+	Subtitle        string               `json:"subtitle"`
+	ResourceID      *string              `json:"resource_id"`
+	EvidenceData    map[string]string    `json:"evidence_data"`
+	EvidenceDisplay []occurrenceEvidence `json:"evidence_display"`
+	Type            int                  `json:"type"`
+	DetectionTime   float64              `json:"detection_time"`
+	Level           string               `json:"level"`
+	Culprit         string               `json:"culprit"`
+	Event           occurrenceEvent      `json:"event"`
+}
+
+type occurrenceEvidence struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Important bool   `json:"important"`
+}
+
+type occurrenceEvent struct {
+	EventID   sentry.EventID         `json:"event_id"`
+	ProjectID int                    `json:"project_id"`
+	Platform  string                 `json:"platform"`
+	Timestamp float64                `json:"timestamp"`
+	Received  float64                `json:"received"`
+	Tags      map[string]string      `json:"tags"`
+	Contexts  map[string]interface{} `json:"contexts,omitempty"`
+}
+
+// newOccurrence creates the occurrence of a rule for a matched span or log record.
+func (r *occurrenceRule) newOccurrence(projectID int, culprit string, attrs map[string]string, traceID pdata.TraceID, spanID pdata.SpanID, timestamp, now time.Time) occurrence {
+	fingerprint := []string{strconv.Itoa(r.Type), r.IssueTitle}
+	for _, key := range r.Fingerprint {
+		fingerprint = append(fingerprint, attrs[key])
+	}
+
+	evidenceData := make(map[string]string, len(r.Evidence))
+	evidenceDisplay := make([]occurrenceEvidence, 0, len(r.Evidence))
+	for i, key := range r.Evidence {
+		value, ok := attrs[key]
+		if !ok {
+			continue
+		}
+		evidenceData[key] = value
+		evidenceDisplay = append(evidenceDisplay, occurrenceEvidence{Name: key, Value: value, Important: i == 0})
+	}
+
+	event := occurrenceEvent{
+		EventID:   newEventID(),
+		ProjectID: projectID,
+		Platform:  "other",
+		Timestamp: float64(timestamp.UnixNano()) / 1e9,
+		Received:  float64(now.UnixNano()) / 1e9,
+		Tags:      attrs,
+	}
+	if !traceID.IsEmpty() {
+		trace := map[string]string{"trace_id": traceID.HexString()}
+		if !spanID.IsEmpty() {
+			trace["span_id"] = spanID.HexString()
+		}
+		event.Contexts = map[string]interface{}{"trace": trace}
+	}
+
+	return occurrence{
+		ID:              string(newEventID()),
+		ProjectID:       projectID,
+		EventID:         event.EventID,
+		Fingerprint:     fingerprint,
+		IssueTitle:      r.IssueTitle,
+		Subtitle:        r.Subtitle,
+		EvidenceData:    evidenceData,
+		EvidenceDisplay: evidenceDisplay,
+		Type:            r.Type,
+		DetectionTime:   float64(now.UnixNano()) / 1e9,
+		Level:           r.Level,
+		Culprit:         culprit,
+		Event:           event,
+	}
+}
+
+// occurrenceClient sends occurrences to the issue occurrence endpoint of Sentry.
+type occurrenceClient struct {
+	client    *http.Client
+	url       string
+	authToken string
+	projectID int
+}
+
+// newOccurrenceClient returns a client sending to the configured URL, or to the Sentry host
+// of the DSN. The project of the DSN is the project occurrences are created in.
+func newOccurrenceClient(dsn string, settings OccurrencesSettings) (*occurrenceClient, error) {
+	parsed, err := sentry.NewDsn(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("a valid 'dsn' is required for occurrences: %w", err)
+	}
+
+	// The envelope URL is <base>/api/<project ID>/envelope/.
+	envelopeURL := parsed.EnvelopeAPIURL().String()
+	apiIndex := strings.LastIndex(envelopeURL, "/api/")
+	projectID, err := strconv.Atoi(strings.Trim(strings.TrimSuffix(envelopeURL[apiIndex+len("/api/"):], "envelope/"), "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID in 'dsn': %w", err)
+	}
+
+	url := settings.URL
+	if url == "" {
+		url = envelopeURL[:apiIndex] + occurrenceAPIPath
+	}
+
+	return &occurrenceClient{
+		client:    &http.Client{Timeout: defaultTransportTimeout},
+		url:       url,
+		authToken: settings.AuthToken,
+		projectID: projectID,
+	}, nil
+}
+
+// SendOccurrence posts an occurrence to Sentry.
+func (c *occurrenceClient) SendOccurrence(ctx context.Context, o occurrence) error {
+	body, err := json.Marshal(o)
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.authToken != "" {
+		request.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("sending occurrence to Sentry failed with status %q", response.Status)
+	}
+	return nil
+}
+
+// spanOccurrences returns the occurrences of the span rules for the spans of the traces.
+func (s *SentryExporter) spanOccurrences(td pdata.Traces, now time.Time) []occurrence {
+	var occurrences []occurrence
+	if !s.hasOccurrenceRules(occurrenceSignalSpans) {
+		return occurrences
+	}
+
+	resourceSpans := td.ResourceSpans()
+	for i := 0; i < resourceSpans.Len(); i++ {
+		rs := resourceSpans.At(i)
+		resourceTags := generateTagsFromResource(rs.Resource())
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				attrs := mergeTags(resourceTags, generateTagsFromAttributes(span.Attributes()))
+
+				for _, rule := range s.occurrenceRules {
+					if rule.Signal != occurrenceSignalSpans || !rule.matches(span.Name(), attrs) {
+						continue
+					}
+					occurrences = append(occurrences, rule.newOccurrence(s.occurrences.projectID, span.Name(), attrs,
+						span.TraceID(), span.SpanID(), unixNanoToTime(span.EndTimestamp()), now))
+				}
+			}
+		}
+	}
+	return occurrences
+}
+
+// logOccurrences returns the occurrences of the log rules for the records of the logs.
+func (s *SentryExporter) logOccurrences(ld pdata.Logs, now time.Time) []occurrence {
+	var occurrences []occurrence
+	if !s.hasOccurrenceRules(occurrenceSignalLogs) {
+		return occurrences
+	}
+
+	resourceLogs := ld.ResourceLogs()
+	for i := 0; i < resourceLogs.Len(); i++ {
+		rl := resourceLogs.At(i)
+		resourceTags := generateTagsFromResource(rl.Resource())
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				record := logs.At(k)
+				attrs := mergeTags(resourceTags, generateTagsFromAttributes(record.Attributes()))
+				body := logBodyString(record.Body())
+
+				timestamp := now
+				if record.Timestamp() != 0 {
+					timestamp = unixNanoToTime(record.Timestamp())
+				}
+
+				for _, rule := range s.occurrenceRules {
+					if rule.Signal != occurrenceSignalLogs || !rule.matches(body, attrs) {
+						continue
+					}
+					occurrences = append(occurrences, rule.newOccurrence(s.occurrences.projectID, body, attrs,
+						record.TraceID(), record.SpanID(), timestamp, now))
+				}
+			}
+		}
+	}
+	return occurrences
+}
+
+func (s *SentryExporter) hasOccurrenceRules(signal string) bool {
+	for _, rule := range s.occurrenceRules {
+		if rule.Signal == signal {
+			return true
+		}
+	}
+	return false
+}
+
+// sendOccurrences sends each occurrence to Sentry. It returns the number of occurrences sent, and
+// the errors of the others.
+func (s *SentryExporter) sendOccurrences(ctx context.Context, occurrences []occurrence) (int, []error) {
+	var errs []error
+	for _, o := range occurrences {
+		if err := s.occurrences.SendOccurrence(ctx, o); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(occurrences) - len(errs), errs
+}
+
+// mergeTags returns the union of resource and record tags, with record tags taking precedence.
+func mergeTags(resourceTags, tags map[string]string) map[string]string {
+	merged := make(map[string]string, len(resourceTags)+len(tags))
+	for k, v := range resourceTags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
+
+func logBodyString(body pdata.AttributeValue) string {
+	switch body.Type() {
+	case pdata.AttributeValueTypeString:
+		return body.StringVal()
+	case pdata.AttributeValueTypeBool:
+		return strconv.FormatBool(body.BoolVal())
+	case pdata.AttributeValueTypeDouble:
+		return strconv.FormatFloat(body.DoubleVal(), 'g', -1, 64)
+	case pdata.AttributeValueTypeInt:
+		return strconv.FormatInt(body.IntVal(), 10)
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestNewOccurrenceRules(t *testing.T) {
+	valid := OccurrenceRule{Signal: "spans", Type: 9001, IssueTitle: "title"}
+	rules, err := newOccurrenceRules([]OccurrenceRule{valid})
+	require.NoError(t, err)
+	assert.Equal(t, "warning", rules[0].Level)
+
+	tests := []struct {
+		name   string
+		modify func(r *OccurrenceRule)
+	}{
+		{name: "signal", modify: func(r *OccurrenceRule) { r.Signal = "metrics" }},
+		{name: "type", modify: func(r *OccurrenceRule) { r.Type = 0 }},
+		{name: "issue title", modify: func(r *OccurrenceRule) { r.IssueTitle = "" }},
+		{name: "level", modify: func(r *OccurrenceRule) { r.Level = "critical" }},
+		{name: "name", modify: func(r *OccurrenceRule) { r.Name = "(" }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rule := valid
+			test.modify(&rule)
+			_, err := newOccurrenceRules([]OccurrenceRule{rule})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewOccurrenceClient(t *testing.T) {
+	client, err := newOccurrenceClient("https://key@sentry.example.com/path/42", OccurrencesSettings{})
+	require.NoError(t, err)
+	assert.Equal(t, 42, client.projectID)
+	assert.Equal(t, "https://sentry.example.com/path/api/0/issue-occurrence/", client.url)
+
+	client, err = newOccurrenceClient("https://key@sentry.example.com/42", OccurrencesSettings{URL: "https://occurrences.example.com/"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://occurrences.example.com/", client.url)
+
+	_, err = newOccurrenceClient("", OccurrencesSettings{})
+	assert.Error(t, err)
+}
+
+type occurrenceServer struct {
+	*httptest.Server
+	occurrences []occurrence
+	auth        []string
+}
+
+func newOccurrenceServer(t *testing.T) *occurrenceServer {
+	s := &occurrenceServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var o occurrence
+		require.NoError(t, json.Unmarshal(body, &o))
+		s.occurrences = append(s.occurrences, o)
+		s.auth = append(s.auth, r.Header.Get("Authorization"))
+	}))
+	return s
+}
+
+func newOccurrenceExporter(t *testing.T, url string, rules ...OccurrenceRule) *SentryExporter {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DSN = "https://key@sentry.example.com/42"
+	cfg.Occurrences = OccurrencesSettings{URL: url, AuthToken: "token", Rules: rules}
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	s.transport = &mockTransport{}
+	return s
+}
+
+func TestSpanOccurrences(t *testing.T) {
+	server := newOccurrenceServer(t)
+	defer server.Close()
+
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "checkout")
+	spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for _, name := range []string{"GET /checkout", "GET /cart"} {
+		span := spans.AppendEmpty()
+		span.SetName(name)
+		span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+		span.SetEndTimestamp(pdata.TimestampFromTime(time.Unix(1622505600, 0)))
+		span.Attributes().InsertString("slo.breached", "true")
+		span.Attributes().InsertString("http.route", "/checkout")
+	}
+
+	s := newOccurrenceExporter(t, server.URL, OccurrenceRule{
+		Signal:      "spans",
+		Name:        "^GET /checkout$",
+		Attributes:  map[string]string{"slo.breached": "true"},
+		Type:        9001,
+		IssueTitle:  "Checkout SLO breached",
+		Fingerprint: []string{"service.name"},
+		Evidence:    []string{"http.route", "missing"},
+	})
+
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+	require.Len(t, server.occurrences, 1)
+	assert.Equal(t, []string{"Bearer token"}, server.auth)
+
+	o := server.occurrences[0]
+	assert.Equal(t, 42, o.ProjectID)
+	assert.Equal(t, 9001, o.Type)
+	assert.Equal(t, "Checkout SLO breached", o.IssueTitle)
+	assert.Equal(t, "warning", o.Level)
+	assert.Equal(t, "GET /checkout", o.Culprit)
+	assert.Equal(t, []string{"9001", "Checkout SLO breached", "checkout"}, o.Fingerprint)
+	assert.Equal(t, map[string]string{"http.route": "/checkout"}, o.EvidenceData)
+	assert.Equal(t, []occurrenceEvidence{{Name: "http.route", Value: "/checkout", Important: true}}, o.EvidenceDisplay)
+	assert.Equal(t, o.EventID, o.Event.EventID)
+	assert.Equal(t, float64(1622505600), o.Event.Timestamp)
+	assert.Equal(t, "checkout", o.Event.Tags["service.name"])
+	assert.Equal(t, map[string]interface{}{
+		"trace": map[string]interface{}{"trace_id": "0102030405060708090a0b0c0d0e0f10", "span_id": "0102030405060708"},
+	}, o.Event.Contexts)
+}
+
+func TestLogOccurrences(t *testing.T) {
+	server := newOccurrenceServer(t)
+	defer server.Close()
+
+	ld := pdata.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+	for _, body := range []string{"row count 0 in table orders", "row count 12 in table users"} {
+		record := logs.AppendEmpty()
+		record.Body().SetStringVal(body)
+		record.Attributes().InsertString("table", strings.Fields(body)[5])
+	}
+
+	s := newOccurrenceExporter(t, server.URL, OccurrenceRule{
+		Signal:      "logs",
+		Name:        "row count 0",
+		Type:        9002,
+		IssueTitle:  "Empty table",
+		Level:       "error",
+		Fingerprint: []string{"table"},
+	})
+
+	require.NoError(t, s.pushLogData(context.Background(), ld))
+	require.Len(t, server.occurrences, 1)
+	o := server.occurrences[0]
+	assert.Equal(t, "error", o.Level)
+	assert.Equal(t, "row count 0 in table orders", o.Culprit)
+	assert.Equal(t, []string{"9002", "Empty table", "orders"}, o.Fingerprint)
+	assert.Nil(t, o.Event.Contexts)
+}
+
+func TestSendOccurrenceFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client, err := newOccurrenceClient("https://key@sentry.example.com/42", OccurrencesSettings{URL: server.URL})
+	require.NoError(t, err)
+	assert.Error(t, client.SendOccurrence(context.Background(), occurrence{}))
+}
+
+func TestNewSentryExporterOccurrencesWithoutDSN(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Occurrences.Rules = []OccurrenceRule{{Signal: "logs", Type: 1, IssueTitle: "title"}}
+	_, err := newSentryExporter(cfg, zap.NewNop())
+	assert.Error(t, err)
+}
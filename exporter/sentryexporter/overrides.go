@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import "strings"
+
+// Reserved attributes overriding the op, description, tags and environment derived from the
+// other attributes of a span. They are not added to the tags.
+const (
+	opAttribute          = "sentry.op"
+	descriptionAttribute = "sentry.description"
+	environmentAttribute = "sentry.environment"
+	tagAttributePrefix   = "sentry.tag."
+)
+
+// applyTagOverrides sets the tag of each sentry.tag.<name> attribute in tags, overriding the tag
+// of an attribute of that name, and removes the reserved attributes but sentry.environment, which
+// is removed with the release ones. It returns the values of sentry.op and sentry.description, if
+// set.
+func applyTagOverrides(tags map[string]string) (op string, description string) {
+	op = tags[opAttribute]
+	delete(tags, opAttribute)
+	description = tags[descriptionAttribute]
+	delete(tags, descriptionAttribute)
+
+	var overrides map[string]string
+	for k, v := range tags {
+		if !strings.HasPrefix(k, tagAttributePrefix) {
+			continue
+		}
+		if overrides == nil {
+			overrides = make(map[string]string)
+		}
+		overrides[strings.TrimPrefix(k, tagAttributePrefix)] = v
+		delete(tags, k)
+	}
+	for k, v := range overrides {
+		if k != "" {
+			tags[k] = v
+		}
+	}
+	return op, description
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestApplyTagOverrides(t *testing.T) {
+	tags := map[string]string{
+		"http.method":          "GET",
+		"team":                 "payments",
+		"sentry.op":            "checkout.pay",
+		"sentry.description":   "Pay order",
+		"sentry.tag.team":      "checkout",
+		"sentry.tag.tier":      "1",
+		"sentry.tag.":          "ignored",
+		"sentry.environment":   "canary",
+		"sentry.something_new": "kept",
+	}
+	op, description := applyTagOverrides(tags)
+	assert.Equal(t, "checkout.pay", op)
+	assert.Equal(t, "Pay order", description)
+	assert.Equal(t, map[string]string{
+		"http.method":          "GET",
+		"team":                 "checkout",
+		"tier":                 "1",
+		"sentry.environment":   "canary",
+		"sentry.something_new": "kept",
+	}, tags)
+}
+
+func TestSpanOverrides(t *testing.T) {
+	span := pdata.NewSpan()
+	span.SetName("/api/pay")
+	span.SetKind(pdata.SpanKindServer)
+	span.Attributes().InsertString("http.method", "POST")
+	span.Attributes().InsertString("sentry.op", "checkout.pay")
+	span.Attributes().InsertString("sentry.tag.team", "checkout")
+	span.Attributes().InsertString("sentry.environment", "canary")
+
+	sentrySpan := convertToSentrySpan(span, pdata.NewInstrumentationLibrary(), map[string]string{"team": "payments"})
+	assert.Equal(t, "checkout.pay", sentrySpan.Op)
+	assert.Equal(t, "POST /api/pay", sentrySpan.Description)
+	assert.Equal(t, "checkout", sentrySpan.Tags["team"])
+	assert.NotContains(t, sentrySpan.Tags, "sentry.op")
+
+	transaction := transactionFromSpan(sentrySpan)
+	assert.Equal(t, "canary", transaction.Environment)
+	assert.NotContains(t, transaction.Tags, "sentry.environment")
+}
+
+func TestSpanErrorEventTagOverrides(t *testing.T) {
+	span := pdata.NewSpan()
+	span.Attributes().InsertString("sentry.tag.team", "checkout")
+	span.Attributes().InsertString("sentry.op", "checkout.pay")
+
+	event := newSpanErrorEvent(span, map[string]string{})
+	assert.Equal(t, map[string]string{"team": "checkout"}, event.Tags)
+	assert.Equal(t, sentry.LevelError, event.Level)
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import "strings"
+
+// Placeholders of the path segments replaced by parameterizeURLPath.
+const (
+	idPlaceholder   = "{id}"
+	uuidPlaceholder = "{uuid}"
+	hashPlaceholder = "{hash}"
+)
+
+// minHashLength is the minimum length of the hex path segments considered hashes, such as those of
+// SHA-1 digests or MongoDB object IDs.
+const minHashLength = 16
+
+// parameterizeURLPath replaces the segments of a URL path that are likely identifiers, numbers,
+// UUIDs with digits and hex hashes, with placeholders, e.g. /users/42/avatar with
+// /users/{id}/avatar. The query and fragment are removed, as they have even more distinct values
+// and may contain personal data. Paths without such segments are otherwise returned as they are.
+func parameterizeURLPath(path string) string {
+	if end := strings.IndexAny(path, "?#"); end >= 0 {
+		path = path[:end]
+	}
+	// Identifiers have digits, which most paths do not.
+	if strings.IndexByte(path, '/') < 0 || strings.IndexAny(path, "0123456789") < 0 {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	changed := false
+	for i, segment := range segments {
+		if placeholder := segmentPlaceholder(segment); placeholder != "" {
+			segments[i] = placeholder
+			changed = true
+		}
+	}
+	if !changed {
+		return path
+	}
+	return strings.Join(segments, "/")
+}
+
+// segmentPlaceholder returns the placeholder of a path segment that is an identifier, or an empty
+// string.
+func segmentPlaceholder(segment string) string {
+	if segment == "" {
+		return ""
+	}
+	digits, hex := 0, 0
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+			hex++
+		case (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F'):
+			hex++
+		}
+	}
+	switch {
+	case digits == len(segment):
+		return idPlaceholder
+	case isUUID(segment):
+		return uuidPlaceholder
+	case hex == len(segment) && len(segment) >= minHashLength && digits > 0:
+		return hashPlaceholder
+	}
+	return ""
+}
+
+// isUUID returns whether s is a UUID in its canonical 8-4-4-4-12 hex form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			if c != '-' {
+				return false
+			}
+			continue
+		}
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterizeURLPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/api/checkout", "/api/checkout"},
+		{"HTTP GET", "HTTP GET"},
+		{"/users/42", "/users/{id}"},
+		{"/users/42/orders/7?expand=items", "/users/{id}/orders/{id}"},
+		{"/orders/3fa85f64-5717-4562-b3fc-2c963f66afa6/", "/orders/{uuid}/"},
+		{"/commits/da39a3ee5e6b4b0d3255bfef95601890afd80709", "/commits/{hash}"},
+		{"/objects/507f1f77bcf86cd799439011", "/objects/{hash}"},
+		{"/v2/users", "/v2/users"},
+		{"/files/cafe", "/files/cafe"},
+		{"/reports/2021", "/reports/{id}"},
+		{"/search?page=2", "/search"},
+		{"/reset?email=jane@example.com#token", "/reset"},
+		{"/docs#section-2", "/docs"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, parameterizeURLPath(test.path), test.path)
+	}
+}
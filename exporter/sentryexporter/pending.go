@@ -0,0 +1,184 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+)
+
+// pendingEnvelopeOverhead approximates the memory used by a pending envelope besides its bytes.
+const pendingEnvelopeOverhead = 64
+
+var (
+	tagExporterKey = tag.MustNewKey("exporter")
+
+	mPendingEnvelopes     = stats.Int64("pending_envelopes", "Number of envelopes kept in memory for later delivery", stats.UnitDimensionless)
+	mPendingEnvelopeBytes = stats.Int64("pending_envelope_bytes", "Approximate memory used by the envelopes kept for later delivery", stats.UnitBytes)
+	mEvictedEnvelopes     = stats.Int64("evicted_envelopes", "Number of pending envelopes dropped to stay within the memory limit", stats.UnitDimensionless)
+	mDroppedEnvelopes     = stats.Int64("dropped_envelopes", "Number of envelopes and occurrences not sent nor retried, since the rest of their batch was delivered", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views of the Sentry exporter.
+func MetricViews() []*view.View {
+	return []*view.View{
+		pendingView(mPendingEnvelopes, view.LastValue()),
+		pendingView(mPendingEnvelopeBytes, view.LastValue()),
+		pendingView(mEvictedEnvelopes, view.Sum()),
+		pendingView(mDroppedEnvelopes, view.Sum()),
+	}
+}
+
+func pendingView(m *stats.Int64Measure, aggregation *view.Aggregation) *view.View {
+	return &view.View{
+		Name:        "exporter/" + typeStr + "/" + m.Name(),
+		Measure:     m,
+		Description: m.Description(),
+		TagKeys:     []tag.Key{tagExporterKey},
+		Aggregation: aggregation,
+	}
+}
+
+// pendingEnvelopes keeps envelopes that could not be delivered in memory, oldest first, within
+// an approximate memory limit.
+type pendingEnvelopes struct {
+	mutators []tag.Mutator
+	maxSize  int64
+
+	mu      sync.Mutex
+	entries []pendingEntry
+	size    int64
+	nextID  uint64
+}
+
+type pendingEntry struct {
+	id       uint64
+	envelope []byte
+}
+
+func newPendingEnvelopes(exporter string, maxSize int64) *pendingEnvelopes {
+	return &pendingEnvelopes{
+		mutators: []tag.Mutator{tag.Upsert(tagExporterKey, exporter)},
+		maxSize:  maxSize,
+	}
+}
+
+func pendingEntrySize(envelope []byte) int64 {
+	return int64(len(envelope)) + pendingEnvelopeOverhead
+}
+
+// push adds an envelope, evicting the oldest envelopes until they fit in the memory limit. An
+// envelope larger than the limit is dropped itself. It returns the number of dropped envelopes.
+func (p *pendingEnvelopes) push(envelope []byte) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	size := pendingEntrySize(envelope)
+	if size > p.maxSize {
+		p.record(mEvictedEnvelopes.M(1))
+		return 1
+	}
+
+	evicted := 0
+	for p.size+size > p.maxSize {
+		p.removeOldest()
+		evicted++
+	}
+	p.entries = append(p.entries, pendingEntry{id: p.nextID, envelope: envelope})
+	p.nextID++
+	p.size += size
+
+	p.record(mEvictedEnvelopes.M(int64(evicted)))
+	return evicted
+}
+
+// peek returns the oldest envelope, or false if there is none.
+func (p *pendingEnvelopes) peek() (pendingEntry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.entries) == 0 {
+		return pendingEntry{}, false
+	}
+	return p.entries[0], true
+}
+
+// remove removes the envelope with the given id, if it is still the oldest one.
+func (p *pendingEnvelopes) remove(id uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.entries) > 0 && p.entries[0].id == id {
+		p.removeOldest()
+		p.record()
+	}
+}
+
+// stats returns the number of pending envelopes and their approximate size.
+func (p *pendingEnvelopes) stats() (int, int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries), p.size
+}
+
+func (p *pendingEnvelopes) removeOldest() {
+	p.size -= pendingEntrySize(p.entries[0].envelope)
+	p.entries[0] = pendingEntry{}
+	p.entries = p.entries[1:]
+}
+
+// record records the current number and size of pending envelopes, along with ms.
+func (p *pendingEnvelopes) record(ms ...stats.Measurement) {
+	ms = append(ms, mPendingEnvelopes.M(int64(len(p.entries))), mPendingEnvelopeBytes.M(p.size))
+	_ = stats.RecordWithTags(context.Background(), p.mutators, ms...)
+}
+
+func (s *SentryExporter) drainPending() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(spoolRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.resendPending(context.Background())
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// resendPending sends pending envelopes oldest first until there are none left or sending fails.
+func (s *SentryExporter) resendPending(ctx context.Context) {
+	for {
+		entry, ok := s.pending.peek()
+		if !ok {
+			return
+		}
+
+		if err := s.transport.SendEnvelope(ctx, entry.envelope); err != nil {
+			s.logger.Debug("Failed to resend pending envelope", zap.Error(err))
+			return
+		}
+		s.pending.remove(entry.id)
+	}
+}
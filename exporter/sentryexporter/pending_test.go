@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.uber.org/zap"
+)
+
+func TestPendingEnvelopesEvictsOldest(t *testing.T) {
+	p := newPendingEnvelopes("sentry", 3*pendingEntrySize([]byte("envelope-0")))
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, 0, p.push([]byte("envelope-"+string(rune('0'+i)))))
+	}
+	assert.Equal(t, 1, p.push([]byte("envelope-3")))
+
+	count, size := p.stats()
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 3*pendingEntrySize([]byte("envelope-0")), size)
+	entry, ok := p.peek()
+	require.True(t, ok)
+	assert.Equal(t, "envelope-1", string(entry.envelope))
+
+	// An envelope larger than the limit evicts nothing but itself.
+	assert.Equal(t, 1, p.push(make([]byte, p.maxSize)))
+	count, _ = p.stats()
+	assert.Equal(t, 3, count)
+}
+
+func TestPendingEnvelopesRemove(t *testing.T) {
+	p := newPendingEnvelopes("sentry", pendingEntrySize([]byte("first")))
+	p.push([]byte("first"))
+	first, ok := p.peek()
+	require.True(t, ok)
+
+	// The peeked envelope was evicted in the meantime, the newer one is kept.
+	p.push([]byte("newer"))
+	p.remove(first.id)
+	entry, ok := p.peek()
+	require.True(t, ok)
+	assert.Equal(t, "newer", string(entry.envelope))
+
+	p.remove(entry.id)
+	_, ok = p.peek()
+	assert.False(t, ok)
+	count, size := p.stats()
+	assert.Equal(t, 0, count)
+	assert.Equal(t, int64(0), size)
+}
+
+func TestPendingEnvelopesMetrics(t *testing.T) {
+	// The views are registered by the factory.
+	NewFactory()
+
+	p := newPendingEnvelopes("sentry/metrics", pendingEntrySize([]byte("envelope")))
+	evicted := exporterViewData(t, "exporter/sentry/evicted_envelopes", "sentry/metrics")
+	p.push([]byte("envelope"))
+	p.push([]byte("envelope"))
+
+	assert.Equal(t, evicted+1, exporterViewData(t, "exporter/sentry/evicted_envelopes", "sentry/metrics"))
+	size := exporterViewData(t, "exporter/sentry/pending_envelope_bytes", "sentry/metrics")
+	assert.Equal(t, float64(pendingEntrySize([]byte("envelope"))), size)
+}
+
+// exporterViewData returns the value of a sum or last value view for an exporter, 0 if it has
+// none yet.
+func exporterViewData(t *testing.T, name string, exporter string) float64 {
+	rows, err := view.RetrieveData(name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key != tagExporterKey || tag.Value != exporter {
+				continue
+			}
+			switch data := row.Data.(type) {
+			case *view.SumData:
+				return data.Value
+			case *view.LastValueData:
+				return data.Value
+			}
+		}
+	}
+	return 0
+}
+
+func TestSendEnvelopeKeepsPending(t *testing.T) {
+	transport := &mockTransport{err: errors.New("unavailable")}
+	s := &SentryExporter{transport: transport, pending: newPendingEnvelopes("sentry", 1<<20), logger: zap.NewNop()}
+
+	require.NoError(t, s.sendEnvelope(context.Background(), []byte("first")))
+	require.NoError(t, s.sendEnvelope(context.Background(), []byte("second")))
+	s.resendPending(context.Background())
+	count, _ := s.pending.stats()
+	assert.Equal(t, 2, count)
+
+	transport.err = nil
+	s.resendPending(context.Background())
+	assert.Equal(t, [][]byte{[]byte("first"), []byte("second")}, transport.envelopes)
+	count, _ = s.pending.stats()
+	assert.Equal(t, 0, count)
+}
+
+func TestNewSentryExporterPending(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, s.pending)
+
+	cfg.Pending.MaxMemoryMiB = 1
+	s, err = newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, s.pending)
+	assert.Equal(t, int64(1<<20), s.pending.maxSize)
+
+	// Envelopes are spooled to the storage instead.
+	cfg.Storage = "envelope_storage"
+	s, err = newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, s.pending)
+
+	cfg.Pending.MaxMemoryMiB = -1
+	_, err = newSentryExporter(cfg, zap.NewNop())
+	assert.Error(t, err)
+}
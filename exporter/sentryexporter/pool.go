@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// maxPooledTags is the size above which tag maps are not returned to their pool, so that a few spans
+// with many attributes do not keep large maps alive.
+const maxPooledTags = 64
+
+var (
+	spanPool      = sync.Pool{New: func() interface{} { return &sentry.Span{} }}
+	tagsPool      = sync.Pool{New: func() interface{} { return make(map[string]string) }}
+	spanBatchPool = sync.Pool{New: func() interface{} { return &spanBatch{} }}
+)
+
+// spanBatch holds the Sentry spans converted from one batch of traces, and the tag maps of the
+// spans and of their resources. They are taken from pools, and returned to them by release once
+// the transactions built from them have been encoded.
+type spanBatch struct {
+	spans []*sentry.Span
+	tags  []map[string]string
+	// orphans is the slice of spans whose root span has not been seen yet.
+	orphans []*sentry.Span
+	// options are the settings of the conversion of the spans.
+	options conversionOptions
+	// interner is kept along with the batch in its pool, so that the strings of a batch are
+	// reused by the next ones.
+	interner interner
+}
+
+func newSpanBatch() *spanBatch {
+	return spanBatchPool.Get().(*spanBatch)
+}
+
+// newTags returns an empty tag map, owned by the batch.
+func (b *spanBatch) newTags() map[string]string {
+	tags := tagsPool.Get().(map[string]string)
+	b.tags = append(b.tags, tags)
+	return tags
+}
+
+// convertSpan converts a span like convertToSentrySpan, with a Sentry span and tag map owned by the batch.
+func (b *spanBatch) convertSpan(span pdata.Span, library pdata.InstrumentationLibrary, resourceTags map[string]string) *sentry.Span {
+	sentrySpan := spanPool.Get().(*sentry.Span)
+	b.spans = append(b.spans, sentrySpan)
+	fillSentrySpan(sentrySpan, span, library, resourceTags, b.newTags(), &b.interner, b.options)
+	return sentrySpan
+}
+
+// release returns the spans, tag maps and the batch itself to their pools. Neither the spans nor
+// the transactions built from them can be used afterwards.
+func (b *spanBatch) release() {
+	for i, s := range b.spans {
+		*s = sentry.Span{}
+		spanPool.Put(s)
+		b.spans[i] = nil
+	}
+	for i, tags := range b.tags {
+		if len(tags) <= maxPooledTags {
+			for k := range tags {
+				delete(tags, k)
+			}
+			tagsPool.Put(tags)
+		}
+		b.tags[i] = nil
+	}
+	for i := range b.orphans {
+		b.orphans[i] = nil
+	}
+	b.spans, b.tags, b.orphans = b.spans[:0], b.tags[:0], b.orphans[:0]
+	spanBatchPool.Put(b)
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRetryAfter is how long a category is rate limited for when Sentry does not say.
+const defaultRetryAfter = 60 * time.Second
+
+// allCategories is the key of the rate limits of all data categories.
+const allCategories = ""
+
+// envelopeCategories maps the item types of envelopes to the data categories Sentry rate limits.
+// See https://develop.sentry.dev/sdk/rate-limiting/ for details.
+var envelopeCategories = map[string]string{
+	envelopeItemTypeEvent:       "error",
+	envelopeItemTypeTransaction: "transaction",
+	envelopeItemTypeSessions:    "session",
+	envelopeItemTypeStatsd:      "metric_bucket",
+}
+
+// rateLimitedError is returned for an envelope that is not sent, because Sentry rate limits its
+// data category.
+type rateLimitedError struct {
+	category string
+	until    time.Time
+}
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("sending %s envelopes to Sentry is rate limited until %s", e.category, e.until.Format(time.RFC3339))
+}
+
+// rateLimits are the times until which Sentry rejects each data category, as it responded with
+// the X-Sentry-Rate-Limits and Retry-After headers.
+type rateLimits struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// limitedUntil returns the time until which a category is rate limited, if it is at now.
+func (l *rateLimits) limitedUntil(category string, now time.Time) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := l.until[allCategories]
+	if u := l.until[category]; u.After(until) {
+		until = u
+	}
+	return until, until.After(now)
+}
+
+// update records the rate limits of a response. Without X-Sentry-Rate-Limits header, a 429
+// response limits all categories for its Retry-After header.
+func (l *rateLimits) update(header http.Header, statusCode int, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.until == nil {
+		l.until = make(map[string]time.Time)
+	}
+
+	if limits := header.Get("X-Sentry-Rate-Limits"); limits != "" {
+		for _, quota := range strings.Split(limits, ",") {
+			// Each quota is retry_after:categories:scope, followed by optional fields.
+			fields := strings.Split(strings.TrimSpace(quota), ":")
+			seconds, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				continue
+			}
+			until := now.Add(time.Duration(seconds * float64(time.Second)))
+			categories := []string{allCategories}
+			if len(fields) > 1 && fields[1] != "" {
+				categories = strings.Split(fields[1], ";")
+			}
+			for _, category := range categories {
+				l.extend(category, until)
+			}
+		}
+		return
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		l.extend(allCategories, now.Add(parseRetryAfter(header.Get("Retry-After"), now)))
+	}
+}
+
+// extend rate limits a category until a time, unless it already is for longer.
+func (l *rateLimits) extend(category string, until time.Time) {
+	if until.After(l.until[category]) {
+		l.until[category] = until
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as a date, defaulting to
+// defaultRetryAfter.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now)
+	}
+	return defaultRetryAfter
+}
+
+// envelopeCategory returns the data category of the single item of an envelope.
+func envelopeCategory(envelope []byte) string {
+	lines := bytes.SplitN(envelope, []byte("\n"), 3)
+	if len(lines) < 2 {
+		return allCategories
+	}
+	var header envelopeItemHeader
+	if err := json.Unmarshal(lines[1], &header); err != nil {
+		return allCategories
+	}
+	if category, ok := envelopeCategories[header.Type]; ok {
+		return category
+	}
+	return header.Type
+}
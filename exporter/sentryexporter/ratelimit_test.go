@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitsUpdate(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	var limits rateLimits
+	header := http.Header{}
+	header.Set("X-Sentry-Rate-Limits", "60:transaction;error:organization, 2700:session:key")
+	limits.update(header, http.StatusOK, now)
+
+	until, limited := limits.limitedUntil("transaction", now)
+	assert.True(t, limited)
+	assert.Equal(t, now.Add(time.Minute), until)
+	until, limited = limits.limitedUntil("session", now)
+	assert.True(t, limited)
+	assert.Equal(t, now.Add(45*time.Minute), until)
+	_, limited = limits.limitedUntil("metric_bucket", now)
+	assert.False(t, limited)
+	_, limited = limits.limitedUntil("error", now.Add(time.Minute))
+	assert.False(t, limited)
+}
+
+func TestRateLimitsUpdateAllCategories(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	var limits rateLimits
+	header := http.Header{}
+	header.Set("X-Sentry-Rate-Limits", "30::organization")
+	limits.update(header, http.StatusTooManyRequests, now)
+
+	until, limited := limits.limitedUntil("metric_bucket", now)
+	assert.True(t, limited)
+	assert.Equal(t, now.Add(30*time.Second), until)
+}
+
+func TestRateLimitsUpdateRetryAfter(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	var limits rateLimits
+	header := http.Header{}
+	header.Set("Retry-After", "10")
+	limits.update(header, http.StatusOK, now)
+	_, limited := limits.limitedUntil("error", now)
+	assert.False(t, limited, "only 429 responses are limited by Retry-After")
+
+	limits.update(header, http.StatusTooManyRequests, now)
+	until, limited := limits.limitedUntil("error", now)
+	assert.True(t, limited)
+	assert.Equal(t, now.Add(10*time.Second), until)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	assert.Equal(t, 5*time.Second, parseRetryAfter("5", now))
+	assert.Equal(t, 2*time.Minute, parseRetryAfter(now.Add(2*time.Minute).UTC().Format(http.TimeFormat), now))
+	assert.Equal(t, defaultRetryAfter, parseRetryAfter("", now))
+	assert.Equal(t, defaultRetryAfter, parseRetryAfter("soon", now))
+}
+
+func TestEnvelopeCategory(t *testing.T) {
+	sentAt := time.Unix(1600000000, 0)
+	transaction, err := transactionToEnvelope(&sentry.Event{EventID: "1", Type: "transaction"}, sentAt)
+	require.NoError(t, err)
+	assert.Equal(t, "transaction", envelopeCategory(transaction))
+
+	event, err := eventToEnvelope(&sentry.Event{EventID: "2"}, sentAt)
+	require.NoError(t, err)
+	assert.Equal(t, "error", envelopeCategory(event))
+
+	assert.Equal(t, allCategories, envelopeCategory([]byte("envelope")))
+}
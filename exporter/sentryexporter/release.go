@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// distAttribute is the attribute setting the distribution of a release, overriding the configured
+// one. It is not added to the tags.
+const distAttribute = "sentry.dist"
+
+// releaseOptions are the release and environment set by the configuration, either overriding the
+// ones of the resource attributes or used when they are not set, and the default distribution.
+type releaseOptions struct {
+	release            string
+	environment        string
+	defaultRelease     string
+	defaultEnvironment string
+	dist               string
+}
+
+// setReleaseFromTags sets the release of an event from the service.name and service.version
+// attributes in its tags, as service.name@service.version or only the version without a name,
+// the environment from sentry.environment or else deployment.environment, and the distribution
+// from sentry.dist.
+// See https://docs.sentry.io/product/releases/naming-releases/.
+func setReleaseFromTags(event *sentry.Event, tags map[string]string) {
+	if dist, ok := tags[distAttribute]; ok {
+		event.Dist = dist
+		delete(tags, distAttribute)
+	}
+	if version := tags[conventions.AttributeServiceVersion]; version != "" {
+		event.Release = version
+		if name := tags[conventions.AttributeServiceName]; name != "" {
+			event.Release = name + "@" + version
+		}
+	}
+	event.Environment = tags[conventions.AttributeDeploymentEnvironment]
+	if environment, ok := tags[environmentAttribute]; ok {
+		event.Environment = environment
+		delete(tags, environmentAttribute)
+	}
+}
+
+// apply overrides the release and environment of an event with the configured ones, and sets the
+// configured defaults if they are not set.
+func (o releaseOptions) apply(event *sentry.Event) {
+	event.Release = withDefault(o.release, event.Release, o.defaultRelease)
+	event.Environment = withDefault(o.environment, event.Environment, o.defaultEnvironment)
+	event.Dist = withDefault("", event.Dist, o.dist)
+}
+
+// applySessions overrides the release and environment of session aggregates like apply.
+func (o releaseOptions) applySessions(aggregates *SessionAggregates) {
+	aggregates.Release = withDefault(o.release, aggregates.Release, o.defaultRelease)
+	aggregates.Environment = withDefault(o.environment, aggregates.Environment, o.defaultEnvironment)
+}
+
+// withDefault returns the override if it is set, else the value if it is set, else the default.
+func withDefault(override, value, def string) string {
+	if override != "" {
+		return override
+	}
+	if value != "" {
+		return value
+	}
+	return def
+}
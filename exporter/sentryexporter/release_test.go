@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSetReleaseFromTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        map[string]string
+		release     string
+		environment string
+	}{
+		{
+			name: "name and version",
+			tags: map[string]string{
+				"service.name":           "checkout",
+				"service.version":        "1.2.3",
+				"deployment.environment": "production",
+			},
+			release:     "checkout@1.2.3",
+			environment: "production",
+		},
+		{
+			name:    "version without name",
+			tags:    map[string]string{"service.version": "1.2.3"},
+			release: "1.2.3",
+		},
+		{
+			name: "name without version",
+			tags: map[string]string{"service.name": "checkout"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event := sentry.NewEvent()
+			setReleaseFromTags(event, test.tags)
+			assert.Equal(t, test.release, event.Release)
+			assert.Equal(t, test.environment, event.Environment)
+		})
+	}
+}
+
+func TestReleaseOptionsApply(t *testing.T) {
+	options := releaseOptions{environment: "staging", defaultRelease: "unknown", defaultEnvironment: "production"}
+
+	event := sentry.NewEvent()
+	event.Release = "checkout@1.2.3"
+	event.Environment = "production-eu"
+	options.apply(event)
+	assert.Equal(t, "checkout@1.2.3", event.Release)
+	assert.Equal(t, "staging", event.Environment)
+
+	event = sentry.NewEvent()
+	options.apply(event)
+	assert.Equal(t, "unknown", event.Release)
+	assert.Equal(t, "staging", event.Environment)
+
+	event = sentry.NewEvent()
+	releaseOptions{defaultEnvironment: "production"}.apply(event)
+	assert.Equal(t, "", event.Release)
+	assert.Equal(t, "production", event.Environment)
+}
+
+func TestDist(t *testing.T) {
+	tags := map[string]string{"service.version": "1.2.3", "sentry.dist": "1042"}
+	event := sentry.NewEvent()
+	setReleaseFromTags(event, tags)
+	assert.Equal(t, "1042", event.Dist)
+	assert.NotContains(t, tags, "sentry.dist")
+
+	releaseOptions{dist: "1000"}.apply(event)
+	assert.Equal(t, "1042", event.Dist)
+
+	event = sentry.NewEvent()
+	releaseOptions{dist: "1000"}.apply(event)
+	assert.Equal(t, "1000", event.Dist)
+}
+
+func TestExportSessionsWithRelease(t *testing.T) {
+	transport := &mockTransport{}
+	s := &SentryExporter{
+		transport: transport,
+		logger:    zap.NewNop(),
+		release:   releaseOptions{release: "checkout@2.0.0", defaultEnvironment: "production"},
+	}
+
+	started := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	err := s.sendSessions(context.Background(), []SessionAggregates{
+		{Release: "1.2.3", Buckets: []SessionBucket{{Started: started, Exited: 1}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, transport.envelopes, 1)
+	assert.Contains(t, string(transport.envelopes[0]), `"release":"checkout@2.0.0"`)
+	assert.Contains(t, string(transport.envelopes[0]), `"environment":"production"`)
+}
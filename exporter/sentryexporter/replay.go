@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Replay sends the envelopes in files written by the file mode of the exporter to the
+// Sentry project of the given DSN. Paths can be files or directories; the envelope files
+// in a directory are sent oldest first. It returns the number of envelopes sent, and stops
+// at the first envelope that cannot be sent.
+func Replay(ctx context.Context, dsn string, paths []string) (int, error) {
+	if dsn == "" {
+		return 0, fmt.Errorf("a DSN is required to replay envelopes")
+	}
+	transport, err := newSentryTransport(dsn)
+	if err != nil {
+		return 0, fmt.Errorf("invalid 'dsn': %w", err)
+	}
+
+	files, err := expandEnvelopeFiles(paths)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, file := range files {
+		err = readEnvelopeFile(file, func(envelope []byte) error {
+			if err := transport.SendEnvelope(ctx, envelope); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			sent++
+			return nil
+		})
+		if err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+func expandEnvelopeFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		dirFiles, err := listEnvelopeFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
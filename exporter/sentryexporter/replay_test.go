@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		received = append(received, string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	transport := newTestFileTransport(t, FileSettings{Compress: true})
+	transport.maxSize = 10
+	for _, envelope := range []string{"envelope1", "envelope2"} {
+		require.NoError(t, transport.SendEnvelope(context.Background(), []byte(envelope)))
+	}
+	require.NoError(t, transport.Close())
+
+	dsn := strings.Replace(server.URL, "http://", "http://key@", 1) + "/42"
+	sent, err := Replay(context.Background(), dsn, []string{transport.directory})
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, []string{"envelope1", "envelope2"}, received)
+}
+
+func TestReplayFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	transport := newTestFileTransport(t, FileSettings{})
+	require.NoError(t, transport.SendEnvelope(context.Background(), []byte("envelope")))
+	require.NoError(t, transport.Close())
+
+	dsn := strings.Replace(server.URL, "http://", "http://key@", 1) + "/42"
+	sent, err := Replay(context.Background(), dsn, []string{transport.directory})
+	assert.Error(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestReplayInvalid(t *testing.T) {
+	_, err := Replay(context.Background(), "", nil)
+	assert.Error(t, err)
+
+	_, err = Replay(context.Background(), "not a dsn", nil)
+	assert.Error(t, err)
+
+	_, err = Replay(context.Background(), "https://key@host/42", []string{"does-not-exist"})
+	assert.Error(t, err)
+}
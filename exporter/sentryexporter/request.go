@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// attributeHTTPRequestHeaderPrefix prefixes the attributes of HTTP request headers, e.g.
+// "http.request.header.content_type".
+const attributeHTTPRequestHeaderPrefix = "http.request.header."
+
+// requestFromTags builds the request interface of a transaction from the HTTP attributes of its
+// server span, in its tags. The URL, headers and client address are removed from the tags, as
+// they are part of the request.
+// See https://develop.sentry.dev/sdk/event-payloads/request/.
+func requestFromTags(tags map[string]string) *sentry.Request {
+	method, ok := tags[conventions.AttributeHTTPMethod]
+	if !ok {
+		return nil
+	}
+	request := &sentry.Request{Method: method}
+
+	if rawURL, ok := tags[conventions.AttributeHTTPURL]; ok {
+		if u, err := url.Parse(rawURL); err == nil {
+			request.QueryString = u.RawQuery
+			u.RawQuery, u.Fragment = "", ""
+			request.URL = u.String()
+		}
+	} else if target, ok := tags[conventions.AttributeHTTPTarget]; ok {
+		path := target
+		if i := strings.IndexByte(target, '?'); i >= 0 {
+			path, request.QueryString = target[:i], target[i+1:]
+		}
+		request.URL = path
+		if host := requestHost(tags); host != "" {
+			scheme := tags[conventions.AttributeHTTPScheme]
+			if scheme == "" {
+				scheme = "http"
+			}
+			request.URL = scheme + "://" + host + path
+		}
+	}
+	delete(tags, conventions.AttributeHTTPURL)
+	delete(tags, conventions.AttributeHTTPTarget)
+
+	if userAgent, ok := tags[conventions.AttributeHTTPUserAgent]; ok {
+		request.Headers = map[string]string{"User-Agent": userAgent}
+		delete(tags, conventions.AttributeHTTPUserAgent)
+	}
+	for k, v := range tags {
+		if !strings.HasPrefix(k, attributeHTTPRequestHeaderPrefix) {
+			continue
+		}
+		if request.Headers == nil {
+			request.Headers = make(map[string]string)
+		}
+		name := strings.ReplaceAll(k[len(attributeHTTPRequestHeaderPrefix):], "_", "-")
+		request.Headers[textproto.CanonicalMIMEHeaderKey(name)] = v
+		delete(tags, k)
+	}
+
+	if clientIP, ok := tags[conventions.AttributeHTTPClientIP]; ok {
+		request.Env = map[string]string{"REMOTE_ADDR": clientIP}
+		delete(tags, conventions.AttributeHTTPClientIP)
+	} else if peerIP, ok := tags[conventions.AttributeNetPeerIP]; ok {
+		request.Env = map[string]string{"REMOTE_ADDR": peerIP}
+	}
+
+	return request
+}
+
+// requestHost returns the host of a request from the http.host attribute, or the net.host.name
+// and net.host.port attributes.
+func requestHost(tags map[string]string) string {
+	if host, ok := tags[conventions.AttributeHTTPHost]; ok {
+		return host
+	}
+	name, ok := tags[conventions.AttributeNetHostName]
+	if !ok {
+		return ""
+	}
+	if port, ok := tags[conventions.AttributeNetHostPort]; ok {
+		return net.JoinHostPort(name, port)
+	}
+	return name
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestFromTags(t *testing.T) {
+	testCases := []struct {
+		testName string
+		tags     map[string]string
+		request  *sentry.Request
+		rest     map[string]string
+	}{
+		{
+			testName: "not http",
+			tags:     map[string]string{"db.system": "postgresql"},
+			rest:     map[string]string{"db.system": "postgresql"},
+		},
+		{
+			testName: "url",
+			tags: map[string]string{
+				"http.method":     "GET",
+				"http.url":        "https://shop.example.com/cart?id=42#items",
+				"http.user_agent": "curl/7.64.1",
+				"http.client_ip":  "203.0.113.7",
+				"net.peer.ip":     "10.0.0.1",
+				"http.route":      "/cart",
+			},
+			request: &sentry.Request{
+				URL:         "https://shop.example.com/cart",
+				Method:      "GET",
+				QueryString: "id=42",
+				Headers:     map[string]string{"User-Agent": "curl/7.64.1"},
+				Env:         map[string]string{"REMOTE_ADDR": "203.0.113.7"},
+			},
+			rest: map[string]string{"http.method": "GET", "net.peer.ip": "10.0.0.1", "http.route": "/cart"},
+		},
+		{
+			testName: "target with net host",
+			tags: map[string]string{
+				"http.method":                      "POST",
+				"http.target":                      "/orders",
+				"net.host.name":                    "orders",
+				"net.host.port":                    "8080",
+				"net.peer.ip":                      "10.0.0.1",
+				"http.request.header.content_type": "application/json",
+			},
+			request: &sentry.Request{
+				URL:     "http://orders:8080/orders",
+				Method:  "POST",
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Env:     map[string]string{"REMOTE_ADDR": "10.0.0.1"},
+			},
+			rest: map[string]string{"http.method": "POST", "net.host.name": "orders", "net.host.port": "8080", "net.peer.ip": "10.0.0.1"},
+		},
+		{
+			testName: "target without host",
+			tags:     map[string]string{"http.method": "GET", "http.target": "/search?q=shoes"},
+			request:  &sentry.Request{URL: "/search", Method: "GET", QueryString: "q=shoes"},
+			rest:     map[string]string{"http.method": "GET"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			assert.Equal(t, test.request, requestFromTags(test.tags))
+			assert.Equal(t, test.rest, test.tags)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// rpcOp is the op of RPC spans without rpc.system.
+const rpcOp = "rpc"
+
+// rpcOps are the ops of the RPC systems of the semantic conventions, to not build them for each span.
+var rpcOps = map[string]string{
+	"grpc":         "rpc.grpc",
+	"java_rmi":     "rpc.java_rmi",
+	"dotnet_wcf":   "rpc.dotnet_wcf",
+	"apache_dubbo": "rpc.apache_dubbo",
+	"connect_rpc":  "rpc.connect_rpc",
+}
+
+// rpcSpanDescriptors returns the op, rpc.<rpc.system>, and the description, <rpc.service>/<rpc.method>,
+// of a RPC span, or the span name without rpc.service or rpc.method. It returns false if the span has neither rpc.system nor rpc.service.
+func rpcSpanDescriptors(name string, attrs pdata.AttributeMap, in *interner) (op string, description string, ok bool) {
+	system, hasSystem := attrs.Get(conventions.AttributeRPCSystem)
+	service, hasService := attrs.Get(conventions.AttributeRPCService)
+	if !hasSystem && !hasService {
+		return "", "", false
+	}
+
+	op = rpcOp
+	if hasSystem && system.StringVal() != "" {
+		if op, ok = rpcOps[system.StringVal()]; !ok {
+			op = rpcOp + "." + system.StringVal()
+		}
+	}
+
+	description = name
+	if method, ok := attrs.Get(conventions.AttributeRPCMethod); ok && method.StringVal() != "" && service.StringVal() != "" {
+		description = in.joinWith(service.StringVal(), '/', method.StringVal())
+	}
+	return op, description, true
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestRPCSpanDescriptors(t *testing.T) {
+	attrs := pdata.NewAttributeMap()
+	_, _, ok := rpcSpanDescriptors("Echo", attrs, nil)
+	assert.False(t, ok)
+
+	attrs.InsertString(conventions.AttributeRPCSystem, "apache_dubbo")
+	op, description, ok := rpcSpanDescriptors("Echo", attrs, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "rpc.apache_dubbo", op)
+	assert.Equal(t, "Echo", description)
+
+	attrs.InsertString(conventions.AttributeRPCService, "EchoService")
+	attrs.InsertString(conventions.AttributeRPCMethod, "Echo")
+	op, description, _ = rpcSpanDescriptors("Echo", attrs, &interner{})
+	assert.Equal(t, "rpc.apache_dubbo", op)
+	assert.Equal(t, "EchoService/Echo", description)
+}
+
+func TestFillSentrySpanGRPCStatus(t *testing.T) {
+	span := pdata.NewSpan()
+	span.Attributes().InsertString(conventions.AttributeRPCSystem, "grpc")
+	span.Attributes().InsertInt(grpcStatusCodeAttribute, 0)
+	library := pdata.NewInstrumentationLibrary()
+
+	var sentrySpan sentry.Span
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "ok", sentrySpan.Status)
+
+	span.Attributes().UpdateInt(grpcStatusCodeAttribute, 4)
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "deadline_exceeded", sentrySpan.Status)
+
+	// Codes that are not gRPC codes are ignored.
+	span.Attributes().UpdateInt(grpcStatusCodeAttribute, 42)
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "unknown", sentrySpan.Status)
+
+	// The error status of the span is not overridden by an ok code.
+	span.Attributes().UpdateInt(grpcStatusCodeAttribute, 0)
+	span.Status().SetCode(pdata.StatusCodeError)
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "internal_error", sentrySpan.Status)
+}
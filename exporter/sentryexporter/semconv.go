@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// Attributes of the stabilized HTTP semantic conventions.
+// See https://github.com/open-telemetry/semantic-conventions/blob/v1.23.0/docs/http/http-spans.md.
+const (
+	httpRequestMethodAttribute      = "http.request.method"
+	httpResponseStatusCodeAttribute = "http.response.status_code"
+	urlFullAttribute                = "url.full"
+	urlPathAttribute                = "url.path"
+	urlQueryAttribute               = "url.query"
+	urlSchemeAttribute              = "url.scheme"
+	serverAddressAttribute          = "server.address"
+	serverPortAttribute             = "server.port"
+	clientAddressAttribute          = "client.address"
+	userAgentOriginalAttribute      = "user_agent.original"
+)
+
+// legacyHTTPAttributeNames maps the stabilized HTTP attributes to their legacy names. url.path
+// and url.query are combined into http.target.
+var legacyHTTPAttributeNames = map[string]string{
+	httpRequestMethodAttribute:      conventions.AttributeHTTPMethod,
+	httpResponseStatusCodeAttribute: conventions.AttributeHTTPStatusCode,
+	urlFullAttribute:                conventions.AttributeHTTPURL,
+	urlSchemeAttribute:              conventions.AttributeHTTPScheme,
+	serverAddressAttribute:          conventions.AttributeNetHostName,
+	serverPortAttribute:             conventions.AttributeNetHostPort,
+	clientAddressAttribute:          conventions.AttributeHTTPClientIP,
+	userAgentOriginalAttribute:      conventions.AttributeHTTPUserAgent,
+}
+
+// legacyHTTPAttributes returns the attributes of a span with the stabilized HTTP attributes renamed
+// to their legacy names, so that they are converted like those of older instrumentations. Legacy
+// attributes that are already set are kept. attrs is returned as is if it has no stabilized HTTP
+// attribute, and is otherwise not modified.
+func legacyHTTPAttributes(attrs pdata.AttributeMap) pdata.AttributeMap {
+	if !hasStableHTTPAttributes(attrs) {
+		return attrs
+	}
+
+	legacy := pdata.NewAttributeMap()
+	attrs.CopyTo(legacy)
+	for stable, name := range legacyHTTPAttributeNames {
+		if v, ok := legacy.Get(stable); ok {
+			legacy.Insert(name, v)
+			legacy.Delete(stable)
+		}
+	}
+
+	if path, ok := legacy.Get(urlPathAttribute); ok {
+		target := path.StringVal()
+		if query, ok := legacy.Get(urlQueryAttribute); ok && query.StringVal() != "" {
+			target += "?" + query.StringVal()
+		}
+		legacy.InsertString(conventions.AttributeHTTPTarget, target)
+		legacy.Delete(urlPathAttribute)
+		legacy.Delete(urlQueryAttribute)
+	}
+	return legacy
+}
+
+// hasStableHTTPAttributes returns whether attrs has any stabilized HTTP attribute.
+func hasStableHTTPAttributes(attrs pdata.AttributeMap) bool {
+	if _, ok := attrs.Get(urlPathAttribute); ok {
+		return true
+	}
+	for stable := range legacyHTTPAttributeNames {
+		if _, ok := attrs.Get(stable); ok {
+			return true
+		}
+	}
+	return false
+}
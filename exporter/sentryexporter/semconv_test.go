@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+func TestLegacyHTTPAttributes(t *testing.T) {
+	attrs := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeHTTPMethod: pdata.NewAttributeValueString("GET"),
+	})
+	assert.Equal(t, attrs, legacyHTTPAttributes(attrs))
+
+	attrs = pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"http.request.method":               pdata.NewAttributeValueString("GET"),
+		"http.response.status_code":         pdata.NewAttributeValueInt(200),
+		"url.path":                          pdata.NewAttributeValueString("/api/users"),
+		"url.query":                         pdata.NewAttributeValueString("page=2"),
+		"server.address":                    pdata.NewAttributeValueString("example.com"),
+		conventions.AttributeHTTPStatusCode: pdata.NewAttributeValueInt(204),
+	})
+	legacy := legacyHTTPAttributes(attrs)
+	assert.Equal(t, map[string]string{
+		conventions.AttributeHTTPMethod:     "GET",
+		conventions.AttributeHTTPStatusCode: "204",
+		conventions.AttributeHTTPTarget:     "/api/users?page=2",
+		conventions.AttributeNetHostName:    "example.com",
+	}, generateTagsFromAttributes(legacy))
+
+	// The attributes of the span are not modified.
+	assert.Equal(t, 6, attrs.Len())
+}
+
+func TestFillSentrySpanStableHTTPSemconv(t *testing.T) {
+	span := pdata.NewSpan()
+	span.SetName("GET /api/users/{id}")
+	span.SetKind(pdata.SpanKindServer)
+	span.Attributes().InsertString("http.request.method", "GET")
+	span.Attributes().InsertString(conventions.AttributeHTTPRoute, "/api/users/{id}")
+	span.Attributes().InsertString("url.full", "https://example.com/api/users/42?page=2")
+	span.Attributes().InsertInt("http.response.status_code", 503)
+	library := pdata.NewInstrumentationLibrary()
+
+	var sentrySpan sentry.Span
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{inferHTTPStatus: true, stableHTTPSemconv: true})
+	assert.Equal(t, "http.server", sentrySpan.Op)
+	assert.Equal(t, "GET /api/users/{id}", sentrySpan.Description)
+	assert.Equal(t, "unavailable", sentrySpan.Status)
+
+	request := requestFromTags(sentrySpan.Tags)
+	require.NotNil(t, request)
+	assert.Equal(t, "GET", request.Method)
+	assert.Equal(t, "https://example.com/api/users/42", request.URL)
+	assert.Equal(t, "page=2", request.QueryString)
+
+	// Without semantic_conventions, the stabilized attributes are plain tags.
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{inferHTTPStatus: true})
+	assert.Equal(t, "", sentrySpan.Op)
+	assert.Equal(t, "unknown", sentrySpan.Status)
+	assert.Equal(t, "GET", sentrySpan.Tags["http.request.method"])
+}
+
+func TestNewSentryExporterSemanticConventions(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	assert.False(t, s.conversion.stableHTTPSemconv)
+
+	cfg.SemanticConventions = true
+	s, err = newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	assert.True(t, s.conversion.stableHTTPSemconv)
+}
@@ -17,100 +17,296 @@ package sentryexporter
 import (
 	"context"
 	"fmt"
-	"log"
+	"net/http"
+	"runtime"
+	"sort"
 	"strconv"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/envelopestorage"
 )
 
 const (
 	sentryStatusUnknown       = "unknown"
 	otelSentryExporterVersion = "0.0.1"
 	otelSentryExporterName    = "sentry.opentelemetry"
+
+	// grpcStatusCodeAttribute is the numeric gRPC status code of RPC spans.
+	grpcStatusCodeAttribute = "rpc.grpc.status_code"
+	// statusDescriptionAttribute holds the status message of spans translated from formats without
+	// span status, such as Zipkin. It is not added to the tags.
+	statusDescriptionAttribute = "otel.status_description"
+	// statusMessageTag is the tag of the status message of spans.
+	statusMessageTag = "status_message"
 )
 
-// canonicalCodes maps OpenTelemetry span codes to Sentry's span status.
+// canonicalCodes maps OpenTelemetry span codes, unset, ok and error, to Sentry's span status. The
+// status of error spans is refined from their gRPC or HTTP status code, if any.
 // See numeric codes in https://github.com/open-telemetry/opentelemetry-proto/blob/6cf77b2f544f6bc7fe1e4b4a8a52e5a42cb50ead/opentelemetry/proto/trace/v1/trace.proto#L303
 var canonicalCodes = [...]string{
 	"unknown",
 	"ok",
+	"internal_error",
+}
+
+// grpcCodes maps gRPC status codes to Sentry's span status.
+// See https://github.com/grpc/grpc/blob/master/doc/statuscodes.md
+var grpcCodes = [...]string{
+	"ok",
+	"cancelled",
 	"unknown",
+	"invalid_argument",
+	"deadline_exceeded",
+	"not_found",
+	"already_exists",
+	"permission_denied",
+	"resource_exhausted",
+	"failed_precondition",
+	"aborted",
+	"out_of_range",
+	"unimplemented",
+	"internal_error",
+	"unavailable",
+	"data_loss",
+	"unauthenticated",
 }
 
 // SentryExporter defines the Sentry Exporter.
 type SentryExporter struct {
+	id        config.ComponentID
 	transport transport
+	logger    *zap.Logger
+
+	// storageID is the envelope storage extension undeliverable envelopes are spooled to, if any.
+	storageID *config.ComponentID
+	spool     envelopestorage.Spool
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	// dataType names the spool, so that the exporters of each data type created from one config
+	// don't deliver the envelopes of the same spool.
+	dataType config.DataType
+
+	// pending keeps undeliverable envelopes in memory when there is no storage, if enabled.
+	pending *pendingEnvelopes
+
+	// occurrences sends the issue occurrences created by occurrenceRules, if any.
+	occurrences     *occurrenceClient
+	occurrenceRules []*occurrenceRule
+
+	// errorsOnly sends the error events of spans, but not the transactions.
+	errorsOnly bool
+
+	// release holds the configured release and environment of events.
+	release releaseOptions
+
+	// conversion holds the settings of the conversion of spans.
+	conversion conversionOptions
+
+	// tagFilter selects the tags of transactions, spans and events, all if nil.
+	tagFilter *tagFilter
+
+	// staticTags are the configured tags of all transactions and events.
+	staticTags staticTags
+
+	// tagLimits are the maximum lengths of the keys and values of tags.
+	tagLimits tagLimits
+
+	// maxBreadcrumbs is the maximum number of breadcrumbs, converted from span events, of a transaction.
+	maxBreadcrumbs int
+
+	// metrics converts metrics, keeping the previous points of cumulative series.
+	metrics *metricsConverter
+
+	// minLogLevel and errorLogLevel are the ranks in logLevels of the lowest levels of log
+	// records sent at all, and sent as events instead of breadcrumbs.
+	minLogLevel   int
+	errorLogLevel int
+
+	// workers is the maximum number of ResourceSpans converted concurrently, 1 or less converts
+	// them in turn.
+	workers int
+
+	// sendWorkers is the maximum number of envelopes of a push sent concurrently, 1 or less sends
+	// them in turn.
+	sendWorkers int
 }
 
 // pushTraceData takes an incoming OpenTelemetry trace, converts them into Sentry spans and transactions
 // and sends them using Sentry's transport.
-func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) error {
+func (s *SentryExporter) pushTraceData(ctx context.Context, td pdata.Traces) error {
 	resourceSpans := td.ResourceSpans()
 	if resourceSpans.Len() == 0 {
 		return nil
 	}
 
-	maybeOrphanSpans := make([]*sentry.Span, 0, td.SpanCount())
+	occurrences := s.spanOccurrences(td, time.Now())
+	errorEvents := spanErrorEvents(td, s.errorsOnly)
+	if s.conversion.scrubSQL {
+		for _, event := range errorEvents {
+			scrubSQLStatementTag(event.Tags)
+		}
+	}
+	if s.errorsOnly {
+		sent, errs := s.sendEvents(ctx, errorEvents)
+		n, occurrenceErrs := s.sendOccurrences(ctx, occurrences)
+		return s.pushError(sent+n, append(errs, occurrenceErrs...))
+	}
+
+	// The spans of each ResourceSpans are converted concurrently, and then assembled into
+	// transactions in order. They are pooled, and released once the transactions are encoded.
+	batches := s.convertResourceSpans(resourceSpans)
+	defer func() {
+		for _, batch := range batches {
+			batch.release()
+		}
+	}()
+	batch := batches[0]
+	maybeOrphanSpans := batch.orphans
 
 	// Maps all child span ids to their root span.
 	idMap := make(map[string]string)
 	// Maps root span id to a transaction.
 	transactionMap := make(map[string]*sentry.Event)
 
-	for i := 0; i < resourceSpans.Len(); i++ {
-		rs := resourceSpans.At(i)
-		resourceTags := generateTagsFromResource(rs.Resource())
-
-		ilss := rs.InstrumentationLibrarySpans()
-		for j := 0; j < ilss.Len(); j++ {
-			ils := ilss.At(j)
-			library := ils.InstrumentationLibrary()
-
-			spans := ils.Spans()
-			for k := 0; k < spans.Len(); k++ {
-				sentrySpan := convertToSentrySpan(spans.At(k), library, resourceTags)
-
-				// If a span is a root span, we consider it the start of a Sentry transaction.
-				// We should then create a new transaction for that root span, and keep track of it.
-				//
-				// If the span is not a root span, we can either associate it with an existing
-				// transaction, or we can temporarily consider it an orphan span.
-				if isRootSpan(sentrySpan) {
-					transactionMap[sentrySpan.SpanID] = transactionFromSpan(sentrySpan)
-					idMap[sentrySpan.SpanID] = sentrySpan.SpanID
+	for _, b := range batches {
+		for _, sentrySpan := range b.spans {
+			// If a span is a root span, we consider it the start of a Sentry transaction.
+			// We should then create a new transaction for that root span, and keep track of it.
+			//
+			// If the span is not a root span, we can either associate it with an existing
+			// transaction, or we can temporarily consider it an orphan span.
+			if isRootSpan(sentrySpan) {
+				transactionMap[sentrySpan.SpanID] = transactionFromSpan(sentrySpan)
+				idMap[sentrySpan.SpanID] = sentrySpan.SpanID
+			} else {
+				if rootSpanID, ok := idMap[sentrySpan.ParentSpanID]; ok {
+					idMap[sentrySpan.SpanID] = rootSpanID
+					transactionMap[rootSpanID].Spans = append(transactionMap[rootSpanID].Spans, sentrySpan)
 				} else {
-					if rootSpanID, ok := idMap[sentrySpan.ParentSpanID]; ok {
-						idMap[sentrySpan.SpanID] = rootSpanID
-						transactionMap[rootSpanID].Spans = append(transactionMap[rootSpanID].Spans, sentrySpan)
-					} else {
-						maybeOrphanSpans = append(maybeOrphanSpans, sentrySpan)
-					}
+					maybeOrphanSpans = append(maybeOrphanSpans, sentrySpan)
 				}
 			}
 		}
 	}
 
-	if len(transactionMap) == 0 {
-		return nil
+	// Keeps the grown slice for the next batch.
+	batch.orphans = maybeOrphanSpans
+
+	var sent int
+	var errs []error
+	if len(transactionMap) > 0 {
+		// After the first pass through, we can't necessarily make the assumption we have not associated all
+		// the spans with a transaction. As such, we must classify the remaining spans as orphans or not.
+		orphanSpans := classifyAsOrphanSpans(maybeOrphanSpans, len(maybeOrphanSpans)+1, idMap, transactionMap)
+
+		transactions := generateTransactions(transactionMap, orphanSpans)
+		collectBreadcrumbs(transactions, s.maxBreadcrumbs)
+		s.conversion.spanData.apply(transactions, s.tagFilter)
+
+		sent, errs = s.sendTransactions(ctx, transactions)
+	}
+	n, eventErrs := s.sendEvents(ctx, errorEvents)
+	sent += n
+	errs = append(errs, eventErrs...)
+	n, occurrenceErrs := s.sendOccurrences(ctx, occurrences)
+	return s.pushError(sent+n, append(errs, occurrenceErrs...))
+}
+
+// convertResourceSpans converts the spans of each ResourceSpans, in their order, into a batch of
+// its own. Up to s.workers ResourceSpans are converted concurrently; they only share the
+// read-only traces.
+func (s *SentryExporter) convertResourceSpans(resourceSpans pdata.ResourceSpansSlice) []*spanBatch {
+	batches := make([]*spanBatch, resourceSpans.Len())
+	convert := func(i int) {
+		batches[i] = convertResourceSpans(resourceSpans.At(i), s.conversion)
 	}
 
-	// After the first pass through, we can't necessarily make the assumption we have not associated all
-	// the spans with a transaction. As such, we must classify the remaining spans as orphans or not.
-	orphanSpans := classifyAsOrphanSpans(maybeOrphanSpans, len(maybeOrphanSpans)+1, idMap, transactionMap)
+	workers := s.workers
+	if workers > len(batches) {
+		workers = len(batches)
+	}
+	if workers <= 1 {
+		for i := range batches {
+			convert(i)
+		}
+		return batches
+	}
 
-	transactions := generateTransactions(transactionMap, orphanSpans)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				convert(i)
+			}
+		}()
+	}
+	for i := range batches {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return batches
+}
 
-	s.transport.SendTransactions(transactions)
+// convertResourceSpans converts the spans of a ResourceSpans into a new batch, tagged with the
+// attributes of its resource.
+func convertResourceSpans(rs pdata.ResourceSpans, options conversionOptions) *spanBatch {
+	batch := newSpanBatch()
+	batch.options = options
+	resourceTags := batch.newTags()
+	addTagsFromAttributes(rs.Resource().Attributes(), resourceTags, &batch.interner)
+
+	ilss := rs.InstrumentationLibrarySpans()
+	for j := 0; j < ilss.Len(); j++ {
+		ils := ilss.At(j)
+		library := ils.InstrumentationLibrary()
+
+		spans := ils.Spans()
+		for k := 0; k < spans.Len(); k++ {
+			batch.convertSpan(spans.At(k), library, resourceTags)
+		}
+	}
+	return batch
+}
 
-	return nil
+// sendTransactions encodes each transaction as an envelope and sends them to Sentry. It returns
+// the number of envelopes sent, and the errors of the others.
+func (s *SentryExporter) sendTransactions(ctx context.Context, transactions []*sentry.Event) (int, []error) {
+	var errs []error
+	envelopes := make([][]byte, 0, len(transactions))
+	sentAt := time.Now()
+	for _, transaction := range transactions {
+		s.release.apply(transaction)
+		s.tagFilter.apply(transaction)
+		s.staticTags.apply(transaction)
+		s.tagLimits.apply(transaction)
+		envelope, err := transactionToEnvelope(transaction, sentAt)
+		if err != nil {
+			errs = append(errs, consumererror.Permanent(err))
+			continue
+		}
+		envelopes = append(envelopes, envelope)
+	}
+	sent, sendErrs := s.sendEnvelopes(ctx, envelopes)
+	return sent, append(errs, sendErrs...)
 }
 
 // generateTransactions creates a set of Sentry transactions from a transaction map and orphan spans.
+// The transactions are ordered by start timestamp, then trace and span id, so that they are sent
+// in the same order for the same spans.
 func generateTransactions(transactionMap map[string]*sentry.Event, orphanSpans []*sentry.Span) []*sentry.Event {
 	transactions := make([]*sentry.Event, 0, len(transactionMap)+len(orphanSpans))
 
@@ -123,9 +319,32 @@ func generateTransactions(transactionMap map[string]*sentry.Event, orphanSpans [
 		transactions = append(transactions, t)
 	}
 
+	sort.Slice(transactions, func(i, j int) bool {
+		a, b := transactions[i], transactions[j]
+		if !a.StartTimestamp.Equal(b.StartTimestamp) {
+			return a.StartTimestamp.Before(b.StartTimestamp)
+		}
+		traceA, traceB := transactionTraceContext(a), transactionTraceContext(b)
+		if traceA.TraceID != traceB.TraceID {
+			return traceA.TraceID < traceB.TraceID
+		}
+		return traceA.SpanID < traceB.SpanID
+	})
+
 	return transactions
 }
 
+// transactionTraceContext returns the trace context set by transactionFromSpan.
+func transactionTraceContext(transaction *sentry.Event) sentry.TraceContext {
+	switch trace := transaction.Contexts["trace"].(type) {
+	case sentry.TraceContext:
+		return trace
+	case traceContextPayload:
+		return trace.TraceContext
+	}
+	return sentry.TraceContext{}
+}
+
 // classifyAsOrphanSpans iterates through a list of possible orphan spans and tries to associate them
 // with a transaction. As the order of the spans is not guaranteed, we have to recursively call
 // classifyAsOrphanSpans to make sure that we did not leave any spans out of the transaction they belong to.
@@ -149,37 +368,161 @@ func classifyAsOrphanSpans(orphanSpans []*sentry.Span, prevLength int, idMap map
 }
 
 func convertToSentrySpan(span pdata.Span, library pdata.InstrumentationLibrary, resourceTags map[string]string) (sentrySpan *sentry.Span) {
+	sentrySpan = &sentry.Span{}
+	fillSentrySpan(sentrySpan, span, library, resourceTags, make(map[string]string), nil, conversionOptions{})
+	return sentrySpan
+}
+
+// conversionOptions are the settings of the conversion of spans.
+type conversionOptions struct {
+	// inferHTTPStatus sets the status of spans with an unset status from their HTTP status code.
+	inferHTTPStatus bool
+	// measurementPrefixes are the prefixes of the attributes sent as measurements.
+	measurementPrefixes []string
+	// scrubSQL normalizes the statements of SQL database spans, removing their literals.
+	scrubSQL bool
+	// stableHTTPSemconv converts the stabilized HTTP attributes like their legacy names.
+	stableHTTPSemconv bool
+	// spanData sends span attributes in the data of spans rather than as tags.
+	spanData spanDataOptions
+	// omitLibraryTags does not tag spans with the name and version of their instrumentation library.
+	omitLibraryTags bool
+}
+
+// fillSentrySpan sets the fields of sentrySpan from an otel span, with tags, an empty map, as its tags.
+// Repeated strings are deduplicated with in, if not nil.
+func fillSentrySpan(
+	sentrySpan *sentry.Span,
+	span pdata.Span,
+	library pdata.InstrumentationLibrary,
+	resourceTags map[string]string,
+	tags map[string]string,
+	in *interner,
+	options conversionOptions,
+) {
 	parentSpanID := ""
 	if psID := span.ParentSpanID(); !psID.IsEmpty() {
-		parentSpanID = psID.HexString()
+		// Parent span ids are shared by the children of a span, and trace ids by all its spans.
+		bytes := psID.Bytes()
+		parentSpanID = in.hex(bytes[:])
 	}
+	traceID := span.TraceID().Bytes()
 
 	attributes := span.Attributes()
+	if options.stableHTTPSemconv {
+		attributes = legacyHTTPAttributes(attributes)
+	}
 	name := span.Name()
 	spanKind := span.Kind()
 
-	op, description := generateSpanDescriptors(name, attributes, spanKind)
-	tags := generateTagsFromAttributes(attributes)
+	op, description := generateSpanDescriptors(name, attributes, spanKind, in)
+	addTagsFromAttributes(attributes, tags, in)
+	addAWSTags(attributes, tags)
 
 	for k, v := range resourceTags {
 		tags[k] = v
 	}
 
 	status, message := statusFromSpanStatus(span.Status())
+	if message == "" {
+		message = statusDescription(attributes)
+	}
+	delete(tags, statusDescriptionAttribute)
+	switch span.Status().Code() {
+	case pdata.StatusCodeUnset:
+		if grpcStatus := statusFromGRPCStatusCode(attributes); grpcStatus != "" {
+			status = grpcStatus
+		} else if options.inferHTTPStatus {
+			if httpStatus := statusFromHTTPStatusCode(attributes); httpStatus != "" {
+				status = httpStatus
+			}
+		}
+	case pdata.StatusCodeError:
+		if errorStatus := statusFromErrorAttributes(attributes); errorStatus != "" {
+			status = errorStatus
+		}
+	}
 
 	if message != "" {
-		tags["status_message"] = message
+		tags[statusMessageTag] = message
 	}
 
 	if spanKind != pdata.SpanKindUnspecified {
 		tags["span_kind"] = spanKind.String()
 	}
 
-	tags["library_name"] = library.Name()
-	tags["library_version"] = library.Version()
+	if !options.omitLibraryTags {
+		tags["library_name"] = library.Name()
+		tags["library_version"] = library.Version()
+	}
+
+	if options.scrubSQL && op == dbSQLQueryOp {
+		statement := tags[conventions.AttributeDBStatement]
+		scrubSQLStatementTag(tags)
+		if description == statement {
+			description = tags[conventions.AttributeDBStatement]
+		} else {
+			description = scrubSQL(description)
+		}
+	}
+
+	// The names of transactions of HTTP server spans have a source, those of other spans and of
+	// renamed spans are custom.
+	var source string
+	if op == "http.server" {
+		source = httpServerTransactionSource(attributes)
+	}
+
+	// The reserved sentry.* attributes override the derived op, description and tags.
+	opOverride, descriptionOverride := applyTagOverrides(tags)
+	if opOverride != "" {
+		op = opOverride
+	}
+	if descriptionOverride != "" {
+		description = descriptionOverride
+		source = ""
+	}
+
+	var data map[string]interface{}
+	delete(tags, metricsSummaryAttribute)
+	if summary := spanMetricsSummary(span); summary != nil {
+		data = map[string]interface{}{metricsSummaryKey: summary}
+	}
+	if measurements := spanMeasurements(span, options.measurementPrefixes, tags); measurements != nil {
+		if data == nil {
+			data = make(map[string]interface{}, 1)
+		}
+		data[measurementsKey] = measurements
+	}
+	if source != "" {
+		if data == nil {
+			data = make(map[string]interface{}, 1)
+		}
+		data[transactionSourceKey] = source
+	}
+	if breadcrumbs := spanBreadcrumbs(span); breadcrumbs != nil {
+		if data == nil {
+			data = make(map[string]interface{}, 1)
+		}
+		data[spanBreadcrumbsKey] = breadcrumbs
+	}
+	if links := spanLinks(span); links != nil {
+		if data == nil {
+			data = make(map[string]interface{}, 1)
+		}
+		data[spanLinksKey] = links
+	}
+	if options.spanData.enabled {
+		if attributes := generateStructuredDataFromAttributes(attributes); attributes != nil {
+			if data == nil {
+				data = make(map[string]interface{}, 1)
+			}
+			data[spanAttributesKey] = attributes
+		}
+	}
 
-	sentrySpan = &sentry.Span{
-		TraceID:        span.TraceID().HexString(),
+	*sentrySpan = sentry.Span{
+		TraceID:        in.hex(traceID[:]),
 		SpanID:         span.SpanID().HexString(),
 		ParentSpanID:   parentSpanID,
 		Description:    description,
@@ -188,9 +531,8 @@ func convertToSentrySpan(span pdata.Span, library pdata.InstrumentationLibrary,
 		StartTimestamp: unixNanoToTime(span.StartTimestamp()),
 		EndTimestamp:   unixNanoToTime(span.EndTimestamp()),
 		Status:         status,
+		Data:           data,
 	}
-
-	return sentrySpan
 }
 
 // generateSpanDescriptors generates generate span descriptors (op and description)
@@ -199,64 +541,70 @@ func convertToSentrySpan(span pdata.Span, library pdata.InstrumentationLibrary,
 //
 // See https://github.com/open-telemetry/opentelemetry-specification/tree/5b78ee1/specification/trace/semantic_conventions
 // for more details about the semantic conventions.
-func generateSpanDescriptors(name string, attrs pdata.AttributeMap, spanKind pdata.SpanKind) (op string, description string) {
-	var opBuilder strings.Builder
-	var dBuilder strings.Builder
-
+func generateSpanDescriptors(name string, attrs pdata.AttributeMap, spanKind pdata.SpanKind, in *interner) (op string, description string) {
 	// Generating span descriptors operates under the assumption that only one of the conventions are present.
 	// In the possible case that multiple convention attributes are available, conventions are selected based
 	// on what is most likely and what is most useful (ex. http is prioritized over FaaS)
 
+	// If graphql.operation.type or graphql.operation.name exists, this is a GraphQL operation span,
+	// which may also have HTTP attributes.
+	if op, description, ok := graphqlSpanDescriptors(name, attrs); ok {
+		return op, description
+	}
+
+	// If rpc.system is aws-api, this is an AWS SDK request span, which may also have HTTP attributes.
+	if op, description, ok := awsSDKSpanDescriptors(name, attrs, in); ok {
+		return op, description
+	}
+
 	// If http.method exists, this is an http request span.
 	if httpMethod, ok := attrs.Get(conventions.AttributeHTTPMethod); ok {
-		opBuilder.WriteString("http")
-
 		switch spanKind {
 		case pdata.SpanKindClient:
-			opBuilder.WriteString(".client")
+			op = "http.client"
 		case pdata.SpanKindServer:
-			opBuilder.WriteString(".server")
+			op = "http.server"
+		default:
+			op = "http"
 		}
 
-		// Ex. description="GET /api/users/{user_id}".
-		fmt.Fprintf(&dBuilder, "%s %s", httpMethod.StringVal(), name)
+		// Server spans are named after their route, if any, rather than the raw path, whose
+		// identifiers are otherwise replaced with placeholders.
+		if spanKind == pdata.SpanKindServer {
+			if route, ok := attrs.Get(conventions.AttributeHTTPRoute); ok && route.StringVal() != "" {
+				name = route.StringVal()
+			} else {
+				name = parameterizeURLPath(name)
+			}
+		}
 
-		return opBuilder.String(), dBuilder.String()
+		// Ex. description="GET /api/users/{user_id}".
+		return op, in.join(httpMethod.StringVal(), name)
 	}
 
-	// If db.type exists then this is a database call span.
-	if _, ok := attrs.Get(conventions.AttributeDBSystem); ok {
-		opBuilder.WriteString("db")
-
+	// If db.system or db.type exists then this is a database call span.
+	if op, ok := dbSpanOp(attrs); ok {
 		// Use DB statement (Ex "SELECT * FROM table") if possible as description.
 		if statement, okInst := attrs.Get(conventions.AttributeDBStatement); okInst {
-			dBuilder.WriteString(statement.StringVal())
-		} else {
-			dBuilder.WriteString(name)
+			return op, statement.StringVal()
 		}
 
-		return opBuilder.String(), dBuilder.String()
+		return op, name
 	}
 
-	// If rpc.service exists then this is a rpc call span.
-	if _, ok := attrs.Get(conventions.AttributeRPCService); ok {
-		opBuilder.WriteString("rpc")
-
-		return opBuilder.String(), name
+	// If rpc.system or rpc.service exists then this is a rpc call span.
+	if op, description, ok := rpcSpanDescriptors(name, attrs, in); ok {
+		return op, description
 	}
 
 	// If messaging.system exists then this is a messaging system span.
-	if _, ok := attrs.Get("messaging.system"); ok {
-		opBuilder.WriteString("message")
-
-		return opBuilder.String(), name
+	if _, ok := attrs.Get(messagingSystemAttribute); ok {
+		return messagingSpanDescriptors(name, attrs, spanKind)
 	}
 
-	// If faas.trigger exists then this is a function as a service span.
-	if trigger, ok := attrs.Get("faas.trigger"); ok {
-		opBuilder.WriteString(trigger.StringVal())
-
-		return opBuilder.String(), name
+	// If faas.trigger or faas.invoked_name exists then this is a function as a service span.
+	if op, description, ok := faasSpanDescriptors(name, attrs); ok {
+		return op, description
 	}
 
 	// Default just use span.name.
@@ -269,7 +617,40 @@ func generateTagsFromResource(resource pdata.Resource) map[string]string {
 
 func generateTagsFromAttributes(attrs pdata.AttributeMap) map[string]string {
 	tags := make(map[string]string)
+	addTagsFromAttributes(attrs, tags, nil)
+	return tags
+}
 
+// generateDataFromAttributes returns the string, bool, int and double attributes with their
+// values, or nil if there are none.
+func generateDataFromAttributes(attrs pdata.AttributeMap) map[string]interface{} {
+	var data map[string]interface{}
+	attrs.Range(func(key string, attr pdata.AttributeValue) bool {
+		var value interface{}
+		switch attr.Type() {
+		case pdata.AttributeValueTypeString:
+			value = attr.StringVal()
+		case pdata.AttributeValueTypeBool:
+			value = attr.BoolVal()
+		case pdata.AttributeValueTypeInt:
+			value = attr.IntVal()
+		case pdata.AttributeValueTypeDouble:
+			value = attr.DoubleVal()
+		default:
+			return true
+		}
+		if data == nil {
+			data = make(map[string]interface{})
+		}
+		data[key] = value
+		return true
+	})
+	return data
+}
+
+// addTagsFromAttributes adds the string, bool, double and int attributes to tags. The formatted
+// numbers are deduplicated with in, if not nil.
+func addTagsFromAttributes(attrs pdata.AttributeMap, tags map[string]string, in *interner) {
 	attrs.Range(func(key string, attr pdata.AttributeValue) bool {
 		switch attr.Type() {
 		case pdata.AttributeValueTypeString:
@@ -277,14 +658,12 @@ func generateTagsFromAttributes(attrs pdata.AttributeMap) map[string]string {
 		case pdata.AttributeValueTypeBool:
 			tags[key] = strconv.FormatBool(attr.BoolVal())
 		case pdata.AttributeValueTypeDouble:
-			tags[key] = strconv.FormatFloat(attr.DoubleVal(), 'g', -1, 64)
+			tags[key] = in.formatFloat(attr.DoubleVal())
 		case pdata.AttributeValueTypeInt:
-			tags[key] = strconv.FormatInt(attr.IntVal(), 10)
+			tags[key] = in.formatInt(attr.IntVal())
 		}
 		return true
 	})
-
-	return tags
 }
 
 func statusFromSpanStatus(spanStatus pdata.SpanStatus) (status string, message string) {
@@ -296,6 +675,96 @@ func statusFromSpanStatus(spanStatus pdata.SpanStatus) (status string, message s
 	return canonicalCodes[code], spanStatus.Message()
 }
 
+// statusDescription returns the otel.status_description attribute, or an empty string.
+func statusDescription(attrs pdata.AttributeMap) string {
+	if attr, ok := attrs.Get(statusDescriptionAttribute); ok && attr.Type() == pdata.AttributeValueTypeString {
+		return attr.StringVal()
+	}
+	return ""
+}
+
+// statusFromHTTPStatusCode returns the Sentry status of the HTTP server error status code of a span,
+// or an empty string if it has none.
+func statusFromHTTPStatusCode(attrs pdata.AttributeMap) string {
+	if code := intAttribute(attrs, conventions.AttributeHTTPStatusCode); code >= 500 && code < 600 {
+		return statusFromHTTPCode(code)
+	}
+	return ""
+}
+
+// statusFromErrorAttributes returns the Sentry status of a span with an error status from its
+// rpc.grpc.status_code, or else its HTTP client or server error status code, or an empty string
+// if it has none.
+func statusFromErrorAttributes(attrs pdata.AttributeMap) string {
+	if status := statusFromGRPCStatusCode(attrs); status != "" && status != "ok" {
+		return status
+	}
+	return statusFromHTTPCode(intAttribute(attrs, conventions.AttributeHTTPStatusCode))
+}
+
+// statusFromGRPCStatusCode returns the Sentry status of the rpc.grpc.status_code of a span, or an
+// empty string if it has none.
+func statusFromGRPCStatusCode(attrs pdata.AttributeMap) string {
+	if _, ok := attrs.Get(grpcStatusCodeAttribute); !ok {
+		return ""
+	}
+	if code := intAttribute(attrs, grpcStatusCodeAttribute); code >= 0 && code < int64(len(grpcCodes)) {
+		return grpcCodes[code]
+	}
+	return ""
+}
+
+// statusFromHTTPCode returns the Sentry status of an HTTP client or server error status code, or
+// an empty string for other codes.
+func statusFromHTTPCode(code int64) string {
+	switch code {
+	case http.StatusBadRequest:
+		return "invalid_argument"
+	case http.StatusUnauthorized:
+		return "unauthenticated"
+	case http.StatusForbidden:
+		return "permission_denied"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "already_exists"
+	case http.StatusTooManyRequests:
+		return "resource_exhausted"
+	case 499:
+		return "cancelled"
+	case http.StatusNotImplemented:
+		return "unimplemented"
+	case http.StatusServiceUnavailable:
+		return "unavailable"
+	case http.StatusGatewayTimeout:
+		return "deadline_exceeded"
+	}
+	switch {
+	case code >= 400 && code < 500:
+		return "invalid_argument"
+	case code >= 500 && code < 600:
+		return "internal_error"
+	}
+	return ""
+}
+
+// intAttribute returns the value of an int attribute, or of a string attribute holding an int.
+// It returns 0 if the attribute is not set or not an int.
+func intAttribute(attrs pdata.AttributeMap, key string) int64 {
+	attr, ok := attrs.Get(key)
+	if !ok {
+		return 0
+	}
+	switch attr.Type() {
+	case pdata.AttributeValueTypeInt:
+		return attr.IntVal()
+	case pdata.AttributeValueTypeString:
+		code, _ := strconv.ParseInt(attr.StringVal(), 10, 64)
+		return code
+	}
+	return 0
+}
+
 // isRootSpan determines if a span is a root span.
 // If parent span id is empty, then the span is a root span.
 func isRootSpan(s *sentry.Span) bool {
@@ -306,12 +775,13 @@ func isRootSpan(s *sentry.Span) bool {
 func transactionFromSpan(span *sentry.Span) *sentry.Event {
 	transaction := sentry.NewEvent()
 
-	transaction.Contexts["trace"] = sentry.TraceContext{
+	trace := sentry.TraceContext{
 		TraceID: span.TraceID,
 		SpanID:  span.SpanID,
 		Op:      span.Op,
 		Status:  span.Status,
 	}
+	transaction.Contexts["trace"] = trace
 
 	transaction.Type = "transaction"
 
@@ -322,33 +792,210 @@ func transactionFromSpan(span *sentry.Span) *sentry.Event {
 	transaction.Tags = span.Tags
 	transaction.Timestamp = span.EndTimestamp
 	transaction.Transaction = span.Description
+	setUserFromTags(transaction, span.Tags)
+	setContextsFromTags(transaction, span.Tags)
+	setReleaseFromTags(transaction, span.Tags)
+	if span.Op == "http.server" {
+		transaction.Request = requestFromTags(span.Tags)
+	}
+
+	if summary, ok := span.Data[metricsSummaryKey]; ok {
+		transaction.Extra[metricsSummaryKey] = summary
+	}
+	if measurements, ok := span.Data[measurementsKey]; ok {
+		transaction.Extra[measurementsKey] = measurements
+	}
+	transaction.Extra[transactionSourceKey] = transactionSourceCustom
+	if source, ok := span.Data[transactionSourceKey]; ok {
+		transaction.Extra[transactionSourceKey] = source
+	}
+	if breadcrumbs, ok := span.Data[spanBreadcrumbsKey].([]*sentry.Breadcrumb); ok {
+		transaction.Breadcrumbs = breadcrumbs
+	}
+	if attributes, ok := span.Data[spanAttributesKey]; ok {
+		transaction.Extra[spanAttributesKey] = attributes
+	}
+	// The links and the status message of the root span are sent in the trace context.
+	links, _ := span.Data[spanLinksKey].([]spanLink)
+	message := span.Tags[statusMessageTag]
+	if links != nil || message != "" {
+		payload := traceContextPayload{TraceContext: trace, Links: links}
+		if message != "" {
+			payload.Data = map[string]interface{}{statusMessageTag: message}
+		}
+		transaction.Contexts["trace"] = payload
+	}
 
 	return transaction
 }
 
 // CreateSentryExporter returns a new Sentry Exporter.
-func CreateSentryExporter(config *Config, params component.ExporterCreateParams) (component.TracesExporter, error) {
-	transport := newSentryTransport()
-	transport.Configure(sentry.ClientOptions{
-		Dsn: config.DSN,
-	})
-
-	s := &SentryExporter{
-		transport: transport,
+func CreateSentryExporter(cfg *Config, params component.ExporterCreateParams) (component.TracesExporter, error) {
+	s, err := newSentryExporter(cfg, params.Logger)
+	if err != nil {
+		return nil, err
 	}
+	s.dataType = config.TracesDataType
 
-	return exporterhelper.NewTracesExporter(
-		config,
+	exp, err := exporterhelper.NewTracesExporter(
+		cfg,
 		params.Logger,
 		s.pushTraceData,
-		exporterhelper.WithShutdown(func(ctx context.Context) error {
-			allEventsFlushed := transport.Flush(ctx)
+		exporterOptions(cfg, s)...,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-			if !allEventsFlushed {
-				log.Print("Could not flush all events, reached timeout")
-			}
+	return &sentryTracesExporter{TracesExporter: exp, exporter: s}, nil
+}
+
+// CreateSentryLogsExporter returns a new Sentry logs exporter. Log records are sent as events,
+// and those matching occurrence rules as issue occurrences too.
+func CreateSentryLogsExporter(cfg *Config, params component.ExporterCreateParams) (component.LogsExporter, error) {
+	s, err := newSentryExporter(cfg, params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	s.dataType = config.LogsDataType
+
+	return exporterhelper.NewLogsExporter(
+		cfg,
+		params.Logger,
+		s.pushLogData,
+		exporterOptions(cfg, s)...,
+	)
+}
+
+// CreateSentryMetricsExporter returns a new Sentry metrics exporter. Metrics are sent as Sentry
+// counters, gauges and distributions.
+func CreateSentryMetricsExporter(cfg *Config, params component.ExporterCreateParams) (component.MetricsExporter, error) {
+	s, err := newSentryExporter(cfg, params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	s.dataType = config.MetricsDataType
 
-			return nil
-		}),
+	return exporterhelper.NewMetricsExporter(
+		cfg,
+		params.Logger,
+		s.pushMetricsData,
+		exporterOptions(cfg, s)...,
 	)
 }
+
+func newSentryExporter(cfg *Config, logger *zap.Logger) (*SentryExporter, error) {
+	if cfg.ConversionWorkers < 0 {
+		return nil, fmt.Errorf("invalid 'conversion_workers': %d is negative", cfg.ConversionWorkers)
+	}
+	if cfg.SendWorkers < 0 {
+		return nil, fmt.Errorf("invalid 'send_workers': %d is negative", cfg.SendWorkers)
+	}
+	if cfg.MaxBreadcrumbs < 0 {
+		return nil, fmt.Errorf("invalid 'max_breadcrumbs': %d is negative", cfg.MaxBreadcrumbs)
+	}
+	if cfg.MaxTagKeyLength < 0 {
+		return nil, fmt.Errorf("invalid 'max_tag_key_length': %d is negative", cfg.MaxTagKeyLength)
+	}
+	if cfg.MaxTagValueLength < 0 {
+		return nil, fmt.Errorf("invalid 'max_tag_value_length': %d is negative", cfg.MaxTagValueLength)
+	}
+	if cfg.Pending.MaxMemoryMiB < 0 {
+		return nil, fmt.Errorf("invalid 'pending.max_memory_mib': %d is negative", cfg.Pending.MaxMemoryMiB)
+	}
+
+	var transport transport
+	if cfg.File.Directory != "" {
+		fileTransport, err := newFileTransport(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		transport = fileTransport
+	} else {
+		sentryTransport, err := newSentryTransport(cfg.DSN)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'dsn': %w", err)
+		}
+		transport = sentryTransport
+	}
+
+	s := &SentryExporter{
+		id:          cfg.ID(),
+		transport:   transport,
+		logger:      logger,
+		stopCh:      make(chan struct{}),
+		metrics:     newMetricsConverter(),
+		workers:     cfg.ConversionWorkers,
+		sendWorkers: cfg.SendWorkers,
+
+		staticTags: cfg.Tags,
+		tagLimits: tagLimits{
+			maxKeyLength:   cfg.MaxTagKeyLength,
+			maxValueLength: cfg.MaxTagValueLength,
+		},
+		maxBreadcrumbs: cfg.MaxBreadcrumbs,
+		errorsOnly:     cfg.ErrorsOnly,
+		release: releaseOptions{
+			release:            cfg.Release,
+			environment:        cfg.Environment,
+			defaultRelease:     cfg.DefaultRelease,
+			defaultEnvironment: cfg.DefaultEnvironment,
+			dist:               cfg.Dist,
+		},
+		conversion: conversionOptions{
+			inferHTTPStatus:     cfg.InferHTTPStatus,
+			measurementPrefixes: cfg.MeasurementPrefixes,
+			scrubSQL:            cfg.ScrubSQL,
+			spanData:            newSpanDataOptions(cfg.SpanData),
+			omitLibraryTags:     !cfg.LibraryTags,
+			stableHTTPSemconv:   cfg.SemanticConventions,
+		},
+	}
+	tagFilter, err := newTagFilter(cfg.IncludeTags, cfg.ExcludeTags)
+	if err != nil {
+		return nil, err
+	}
+	s.tagFilter = tagFilter
+	if s.workers == 0 {
+		s.workers = runtime.GOMAXPROCS(0)
+	}
+	if cfg.Pending.MaxMemoryMiB > 0 && cfg.Storage == "" {
+		s.pending = newPendingEnvelopes(s.id.String(), int64(cfg.Pending.MaxMemoryMiB)<<20)
+	}
+
+	if cfg.Logs.MinLevel != "" {
+		s.minLogLevel = logLevelRank(sentry.Level(cfg.Logs.MinLevel))
+		if s.minLogLevel < 0 {
+			return nil, fmt.Errorf("invalid 'logs.min_level': %q", cfg.Logs.MinLevel)
+		}
+	}
+	if cfg.Logs.ErrorLevel != "" {
+		s.errorLogLevel = logLevelRank(sentry.Level(cfg.Logs.ErrorLevel))
+		if s.errorLogLevel < 0 {
+			return nil, fmt.Errorf("invalid 'logs.error_level': %q", cfg.Logs.ErrorLevel)
+		}
+	}
+
+	if cfg.Storage != "" {
+		storageID, err := config.NewIDFromString(cfg.Storage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'storage': %w", err)
+		}
+		s.storageID = &storageID
+	}
+
+	if len(cfg.Occurrences.Rules) > 0 {
+		rules, err := newOccurrenceRules(cfg.Occurrences.Rules)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newOccurrenceClient(cfg.DSN, cfg.Occurrences)
+		if err != nil {
+			return nil, err
+		}
+		s.occurrences = client
+		s.occurrenceRules = rules
+	}
+
+	return s, nil
+}
@@ -15,12 +15,17 @@
 package sentryexporter
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 )
@@ -260,6 +265,27 @@ func TestGenerateSpanDescriptors(t *testing.T) {
 			op:          "http.server",
 			description: "POST /api/users/{user_id}",
 		},
+		{
+			testName: "http-server-with-ids",
+			name:     "/api/users/42",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				conventions.AttributeHTTPMethod: pdata.NewAttributeValueString("GET"),
+			}),
+			spanKind:    pdata.SpanKindServer,
+			op:          "http.server",
+			description: "GET /api/users/{id}",
+		},
+		{
+			testName: "http-server-with-route",
+			name:     "/api/users/42",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				conventions.AttributeHTTPMethod: pdata.NewAttributeValueString("GET"),
+				conventions.AttributeHTTPRoute:  pdata.NewAttributeValueString("/api/users/{user_id}"),
+			}),
+			spanKind:    pdata.SpanKindServer,
+			op:          "http.server",
+			description: "GET /api/users/{user_id}",
+		},
 		{
 			testName: "db-call-without-statement",
 			name:     "SET mykey 'Val'",
@@ -267,7 +293,7 @@ func TestGenerateSpanDescriptors(t *testing.T) {
 				conventions.AttributeDBSystem: pdata.NewAttributeValueString("redis"),
 			}),
 			spanKind:    pdata.SpanKindClient,
-			op:          "db",
+			op:          "db.redis",
 			description: "SET mykey 'Val'",
 		},
 		{
@@ -278,9 +304,29 @@ func TestGenerateSpanDescriptors(t *testing.T) {
 				conventions.AttributeDBStatement: pdata.NewAttributeValueString("SELECT * FROM table"),
 			}),
 			spanKind:    pdata.SpanKindClient,
-			op:          "db",
+			op:          "db.sql.query",
 			description: "SELECT * FROM table",
 		},
+		{
+			testName: "db-call-with-type",
+			name:     "find orders",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				"db.type": pdata.NewAttributeValueString("mongodb"),
+			}),
+			spanKind:    pdata.SpanKindClient,
+			op:          "db.mongodb",
+			description: "find orders",
+		},
+		{
+			testName: "db-call-unknown-system",
+			name:     "query",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				conventions.AttributeDBSystem: pdata.NewAttributeValueString("acmedb"),
+			}),
+			spanKind:    pdata.SpanKindClient,
+			op:          "db",
+			description: "query",
+		},
 		{
 			testName: "rpc",
 			name:     "grpc.test.EchoService/Echo",
@@ -291,6 +337,28 @@ func TestGenerateSpanDescriptors(t *testing.T) {
 			op:          "rpc",
 			description: "grpc.test.EchoService/Echo",
 		},
+		{
+			testName: "rpc-grpc",
+			name:     "Echo",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				conventions.AttributeRPCSystem:  pdata.NewAttributeValueString("grpc"),
+				conventions.AttributeRPCService: pdata.NewAttributeValueString("grpc.test.EchoService"),
+				conventions.AttributeRPCMethod:  pdata.NewAttributeValueString("Echo"),
+			}),
+			spanKind:    pdata.SpanKindServer,
+			op:          "rpc.grpc",
+			description: "grpc.test.EchoService/Echo",
+		},
+		{
+			testName: "rpc-other-system",
+			name:     "Echo",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				conventions.AttributeRPCSystem: pdata.NewAttributeValueString("thrift"),
+			}),
+			spanKind:    pdata.SpanKindClient,
+			op:          "rpc.thrift",
+			description: "Echo",
+		},
 		{
 			testName: "message-system",
 			name:     "message-destination",
@@ -298,9 +366,42 @@ func TestGenerateSpanDescriptors(t *testing.T) {
 				"messaging.system": pdata.NewAttributeValueString("kafka"),
 			}),
 			spanKind:    pdata.SpanKindProducer,
-			op:          "message",
+			op:          "queue.publish",
 			description: "message-destination",
 		},
+		{
+			testName: "message-system-receive",
+			name:     "orders receive",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				"messaging.system":      pdata.NewAttributeValueString("kafka"),
+				"messaging.operation":   pdata.NewAttributeValueString("receive"),
+				"messaging.destination": pdata.NewAttributeValueString("orders"),
+			}),
+			spanKind:    pdata.SpanKindConsumer,
+			op:          "queue.receive",
+			description: "orders",
+		},
+		{
+			testName: "message-system-process",
+			name:     "orders process",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				"messaging.system":           pdata.NewAttributeValueString("rabbitmq"),
+				"messaging.destination.name": pdata.NewAttributeValueString("orders"),
+			}),
+			spanKind:    pdata.SpanKindConsumer,
+			op:          "queue.process",
+			description: "orders",
+		},
+		{
+			testName: "message-system-internal",
+			name:     "batch",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				"messaging.system": pdata.NewAttributeValueString("kafka"),
+			}),
+			spanKind:    pdata.SpanKindInternal,
+			op:          "message",
+			description: "batch",
+		},
 		{
 			testName: "faas",
 			name:     "message-destination",
@@ -311,11 +412,70 @@ func TestGenerateSpanDescriptors(t *testing.T) {
 			op:          "pubsub",
 			description: "message-destination",
 		},
+		{
+			testName: "graphql",
+			name:     "query findBookById",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				"graphql.operation.type": pdata.NewAttributeValueString("query"),
+				"graphql.operation.name": pdata.NewAttributeValueString("findBookById"),
+				"graphql.document":       pdata.NewAttributeValueString("query findBookById { bookById(id: 42) { name } }"),
+				"http.method":            pdata.NewAttributeValueString("POST"),
+			}),
+			spanKind:    pdata.SpanKindServer,
+			op:          "graphql.query",
+			description: "findBookById",
+		},
+		{
+			testName: "graphql-anonymous",
+			name:     "mutation",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				"graphql.operation.type": pdata.NewAttributeValueString("mutation"),
+			}),
+			spanKind:    pdata.SpanKindInternal,
+			op:          "graphql.mutation",
+			description: "mutation",
+		},
+		{
+			testName: "aws-sdk",
+			name:     "DynamoDB.GetItem",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				conventions.AttributeRPCSystem:  pdata.NewAttributeValueString("aws-api"),
+				conventions.AttributeRPCService: pdata.NewAttributeValueString("DynamoDB"),
+				conventions.AttributeRPCMethod:  pdata.NewAttributeValueString("GetItem"),
+				conventions.AttributeHTTPMethod: pdata.NewAttributeValueString("POST"),
+			}),
+			spanKind:    pdata.SpanKindClient,
+			op:          "http.client",
+			description: "DynamoDB.GetItem",
+		},
+		{
+			testName: "aws-sdk-legacy-attributes",
+			name:     "S3.GetObject",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				conventions.AttributeRPCSystem: pdata.NewAttributeValueString("aws-api"),
+				"aws.service":                  pdata.NewAttributeValueString("S3"),
+				"aws.operation":                pdata.NewAttributeValueString("GetObject"),
+			}),
+			spanKind:    pdata.SpanKindClient,
+			op:          "http.client",
+			description: "S3.GetObject",
+		},
+		{
+			testName: "faas-invocation",
+			name:     "Invoke",
+			attrs: pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+				"faas.invoked_name":     pdata.NewAttributeValueString("resize-image"),
+				"faas.invoked_provider": pdata.NewAttributeValueString("aws"),
+			}),
+			spanKind:    pdata.SpanKindClient,
+			op:          "function",
+			description: "resize-image",
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.testName, func(t *testing.T) {
-			op, description := generateSpanDescriptors(test.name, test.attrs, test.spanKind)
+			op, description := generateSpanDescriptors(test.name, test.attrs, test.spanKind, nil)
 			assert.Equal(t, test.op, op)
 			assert.Equal(t, test.description, description)
 		})
@@ -368,7 +528,7 @@ func TestStatusFromSpanStatus(t *testing.T) {
 
 				return spanStatus
 			}(),
-			status:  "unknown",
+			status:  "internal_error",
 			message: "message",
 		},
 		{
@@ -394,6 +554,134 @@ func TestStatusFromSpanStatus(t *testing.T) {
 	}
 }
 
+func TestStatusFromHTTPStatusCode(t *testing.T) {
+	testCases := []struct {
+		attr   pdata.AttributeValue
+		status string
+	}{
+		{pdata.NewAttributeValueInt(200), ""},
+		{pdata.NewAttributeValueInt(404), ""},
+		{pdata.NewAttributeValueInt(500), "internal_error"},
+		{pdata.NewAttributeValueInt(501), "unimplemented"},
+		{pdata.NewAttributeValueInt(502), "internal_error"},
+		{pdata.NewAttributeValueInt(503), "unavailable"},
+		{pdata.NewAttributeValueInt(504), "deadline_exceeded"},
+		{pdata.NewAttributeValueString("500"), "internal_error"},
+		{pdata.NewAttributeValueString("error"), ""},
+	}
+
+	for _, test := range testCases {
+		attrs := pdata.NewAttributeMap()
+		attrs.Insert(conventions.AttributeHTTPStatusCode, test.attr)
+		assert.Equal(t, test.status, statusFromHTTPStatusCode(attrs), "%v", test.attr)
+	}
+	assert.Equal(t, "", statusFromHTTPStatusCode(pdata.NewAttributeMap()))
+}
+
+func TestStatusFromErrorAttributes(t *testing.T) {
+	testCases := []struct {
+		attrs  map[string]pdata.AttributeValue
+		status string
+	}{
+		{map[string]pdata.AttributeValue{}, ""},
+		{map[string]pdata.AttributeValue{"rpc.grpc.status_code": pdata.NewAttributeValueInt(5)}, "not_found"},
+		{map[string]pdata.AttributeValue{"rpc.grpc.status_code": pdata.NewAttributeValueInt(16)}, "unauthenticated"},
+		{map[string]pdata.AttributeValue{"rpc.grpc.status_code": pdata.NewAttributeValueInt(42)}, ""},
+		{map[string]pdata.AttributeValue{
+			"rpc.grpc.status_code":              pdata.NewAttributeValueInt(14),
+			conventions.AttributeHTTPStatusCode: pdata.NewAttributeValueInt(500),
+		}, "unavailable"},
+		{map[string]pdata.AttributeValue{conventions.AttributeHTTPStatusCode: pdata.NewAttributeValueInt(404)}, "not_found"},
+		{map[string]pdata.AttributeValue{conventions.AttributeHTTPStatusCode: pdata.NewAttributeValueInt(429)}, "resource_exhausted"},
+		{map[string]pdata.AttributeValue{conventions.AttributeHTTPStatusCode: pdata.NewAttributeValueInt(418)}, "invalid_argument"},
+		{map[string]pdata.AttributeValue{conventions.AttributeHTTPStatusCode: pdata.NewAttributeValueString("502")}, "internal_error"},
+		{map[string]pdata.AttributeValue{conventions.AttributeHTTPStatusCode: pdata.NewAttributeValueInt(200)}, ""},
+	}
+
+	for _, test := range testCases {
+		attrs := pdata.NewAttributeMap().InitFromMap(test.attrs)
+		assert.Equal(t, test.status, statusFromErrorAttributes(attrs), "%v", test.attrs)
+	}
+}
+
+func TestStatusMessage(t *testing.T) {
+	span := pdata.NewSpan()
+	span.Status().SetCode(pdata.StatusCodeError)
+	span.Attributes().InsertString("otel.status_description", "connection refused")
+
+	sentrySpan := convertToSentrySpan(span, pdata.NewInstrumentationLibrary(), map[string]string{})
+	assert.Equal(t, "connection refused", sentrySpan.Tags["status_message"])
+	assert.NotContains(t, sentrySpan.Tags, "otel.status_description")
+
+	transaction := transactionFromSpan(sentrySpan)
+	trace, ok := transaction.Contexts["trace"].(traceContextPayload)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"status_message": "connection refused"}, trace.Data)
+	assert.Equal(t, "internal_error", transactionTraceContext(transaction).Status)
+
+	// The message of the status takes precedence.
+	span.Status().SetMessage("timeout")
+	sentrySpan = convertToSentrySpan(span, pdata.NewInstrumentationLibrary(), map[string]string{})
+	assert.Equal(t, "timeout", sentrySpan.Tags["status_message"])
+
+	// Spans without status message have no data in their trace context.
+	sentrySpan = convertToSentrySpan(pdata.NewSpan(), pdata.NewInstrumentationLibrary(), map[string]string{})
+	assert.IsType(t, sentry.TraceContext{}, transactionFromSpan(sentrySpan).Contexts["trace"])
+}
+
+func TestFillSentrySpanErrorStatus(t *testing.T) {
+	span := pdata.NewSpan()
+	span.Status().SetCode(pdata.StatusCodeError)
+	library := pdata.NewInstrumentationLibrary()
+
+	var sentrySpan sentry.Span
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "internal_error", sentrySpan.Status)
+
+	span.Attributes().InsertInt(conventions.AttributeHTTPStatusCode, 403)
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "permission_denied", sentrySpan.Status)
+
+	// 4xx codes of spans without an error status are not errors.
+	span.Status().SetCode(pdata.StatusCodeUnset)
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{inferHTTPStatus: true})
+	assert.Equal(t, "unknown", sentrySpan.Status)
+}
+
+func TestFillSentrySpanInfersHTTPStatus(t *testing.T) {
+	span := pdata.NewSpan()
+	span.Attributes().InsertInt(conventions.AttributeHTTPStatusCode, 503)
+	library := pdata.NewInstrumentationLibrary()
+
+	var sentrySpan sentry.Span
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{inferHTTPStatus: true})
+	assert.Equal(t, "unavailable", sentrySpan.Status)
+
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "unknown", sentrySpan.Status)
+
+	// A status set by the instrumentation is kept.
+	span.Status().SetCode(pdata.StatusCodeOk)
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{inferHTTPStatus: true})
+	assert.Equal(t, "ok", sentrySpan.Status)
+}
+
+func TestFillSentrySpanLibraryTags(t *testing.T) {
+	span := pdata.NewSpan()
+	library := pdata.NewInstrumentationLibrary()
+	library.SetName("io.opentelemetry.jdbc")
+	library.SetVersion("1.2.0")
+
+	var sentrySpan sentry.Span
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "io.opentelemetry.jdbc", sentrySpan.Tags["library_name"])
+	assert.Equal(t, "1.2.0", sentrySpan.Tags["library_version"])
+
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{omitLibraryTags: true})
+	assert.NotContains(t, sentrySpan.Tags, "library_name")
+	assert.NotContains(t, sentrySpan.Tags, "library_version")
+}
+
 type ClassifyOrphanSpanTestCase struct {
 	testName string
 	// input
@@ -472,22 +760,31 @@ func TestGenerateTransactions(t *testing.T) {
 
 	transactions := generateTransactions(transactionMap, orphanSpans)
 
-	assert.Len(t, transactions, 4)
+	require.Len(t, transactions, 4)
+	// By start timestamp, then span id for rootSpan1 and childSpan1 of the same trace.
+	var spanIDs []string
+	for _, transaction := range transactions {
+		spanIDs = append(spanIDs, transactionTraceContext(transaction).SpanID)
+	}
+	assert.Equal(t, []string{rootSpan2.SpanID, orphanSpan1.SpanID, rootSpan1.SpanID, childSpan1.SpanID}, spanIDs)
 }
 
 type mockTransport struct {
-	called       bool
-	transactions []*sentry.Event
+	mu        sync.Mutex
+	called    bool
+	err       error
+	envelopes [][]byte
 }
 
-func (t *mockTransport) SendTransactions(transactions []*sentry.Event) {
-	t.transactions = transactions
+func (t *mockTransport) SendEnvelope(_ context.Context, envelope []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.called = true
-}
-
-func (t *mockTransport) Configure(options sentry.ClientOptions) {}
-func (t *mockTransport) Flush(ctx context.Context) bool {
-	return true
+	if t.err != nil {
+		return t.err
+	}
+	t.envelopes = append(t.envelopes, envelope)
+	return nil
 }
 
 type PushTraceDataTestCase struct {
@@ -551,3 +848,241 @@ func TestPushTraceData(t *testing.T) {
 		})
 	}
 }
+
+func TestPushTraceDataReleasesSpans(t *testing.T) {
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport}
+
+	first := benchmarkTraces(1)
+	first.ResourceSpans().At(0).Resource().Attributes().InsertString("first", "true")
+	assert.NoError(t, s.pushTraceData(context.Background(), first))
+	assert.NoError(t, s.pushTraceData(context.Background(), benchmarkTraces(1)))
+	require.Len(t, transport.envelopes, 2)
+
+	// The tags of the pooled spans of the first batch are not left in the second one.
+	var payload struct {
+		Tags  map[string]string `json:"tags"`
+		Spans []struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"spans"`
+	}
+	lines := bytes.SplitN(transport.envelopes[1], []byte("\n"), 3)
+	require.Len(t, lines, 3)
+	require.NoError(t, json.Unmarshal(lines[2], &payload))
+	assert.NotContains(t, payload.Tags, "first")
+	require.Len(t, payload.Spans, 9)
+	for _, span := range payload.Spans {
+		assert.NotContains(t, span.Tags, "first")
+		assert.Equal(t, "postgresql", span.Tags[conventions.AttributeDBSystem])
+	}
+}
+
+func TestSpanBatchRelease(t *testing.T) {
+	batch := newSpanBatch()
+	rs := benchmarkTraces(1).ResourceSpans().At(0)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	sentrySpan := batch.convertSpan(ils.Spans().At(1), ils.InstrumentationLibrary(), map[string]string{"host": "a"})
+	tags := sentrySpan.Tags
+	assert.Equal(t, "a", tags["host"])
+
+	batch.release()
+	assert.Equal(t, sentry.Span{}, *sentrySpan)
+	assert.Empty(t, tags)
+	assert.Empty(t, batch.spans)
+	assert.Empty(t, batch.tags)
+}
+
+func TestPushTraceDataConvertsResourcesConcurrently(t *testing.T) {
+	// The spans of each trace are spread across resources, the children before their root.
+	td := resourcePerSpanTraces(benchmarkTraces(8))
+
+	sequential := &mockTransport{}
+	s := &SentryExporter{transport: sequential, workers: 1}
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+
+	concurrent := &mockTransport{}
+	s = &SentryExporter{transport: concurrent, workers: 4}
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+
+	want := transactionPayloads(t, sequential.envelopes)
+	require.Len(t, want, 8)
+	assert.Equal(t, want, transactionPayloads(t, concurrent.envelopes))
+}
+
+func TestPushTraceDataConcurrentCalls(t *testing.T) {
+	transport := &lockedTransport{}
+	s := &SentryExporter{transport: transport, workers: 4}
+	td := resourcePerSpanTraces(benchmarkTraces(4))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.pushTraceData(context.Background(), td))
+		}()
+	}
+	wg.Wait()
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	assert.Len(t, transport.envelopes, 8*4)
+	for _, payload := range transactionPayloads(t, transport.envelopes) {
+		assert.Len(t, payload["spans"], 9)
+	}
+}
+
+// resourcePerSpanTraces returns a copy of td with every span in a resource of its own, in
+// reverse order.
+func resourcePerSpanTraces(td pdata.Traces) pdata.Traces {
+	out := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	for i := rss.Len() - 1; i >= 0; i-- {
+		rs := rss.At(i)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := ilss.Len() - 1; j >= 0; j-- {
+			ils := ilss.At(j)
+			spans := ils.Spans()
+			for k := spans.Len() - 1; k >= 0; k-- {
+				outRs := out.ResourceSpans().AppendEmpty()
+				rs.Resource().CopyTo(outRs.Resource())
+				outRs.Resource().Attributes().InsertInt("index", int64(k))
+				outIls := outRs.InstrumentationLibrarySpans().AppendEmpty()
+				ils.InstrumentationLibrary().CopyTo(outIls.InstrumentationLibrary())
+				spans.At(k).CopyTo(outIls.Spans().AppendEmpty())
+			}
+		}
+	}
+	return out
+}
+
+// transactionPayloads decodes the transactions of envelopes by their root span id, without
+// their random event id.
+func transactionPayloads(t *testing.T, envelopes [][]byte) map[string]map[string]interface{} {
+	payloads := make(map[string]map[string]interface{})
+	for _, envelope := range envelopes {
+		lines := bytes.SplitN(envelope, []byte("\n"), 3)
+		require.Len(t, lines, 3)
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(lines[2], &payload))
+		delete(payload, "event_id")
+		trace := payload["contexts"].(map[string]interface{})["trace"].(map[string]interface{})
+		payloads[trace["span_id"].(string)] = payload
+	}
+	return payloads
+}
+
+// benchmarkTraces returns traces of one resource with the given number of
+// transactions, each a root span with nine HTTP and database child spans.
+func benchmarkTraces(transactions int) pdata.Traces {
+	traces := pdata.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	rs.Resource().Attributes().InsertString(conventions.AttributeHostName, "checkout-7d9f8b6c5-x2x4q")
+	ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+	ils.InstrumentationLibrary().SetName("otel-go")
+	ils.InstrumentationLibrary().SetVersion("0.20.0")
+	spans := ils.Spans()
+	for i := 0; i < transactions; i++ {
+		traceID := pdata.NewTraceID([16]byte{byte(i >> 8), byte(i), 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14})
+		rootID := pdata.NewSpanID([8]byte{byte(i >> 8), byte(i), 0, 0, 0, 0, 0, 1})
+		for j := 0; j < 10; j++ {
+			span := spans.AppendEmpty()
+			span.SetTraceID(traceID)
+			span.SetSpanID(pdata.NewSpanID([8]byte{byte(i >> 8), byte(i), byte(j), 0, 0, 0, 0, 1}))
+			span.SetStartTimestamp(pdata.Timestamp(1622548800000000000 + j))
+			span.SetEndTimestamp(pdata.Timestamp(1622548800500000000 + j))
+			span.Status().SetCode(pdata.StatusCodeOk)
+			attrs := span.Attributes()
+			if j == 0 {
+				span.SetName("/api/checkout")
+				span.SetKind(pdata.SpanKindServer)
+				attrs.InsertString(conventions.AttributeHTTPMethod, "POST")
+				attrs.InsertString(conventions.AttributeHTTPTarget, "/api/checkout")
+				attrs.InsertInt(conventions.AttributeHTTPStatusCode, 200)
+				continue
+			}
+			span.SetParentSpanID(rootID)
+			span.SetName("SELECT orders")
+			span.SetKind(pdata.SpanKindClient)
+			attrs.InsertString(conventions.AttributeDBSystem, "postgresql")
+			attrs.InsertString(conventions.AttributeDBStatement, "SELECT * FROM orders WHERE id = $1")
+			attrs.InsertInt("db.rows", int64(j))
+			attrs.InsertBool("db.cached", j%2 == 0)
+		}
+	}
+	return traces
+}
+
+func BenchmarkConvertToSentrySpan(b *testing.B) {
+	rs := benchmarkTraces(1).ResourceSpans().At(0)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	span := ils.Spans().At(1)
+	resourceTags := generateTagsFromResource(rs.Resource())
+
+	b.Run("unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			convertToSentrySpan(span, ils.InstrumentationLibrary(), resourceTags)
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			batch := newSpanBatch()
+			batch.convertSpan(span, ils.InstrumentationLibrary(), resourceTags)
+			batch.release()
+		}
+	})
+}
+
+func BenchmarkPushTraceData(b *testing.B) {
+	td := benchmarkTraces(100)
+	s := &SentryExporter{transport: &nopTransport{}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := s.pushTraceData(context.Background(), td); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// lockedTransport records the envelopes of concurrent calls.
+type lockedTransport struct {
+	mu        sync.Mutex
+	envelopes [][]byte
+}
+
+func (t *lockedTransport) SendEnvelope(_ context.Context, envelope []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.envelopes = append(t.envelopes, envelope)
+	return nil
+}
+
+func BenchmarkPushTraceDataResources(b *testing.B) {
+	td := pdata.NewTraces()
+	for i := 0; i < 8; i++ {
+		benchmarkTraces(100).ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	}
+	for _, workers := range []int{1, 4} {
+		s := &SentryExporter{transport: &nopTransport{}, workers: workers}
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := s.pushTraceData(context.Background(), td); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// nopTransport drops the envelopes, so that benchmarks measure the conversion.
+type nopTransport struct{}
+
+func (nopTransport) SendEnvelope(context.Context, []byte) error {
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// SessionsExporter is implemented by the traces exporter of the Sentry exporter. It sends
+// release health session aggregates, e.g. computed from spans by other components.
+type SessionsExporter interface {
+	ExportSessions(ctx context.Context, aggregates []SessionAggregates) error
+}
+
+// SessionAggregates are the session counts of a release in an environment.
+// See https://develop.sentry.dev/sdk/sessions/#session-aggregates-payload for details.
+type SessionAggregates struct {
+	Release     string
+	Environment string
+	Buckets     []SessionBucket
+}
+
+// SessionBucket counts the sessions started in an interval, by their final status.
+type SessionBucket struct {
+	Started  time.Time
+	Exited   int
+	Errored  int
+	Abnormal int
+	Crashed  int
+}
+
+// sentryTracesExporter adds the sessions path to the traces exporter.
+type sentryTracesExporter struct {
+	component.TracesExporter
+	exporter *SentryExporter
+}
+
+var _ SessionsExporter = (*sentryTracesExporter)(nil)
+
+func (e *sentryTracesExporter) ExportSessions(ctx context.Context, aggregates []SessionAggregates) error {
+	return e.exporter.sendSessions(ctx, aggregates)
+}
+
+// sendSessions sends one envelope per release and environment.
+func (s *SentryExporter) sendSessions(ctx context.Context, aggregates []SessionAggregates) error {
+	var errs []error
+	envelopes := make([][]byte, 0, len(aggregates))
+	sentAt := time.Now()
+	for _, a := range aggregates {
+		s.release.applySessions(&a)
+		envelope, err := sessionsToEnvelope(a, sentAt)
+		if err != nil {
+			errs = append(errs, consumererror.Permanent(err))
+			continue
+		}
+		envelopes = append(envelopes, envelope)
+	}
+	sent, sendErrs := s.sendEnvelopes(ctx, envelopes)
+	return s.pushError(sent, append(errs, sendErrs...))
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+func TestCreateSentryExporterImplementsSessionsExporter(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	exp, err := CreateSentryExporter(cfg, component.ExporterCreateParams{Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	_, ok := exp.(SessionsExporter)
+	assert.True(t, ok)
+}
+
+func TestExportSessions(t *testing.T) {
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport, logger: zap.NewNop()}
+	exp := &sentryTracesExporter{exporter: s}
+
+	started := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	err := exp.ExportSessions(context.Background(), []SessionAggregates{
+		{Release: "checkout@1.2.3", Environment: "production", Buckets: []SessionBucket{{Started: started, Exited: 1}}},
+		{Release: "checkout@1.2.4", Buckets: []SessionBucket{{Started: started, Errored: 1}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, transport.envelopes, 2)
+	assert.Contains(t, string(transport.envelopes[0]), `"release":"checkout@1.2.3"`)
+	assert.Contains(t, string(transport.envelopes[1]), `"release":"checkout@1.2.4"`)
+}
+
+func TestExportSessionsFailure(t *testing.T) {
+	transport := &mockTransport{err: errors.New("unavailable")}
+	s := &SentryExporter{transport: transport, logger: zap.NewNop()}
+	exp := &sentryTracesExporter{exporter: s}
+
+	err := exp.ExportSessions(context.Background(), []SessionAggregates{{Release: "checkout@1.2.3"}})
+	assert.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+}
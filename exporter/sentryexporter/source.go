@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	// transactionSourceKey is the key of the source of the name of HTTP server spans in their data,
+	// and of transactions in their extra data, until it is sent in the transaction info.
+	transactionSourceKey = "_transaction_source"
+
+	// Sources of transaction names, telling Sentry whether it can group transactions by their names.
+	// See https://develop.sentry.dev/sdk/event-payloads/transaction/#transaction-annotations.
+	transactionSourceCustom = "custom"
+	transactionSourceRoute  = "route"
+	transactionSourceURL    = "url"
+)
+
+// transactionInfo is the transaction annotation of a transaction payload.
+type transactionInfo struct {
+	Source string `json:"source"`
+}
+
+// httpServerTransactionSource returns the source of the name of an HTTP server span, route if it
+// is named after its http.route, or url for a raw path.
+func httpServerTransactionSource(attrs pdata.AttributeMap) string {
+	if route, ok := attrs.Get(conventions.AttributeHTTPRoute); ok && route.StringVal() != "" {
+		return transactionSourceRoute
+	}
+	return transactionSourceURL
+}
+
+// extractTransactionSource returns data without the transaction source, and the source, if set.
+func extractTransactionSource(data map[string]interface{}) (map[string]interface{}, string) {
+	source, ok := data[transactionSourceKey].(string)
+	if !ok {
+		return data, ""
+	}
+
+	rest := make(map[string]interface{}, len(data)-1)
+	for k, v := range data {
+		if k != transactionSourceKey {
+			rest[k] = v
+		}
+	}
+	return rest, source
+}
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestTransactionSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   pdata.SpanKind
+		attrs  map[string]string
+		source string
+	}{
+		{
+			name:   "route",
+			kind:   pdata.SpanKindServer,
+			attrs:  map[string]string{"http.method": "GET", "http.route": "/users/{id}"},
+			source: "route",
+		},
+		{
+			name:   "raw path",
+			kind:   pdata.SpanKindServer,
+			attrs:  map[string]string{"http.method": "GET"},
+			source: "url",
+		},
+		{
+			name:   "renamed",
+			kind:   pdata.SpanKindServer,
+			attrs:  map[string]string{"http.method": "GET", "http.route": "/users/{id}", "sentry.description": "Show user"},
+			source: "custom",
+		},
+		{
+			name:   "not http",
+			kind:   pdata.SpanKindConsumer,
+			attrs:  map[string]string{"messaging.system": "kafka"},
+			source: "custom",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			span := pdata.NewSpan()
+			span.SetName("/users/42")
+			span.SetKind(test.kind)
+			for k, v := range test.attrs {
+				span.Attributes().InsertString(k, v)
+			}
+
+			transaction := transactionFromSpan(convertToSentrySpan(span, pdata.NewInstrumentationLibrary(), map[string]string{}))
+			payload, err := json.Marshal(newTransactionPayload(transaction))
+			require.NoError(t, err)
+
+			var decoded map[string]interface{}
+			require.NoError(t, json.Unmarshal(payload, &decoded))
+			assert.Equal(t, map[string]interface{}{"source": test.source}, decoded["transaction_info"])
+			assert.NotContains(t, decoded, "extra")
+		})
+	}
+}
+
+func TestChildSpanWithoutTransactionSource(t *testing.T) {
+	root := pdata.NewSpan()
+	root.SetKind(pdata.SpanKindServer)
+	root.Attributes().InsertString("http.method", "GET")
+	child := pdata.NewSpan()
+	child.SetKind(pdata.SpanKindServer)
+	child.Attributes().InsertString("http.method", "GET")
+
+	library := pdata.NewInstrumentationLibrary()
+	transaction := transactionFromSpan(convertToSentrySpan(root, library, map[string]string{}))
+	transaction.Spans = append(transaction.Spans, convertToSentrySpan(child, library, map[string]string{}))
+
+	p := newTransactionPayload(transaction)
+	require.Len(t, p.Spans, 1)
+	assert.NotContains(t, p.Spans[0].Data, transactionSourceKey)
+}
@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// spanAttributesKey is the key of the attributes of spans in their data, and of transactions in
+// their extra data, with the span data mode. Until the transactions are assembled, attributes are
+// kept as tags, that the request, user and contexts are set from.
+const spanAttributesKey = "_attributes"
+
+// defaultSpanDataTags are the attributes kept as tags with the span data mode, those Sentry
+// Performance is most often searched and grouped by.
+var defaultSpanDataTags = []string{
+	conventions.AttributeHTTPMethod,
+	conventions.AttributeHTTPStatusCode,
+	conventions.AttributeHTTPRoute,
+	conventions.AttributeDBSystem,
+	conventions.AttributeRPCSystem,
+	conventions.AttributeRPCService,
+	messagingSystemAttribute,
+}
+
+// spanDataOptions are the settings of the span data mode, in which span attributes are sent in
+// the data of spans, and only an allowlist of them as tags.
+type spanDataOptions struct {
+	enabled bool
+	// tags are the attributes also kept as tags.
+	tags map[string]bool
+}
+
+func newSpanDataOptions(settings SpanDataSettings) spanDataOptions {
+	options := spanDataOptions{enabled: settings.Enabled}
+	if !options.enabled {
+		return options
+	}
+	tags := settings.Tags
+	if tags == nil {
+		tags = defaultSpanDataTags
+	}
+	options.tags = make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		options.tags[tag] = true
+	}
+	return options
+}
+
+// apply moves the span attributes of transactions and their spans from their tags to their data,
+// except the allowlisted tags. Attributes that are not tags, such as arrays and maps, are added
+// to the data too, while those already sent otherwise, such as the request URL, are not. The
+// attributes that filter does not allow are left as tags, for the filter to remove them.
+func (o spanDataOptions) apply(transactions []*sentry.Event, filter *tagFilter) {
+	if !o.enabled {
+		return
+	}
+	for _, transaction := range transactions {
+		if attributes, ok := transaction.Extra[spanAttributesKey].(map[string]interface{}); ok {
+			delete(transaction.Extra, spanAttributesKey)
+			trace := traceContextPayload{TraceContext: transactionTraceContext(transaction)}
+			if payload, ok := transaction.Contexts["trace"].(traceContextPayload); ok {
+				trace = payload
+			}
+			if trace.Data = o.moveAttributes(attributes, transaction.Tags, trace.Data, filter); trace.Data != nil {
+				transaction.Contexts["trace"] = trace
+			}
+		}
+
+		for _, span := range transaction.Spans {
+			attributes, ok := span.Data[spanAttributesKey].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			delete(span.Data, spanAttributesKey)
+			span.Data = o.moveAttributes(attributes, span.Tags, span.Data, filter)
+			if len(span.Data) == 0 {
+				span.Data = nil
+			}
+		}
+	}
+}
+
+// moveAttributes moves the attributes that filter allows from tags to data, which is returned,
+// allocated if needed.
+func (o spanDataOptions) moveAttributes(attributes map[string]interface{}, tags map[string]string, data map[string]interface{}, filter *tagFilter) map[string]interface{} {
+	for key, value := range attributes {
+		if o.tags[key] || !filter.allows(key) {
+			continue
+		}
+		if tag, ok := tags[key]; ok {
+			// The tag may have been normalized, such as a scrubbed db.statement.
+			if _, ok := value.(string); ok {
+				value = tag
+			}
+			delete(tags, key)
+		} else if isTagValue(value) {
+			// The attribute was turned into something else, such as the request or user.
+			continue
+		}
+		if data == nil {
+			data = make(map[string]interface{}, len(attributes))
+		}
+		data[key] = value
+	}
+	return data
+}
+
+// isTagValue returns whether a value of attributeValue is also sent as a tag.
+func isTagValue(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int64, float64:
+		return true
+	}
+	return false
+}
+
+// generateStructuredDataFromAttributes returns all the attributes with their values, including
+// arrays and maps, or nil if there are none.
+func generateStructuredDataFromAttributes(attrs pdata.AttributeMap) map[string]interface{} {
+	if attrs.Len() == 0 {
+		return nil
+	}
+	data := make(map[string]interface{}, attrs.Len())
+	attrs.Range(func(key string, attr pdata.AttributeValue) bool {
+		data[key] = attributeValue(attr)
+		return true
+	})
+	return data
+}
+
+// attributeValue returns the value of an attribute as a JSON value, or nil if it is empty.
+func attributeValue(attr pdata.AttributeValue) interface{} {
+	switch attr.Type() {
+	case pdata.AttributeValueTypeString:
+		return attr.StringVal()
+	case pdata.AttributeValueTypeBool:
+		return attr.BoolVal()
+	case pdata.AttributeValueTypeInt:
+		return attr.IntVal()
+	case pdata.AttributeValueTypeDouble:
+		return attr.DoubleVal()
+	case pdata.AttributeValueTypeArray:
+		values := attr.ArrayVal()
+		array := make([]interface{}, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			array = append(array, attributeValue(values.At(i)))
+		}
+		return array
+	case pdata.AttributeValueTypeMap:
+		values := attr.MapVal()
+		object := make(map[string]interface{}, values.Len())
+		values.Range(func(key string, value pdata.AttributeValue) bool {
+			object[key] = attributeValue(value)
+			return true
+		})
+		return object
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func TestPushTraceDataSpanData(t *testing.T) {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+
+	root := spans.AppendEmpty()
+	root.SetName("POST /charge")
+	root.SetKind(pdata.SpanKindServer)
+	root.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	root.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	root.Attributes().InsertString(conventions.AttributeHTTPMethod, "POST")
+	root.Attributes().InsertString(conventions.AttributeHTTPURL, "https://example.com/charge")
+	root.Attributes().InsertString("cart.id", "c0ffee")
+	items := pdata.NewAttributeValueArray()
+	items.ArrayVal().Append(pdata.NewAttributeValueString("book"))
+	root.Attributes().Insert("cart.items", items)
+
+	child := spans.AppendEmpty()
+	child.SetName("SELECT cards")
+	child.SetTraceID(root.TraceID())
+	child.SetSpanID(pdata.NewSpanID([8]byte{2, 2, 3, 4, 5, 6, 7, 8}))
+	child.SetParentSpanID(root.SpanID())
+	child.Attributes().InsertString(conventions.AttributeDBSystem, "postgresql")
+	child.Attributes().InsertString(conventions.AttributeDBStatement, "SELECT * FROM cards WHERE number = '4111111111111111'")
+	child.Attributes().InsertInt("db.rows", 1)
+
+	transport := &mockTransport{}
+	s := &SentryExporter{
+		transport: transport,
+		conversion: conversionOptions{
+			scrubSQL: true,
+			spanData: newSpanDataOptions(SpanDataSettings{Enabled: true}),
+		},
+	}
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+	require.Len(t, transport.envelopes, 1)
+
+	lines := bytes.Split(bytes.TrimSuffix(transport.envelopes[0], []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+	var payload struct {
+		Tags     map[string]string      `json:"tags"`
+		Extra    map[string]interface{} `json:"extra"`
+		Contexts struct {
+			Trace struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"trace"`
+		} `json:"contexts"`
+		Request struct {
+			URL string `json:"url"`
+		} `json:"request"`
+		Spans []struct {
+			Tags map[string]string      `json:"tags"`
+			Data map[string]interface{} `json:"data"`
+		} `json:"spans"`
+	}
+	require.NoError(t, json.Unmarshal(lines[2], &payload))
+
+	// The allowlisted attributes, resource attributes and derived tags are kept as tags.
+	assert.Equal(t, "POST", payload.Tags[conventions.AttributeHTTPMethod])
+	assert.Equal(t, "checkout", payload.Tags[conventions.AttributeServiceName])
+	assert.Equal(t, "SPAN_KIND_SERVER", payload.Tags["span_kind"])
+	assert.NotContains(t, payload.Tags, "cart.id")
+	assert.NotContains(t, payload.Extra, spanAttributesKey)
+	assert.Equal(t, map[string]interface{}{
+		"cart.id":    "c0ffee",
+		"cart.items": []interface{}{"book"},
+	}, payload.Contexts.Trace.Data)
+	assert.Equal(t, "https://example.com/charge", payload.Request.URL)
+
+	require.Len(t, payload.Spans, 1)
+	assert.Equal(t, "postgresql", payload.Spans[0].Tags[conventions.AttributeDBSystem])
+	assert.NotContains(t, payload.Spans[0].Tags, conventions.AttributeDBStatement)
+	assert.Equal(t, map[string]interface{}{
+		conventions.AttributeDBStatement: "SELECT * FROM cards WHERE number = %s",
+		"db.rows":                        float64(1),
+	}, payload.Spans[0].Data)
+}
+
+func TestSpanDataOptions(t *testing.T) {
+	options := newSpanDataOptions(SpanDataSettings{})
+	assert.False(t, options.enabled)
+
+	options = newSpanDataOptions(SpanDataSettings{Enabled: true})
+	assert.True(t, options.tags[conventions.AttributeHTTPStatusCode])
+
+	options = newSpanDataOptions(SpanDataSettings{Enabled: true, Tags: []string{"tenant"}})
+	assert.Equal(t, map[string]bool{"tenant": true}, options.tags)
+
+	attrs := pdata.NewAttributeMap()
+	labels := pdata.NewAttributeValueMap()
+	labels.MapVal().InsertString("team", "payments")
+	attrs.Insert("labels", labels)
+	attrs.InsertDouble("ratio", 0.5)
+	assert.Equal(t, map[string]interface{}{
+		"labels": map[string]interface{}{"team": "payments"},
+		"ratio":  0.5,
+	}, generateStructuredDataFromAttributes(attrs))
+	assert.Nil(t, generateStructuredDataFromAttributes(pdata.NewAttributeMap()))
+}
+
+func TestPushTraceDataSpanDataFiltersTags(t *testing.T) {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+
+	root := spans.AppendEmpty()
+	root.SetName("POST /charge")
+	root.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	root.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	root.Attributes().InsertString("cart.id", "c0ffee")
+	root.Attributes().InsertString("card.number", "4111111111111111")
+
+	child := spans.AppendEmpty()
+	child.SetName("SELECT cards")
+	child.SetTraceID(root.TraceID())
+	child.SetSpanID(pdata.NewSpanID([8]byte{2, 2, 3, 4, 5, 6, 7, 8}))
+	child.SetParentSpanID(root.SpanID())
+	child.Attributes().InsertString("card.number", "4111111111111111")
+	child.Attributes().InsertInt("db.rows", 1)
+
+	filter, err := newTagFilter(nil, []string{"card.*"})
+	require.NoError(t, err)
+	transport := &mockTransport{}
+	s := &SentryExporter{
+		transport:  transport,
+		tagFilter:  filter,
+		conversion: conversionOptions{spanData: newSpanDataOptions(SpanDataSettings{Enabled: true})},
+	}
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+	require.Len(t, transport.envelopes, 1)
+
+	lines := bytes.Split(bytes.TrimSuffix(transport.envelopes[0], []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+	var payload struct {
+		Tags     map[string]string `json:"tags"`
+		Contexts struct {
+			Trace struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"trace"`
+		} `json:"contexts"`
+		Spans []struct {
+			Tags map[string]string      `json:"tags"`
+			Data map[string]interface{} `json:"data"`
+		} `json:"spans"`
+	}
+	require.NoError(t, json.Unmarshal(lines[2], &payload))
+
+	// Excluded attributes are neither sent as tags nor as data.
+	assert.NotContains(t, payload.Tags, "card.number")
+	assert.Equal(t, map[string]interface{}{"cart.id": "c0ffee"}, payload.Contexts.Trace.Data)
+	require.Len(t, payload.Spans, 1)
+	assert.NotContains(t, payload.Spans[0].Tags, "card.number")
+	assert.Equal(t, map[string]interface{}{"db.rows": float64(1)}, payload.Spans[0].Data)
+}
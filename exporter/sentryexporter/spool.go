@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/envelopestorage"
+)
+
+// spoolRetryInterval is the time between attempts to deliver spooled envelopes.
+const spoolRetryInterval = 10 * time.Second
+
+// defaultSendWorkers is the default maximum number of envelopes of a push sent concurrently.
+const defaultSendWorkers = 8
+
+// start looks up the configured envelope storage and starts delivering envelopes spooled by previous runs,
+// or kept in memory.
+func (s *SentryExporter) start(ctx context.Context, host component.Host) error {
+	if s.pending != nil {
+		s.wg.Add(1)
+		go s.drainPending()
+	}
+
+	if s.storageID == nil {
+		return nil
+	}
+
+	ext, ok := host.GetExtensions()[*s.storageID]
+	if !ok {
+		return fmt.Errorf("storage extension %q not found", s.storageID.String())
+	}
+	storage, ok := ext.(envelopestorage.Extension)
+	if !ok {
+		return fmt.Errorf("extension %q is not an envelope storage", s.storageID.String())
+	}
+
+	spool, err := storage.GetSpool(ctx, component.KindExporter, s.id, string(s.dataType))
+	if err != nil {
+		return fmt.Errorf("failed to get envelope spool: %w", err)
+	}
+	s.spool = spool
+
+	s.wg.Add(1)
+	go s.drainSpool()
+
+	return nil
+}
+
+func (s *SentryExporter) shutdown(context.Context) error {
+	close(s.stopCh)
+	s.wg.Wait()
+
+	if closer, ok := s.transport.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// sendEnvelope sends an envelope to Sentry. If that fails and a storage is
+// configured, the envelope is spooled and delivered later instead. Without a
+// storage, it is kept in memory if pending envelopes are enabled.
+func (s *SentryExporter) sendEnvelope(ctx context.Context, envelope []byte) error {
+	err := s.transport.SendEnvelope(ctx, envelope)
+	if err == nil {
+		return nil
+	}
+
+	if s.spool == nil {
+		if s.pending == nil {
+			return err
+		}
+		if evicted := s.pending.push(envelope); evicted > 0 {
+			s.logger.Warn("Dropped pending envelopes over the memory limit", zap.Int("evicted", evicted))
+		}
+		s.logger.Debug("Kept envelope in memory for later delivery", zap.Error(err))
+		return nil
+	}
+
+	if spoolErr := s.spool.Push(ctx, envelope); spoolErr != nil {
+		return fmt.Errorf("%v, and spooling the envelope failed: %w", err, spoolErr)
+	}
+	s.logger.Debug("Spooled envelope for later delivery", zap.Error(err))
+	return nil
+}
+
+// sendEnvelopes sends envelopes with sendEnvelope, up to s.sendWorkers at a time. It returns the
+// number of envelopes that were delivered, spooled or kept pending, and the errors of the others.
+func (s *SentryExporter) sendEnvelopes(ctx context.Context, envelopes [][]byte) (int, []error) {
+	errs := make([]error, len(envelopes))
+	workers := s.sendWorkers
+	if workers > len(envelopes) {
+		workers = len(envelopes)
+	}
+	if workers <= 1 {
+		for i, envelope := range envelopes {
+			errs[i] = s.sendEnvelope(ctx, envelope)
+		}
+	} else {
+		next := make(chan int)
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					errs[i] = s.sendEnvelope(ctx, envelopes[i])
+				}
+			}()
+		}
+		for i := range envelopes {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+	}
+
+	sent := 0
+	failed := errs[:0]
+	for _, err := range errs {
+		if err == nil {
+			sent++
+		} else {
+			failed = append(failed, err)
+		}
+	}
+	return sent, failed
+}
+
+// pushError combines the errors of a push, of which sent envelopes or occurrences were accepted.
+// The exporter helper retries a failed push as a whole, so once any were accepted, the errors are
+// permanent, not to send those again; undeliverable envelopes are only kept with a storage or
+// pending envelopes, and the dropped ones are logged and counted. A push that was only rate
+// limited is retried once the rate limits end.
+func (s *SentryExporter) pushError(sent int, errs []error) error {
+	err := consumererror.Combine(errs)
+	if err == nil {
+		return nil
+	}
+	if sent > 0 || consumererror.IsPermanent(err) {
+		s.recordDropped(errs)
+		if sent > 0 {
+			return consumererror.Permanent(err)
+		}
+		return err
+	}
+
+	var until time.Time
+	for _, e := range errs {
+		var limited *rateLimitedError
+		if !errors.As(e, &limited) {
+			return err
+		}
+		if limited.until.After(until) {
+			until = limited.until
+		}
+	}
+	return exporterhelper.NewThrottleRetry(err, time.Until(until))
+}
+
+// recordDropped logs and counts the envelopes and occurrences of a push that could not be sent, but
+// are not retried.
+func (s *SentryExporter) recordDropped(errs []error) {
+	var dropped []error
+	for _, err := range errs {
+		if !consumererror.IsPermanent(err) {
+			dropped = append(dropped, err)
+		}
+	}
+	if len(dropped) == 0 {
+		return
+	}
+	s.logger.Warn("Dropped envelopes that could not be sent, since the rest of their batch was delivered",
+		zap.Int("dropped", len(dropped)), zap.Error(consumererror.Combine(dropped)))
+	_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Upsert(tagExporterKey, s.id.String())},
+		mDroppedEnvelopes.M(int64(len(dropped))))
+}
+
+func (s *SentryExporter) drainSpool() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(spoolRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.resendSpooled(context.Background())
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// resendSpooled sends spooled envelopes oldest first until the spool is empty or sending fails.
+func (s *SentryExporter) resendSpooled(ctx context.Context) {
+	for {
+		entry, err := s.spool.Peek(ctx)
+		if err != nil {
+			s.logger.Warn("Failed to read spooled envelope", zap.Error(err))
+			return
+		}
+		if entry == nil {
+			return
+		}
+
+		if err = s.transport.SendEnvelope(ctx, entry.Envelope); err != nil {
+			s.logger.Debug("Failed to resend spooled envelope", zap.Error(err))
+			return
+		}
+
+		if err = s.spool.Remove(ctx, entry.ID); err != nil {
+			s.logger.Warn("Failed to remove delivered envelope from spool", zap.Error(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/envelopestorage"
+)
+
+type mockSpool struct {
+	entries []*envelopestorage.Entry
+	seq     int
+}
+
+func (s *mockSpool) Push(_ context.Context, envelope []byte) error {
+	s.seq++
+	s.entries = append(s.entries, &envelopestorage.Entry{ID: strconv.Itoa(s.seq), Envelope: envelope})
+	return nil
+}
+
+func (s *mockSpool) Peek(context.Context) (*envelopestorage.Entry, error) {
+	if len(s.entries) == 0 {
+		return nil, nil
+	}
+	return s.entries[0], nil
+}
+
+func (s *mockSpool) Remove(_ context.Context, id string) error {
+	for i, entry := range s.entries {
+		if entry.ID == id {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *mockSpool) Len() int {
+	return len(s.entries)
+}
+
+type mockStorage struct {
+	component.Component
+	spool *mockSpool
+	name  string
+}
+
+func (m *mockStorage) GetSpool(_ context.Context, _ component.Kind, _ config.ComponentID, name string) (envelopestorage.Spool, error) {
+	m.name = name
+	return m.spool, nil
+}
+
+type mockHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h *mockHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+func TestSendEnvelopeSpoolsOnFailure(t *testing.T) {
+	transport := &mockTransport{err: errors.New("unavailable")}
+	spool := &mockSpool{}
+	s := &SentryExporter{transport: transport, spool: spool, logger: zap.NewNop()}
+
+	require.NoError(t, s.sendEnvelope(context.Background(), []byte("envelope")))
+	assert.Equal(t, 1, spool.Len())
+
+	transport.err = nil
+	s.resendSpooled(context.Background())
+	assert.Equal(t, 0, spool.Len())
+	assert.Equal(t, [][]byte{[]byte("envelope")}, transport.envelopes)
+}
+
+func TestSendEnvelopeWithoutSpool(t *testing.T) {
+	transport := &mockTransport{err: errors.New("unavailable")}
+	s := &SentryExporter{transport: transport, logger: zap.NewNop()}
+
+	assert.Error(t, s.sendEnvelope(context.Background(), []byte("envelope")))
+}
+
+// failingTransport fails to send the envelopes in fail, and records the others.
+type failingTransport struct {
+	mu        sync.Mutex
+	fail      map[string]error
+	envelopes []string
+}
+
+func (t *failingTransport) SendEnvelope(_ context.Context, envelope []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err, ok := t.fail[string(envelope)]; ok {
+		return err
+	}
+	t.envelopes = append(t.envelopes, string(envelope))
+	return nil
+}
+
+func TestSendEnvelopesConcurrently(t *testing.T) {
+	transport := &failingTransport{fail: map[string]error{"3": errors.New("unavailable")}}
+	s := &SentryExporter{transport: transport, logger: zap.NewNop(), sendWorkers: 4}
+
+	var envelopes [][]byte
+	for i := 0; i < 10; i++ {
+		envelopes = append(envelopes, []byte(strconv.Itoa(i)))
+	}
+	sent, errs := s.sendEnvelopes(context.Background(), envelopes)
+	assert.Equal(t, 9, sent)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "unavailable")
+	assert.ElementsMatch(t, []string{"0", "1", "2", "4", "5", "6", "7", "8", "9"}, transport.envelopes)
+}
+
+func TestSendEnvelopesSpoolsOnlyFailures(t *testing.T) {
+	transport := &failingTransport{fail: map[string]error{"second": errors.New("unavailable")}}
+	spool := &mockSpool{}
+	s := &SentryExporter{transport: transport, spool: spool, logger: zap.NewNop()}
+
+	sent, errs := s.sendEnvelopes(context.Background(), [][]byte{[]byte("first"), []byte("second")})
+	assert.Equal(t, 2, sent)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"first"}, transport.envelopes)
+	require.Equal(t, 1, spool.Len())
+	assert.Equal(t, []byte("second"), spool.entries[0].Envelope)
+}
+
+func TestPushError(t *testing.T) {
+	// The views are registered by the factory.
+	NewFactory()
+
+	core, logs := observer.New(zap.WarnLevel)
+	s := &SentryExporter{id: config.NewIDWithName(typeStr, "push"), logger: zap.New(core)}
+	assert.NoError(t, s.pushError(3, nil))
+
+	err := s.pushError(0, []error{errors.New("unavailable")})
+	assert.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+
+	err = s.pushError(0, []error{consumererror.Permanent(errors.New("invalid"))})
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 0, logs.Len())
+
+	// Retrying a push would send its accepted envelopes again, so the failed ones are dropped.
+	dropped := exporterViewData(t, "exporter/sentry/dropped_envelopes", "sentry/push")
+	err = s.pushError(1, []error{errors.New("unavailable"), consumererror.Permanent(errors.New("invalid"))})
+	assert.True(t, consumererror.IsPermanent(err))
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, int64(1), logs.All()[0].ContextMap()["dropped"])
+	assert.Equal(t, dropped+1, exporterViewData(t, "exporter/sentry/dropped_envelopes", "sentry/push"))
+}
+
+func TestPushErrorRateLimited(t *testing.T) {
+	s := &SentryExporter{logger: zap.NewNop()}
+	until := time.Now().Add(time.Minute)
+	err := s.pushError(0, []error{
+		&rateLimitedError{category: "transaction", until: until.Add(-time.Second)},
+		&rateLimitedError{category: "error", until: until},
+	})
+	assert.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.IsType(t, exporterhelper.NewThrottleRetry(nil, 0), err)
+
+	err = s.pushError(0, []error{&rateLimitedError{category: "error", until: until}, errors.New("unavailable")})
+	assert.NotEqual(t, reflect.TypeOf(exporterhelper.NewThrottleRetry(nil, 0)), reflect.TypeOf(err))
+}
+
+func TestResendSpooledStopsOnFailure(t *testing.T) {
+	transport := &mockTransport{err: errors.New("unavailable")}
+	spool := &mockSpool{}
+	require.NoError(t, spool.Push(context.Background(), []byte("first")))
+	require.NoError(t, spool.Push(context.Background(), []byte("second")))
+	s := &SentryExporter{transport: transport, spool: spool, logger: zap.NewNop()}
+
+	s.resendSpooled(context.Background())
+	assert.Equal(t, 2, spool.Len())
+}
+
+func TestStartWithStorage(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Storage = "envelope_storage"
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	s.dataType = config.LogsDataType
+
+	spool := &mockSpool{}
+	storage := &mockStorage{Component: componenthelper.New(), spool: spool}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{
+			config.NewID("envelope_storage"): storage,
+		},
+	}
+	require.NoError(t, s.start(context.Background(), host))
+	assert.Equal(t, spool, s.spool)
+	assert.Equal(t, "logs", storage.name)
+	require.NoError(t, s.shutdown(context.Background()))
+}
+
+func TestStartWithMissingStorage(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Storage = "envelope_storage"
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	host := &mockHost{Host: componenttest.NewNopHost()}
+	assert.Error(t, s.start(context.Background(), host))
+
+	host.extensions = map[config.ComponentID]component.Extension{
+		config.NewID("envelope_storage"): componenthelper.New(),
+	}
+	assert.Error(t, s.start(context.Background(), host))
+}
+
+func TestNewSentryExporterInvalidConfig(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.DSN = "not a dsn"
+	_, err := newSentryExporter(cfg, zap.NewNop())
+	assert.Error(t, err)
+
+	cfg.DSN = ""
+	cfg.Storage = "/invalid"
+	_, err = newSentryExporter(cfg, zap.NewNop())
+	assert.Error(t, err)
+
+	cfg.Storage = ""
+	cfg.SendWorkers = -1
+	_, err = newSentryExporter(cfg, zap.NewNop())
+	assert.EqualError(t, err, "invalid 'send_workers': -1 is negative")
+}
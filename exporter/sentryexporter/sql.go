@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+var (
+	// sqlValueListRegexp matches parenthesized lists of values or placeholders, such as IN lists.
+	sqlValueListRegexp = regexp.MustCompile(`\(\s*(?:%s|\?|\$\d+|:\w+)(?:\s*,\s*(?:%s|\?|\$\d+|:\w+))*\s*\)`)
+	// sqlRowListRegexp matches lists of rows of values, such as those of multi-row inserts.
+	sqlRowListRegexp = regexp.MustCompile(`\(%s\)(?:\s*,\s*\(%s\))+`)
+)
+
+// scrubSQL normalizes a SQL statement so that statements differing only by their values are the
+// same: string and number literals are replaced with %s, lists of values and placeholders are
+// collapsed to (%s), comments are removed and whitespace is collapsed. Literals, which may hold
+// personal data, are not kept.
+func scrubSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	// prev is the last byte read, and space is set when whitespace or a comment was skipped.
+	var prev byte
+	space := false
+	write := func(s string) {
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteString(s)
+		prev = s[len(s)-1]
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			prev = c
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			i += end
+			space = true
+			prev = ' '
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 3
+			}
+			space = true
+			prev = ' '
+		case c == '\'':
+			// String literals end at the next quote that is not doubled.
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			write("%s")
+		case c == '"' || c == '`':
+			// Quoted identifiers are kept.
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				write(query[i:])
+				i = len(query)
+			} else {
+				write(query[i : i+end+2])
+				i += end + 1
+			}
+		case c >= '0' && c <= '9' && !isSQLIdentifierByte(prev):
+			for i+1 < len(query) && (isSQLIdentifierByte(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			write("%s")
+		default:
+			write(query[i : i+1])
+		}
+	}
+
+	scrubbed := sqlValueListRegexp.ReplaceAllString(b.String(), "(%s)")
+	return sqlRowListRegexp.ReplaceAllString(scrubbed, "(%s)")
+}
+
+// isSQLIdentifierByte returns whether c can be part of an identifier or placeholder, such that
+// digits following it are not a number.
+func isSQLIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c == '@' || c == ':' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// scrubSQLStatementTag scrubs the db.statement tag of a span or event of a SQL database.
+func scrubSQLStatementTag(tags map[string]string) {
+	system, ok := tags[conventions.AttributeDBSystem]
+	if !ok {
+		system = tags[dbTypeAttribute]
+	}
+	if dbOps[system] != dbSQLQueryOp {
+		return
+	}
+	if statement, ok := tags[conventions.AttributeDBStatement]; ok {
+		tags[conventions.AttributeDBStatement] = scrubSQL(statement)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestScrubSQL(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "SELECT * FROM users WHERE email = 'jane@example.com' AND age > 42",
+			expected: "SELECT * FROM users WHERE email = %s AND age > %s",
+		},
+		{
+			query:    "SELECT * FROM users WHERE name = 'O''Brien'",
+			expected: "SELECT * FROM users WHERE name = %s",
+		},
+		{
+			query:    "SELECT * FROM orders WHERE id IN (1, 2, 3) AND status IN ('paid','sent')",
+			expected: "SELECT * FROM orders WHERE id IN (%s) AND status IN (%s)",
+		},
+		{
+			query:    "SELECT * FROM orders WHERE id IN ($1, $2, $3)",
+			expected: "SELECT * FROM orders WHERE id IN (%s)",
+		},
+		{
+			query:    "INSERT INTO t1 (a, b) VALUES (1, 'x'), (2, 'y')",
+			expected: "INSERT INTO t1 (a, b) VALUES (%s)",
+		},
+		{
+			query:    "SELECT \"user\".\"id\", price * 1.5 FROM \"user\" -- jane@example.com\n  WHERE  id = ? /* note */ LIMIT 10",
+			expected: "SELECT \"user\".\"id\", price * %s FROM \"user\" WHERE id = ? LIMIT %s",
+		},
+		{
+			query:    "SELECT col1, table2.col2 FROM table2 WHERE x = $1",
+			expected: "SELECT col1, table2.col2 FROM table2 WHERE x = $1",
+		},
+		{
+			query:    "SELECT 'unterminated",
+			expected: "SELECT %s",
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, scrubSQL(test.query), test.query)
+	}
+}
+
+func TestFillSentrySpanScrubsSQL(t *testing.T) {
+	span := pdata.NewSpan()
+	span.SetName("SELECT users")
+	span.Attributes().InsertString("db.system", "postgresql")
+	span.Attributes().InsertString("db.statement", "SELECT * FROM users WHERE email = 'jane@example.com'")
+	library := pdata.NewInstrumentationLibrary()
+
+	var sentrySpan sentry.Span
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{scrubSQL: true})
+	assert.Equal(t, "SELECT * FROM users WHERE email = %s", sentrySpan.Description)
+	assert.Equal(t, "SELECT * FROM users WHERE email = %s", sentrySpan.Tags["db.statement"])
+
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{})
+	assert.Equal(t, "SELECT * FROM users WHERE email = 'jane@example.com'", sentrySpan.Description)
+
+	// Statements of other databases are kept.
+	span.Attributes().UpdateString("db.system", "redis")
+	span.Attributes().UpdateString("db.statement", "GET 'session:42'")
+	fillSentrySpan(&sentrySpan, span, library, nil, map[string]string{}, nil, conversionOptions{scrubSQL: true})
+	assert.Equal(t, "GET 'session:42'", sentrySpan.Description)
+}
+
+func TestPushTraceDataScrubsSQLOfErrorEvents(t *testing.T) {
+	td := newExceptionTraces(nil)
+	span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.Attributes().InsertString("db.system", "mysql")
+	span.Attributes().InsertString("db.statement", "DELETE FROM cards WHERE number = '4111111111111111'")
+
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport, errorsOnly: true, conversion: conversionOptions{scrubSQL: true}}
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+	require.Len(t, transport.envelopes, 1)
+	assert.NotContains(t, string(transport.envelopes[0]), "4111111111111111")
+	assert.Contains(t, string(transport.envelopes[0]), "DELETE FROM cards WHERE number = %s")
+}
@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import "github.com/getsentry/sentry-go"
+
+// staticTags are the tags of all transactions and events, such as the region of a fleet.
+type staticTags map[string]string
+
+// apply adds the static tags to an event. The tags of the event take precedence.
+func (t staticTags) apply(event *sentry.Event) {
+	if len(t) == 0 {
+		return
+	}
+	if event.Tags == nil {
+		event.Tags = make(map[string]string, len(t))
+	}
+	for k, v := range t {
+		if _, ok := event.Tags[k]; !ok {
+			event.Tags[k] = v
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticTagsApply(t *testing.T) {
+	tags := staticTags{"region": "eu-west-1", "team": "checkout"}
+
+	event := sentry.NewEvent()
+	event.Tags = map[string]string{"team": "payments"}
+	tags.apply(event)
+	assert.Equal(t, map[string]string{"region": "eu-west-1", "team": "payments"}, event.Tags)
+
+	event = &sentry.Event{}
+	tags.apply(event)
+	assert.Equal(t, map[string]string{"region": "eu-west-1", "team": "checkout"}, event.Tags)
+
+	event = sentry.NewEvent()
+	staticTags(nil).apply(event)
+	assert.Empty(t, event.Tags)
+}
+
+func TestPushTraceDataStaticTags(t *testing.T) {
+	transport := &mockTransport{}
+	s := &SentryExporter{transport: transport, staticTags: staticTags{"region": "eu-west-1"}}
+	require.NoError(t, s.pushTraceData(context.Background(), newExceptionTraces(nil)))
+
+	// The transaction and the error event are tagged.
+	require.Len(t, transport.envelopes, 2)
+	for _, envelope := range transport.envelopes {
+		assert.Contains(t, string(envelope), `"region":"eu-west-1"`)
+	}
+}
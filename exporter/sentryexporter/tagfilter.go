@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// tagFilter selects the tags that are sent by their keys, with glob patterns such as "http.*",
+// and the span attributes sent as data with the span data mode. It is applied to the converted
+// events, so the release, user, contexts and request are still set from all attributes. A nil
+// filter selects all tags.
+type tagFilter struct {
+	// include are the patterns of the tags that are sent, all if empty.
+	include []string
+	// exclude are the patterns of the tags that are never sent.
+	exclude []string
+}
+
+// newTagFilter returns a filter of the include and exclude patterns, or nil if there are none.
+func newTagFilter(include, exclude []string) (*tagFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	for _, pattern := range include {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid 'include_tags' pattern %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid 'exclude_tags' pattern %q: %w", pattern, err)
+		}
+	}
+	return &tagFilter{include: include, exclude: exclude}, nil
+}
+
+// apply removes the tags of an event and of its spans that the filter does not allow.
+func (f *tagFilter) apply(event *sentry.Event) {
+	if f == nil {
+		return
+	}
+	f.filter(event.Tags)
+	for _, span := range event.Spans {
+		f.filter(span.Tags)
+	}
+}
+
+// filter removes the tags that the filter does not allow.
+func (f *tagFilter) filter(tags map[string]string) {
+	for k := range tags {
+		if !f.allows(k) {
+			delete(tags, k)
+		}
+	}
+}
+
+// allows returns whether the tag key is sent.
+func (f *tagFilter) allows(key string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.include) > 0 && !matchesAny(f.include, key) {
+		return false
+	}
+	return !matchesAny(f.exclude, key)
+}
+
+// matchesAny returns whether key matches any of the validated patterns.
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+func TestTagFilter(t *testing.T) {
+	var filter *tagFilter
+	assert.True(t, filter.allows("http.url"))
+
+	filter, err := newTagFilter(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+
+	filter, err = newTagFilter([]string{"http.*", "service.name"}, []string{"http.url", "*.id"})
+	require.NoError(t, err)
+	assert.True(t, filter.allows("http.method"))
+	assert.True(t, filter.allows("service.name"))
+	assert.False(t, filter.allows("http.url"))
+	assert.False(t, filter.allows("db.statement"))
+
+	filter, err = newTagFilter(nil, []string{"*.id"})
+	require.NoError(t, err)
+	assert.True(t, filter.allows("db.statement"))
+	assert.False(t, filter.allows("user.id"))
+
+	_, err = newTagFilter([]string{"http.["}, nil)
+	assert.Error(t, err)
+	_, err = newTagFilter(nil, []string{"http.["})
+	assert.Error(t, err)
+}
+
+func TestTagFilterApply(t *testing.T) {
+	filter, err := newTagFilter(nil, []string{"cart.id"})
+	require.NoError(t, err)
+	event := &sentry.Event{
+		Tags:  map[string]string{"http.method": "GET", "cart.id": "c0ffee"},
+		Spans: []*sentry.Span{{Tags: map[string]string{"cart.id": "c0ffee", "cart.size": "3"}}},
+	}
+	filter.apply(event)
+	assert.Equal(t, map[string]string{"http.method": "GET"}, event.Tags)
+	assert.Equal(t, map[string]string{"cart.size": "3"}, event.Spans[0].Tags)
+
+	filter = nil
+	filter.apply(event)
+	assert.Equal(t, map[string]string{"http.method": "GET"}, event.Tags)
+}
+
+func TestPushTraceDataFiltersTags(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.ExcludeTags = []string{"http.route", "service.*"}
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	transport := &mockTransport{}
+	s.transport = transport
+
+	require.NoError(t, s.pushTraceData(context.Background(), newExceptionTraces(nil)))
+	require.Len(t, transport.envelopes, 2)
+	for _, envelope := range transport.envelopes {
+		assert.NotContains(t, string(envelope), "service.name")
+		assert.NotContains(t, string(envelope), "http.route")
+	}
+
+	cfg.IncludeTags = []string{"[a-"}
+	_, err = newSentryExporter(cfg, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestPushTraceDataIncludeTagsKeepsConversion(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.IncludeTags = []string{"http.*"}
+	cfg.ScrubSQL = true
+	s, err := newSentryExporter(cfg, zap.NewNop())
+	require.NoError(t, err)
+	transport := &mockTransport{}
+	s.transport = transport
+
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceVersion, "1.2.0")
+	rs.Resource().Attributes().InsertString(conventions.AttributeDeploymentEnvironment, "production")
+	spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	root := spans.AppendEmpty()
+	root.SetName("GET /users")
+	root.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	root.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	root.SetKind(pdata.SpanKindServer)
+	root.Attributes().InsertString("http.method", "GET")
+	root.Attributes().InsertString("enduser.id", "jane")
+	child := spans.AppendEmpty()
+	child.SetName("SELECT users")
+	child.SetTraceID(root.TraceID())
+	child.SetSpanID(pdata.NewSpanID([8]byte{2, 2, 3, 4, 5, 6, 7, 8}))
+	child.SetParentSpanID(root.SpanID())
+	child.Attributes().InsertString(conventions.AttributeDBSystem, "mysql")
+	child.Attributes().InsertString(conventions.AttributeDBStatement, "SELECT * FROM users WHERE email = 'jane@example.com'")
+
+	require.NoError(t, s.pushTraceData(context.Background(), td))
+	require.Len(t, transport.envelopes, 1)
+	envelope := string(transport.envelopes[0])
+	assert.Contains(t, envelope, `"release":"checkout@1.2.0"`)
+	assert.Contains(t, envelope, `"environment":"production"`)
+	assert.Contains(t, envelope, `"id":"jane"`)
+	assert.Contains(t, envelope, `"http.method":"GET"`)
+	assert.NotContains(t, envelope, "jane@example.com")
+	assert.NotContains(t, envelope, conventions.AttributeDBSystem)
+}
@@ -15,55 +15,86 @@
 package sentryexporter
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"time"
 
 	"github.com/getsentry/sentry-go"
 )
 
-// transport is used by exporter to send events to Sentry
+const defaultTransportTimeout = 30 * time.Second
+
+// transport is used by exporter to send envelopes to Sentry
 type transport interface {
-	SendTransactions(transactions []*sentry.Event)
-	Configure(options sentry.ClientOptions)
-	Flush(ctx context.Context) bool
+	SendEnvelope(ctx context.Context, envelope []byte) error
 }
 
 type sentryTransport struct {
-	httpTransport *sentry.HTTPTransport
+	client *http.Client
+	dsn    *sentry.Dsn
+	// limits are the rate limits Sentry responded with. Envelopes of a rate limited category are
+	// not sent until the limit ends.
+	limits rateLimits
 }
 
 // newSentryTransport returns a new pre-configured instance of sentryTransport.
-func newSentryTransport() *sentryTransport {
+// Envelopes are silently dropped when no DSN is set.
+func newSentryTransport(dsn string) (*sentryTransport, error) {
 	transport := sentryTransport{
-		httpTransport: sentry.NewHTTPTransport(),
+		client: &http.Client{Timeout: defaultTransportTimeout},
 	}
-	return &transport
-}
 
-func (t *sentryTransport) Configure(options sentry.ClientOptions) {
-	t.httpTransport.Configure(options)
+	if dsn != "" {
+		parsed, err := sentry.NewDsn(dsn)
+		if err != nil {
+			return nil, err
+		}
+		transport.dsn = parsed
+	}
+
+	return &transport, nil
 }
 
-func (t *sentryTransport) Flush(ctx context.Context) bool {
-	deadline, ok := ctx.Deadline()
-	if ok {
-		return t.httpTransport.Flush(time.Until(deadline))
+// SendEnvelope posts an envelope to the envelope endpoint of the Sentry project. A
+// *rateLimitedError is returned, without posting it, if Sentry rate limits the data category of the
+// envelope, and if Sentry rejects it with a 429 status.
+func (t *sentryTransport) SendEnvelope(ctx context.Context, envelope []byte) error {
+	if t.dsn == nil {
+		return nil
+	}
+	category := envelopeCategory(envelope)
+	if until, limited := t.limits.limitedUntil(category, time.Now()); limited {
+		return &rateLimitedError{category: category, until: until}
 	}
-	return t.httpTransport.Flush(time.Second)
-}
 
-// sendTransactions uses a Sentry HTTPTransport to send transaction events to Sentry
-func (t *sentryTransport) SendTransactions(transactions []*sentry.Event) {
-	bufferCounter := 0
-	for _, transaction := range transactions {
-		// We should flush all events when we send transactions equal to the transport
-		// buffer size so we don't drop transactions.
-		if bufferCounter == t.httpTransport.BufferSize {
-			t.httpTransport.Flush(time.Second)
-			bufferCounter = 0
-		}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.dsn.EnvelopeAPIURL().String(), bytes.NewReader(envelope))
+	if err != nil {
+		return err
+	}
+	for k, v := range t.dsn.RequestHeaders() {
+		request.Header.Set(k, v)
+	}
+	request.Header.Set("Content-Type", envelopeContentType)
 
-		t.httpTransport.SendEvent(transaction)
-		bufferCounter++
+	response, err := t.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	now := time.Now()
+	t.limits.update(response.Header, response.StatusCode, now)
+	if response.StatusCode == http.StatusTooManyRequests {
+		until, _ := t.limits.limitedUntil(category, now)
+		return &rateLimitedError{category: category, until: until}
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("sending envelope to Sentry failed with status %q", response.Status)
 	}
+	return nil
 }
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSentryTransportInvalidDSN(t *testing.T) {
+	_, err := newSentryTransport("not a dsn")
+	assert.Error(t, err)
+}
+
+func TestSentryTransportWithoutDSN(t *testing.T) {
+	transport, err := newSentryTransport("")
+	require.NoError(t, err)
+	assert.NoError(t, transport.SendEnvelope(context.Background(), []byte("{}\n")))
+}
+
+func TestSentryTransportSendEnvelope(t *testing.T) {
+	var (
+		path        string
+		contentType string
+		auth        string
+		body        string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		auth = r.Header.Get("X-Sentry-Auth")
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	transport, err := newSentryTransport(strings.Replace(server.URL, "http://", "http://key@", 1) + "/42")
+	require.NoError(t, err)
+
+	require.NoError(t, transport.SendEnvelope(context.Background(), []byte("envelope")))
+	assert.Equal(t, "/api/42/envelope/", path)
+	assert.Equal(t, envelopeContentType, contentType)
+	assert.Contains(t, auth, "sentry_key=key")
+	assert.Equal(t, "envelope", body)
+}
+
+func TestSentryTransportSendEnvelopeFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	transport, err := newSentryTransport(strings.Replace(server.URL, "http://", "http://key@", 1) + "/42")
+	require.NoError(t, err)
+
+	err = transport.SendEnvelope(context.Background(), []byte("envelope"))
+	assert.Error(t, err)
+	var limited *rateLimitedError
+	assert.False(t, errors.As(err, &limited))
+}
+
+func TestSentryTransportSendEnvelopeRateLimited(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Sentry-Rate-Limits", "60:transaction:organization")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	transport, err := newSentryTransport(strings.Replace(server.URL, "http://", "http://key@", 1) + "/42")
+	require.NoError(t, err)
+
+	sentAt := time.Now()
+	transaction, err := transactionToEnvelope(&sentry.Event{EventID: "1", Type: "transaction"}, sentAt)
+	require.NoError(t, err)
+	var limited *rateLimitedError
+	require.True(t, errors.As(transport.SendEnvelope(context.Background(), transaction), &limited))
+	assert.Equal(t, "transaction", limited.category)
+	assert.True(t, limited.until.After(sentAt))
+
+	// Transactions are not sent again until the rate limit ends, but errors are.
+	require.True(t, errors.As(transport.SendEnvelope(context.Background(), transaction), &limited))
+	assert.Equal(t, 1, requests)
+
+	event, err := eventToEnvelope(&sentry.Event{EventID: "2"}, sentAt)
+	require.NoError(t, err)
+	assert.Error(t, transport.SendEnvelope(context.Background(), event))
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const (
+	// metaKey is the key of the metadata of the truncated tags of events in their extra data,
+	// until it is sent in the _meta of their payloads.
+	metaKey = "_meta"
+
+	// Sentry's limits of the lengths of tag keys and values, in characters. Longer tags are
+	// dropped with their event.
+	defaultMaxTagKeyLength   = 32
+	defaultMaxTagValueLength = 200
+
+	// ellipsis ends the truncated tag values.
+	ellipsis = "..."
+)
+
+// metaRemark is the metadata of a truncated value, the "!limit" rule with the range of the value
+// it substituted, or "x" if the value was only cut, and the original length.
+// See https://develop.sentry.dev/sdk/data-handling/#annotating-truncated-data.
+type metaRemark struct {
+	Rem [][]interface{} `json:"rem"`
+	Len int             `json:"len"`
+}
+
+// tagLimits are the maximum lengths of the keys and values of tags, 0 for no limit.
+type tagLimits struct {
+	maxKeyLength   int
+	maxValueLength int
+}
+
+// apply truncates the tags of an event and of its spans, and annotates the truncated values in
+// the extra data of the event.
+func (l tagLimits) apply(event *sentry.Event) {
+	if l.maxKeyLength == 0 && l.maxValueLength == 0 {
+		return
+	}
+
+	meta := make(map[string]interface{})
+	if tagsMeta := l.truncate(event.Tags); tagsMeta != nil {
+		meta["tags"] = tagsMeta
+	}
+	spansMeta := make(map[string]interface{})
+	for i, span := range event.Spans {
+		if tagsMeta := l.truncate(span.Tags); tagsMeta != nil {
+			spansMeta[strconv.Itoa(i)] = map[string]interface{}{"tags": tagsMeta}
+		}
+	}
+	if len(spansMeta) > 0 {
+		meta["spans"] = spansMeta
+	}
+	if len(meta) > 0 {
+		event.Extra[metaKey] = meta
+	}
+}
+
+// truncate truncates the keys and values of tags, and returns the metadata of the truncated
+// values, or nil if none were. A truncated key is dropped if its tag already exists.
+func (l tagLimits) truncate(tags map[string]string) map[string]interface{} {
+	var meta map[string]interface{}
+	for key, value := range tags {
+		truncatedKey := key
+		if l.maxKeyLength > 0 {
+			truncatedKey = truncateString(key, l.maxKeyLength)
+		}
+		if l.maxValueLength > 0 {
+			if length := utf8.RuneCountInString(value); length > l.maxValueLength {
+				value = truncateWithEllipsis(value, l.maxValueLength)
+				if meta == nil {
+					meta = make(map[string]interface{})
+				}
+				meta[truncatedKey] = map[string]metaRemark{"": l.valueRemark(length)}
+			}
+		}
+
+		if truncatedKey == key {
+			tags[key] = value
+			continue
+		}
+		delete(tags, key)
+		if _, ok := tags[truncatedKey]; !ok {
+			tags[truncatedKey] = value
+		}
+	}
+	return meta
+}
+
+// valueRemark returns the metadata of a value of length characters truncated to the maximum
+// length, which ends with the substituted ellipsis if there is room for it.
+func (l tagLimits) valueRemark(length int) metaRemark {
+	if l.maxValueLength <= len(ellipsis) {
+		return metaRemark{Rem: [][]interface{}{{"!limit", "x"}}, Len: length}
+	}
+	return metaRemark{
+		Rem: [][]interface{}{{"!limit", "s", l.maxValueLength - len(ellipsis), l.maxValueLength}},
+		Len: length,
+	}
+}
+
+// truncateString returns the first max characters of s, without splitting characters.
+func truncateString(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	n := 0
+	for i := range s {
+		if n == max {
+			return s[:i]
+		}
+		n++
+	}
+	return s
+}
+
+// truncateWithEllipsis returns s truncated to max characters, ending with an ellipsis if there is
+// room for it.
+func truncateWithEllipsis(s string, max int) string {
+	if max <= len(ellipsis) {
+		return truncateString(s, max)
+	}
+	return truncateString(s, max-len(ellipsis)) + ellipsis
+}
+
+// extractMeta returns extra without the metadata of truncated values, and the metadata, if set.
+func extractMeta(extra map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	meta, ok := extra[metaKey].(map[string]interface{})
+	if !ok {
+		return extra, nil
+	}
+
+	rest := make(map[string]interface{}, len(extra)-1)
+	for k, v := range extra {
+		if k != metaKey {
+			rest[k] = v
+		}
+	}
+	return rest, meta
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "short", truncateString("short", 10))
+	assert.Equal(t, "abc", truncateString("abcdef", 3))
+	// Characters are not split.
+	assert.Equal(t, "héé", truncateString("héééé", 3))
+	assert.Equal(t, "hé...", truncateWithEllipsis("héééééé", 5))
+	assert.Equal(t, "hé", truncateWithEllipsis("héééééé", 2))
+}
+
+func TestTagLimitsApply(t *testing.T) {
+	event := sentry.NewEvent()
+	event.Tags = map[string]string{
+		"db.statement":    strings.Repeat("é", 12),
+		"http.method":     "GET",
+		"a.very.long.key": "value",
+	}
+	event.Spans = []*sentry.Span{
+		{Tags: map[string]string{"http.method": "GET"}},
+		{Tags: map[string]string{"http.url": "https://example.com/" + strings.Repeat("a", 20)}},
+	}
+
+	tagLimits{maxKeyLength: 10, maxValueLength: 10}.apply(event)
+	assert.Equal(t, map[string]string{
+		"db.stateme": "ééééééé...",
+		"http.metho": "GET",
+		"a.very.lon": "value",
+	}, event.Tags)
+	assert.Equal(t, map[string]string{"http.metho": "GET"}, event.Spans[0].Tags)
+	assert.Equal(t, map[string]string{"http.url": "https:/..."}, event.Spans[1].Tags)
+
+	remark := map[string]metaRemark{"": {Rem: [][]interface{}{{"!limit", "s", 7, 10}}, Len: 12}}
+	urlRemark := map[string]metaRemark{"": {Rem: [][]interface{}{{"!limit", "s", 7, 10}}, Len: 40}}
+	assert.Equal(t, map[string]interface{}{
+		"tags": map[string]interface{}{"db.stateme": remark},
+		"spans": map[string]interface{}{
+			"1": map[string]interface{}{"tags": map[string]interface{}{"http.url": urlRemark}},
+		},
+	}, event.Extra[metaKey])
+
+	// Events without long tags are not annotated.
+	event = sentry.NewEvent()
+	event.Tags = map[string]string{"http.method": "GET"}
+	tagLimits{maxKeyLength: defaultMaxTagKeyLength, maxValueLength: defaultMaxTagValueLength}.apply(event)
+	assert.NotContains(t, event.Extra, metaKey)
+}
+
+func TestEventToEnvelopeMeta(t *testing.T) {
+	event := sentry.NewEvent()
+	event.Timestamp = time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	event.Tags = map[string]string{"http.url": strings.Repeat("a", 300)}
+	tagLimits{maxValueLength: defaultMaxTagValueLength}.apply(event)
+
+	envelope, err := eventToEnvelope(event, time.Now())
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSuffix(envelope, []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 3)
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[2], &payload))
+	assert.NotContains(t, payload, "extra")
+	assert.Equal(t, map[string]interface{}{
+		"tags": map[string]interface{}{
+			"http.url": map[string]interface{}{
+				"": map[string]interface{}{"rem": []interface{}{[]interface{}{"!limit", "s", float64(197), float64(200)}}, "len": float64(300)},
+			},
+		},
+	}, payload["_meta"])
+	assert.Len(t, payload["tags"].(map[string]interface{})["http.url"], 200)
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+)
+
+const (
+	// userDataKey is the key of the additional data of the user of events. Until they are encoded,
+	// the data are kept under this key in the extra data of events, as sentry.User has no field
+	// for them.
+	userDataKey = "_user_data"
+
+	attributeEndUserID     = "enduser.id"
+	attributeUserID        = "user.id"
+	attributeUserEmail     = "user.email"
+	attributeUserName      = "user.name"
+	attributeUserFullName  = "user.full_name"
+	attributeEndUserPrefix = "enduser."
+	attributeUserPrefix    = "user."
+)
+
+// userPayload is the user interface of an event.
+// See https://develop.sentry.dev/sdk/event-payloads/user/.
+type userPayload struct {
+	sentry.User
+	Name string                 `json:"name,omitempty"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// setUserFromTags sets the user of an event from the enduser.* and user.* attributes in its tags.
+// "user.id" is preferred to "enduser.id", the other attributes are additional data of the user,
+// such as the "role" of "enduser.role".
+func setUserFromTags(event *sentry.Event, tags map[string]string) {
+	var additional *userPayload
+	for k, v := range tags {
+		var key string
+		switch {
+		case strings.HasPrefix(k, attributeUserPrefix):
+			key = k[len(attributeUserPrefix):]
+		case strings.HasPrefix(k, attributeEndUserPrefix):
+			key = k[len(attributeEndUserPrefix):]
+		default:
+			continue
+		}
+
+		switch k {
+		case attributeUserID:
+			event.User.ID = v
+		case attributeEndUserID:
+			if _, ok := tags[attributeUserID]; !ok {
+				event.User.ID = v
+			}
+		case attributeUserEmail:
+			event.User.Email = v
+		case attributeUserName:
+			event.User.Username = v
+		default:
+			if additional == nil {
+				additional = &userPayload{}
+			}
+			if k == attributeUserFullName {
+				additional.Name = v
+				continue
+			}
+			if additional.Data == nil {
+				additional.Data = make(map[string]interface{})
+			}
+			additional.Data[key] = v
+		}
+	}
+	if additional != nil {
+		event.Extra[userDataKey] = additional
+	}
+}
+
+// newUserPayload returns the user of an event with the additional data in its extra data, and a
+// copy of the extra data without them. The user is nil if it is not set.
+func newUserPayload(eventUser sentry.User, extra map[string]interface{}) (*userPayload, map[string]interface{}) {
+	additional, _ := extra[userDataKey].(*userPayload)
+	if additional != nil {
+		rest := make(map[string]interface{}, len(extra)-1)
+		for k, v := range extra {
+			if k != userDataKey {
+				rest[k] = v
+			}
+		}
+		extra = rest
+	}
+	if eventUser == (sentry.User{}) && additional == nil {
+		return nil, extra
+	}
+
+	user := &userPayload{User: eventUser}
+	if additional != nil {
+		user.Name = additional.Name
+		user.Data = additional.Data
+	}
+	return user, extra
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetUserFromTags(t *testing.T) {
+	testCases := []struct {
+		testName string
+		tags     map[string]string
+		user     *userPayload
+	}{
+		{
+			testName: "without user",
+			tags:     map[string]string{"http.method": "GET"},
+		},
+		{
+			testName: "enduser",
+			tags:     map[string]string{"enduser.id": "42", "enduser.role": "admin", "enduser.scope": "read:orders"},
+			user: &userPayload{
+				User: sentry.User{ID: "42"},
+				Data: map[string]interface{}{"role": "admin", "scope": "read:orders"},
+			},
+		},
+		{
+			testName: "user",
+			tags: map[string]string{
+				"enduser.id":     "42",
+				"user.id":        "u-42",
+				"user.email":     "jane@example.com",
+				"user.name":      "jane",
+				"user.full_name": "Jane Doe",
+				"user.roles":     "admin",
+			},
+			user: &userPayload{
+				User: sentry.User{ID: "u-42", Email: "jane@example.com", Username: "jane"},
+				Name: "Jane Doe",
+				Data: map[string]interface{}{"roles": "admin"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			event := sentry.NewEvent()
+			event.Extra["other"] = true
+			setUserFromTags(event, test.tags)
+
+			user, extra := newUserPayload(event.User, event.Extra)
+			assert.Equal(t, test.user, user)
+			assert.Equal(t, map[string]interface{}{"other": true}, extra)
+		})
+	}
+}
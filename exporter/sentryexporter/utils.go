@@ -15,8 +15,13 @@
 package sentryexporter
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
 	"time"
 
+	"github.com/getsentry/sentry-go"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
@@ -25,3 +30,14 @@ import (
 func unixNanoToTime(u pdata.Timestamp) time.Time {
 	return time.Unix(0, int64(u)).UTC()
 }
+
+// newEventID returns a random Sentry event ID.
+func newEventID() sentry.EventID {
+	id := make([]byte, 16)
+	_, _ = io.ReadFull(rand.Reader, id)
+	id[6] &= 0x0F // clear version
+	id[6] |= 0x40 // set version to 4 (random uuid)
+	id[8] &= 0x3F // clear variant
+	id[8] |= 0x80 // set to IETF variant
+	return sentry.EventID(hex.EncodeToString(id))
+}
@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorreporterextension
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the error reporter extension.
+type Config struct {
+	config.ExtensionSettings `mapstructure:",squash"`
+
+	// DSN of the Sentry project the collector's own errors are reported to.
+	DSN string `mapstructure:"dsn"`
+
+	// Environment is attached to every reported event.
+	Environment string `mapstructure:"environment"`
+
+	// Release is attached to every reported event. Defaults to the collector
+	// command and version.
+	Release string `mapstructure:"release"`
+
+	// Level is the minimum internal log level that is reported. Must be one of
+	// "warn", "error", "dpanic", "panic" or "fatal".
+	Level string `mapstructure:"level"`
+
+	// FlushTimeout bounds how long shutdown waits for pending events.
+	FlushTimeout time.Duration `mapstructure:"flush_timeout"`
+}
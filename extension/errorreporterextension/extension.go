@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorreporterextension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// Keys used by the collector service to identify the component that wrote a log entry.
+	zapKindKey = "kind"
+	zapNameKey = "name"
+)
+
+type errorReporter struct {
+	client       *sentry.Client
+	level        zapcore.Level
+	flushTimeout time.Duration
+	logger       *zap.Logger
+}
+
+var _ component.Extension = (*errorReporter)(nil)
+
+func newErrorReporter(config *Config, logger *zap.Logger, buildInfo component.BuildInfo) (component.Extension, error) {
+	if config.DSN == "" {
+		return nil, errors.New("'dsn' config option cannot be empty")
+	}
+
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+		return nil, fmt.Errorf("invalid 'level' config option: %w", err)
+	}
+	if level < zapcore.WarnLevel {
+		return nil, fmt.Errorf("'level' config option must be warn or above, got %q", config.Level)
+	}
+
+	release := config.Release
+	if release == "" && buildInfo.Command != "" {
+		release = buildInfo.Command + "@" + buildInfo.Version
+	}
+
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         config.DSN,
+		Environment: config.Environment,
+		Release:     release,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Sentry client: %w", err)
+	}
+
+	return &errorReporter{
+		client:       client,
+		level:        level,
+		flushTimeout: config.FlushTimeout,
+		logger:       logger,
+	}, nil
+}
+
+// Start makes the reporter receive internal log entries, panics and fatal errors.
+func (r *errorReporter) Start(context.Context, component.Host) error {
+	register(r)
+	return nil
+}
+
+func (r *errorReporter) Shutdown(context.Context) error {
+	unregister(r)
+	if !r.client.Flush(r.flushTimeout) {
+		r.logger.Warn("Could not flush all error events, reached timeout")
+	}
+	return nil
+}
+
+// captureLogEntry reports an internal log entry. Fields attached by the collector
+// to identify the component become tags, all other fields are sent as extra data.
+func (r *errorReporter) captureLogEntry(ent zapcore.Entry, fields map[string]interface{}, err error) {
+	event := sentry.NewEvent()
+	event.Level = levelFromZap(ent.Level)
+	event.Message = ent.Message
+	event.Logger = ent.LoggerName
+	event.Timestamp = ent.Time
+
+	if err != nil {
+		event.Exception = []sentry.Exception{{
+			Type:  reflect.TypeOf(err).String(),
+			Value: err.Error(),
+		}}
+	}
+
+	for k, v := range fields {
+		switch k {
+		case zapKindKey, zapNameKey:
+			event.Tags["component_"+k] = fmt.Sprint(v)
+		default:
+			event.Extra[k] = v
+		}
+	}
+
+	if ent.Caller.Defined {
+		event.Extra["caller"] = ent.Caller.TrimmedPath()
+	}
+
+	r.client.CaptureEvent(event, nil, nil)
+
+	// Panic and fatal entries terminate the process right after being written.
+	if ent.Level >= zapcore.PanicLevel {
+		r.client.Flush(r.flushTimeout)
+	}
+}
+
+// captureFatalError reports an error that terminates the collector and waits for it to be sent.
+func (r *errorReporter) captureFatalError(err error) {
+	event := sentry.NewEvent()
+	event.Level = sentry.LevelFatal
+	event.Message = err.Error()
+	event.Exception = []sentry.Exception{{
+		Type:  reflect.TypeOf(err).String(),
+		Value: err.Error(),
+	}}
+
+	r.client.CaptureEvent(event, nil, nil)
+	r.client.Flush(r.flushTimeout)
+}
+
+// capturePanic reports a recovered panic value and waits for it to be sent.
+func (r *errorReporter) capturePanic(v interface{}) {
+	if err, ok := v.(error); ok {
+		r.client.Recover(err, nil, nil)
+	} else {
+		r.client.Recover(fmt.Sprint(v), nil, nil)
+	}
+	r.client.Flush(r.flushTimeout)
+}
+
+func levelFromZap(level zapcore.Level) sentry.Level {
+	switch level {
+	case zapcore.DebugLevel:
+		return sentry.LevelDebug
+	case zapcore.InfoLevel:
+		return sentry.LevelInfo
+	case zapcore.WarnLevel:
+		return sentry.LevelWarning
+	case zapcore.ErrorLevel:
+		return sentry.LevelError
+	default:
+		return sentry.LevelFatal
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorreporterextension
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type mockTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *mockTransport) Configure(sentry.ClientOptions) {}
+
+func (t *mockTransport) Flush(time.Duration) bool {
+	return true
+}
+
+func (t *mockTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *mockTransport) sent() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*sentry.Event(nil), t.events...)
+}
+
+func newTestReporter(t *testing.T, level string) (*errorReporter, *mockTransport) {
+	ext, err := newErrorReporter(&Config{
+		DSN:   "https://key@host/path/42",
+		Level: level,
+	}, zap.NewNop(), component.BuildInfo{Command: "otelcontribcol", Version: "1.2.3"})
+	require.NoError(t, err)
+
+	r := ext.(*errorReporter)
+	transport := &mockTransport{}
+	r.client, err = sentry.NewClient(sentry.ClientOptions{
+		Dsn:       "https://key@host/path/42",
+		Release:   r.client.Options().Release,
+		Transport: transport,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r, transport
+}
+
+func TestReleaseDefaultsToBuildInfo(t *testing.T) {
+	r, _ := newTestReporter(t, "error")
+	assert.Equal(t, "otelcontribcol@1.2.3", r.client.Options().Release)
+}
+
+func TestWrapCoreReportsLogEntries(t *testing.T) {
+	_, transport := newTestReporter(t, "error")
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(WrapCore(core)).With(zap.String(zapKindKey, "exporter"), zap.String(zapNameKey, "sentry"))
+
+	logger.Info("ignored")
+	logger.Warn("ignored as well")
+	logger.Error("Exporting failed", zap.Error(errors.New("connection refused")), zap.Int("dropped_items", 3))
+
+	// The wrapped core keeps receiving everything.
+	assert.Equal(t, 3, logs.Len())
+
+	events := transport.sent()
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, sentry.LevelError, event.Level)
+	assert.Equal(t, "Exporting failed", event.Message)
+	assert.Equal(t, "otelcontribcol@1.2.3", event.Release)
+	assert.Equal(t, "exporter", event.Tags["component_kind"])
+	assert.Equal(t, "sentry", event.Tags["component_name"])
+	assert.EqualValues(t, 3, event.Extra["dropped_items"])
+	require.Len(t, event.Exception, 1)
+	assert.Equal(t, "connection refused", event.Exception[0].Value)
+}
+
+func TestWrapCoreHonorsLevel(t *testing.T) {
+	_, transport := newTestReporter(t, "warn")
+
+	logger := zap.New(WrapCore(zapcore.NewNopCore()))
+	logger.Info("ignored")
+	logger.Warn("reported")
+
+	events := transport.sent()
+	require.Len(t, events, 1)
+	assert.Equal(t, sentry.LevelWarning, events[0].Level)
+}
+
+func TestNothingReportedAfterShutdown(t *testing.T) {
+	r, transport := newTestReporter(t, "error")
+	unregister(r)
+
+	logger := zap.New(WrapCore(zapcore.NewNopCore()))
+	logger.Error("not reported")
+	ReportError(errors.New("not reported either"))
+
+	assert.Empty(t, transport.sent())
+	register(r)
+}
+
+func TestReportError(t *testing.T) {
+	_, transport := newTestReporter(t, "error")
+
+	ReportError(nil)
+	ReportError(errors.New("cannot start receivers: listen tcp: address already in use"))
+
+	events := transport.sent()
+	require.Len(t, events, 1)
+	assert.Equal(t, sentry.LevelFatal, events[0].Level)
+	assert.Equal(t, "cannot start receivers: listen tcp: address already in use", events[0].Message)
+}
+
+func TestRecover(t *testing.T) {
+	_, transport := newTestReporter(t, "error")
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer Recover()
+		panic("boom")
+	})
+
+	events := transport.sent()
+	require.Len(t, events, 1)
+	assert.Equal(t, sentry.LevelFatal, events[0].Level)
+	assert.Equal(t, "boom", events[0].Message)
+}
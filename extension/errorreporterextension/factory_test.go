@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorreporterextension
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+func TestFactory(t *testing.T) {
+	f := NewFactory()
+	require.Equal(t, config.Type("error_reporter"), f.Type())
+
+	cfg := f.CreateDefaultConfig().(*Config)
+	require.Equal(t, config.NewID(typeStr), cfg.ID())
+	require.Equal(t, defaultLevel, cfg.Level)
+	require.Equal(t, defaultFlushTimeout, cfg.FlushTimeout)
+
+	tests := []struct {
+		name           string
+		config         *Config
+		wantErrMessage string
+	}{
+		{
+			name:           "Default config",
+			config:         cfg,
+			wantErrMessage: "'dsn' config option cannot be empty",
+		},
+		{
+			name:           "Invalid level",
+			config:         &Config{DSN: "https://key@host/path/42", Level: "loud"},
+			wantErrMessage: "invalid 'level' config option",
+		},
+		{
+			name:           "Level below warn",
+			config:         &Config{DSN: "https://key@host/path/42", Level: "info"},
+			wantErrMessage: "'level' config option must be warn or above",
+		},
+		{
+			name:           "Invalid DSN",
+			config:         &Config{DSN: "not a dsn", Level: "error"},
+			wantErrMessage: "failed to create Sentry client",
+		},
+		{
+			name:   "Valid config",
+			config: &Config{DSN: "https://key@host/path/42", Level: "error"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := f.CreateExtension(
+				context.Background(),
+				component.ExtensionCreateParams{
+					Logger: zap.NewNop(),
+				},
+				test.config,
+			)
+			if test.wantErrMessage != "" {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), test.wantErrMessage), err.Error())
+				require.Nil(t, e)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, e)
+				ctx := context.Background()
+				require.NoError(t, e.Start(ctx, componenttest.NewNopHost()))
+				require.NoError(t, e.Shutdown(ctx))
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/extension/errorreporterextension
+
+go 1.15
+
+require (
+	github.com/getsentry/sentry-go v0.6.2-0.20200707113342-e7c66ce62664
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/collector v0.27.1-0.20210526183029-df76aa36cd12
+	go.uber.org/zap v1.16.0
+)
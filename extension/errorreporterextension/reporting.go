@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errorreporterextension
+
+import (
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// The collector's logger and its main goroutine are created before any extension,
+// so running reporters are tracked here for the hooks below to find them.
+var (
+	reportersMu sync.RWMutex
+	reporters   []*errorReporter
+)
+
+func register(r *errorReporter) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	reporters = append(reporters, r)
+}
+
+func unregister(r *errorReporter) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	for i, registered := range reporters {
+		if registered == r {
+			reporters = append(reporters[:i], reporters[i+1:]...)
+			return
+		}
+	}
+}
+
+func activeReporters() []*errorReporter {
+	reportersMu.RLock()
+	defer reportersMu.RUnlock()
+	return append([]*errorReporter(nil), reporters...)
+}
+
+// WrapCore tees the collector's logging core so that entries at or above the
+// configured level are reported by every running error reporter extension.
+// It is meant to be installed with zap.WrapCore through service.AppSettings.LoggingOptions.
+func WrapCore(core zapcore.Core) zapcore.Core {
+	return zapcore.NewTee(core, &reportingCore{})
+}
+
+// ReportError reports an error that makes the collector exit, such as a
+// component failing to start, and waits for it to be delivered.
+func ReportError(err error) {
+	if err == nil {
+		return
+	}
+	for _, r := range activeReporters() {
+		r.captureFatalError(err)
+	}
+}
+
+// Recover reports a panic in the calling goroutine and re-panics.
+// It must be called with defer. Deferred in main, it only sees the panics of the
+// main goroutine, where components are built, started and shut down, but not those
+// of the goroutines components start to receive, process and export data.
+func Recover() {
+	if v := recover(); v != nil {
+		for _, r := range activeReporters() {
+			r.capturePanic(v)
+		}
+		panic(v)
+	}
+}
+
+// reportingCore is a zapcore.Core forwarding entries to the active reporters.
+type reportingCore struct {
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = (*reportingCore)(nil)
+
+func (c *reportingCore) Enabled(level zapcore.Level) bool {
+	for _, r := range activeReporters() {
+		if r.level.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *reportingCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &reportingCore{fields: merged}
+}
+
+func (c *reportingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *reportingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	var err error
+	for _, fs := range [][]zapcore.Field{c.fields, fields} {
+		for _, f := range fs {
+			f.AddTo(enc)
+			if fieldErr, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType {
+				err = fieldErr
+			}
+		}
+	}
+
+	for _, r := range activeReporters() {
+		if r.level.Enabled(ent.Level) {
+			r.captureLogEntry(ent, enc.Fields, err)
+		}
+	}
+	return nil
+}
+
+func (c *reportingCore) Sync() error {
+	return nil
+}
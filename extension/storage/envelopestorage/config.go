@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelopestorage
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the envelope storage extension.
+type Config struct {
+	config.ExtensionSettings `mapstructure:",squash"`
+
+	// Directory the envelope spools are kept in. It must already exist.
+	Directory string `mapstructure:"directory,omitempty"`
+
+	// MaxSizeMiB is the maximum size of all spools combined. The oldest
+	// envelopes are evicted to make room for new ones.
+	MaxSizeMiB int64 `mapstructure:"max_size_mib,omitempty"`
+
+	// MaxAge is how long an envelope is kept before it is evicted.
+	// Zero keeps envelopes until they are removed or evicted for size.
+	MaxAge time.Duration `mapstructure:"max_age,omitempty"`
+}
@@ -1,4 +1,4 @@
-// Copyright 2020, OpenTelemetry Authors
+// Copyright The OpenTelemetry Authors
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,30 +12,15 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package interval
+// +build windows
+
+package envelopestorage
 
 import (
-	"testing"
-	"time"
+	"os"
+	"path/filepath"
 )
 
-func TestScheduler(t *testing.T) {
-	f := &fakeRunnable{}
-	s := NewRunner(time.Second, f)
-	go func() {
-		_ = s.Start()
-	}()
-	s.Stop()
-	// getting here is success
-}
-
-type fakeRunnable struct {
-}
-
-func (t *fakeRunnable) Setup() error {
-	return nil
-}
-
-func (fakeRunnable) Run() error {
-	return nil
+func getDefaultDirectory() string {
+	return filepath.Join(os.Getenv("ProgramData"), "Otelcol", "EnvelopeStorage")
 }
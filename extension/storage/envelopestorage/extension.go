@@ -0,0 +1,359 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelopestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+const envelopeFileSuffix = ".envelope"
+
+// Extension is the interface implemented by the envelope storage extension.
+type Extension interface {
+	component.Extension
+
+	// GetSpool returns the named spool of the specified component. A component
+	// sending several data types, such as an exporter in traces and logs
+	// pipelines, gets a spool for each by naming them after the data type. All
+	// spools are kept in the same directory and share the size limit of the
+	// extension, so that several components can rely on one bounded buffer for
+	// durability.
+	GetSpool(ctx context.Context, kind component.Kind, id config.ComponentID, name string) (Spool, error)
+}
+
+// Spool is a persistent FIFO queue of encoded envelopes.
+type Spool interface {
+	// Push appends an envelope to the spool. The oldest envelopes of all
+	// spools are evicted if the size limit would be exceeded.
+	Push(context.Context, []byte) error
+
+	// Peek returns the oldest envelope without removing it.
+	// It returns nil, nil if the spool is empty.
+	Peek(context.Context) (*Entry, error)
+
+	// Remove deletes the entry with the given ID. It doesn't error if the
+	// entry doesn't exist anymore, e.g. because it was evicted.
+	Remove(context.Context, string) error
+
+	// Len returns the number of envelopes in the spool.
+	Len() int
+}
+
+// Entry is an envelope read from a spool.
+type Entry struct {
+	ID       string
+	Envelope []byte
+	Created  time.Time
+}
+
+type envelopeStorage struct {
+	directory string
+	maxSize   int64
+	maxAge    time.Duration
+	logger    *zap.Logger
+
+	mu     sync.Mutex
+	size   int64
+	seq    uint64
+	spools map[string]*spool
+	now    func() time.Time
+}
+
+var _ Extension = (*envelopeStorage)(nil)
+
+func newEnvelopeStorage(logger *zap.Logger, config *Config) (component.Extension, error) {
+	info, err := os.Stat(config.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("directory must exist: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("directory must exist: %s is not a directory", config.Directory)
+	}
+	if config.MaxSizeMiB <= 0 {
+		return nil, errors.New("'max_size_mib' must be greater than zero")
+	}
+
+	return &envelopeStorage{
+		directory: filepath.Clean(config.Directory),
+		maxSize:   config.MaxSizeMiB * 1024 * 1024,
+		maxAge:    config.MaxAge,
+		logger:    logger,
+		spools:    make(map[string]*spool),
+		now:       time.Now,
+	}, nil
+}
+
+func (es *envelopeStorage) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (es *envelopeStorage) Shutdown(context.Context) error {
+	return nil
+}
+
+func (es *envelopeStorage) GetSpool(_ context.Context, kind component.Kind, ent config.ComponentID, spoolName string) (Spool, error) {
+	name := fmt.Sprintf("%s_%s_%s", kindString(kind), ent.Type(), ent.Name())
+	if spoolName != "" {
+		name += "_" + spoolName
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if s, ok := es.spools[name]; ok {
+		return s, nil
+	}
+
+	dir := filepath.Join(es.directory, name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	s := &spool{storage: es, dir: dir}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	for _, e := range s.entries {
+		es.size += e.size
+	}
+	es.spools[name] = s
+
+	es.evictExpired()
+	es.makeRoom(0)
+
+	return s, nil
+}
+
+// evictExpired drops the envelopes older than the configured max age. Must be called with mu held.
+func (es *envelopeStorage) evictExpired() {
+	if es.maxAge <= 0 {
+		return
+	}
+
+	deadline := es.now().Add(-es.maxAge)
+	evicted := 0
+	for _, s := range es.spools {
+		for len(s.entries) > 0 && s.entries[0].created.Before(deadline) {
+			if err := s.removeAt(0); err != nil {
+				es.logger.Warn("Failed to delete expired envelope", zap.Error(err))
+			}
+			evicted++
+		}
+	}
+
+	if evicted > 0 {
+		es.logger.Warn("Evicted expired envelopes", zap.Int("count", evicted))
+	}
+}
+
+// makeRoom evicts the oldest envelopes until size more bytes fit. Must be called with mu held.
+func (es *envelopeStorage) makeRoom(size int64) {
+	for es.size+size > es.maxSize {
+		if !es.evictOldest() {
+			return
+		}
+	}
+}
+
+// evictOldest drops the oldest envelope across all spools. Must be called with mu held.
+func (es *envelopeStorage) evictOldest() bool {
+	var oldest *spool
+	for _, s := range es.spools {
+		if len(s.entries) == 0 {
+			continue
+		}
+		if oldest == nil || s.entries[0].name < oldest.entries[0].name {
+			oldest = s
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+
+	if err := oldest.removeAt(0); err != nil {
+		es.logger.Warn("Failed to delete evicted envelope", zap.Error(err))
+	}
+	es.logger.Warn("Evicted oldest envelope, storage is full", zap.String("spool", filepath.Base(oldest.dir)))
+	return true
+}
+
+// nextName returns the file name of a new envelope. Names sort in creation order. Must be called with mu held.
+func (es *envelopeStorage) nextName(created time.Time) string {
+	es.seq++
+	return fmt.Sprintf("%020d-%010d%s", created.UnixNano(), es.seq%1e10, envelopeFileSuffix)
+}
+
+type entry struct {
+	name    string
+	size    int64
+	created time.Time
+}
+
+type spool struct {
+	storage *envelopeStorage
+	dir     string
+	entries []entry
+}
+
+var _ Spool = (*spool)(nil)
+
+// load reads the envelopes left in the spool directory by a previous run.
+func (s *spool) load() error {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), envelopeFileSuffix) {
+			continue
+		}
+		created, parseErr := parseCreated(f.Name())
+		if parseErr != nil {
+			s.storage.logger.Warn("Ignoring unexpected file in spool", zap.String("file", f.Name()))
+			continue
+		}
+		s.entries = append(s.entries, entry{name: f.Name(), size: f.Size(), created: created})
+	}
+
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].name < s.entries[j].name
+	})
+	return nil
+}
+
+func (s *spool) Push(_ context.Context, envelope []byte) error {
+	es := s.storage
+	size := int64(len(envelope))
+	if size > es.maxSize {
+		return fmt.Errorf("envelope of %d bytes exceeds the storage size limit", size)
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	es.evictExpired()
+	es.makeRoom(size)
+
+	created := es.now()
+	name := es.nextName(created)
+	path := filepath.Join(s.dir, name)
+
+	// Write to a temporary file first so that a crash never leaves a truncated envelope behind.
+	if err := ioutil.WriteFile(path+".tmp", envelope, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+
+	s.entries = append(s.entries, entry{name: name, size: size, created: created})
+	es.size += size
+	return nil
+}
+
+func (s *spool) Peek(context.Context) (*Entry, error) {
+	es := s.storage
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	es.evictExpired()
+	for len(s.entries) > 0 {
+		e := s.entries[0]
+		data, err := ioutil.ReadFile(filepath.Join(s.dir, e.name))
+		if os.IsNotExist(err) {
+			// Deleted behind our back, forget about it.
+			s.removeAt(0)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &Entry{ID: e.name, Envelope: data, Created: e.created}, nil
+	}
+	return nil, nil
+}
+
+func (s *spool) Remove(_ context.Context, id string) error {
+	es := s.storage
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	for i, e := range s.entries {
+		if e.name == id {
+			return s.removeAt(i)
+		}
+	}
+	return nil
+}
+
+func (s *spool) Len() int {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+	return len(s.entries)
+}
+
+// removeAt deletes the i-th entry of the spool. Must be called with the storage mu held.
+func (s *spool) removeAt(i int) error {
+	e := s.entries[i]
+	s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	s.storage.size -= e.size
+
+	if err := os.Remove(filepath.Join(s.dir, e.name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func parseCreated(name string) (time.Time, error) {
+	sep := strings.IndexByte(name, '-')
+	if sep < 0 {
+		return time.Time{}, fmt.Errorf("malformed envelope file name %q", name)
+	}
+	nanos, err := strconv.ParseInt(name[:sep], 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, nanos), nil
+}
+
+func kindString(k component.Kind) string {
+	switch k {
+	case component.KindReceiver:
+		return "receiver"
+	case component.KindProcessor:
+		return "processor"
+	case component.KindExporter:
+		return "exporter"
+	case component.KindExtension:
+		return "extension"
+	default:
+		return "other" // not expected
+	}
+}
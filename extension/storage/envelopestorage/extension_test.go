@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelopestorage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSpoolIsFIFO(t *testing.T) {
+	ctx := context.Background()
+	es := newTestExtension(t, t.TempDir(), 1, 0)
+
+	s, err := es.GetSpool(ctx, component.KindExporter, config.NewIDWithName("sentry", "a"), "traces")
+	require.NoError(t, err)
+
+	entry, err := s.Peek(ctx)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+
+	for _, envelope := range []string{"first", "second", "third"} {
+		require.NoError(t, s.Push(ctx, []byte(envelope)))
+	}
+	require.Equal(t, 3, s.Len())
+
+	for _, expected := range []string{"first", "second", "third"} {
+		entry, err = s.Peek(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(entry.Envelope))
+
+		// Peeking again returns the same envelope until it is removed.
+		again, err := s.Peek(ctx)
+		require.NoError(t, err)
+		require.Equal(t, entry.ID, again.ID)
+
+		require.NoError(t, s.Remove(ctx, entry.ID))
+	}
+	require.Equal(t, 0, s.Len())
+
+	// Removing twice is a no-op.
+	require.NoError(t, s.Remove(ctx, entry.ID))
+}
+
+func TestSpoolsAreSharedPerComponent(t *testing.T) {
+	ctx := context.Background()
+	es := newTestExtension(t, t.TempDir(), 1, 0)
+
+	id := config.NewIDWithName("sentry", "a")
+	s1, err := es.GetSpool(ctx, component.KindExporter, id, "traces")
+	require.NoError(t, err)
+	s2, err := es.GetSpool(ctx, component.KindExporter, id, "traces")
+	require.NoError(t, err)
+	require.Same(t, s1, s2)
+
+	other, err := es.GetSpool(ctx, component.KindReceiver, id, "traces")
+	require.NoError(t, err)
+	require.NotSame(t, s1, other)
+
+	// The exporters of each data type created from one config get their own spool.
+	logs, err := es.GetSpool(ctx, component.KindExporter, id, "logs")
+	require.NoError(t, err)
+	require.NotSame(t, s1, logs)
+
+	require.NoError(t, s1.Push(ctx, []byte("envelope")))
+	require.Equal(t, 1, s2.Len())
+	require.Equal(t, 0, other.Len())
+	require.Equal(t, 0, logs.Len())
+}
+
+func TestSpoolEvictsOldestWhenFull(t *testing.T) {
+	ctx := context.Background()
+	es := newTestExtension(t, t.TempDir(), 1, 0)
+
+	a, err := es.GetSpool(ctx, component.KindExporter, config.NewIDWithName("sentry", "a"), "traces")
+	require.NoError(t, err)
+	b, err := es.GetSpool(ctx, component.KindExporter, config.NewIDWithName("sentry", "b"), "traces")
+	require.NoError(t, err)
+
+	half := make([]byte, 512*1024)
+	require.NoError(t, a.Push(ctx, half))
+	require.NoError(t, b.Push(ctx, half))
+
+	// The size limit is shared, so the oldest envelope of spool a makes room.
+	require.NoError(t, b.Push(ctx, half))
+	require.Equal(t, 0, a.Len())
+	require.Equal(t, 2, b.Len())
+
+	require.Error(t, a.Push(ctx, make([]byte, 2*1024*1024)))
+}
+
+func TestSpoolEvictsExpiredEnvelopes(t *testing.T) {
+	ctx := context.Background()
+	es := newTestExtension(t, t.TempDir(), 1, time.Hour)
+
+	now := time.Unix(1600000000, 0)
+	es.now = func() time.Time { return now }
+
+	s, err := es.GetSpool(ctx, component.KindExporter, config.NewID("sentry"), "traces")
+	require.NoError(t, err)
+
+	require.NoError(t, s.Push(ctx, []byte("old")))
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, s.Push(ctx, []byte("new")))
+	now = now.Add(45 * time.Minute)
+
+	entry, err := s.Peek(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "new", string(entry.Envelope))
+	require.Equal(t, 1, s.Len())
+}
+
+func TestSpoolSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	id := config.NewID("sentry")
+
+	es := newTestExtension(t, dir, 1, 0)
+	s, err := es.GetSpool(ctx, component.KindExporter, id, "traces")
+	require.NoError(t, err)
+	require.NoError(t, s.Push(ctx, []byte("first")))
+	require.NoError(t, s.Push(ctx, []byte("second")))
+
+	// Leftovers of an interrupted write are ignored.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "exporter_sentry__traces", "123-1.envelope.tmp"), []byte("partial"), 0600))
+
+	restarted := newTestExtension(t, dir, 1, 0)
+	s, err = restarted.GetSpool(ctx, component.KindExporter, id, "traces")
+	require.NoError(t, err)
+	require.Equal(t, 2, s.Len())
+	require.EqualValues(t, len("first")+len("second"), restarted.size)
+
+	entry, err := s.Peek(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "first", string(entry.Envelope))
+}
+
+func TestSpoolSkipsDeletedEnvelopes(t *testing.T) {
+	ctx := context.Background()
+	es := newTestExtension(t, t.TempDir(), 1, 0)
+
+	s, err := es.GetSpool(ctx, component.KindExporter, config.NewID("sentry"), "traces")
+	require.NoError(t, err)
+	require.NoError(t, s.Push(ctx, []byte("first")))
+	require.NoError(t, s.Push(ctx, []byte("second")))
+
+	entry, err := s.Peek(ctx)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(filepath.Join(s.(*spool).dir, entry.ID)))
+
+	entry, err = s.Peek(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(entry.Envelope))
+	require.Equal(t, 1, s.Len())
+}
+
+func newTestExtension(t *testing.T, dir string, maxSizeMiB int64, maxAge time.Duration) *envelopeStorage {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Directory = dir
+	cfg.MaxSizeMiB = maxSizeMiB
+	cfg.MaxAge = maxAge
+
+	params := component.ExtensionCreateParams{Logger: zaptest.NewLogger(t)}
+
+	extension, err := f.CreateExtension(context.Background(), params, cfg)
+	require.NoError(t, err)
+
+	es, ok := extension.(*envelopeStorage)
+	require.True(t, ok)
+	return es
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelopestorage
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/extension/extensionhelper"
+)
+
+// The value of extension "type" in configuration.
+const typeStr config.Type = "envelope_storage"
+
+const (
+	defaultMaxSizeMiB = 100
+	defaultMaxAge     = 24 * time.Hour
+)
+
+// NewFactory creates a factory for the envelope storage extension.
+func NewFactory() component.ExtensionFactory {
+	return extensionhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		createExtension)
+}
+
+func createDefaultConfig() config.Extension {
+	return &Config{
+		ExtensionSettings: config.NewExtensionSettings(config.NewID(typeStr)),
+		Directory:         getDefaultDirectory(),
+		MaxSizeMiB:        defaultMaxSizeMiB,
+		MaxAge:            defaultMaxAge,
+	}
+}
+
+func createExtension(
+	_ context.Context,
+	params component.ExtensionCreateParams,
+	cfg config.Extension,
+) (component.Extension, error) {
+	return newEnvelopeStorage(params.Logger, cfg.(*Config))
+}
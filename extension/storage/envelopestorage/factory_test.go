@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelopestorage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.uber.org/zap"
+)
+
+func TestFactory(t *testing.T) {
+	f := NewFactory()
+	require.Equal(t, typeStr, f.Type())
+
+	cfg := f.CreateDefaultConfig().(*Config)
+	require.Equal(t, config.NewID(typeStr), cfg.ID())
+
+	if runtime.GOOS != "windows" {
+		require.Equal(t, "/var/lib/otelcol/envelope_storage", cfg.Directory)
+	} else {
+		expected := filepath.Join(os.Getenv("ProgramData"), "Otelcol", "EnvelopeStorage")
+		require.Equal(t, expected, cfg.Directory)
+	}
+	require.EqualValues(t, defaultMaxSizeMiB, cfg.MaxSizeMiB)
+	require.Equal(t, defaultMaxAge, cfg.MaxAge)
+
+	tempDir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+	file := filepath.Join(tempDir, "file")
+	require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+
+	tests := []struct {
+		name           string
+		config         *Config
+		wantErrMessage string
+	}{
+		{
+			name:           "Default",
+			config:         cfg,
+			wantErrMessage: "directory must exist",
+		},
+		{
+			name:           "Not a directory",
+			config:         &Config{Directory: file, MaxSizeMiB: 1},
+			wantErrMessage: "directory must exist",
+		},
+		{
+			// Stat fails with another error than not existing.
+			name:           "Below a file",
+			config:         &Config{Directory: filepath.Join(file, "envelopes"), MaxSizeMiB: 1},
+			wantErrMessage: "directory must exist",
+		},
+		{
+			name:           "Invalid size",
+			config:         &Config{Directory: tempDir},
+			wantErrMessage: "'max_size_mib' must be greater than zero",
+		},
+		{
+			name:   "Valid",
+			config: &Config{Directory: tempDir, MaxSizeMiB: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := f.CreateExtension(
+				context.Background(),
+				component.ExtensionCreateParams{
+					Logger: zap.NewNop(),
+				},
+				test.config,
+			)
+			if test.wantErrMessage != "" {
+				require.Error(t, err)
+				require.True(t, strings.HasPrefix(err.Error(), test.wantErrMessage))
+				require.Nil(t, e)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, e)
+				ctx := context.Background()
+				require.NoError(t, e.Start(ctx, componenttest.NewNopHost()))
+				require.NoError(t, e.Shutdown(ctx))
+			}
+		})
+	}
+}
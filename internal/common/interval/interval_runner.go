@@ -0,0 +1,196 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interval runs tasks periodically, such as the scrapes of receivers.
+package interval
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Runnable must be implemented by types passed into the Runner constructor.
+type Runnable interface {
+	// called once at Start() time
+	Setup() error
+	// called on the interval defined by the
+	// settings passed into NewRunner
+	Run() error
+}
+
+// Settings defines when a Runner runs its Runnables.
+type Settings struct {
+	// Interval is the time between the starts of two runs. It must be positive.
+	Interval time.Duration
+	// InitialDelay is the time between Start() and the first run.
+	InitialDelay time.Duration
+	// Jitter is the maximum random delay added to each run, so that the runs of
+	// several runners with the same interval are spread out. Runs are still
+	// scheduled at multiples of the interval, the jitter does not accumulate.
+	Jitter time.Duration
+}
+
+// Runner calls Setup() on each of its Runnables and then, on each interval,
+// calls Run() on each of them sequentially. A run starting while the previous
+// one is still executing is skipped. Call Stop() to stop the Runner.
+type Runner struct {
+	settings  Settings
+	runnables []Runnable
+
+	mu       sync.Mutex
+	started  bool
+	done     chan struct{}
+	stopOnce sync.Once
+	exited   chan struct{}
+
+	running int32
+	skipped int64
+	runs    sync.WaitGroup
+	errs    chan error
+}
+
+// NewRunner creates a new interval runner running the Runnables as defined by
+// settings.
+func NewRunner(settings Settings, runnables ...Runnable) *Runner {
+	return &Runner{
+		settings:  settings,
+		runnables: runnables,
+		done:      make(chan struct{}),
+		exited:    make(chan struct{}),
+		errs:      make(chan error, 1),
+	}
+}
+
+// Start calls Setup() on the Runnables and then runs them until the Runner is
+// stopped, ctx is done or a Runnable fails, whose error is returned. It waits
+// for the current run to finish before returning. Start must be called once.
+func (r *Runner) Start(ctx context.Context) error {
+	r.mu.Lock()
+	select {
+	case <-r.done:
+		r.mu.Unlock()
+		return nil
+	default:
+	}
+	r.started = true
+	r.mu.Unlock()
+	defer close(r.exited)
+
+	if err := r.setup(); err != nil {
+		return err
+	}
+	err := r.loop(ctx)
+	r.runs.Wait()
+	return err
+}
+
+// Stop stops the Runner and waits for the current run to finish, or for ctx
+// to be done, in which case the error of ctx is returned. It can be called
+// more than once.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	r.stopOnce.Do(func() {
+		close(r.done)
+	})
+	started := r.started
+	r.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+	select {
+	case <-r.exited:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Skipped returns the number of runs skipped because the previous run was
+// still executing.
+func (r *Runner) Skipped() int64 {
+	return atomic.LoadInt64(&r.skipped)
+}
+
+func (r *Runner) setup() error {
+	for _, runnable := range r.runnables {
+		err := runnable.Setup()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context) error {
+	start := time.Now()
+	timer := time.NewTimer(r.settings.InitialDelay + r.jitter())
+	defer timer.Stop()
+
+	for next := 1; ; next++ {
+		select {
+		case <-timer.C:
+		case err := <-r.errs:
+			return err
+		case <-r.done:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+
+		r.trigger()
+
+		// Runs missed while the process was not scheduled are not caught up on.
+		elapsed := time.Since(start) - r.settings.InitialDelay
+		if missed := int(elapsed / r.settings.Interval); missed >= next {
+			next = missed + 1
+		}
+		at := start.Add(r.settings.InitialDelay + time.Duration(next)*r.settings.Interval + r.jitter())
+		timer.Reset(time.Until(at))
+	}
+}
+
+// trigger starts a run of the Runnables, unless the previous one is still executing.
+func (r *Runner) trigger() {
+	if !atomic.CompareAndSwapInt32(&r.running, 0, 1) {
+		atomic.AddInt64(&r.skipped, 1)
+		return
+	}
+
+	r.runs.Add(1)
+	go func() {
+		defer r.runs.Done()
+		defer atomic.StoreInt32(&r.running, 0)
+
+		for _, runnable := range r.runnables {
+			if err := runnable.Run(); err != nil {
+				select {
+				case r.errs <- err:
+				default:
+				}
+				return
+			}
+		}
+	}()
+}
+
+func (r *Runner) jitter() time.Duration {
+	if r.settings.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(r.settings.Jitter)))
+}
@@ -0,0 +1,171 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interval
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	f := &fakeRunnable{}
+	s := NewRunner(Settings{Interval: time.Second, InitialDelay: time.Second}, f)
+	go func() {
+		_ = s.Start(context.Background())
+	}()
+	assert.NoError(t, s.Stop(context.Background()))
+	// getting here is success
+}
+
+func TestInitialDelay(t *testing.T) {
+	f := &fakeRunnable{}
+	s := NewRunner(Settings{Interval: time.Hour}, f)
+	done := startRunner(s)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&f.runs) == 1
+	}, 5*time.Second, 10*time.Millisecond, "the first run does not wait for the interval")
+
+	require.NoError(t, s.Stop(context.Background()))
+	assert.NoError(t, waitStart(t, done))
+}
+
+func TestStopDuringInitialDelay(t *testing.T) {
+	f := &fakeRunnable{}
+	s := NewRunner(Settings{Interval: time.Hour, InitialDelay: time.Hour}, f)
+	require.NoError(t, s.Stop(context.Background()))
+	require.NoError(t, s.Stop(context.Background()))
+	assert.NoError(t, s.Start(context.Background()))
+	assert.EqualValues(t, 0, f.runs)
+}
+
+func TestRunsOnInterval(t *testing.T) {
+	f := &fakeRunnable{}
+	s := NewRunner(Settings{Interval: 10 * time.Millisecond, Jitter: 5 * time.Millisecond}, f)
+	done := startRunner(s)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&f.runs) >= 3
+	}, 5*time.Second, time.Millisecond)
+
+	require.NoError(t, s.Stop(context.Background()))
+	assert.NoError(t, waitStart(t, done))
+	assert.Zero(t, s.Skipped())
+}
+
+func TestSkipsOverlappingRuns(t *testing.T) {
+	f := &fakeRunnable{block: make(chan struct{})}
+	s := NewRunner(Settings{Interval: time.Millisecond}, f)
+	done := startRunner(s)
+
+	require.Eventually(t, func() bool {
+		return s.Skipped() >= 3
+	}, 5*time.Second, time.Millisecond, "runs are skipped while the first one executes")
+	assert.EqualValues(t, 1, atomic.LoadInt32(&f.runs))
+
+	close(f.block)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&f.runs) >= 2
+	}, 5*time.Second, time.Millisecond, "runs resume once the first one finished")
+
+	require.NoError(t, s.Stop(context.Background()))
+	assert.NoError(t, waitStart(t, done))
+}
+
+func TestStopWaitsForRun(t *testing.T) {
+	f := &fakeRunnable{block: make(chan struct{})}
+	s := NewRunner(Settings{Interval: time.Hour}, f)
+	done := startRunner(s)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&f.runs) == 1
+	}, 5*time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, s.Stop(ctx), "Stop gives up once its context is done")
+
+	close(f.block)
+	assert.NoError(t, s.Stop(context.Background()))
+	assert.NoError(t, waitStart(t, done))
+}
+
+func TestStartReturnsWhenContextDone(t *testing.T) {
+	s := NewRunner(Settings{Interval: time.Hour, InitialDelay: time.Hour}, &fakeRunnable{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Start(ctx)
+	}()
+
+	cancel()
+	assert.NoError(t, waitStart(t, done))
+}
+
+func TestStartReturnsRunError(t *testing.T) {
+	f := &fakeRunnable{err: errors.New("failed")}
+	s := NewRunner(Settings{Interval: time.Millisecond}, f)
+	assert.EqualError(t, s.Start(context.Background()), "failed")
+	assert.NoError(t, s.Stop(context.Background()))
+}
+
+func TestStartReturnsSetupError(t *testing.T) {
+	f := &fakeRunnable{setupErr: errors.New("failed")}
+	s := NewRunner(Settings{Interval: time.Millisecond}, f)
+	assert.EqualError(t, s.Start(context.Background()), "failed")
+	assert.EqualValues(t, 0, f.runs)
+}
+
+func startRunner(s *Runner) chan error {
+	done := make(chan error)
+	go func() {
+		done <- s.Start(context.Background())
+	}()
+	return done
+}
+
+func waitStart(t *testing.T, done chan error) error {
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return")
+		return nil
+	}
+}
+
+type fakeRunnable struct {
+	runs     int32
+	block    chan struct{}
+	err      error
+	setupErr error
+}
+
+func (t *fakeRunnable) Setup() error {
+	return t.setupErr
+}
+
+func (t *fakeRunnable) Run() error {
+	atomic.AddInt32(&t.runs, 1)
+	if t.block != nil {
+		<-t.block
+	}
+	return t.err
+}
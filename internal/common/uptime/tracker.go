@@ -0,0 +1,100 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package uptime derives the start time of cumulative metrics from the
+// uptime reported by a server, such as the uptime_in_seconds of Redis.
+package uptime
+
+import "time"
+
+// Tolerance is the difference between the growth of the uptime and the
+// elapsed time, or between the wall clock and the monotonic clock, above
+// which a restart or a clock jump is assumed. It absorbs the rounding of
+// uptimes to whole seconds and the latency of the requests.
+const Tolerance = 5 * time.Second
+
+// Tracker provides the start time of a server for cumulative metrics, and the
+// current (server) time for all metrics. The start time is calculated by
+// subtracting the uptime from the current time, when the tracker is created or
+// when a restart of the server is detected. The current time is the start time
+// plus the uptime.
+//
+// Elapsed times are measured with the monotonic clock of the times passed to
+// the tracker, as returned by time.Now(), so that jumps of the wall clock are
+// neither mistaken for restarts nor hide them. When the wall clock jumps, the
+// start time is moved along with it.
+type Tracker struct {
+	start      time.Time
+	current    time.Time
+	last       time.Time
+	lastUptime time.Duration
+	restarts   int64
+}
+
+// NewTracker returns a tracker of a server reporting uptime at now.
+func NewTracker(now time.Time, uptime time.Duration) *Tracker {
+	return &Tracker{
+		start:      now.Add(-uptime),
+		current:    now,
+		last:       now,
+		lastUptime: uptime,
+	}
+}
+
+// Update records the uptime reported by the server at now, and returns
+// whether the server restarted since the previous update. A restart is
+// detected when the uptime decreased, or grew by less than the time elapsed
+// since the previous update.
+func (t *Tracker) Update(now time.Time, uptime time.Duration) bool {
+	// Sub uses the monotonic clock when both times have a reading of it.
+	elapsed := now.Sub(t.last)
+	wallElapsed := now.Round(0).Sub(t.last.Round(0))
+	return t.update(now, elapsed, wallElapsed, uptime)
+}
+
+func (t *Tracker) update(now time.Time, elapsed, wallElapsed, uptime time.Duration) bool {
+	t.last = now
+	growth := uptime - t.lastUptime
+	t.lastUptime = uptime
+
+	if growth < 0 || growth < elapsed-Tolerance {
+		t.restarts++
+		t.start = now.Add(-uptime)
+		t.current = now
+		return true
+	}
+
+	if jump := wallElapsed - elapsed; jump > Tolerance || jump < -Tolerance {
+		t.start = t.start.Add(jump)
+	}
+	t.current = t.start.Add(uptime)
+	return false
+}
+
+// Start returns the time the server started.
+func (t *Tracker) Start() time.Time {
+	return t.start
+}
+
+// Current returns the time of the last update, according to the uptime of the
+// server.
+func (t *Tracker) Current() time.Time {
+	return t.current
+}
+
+// Restarts returns the number of restarts detected since the tracker was
+// created.
+func (t *Tracker) Restarts() int64 {
+	return t.restarts
+}
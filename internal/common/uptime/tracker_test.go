@@ -0,0 +1,79 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(time.Unix(1000, 0), 60*time.Second)
+	assert.Equal(t, time.Unix(940, 0), tracker.Start())
+	assert.Equal(t, time.Unix(1000, 0), tracker.Current())
+
+	assert.False(t, tracker.Update(time.Unix(1010, 0), 70*time.Second))
+	assert.Equal(t, time.Unix(940, 0), tracker.Start())
+	assert.Equal(t, time.Unix(1010, 0), tracker.Current())
+
+	// The uptime is rounded to seconds.
+	assert.False(t, tracker.Update(time.Unix(1020, 0), 79*time.Second))
+	assert.Equal(t, time.Unix(1019, 0), tracker.Current())
+	assert.EqualValues(t, 0, tracker.Restarts())
+}
+
+func TestTrackerRestart(t *testing.T) {
+	tracker := NewTracker(time.Unix(1000, 0), 60*time.Second)
+
+	// A lower uptime.
+	assert.True(t, tracker.Update(time.Unix(1030, 0), 10*time.Second))
+	assert.Equal(t, time.Unix(1020, 0), tracker.Start())
+	assert.Equal(t, time.Unix(1030, 0), tracker.Current())
+
+	// A higher uptime, that grew less than the elapsed time.
+	assert.True(t, tracker.Update(time.Unix(1330, 0), 100*time.Second))
+	assert.Equal(t, time.Unix(1230, 0), tracker.Start())
+	assert.Equal(t, time.Unix(1330, 0), tracker.Current())
+
+	// A restart right after the previous one is still detected.
+	assert.True(t, tracker.Update(time.Unix(1340, 0), 5*time.Second))
+	assert.Equal(t, time.Unix(1335, 0), tracker.Start())
+	assert.EqualValues(t, 3, tracker.Restarts())
+}
+
+func TestTrackerClockJump(t *testing.T) {
+	tracker := NewTracker(time.Unix(1000, 0), 60*time.Second)
+
+	// The wall clock jumps an hour ahead while ten seconds elapse, and the
+	// uptime grows by ten seconds: not a restart.
+	assert.False(t, tracker.update(time.Unix(4610, 0), 10*time.Second, 3610*time.Second, 70*time.Second))
+	assert.Equal(t, time.Unix(4540, 0), tracker.Start())
+	assert.Equal(t, time.Unix(4610, 0), tracker.Current())
+
+	// The wall clock jumps back, while the server restarted.
+	assert.True(t, tracker.update(time.Unix(1020, 0), 10*time.Second, -3590*time.Second, 5*time.Second))
+	assert.Equal(t, time.Unix(1015, 0), tracker.Start())
+	assert.EqualValues(t, 1, tracker.Restarts())
+}
+
+func TestTrackerMonotonicClock(t *testing.T) {
+	now := time.Now()
+	tracker := NewTracker(now, time.Minute)
+	assert.False(t, tracker.Update(now.Add(10*time.Second), time.Minute+10*time.Second))
+	assert.Equal(t, now.Add(-time.Minute), tracker.Start())
+	assert.True(t, tracker.Update(now.Add(20*time.Second), time.Second))
+}
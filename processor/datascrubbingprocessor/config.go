@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datascrubbingprocessor
+
+import (
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the data scrubbing processor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Rules are the data scrubbing rules, applied in order.
+	Rules []RuleConfig `mapstructure:"rules"`
+}
+
+// RuleConfig defines a data scrubbing rule, following the advanced data scrubbing rules of Sentry.
+type RuleConfig struct {
+	// Method is how matching data is scrubbed: mask, remove, hash or replace.
+	Method string `mapstructure:"method"`
+
+	// Type is the kind of data that is scrubbed, e.g. anything, pattern, password, creditcard, ip or email.
+	Type string `mapstructure:"type"`
+
+	// Pattern is the regular expression matching the data to scrub, for the "pattern" type.
+	Pattern string `mapstructure:"pattern"`
+
+	// Placeholder replaces the scrubbed data for the "replace" method. Defaults to "[<type>]".
+	Placeholder string `mapstructure:"placeholder"`
+
+	// Source is a selector of the values the rule applies to, e.g. "$http.headers.Authorization".
+	// Defaults to "$string", i.e. all string values.
+	Source string `mapstructure:"source"`
+}
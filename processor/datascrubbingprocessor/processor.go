@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datascrubbingprocessor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// messagePath is the path of the body of log records, so that it can be selected with $message.
+var messagePath = []string{"message"}
+
+type dataScrubbingProcessor struct {
+	rules []*rule
+}
+
+func newDataScrubbingProcessor(cfg *Config) (*dataScrubbingProcessor, error) {
+	dsp := &dataScrubbingProcessor{}
+	for i, rc := range cfg.Rules {
+		r, err := newRule(rc)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		dsp.rules = append(dsp.rules, r)
+	}
+	return dsp, nil
+}
+
+// ProcessTraces scrubs the attributes of spans and span events.
+func (dsp *dataScrubbingProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				dsp.scrubAttributes(nil, span.Attributes())
+				events := span.Events()
+				for l := 0; l < events.Len(); l++ {
+					dsp.scrubAttributes(nil, events.At(l).Attributes())
+				}
+			}
+		}
+	}
+	return td, nil
+}
+
+// ProcessLogs scrubs the body and the attributes of log records.
+func (dsp *dataScrubbingProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if dsp.scrubValue(messagePath, lr.Body()) {
+					pdata.NewAttributeValueNull().CopyTo(lr.Body())
+				}
+				dsp.scrubAttributes(nil, lr.Attributes())
+			}
+		}
+	}
+	return ld, nil
+}
+
+// scrubAttributes scrubs the values of an attribute map. The path of a value consists of
+// the path of the map and the attribute key split at dots, so that "http.request.header.cookie"
+// can be selected with $http.headers.cookie.
+func (dsp *dataScrubbingProcessor) scrubAttributes(path []string, attrs pdata.AttributeMap) {
+	var removed []string
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		if dsp.scrubValue(appendPath(path, strings.Split(k, ".")...), v) {
+			removed = append(removed, k)
+		}
+		return true
+	})
+	for _, k := range removed {
+		attrs.Delete(k)
+	}
+}
+
+// scrubValue applies the rules to a value and its children. It returns true if the
+// value must be removed.
+func (dsp *dataScrubbingProcessor) scrubValue(path []string, v pdata.AttributeValue) bool {
+	switch v.Type() {
+	case pdata.AttributeValueTypeMap:
+		dsp.scrubAttributes(path, v.MapVal())
+	case pdata.AttributeValueTypeArray:
+		values := v.ArrayVal()
+		for i := 0; i < values.Len(); i++ {
+			if dsp.scrubValue(appendPath(path, strconv.Itoa(i)), values.At(i)) {
+				pdata.NewAttributeValueNull().CopyTo(values.At(i))
+			}
+		}
+	}
+
+	typ := valueTypeOf(v)
+	for _, r := range dsp.rules {
+		var str string
+		if typ == valueTypeString {
+			str = v.StringVal()
+		}
+		if !r.applies(path, typ, str) {
+			continue
+		}
+		if r.method == methodRemove {
+			return true
+		}
+		if typ == valueTypeString {
+			v.SetStringVal(r.redact(str))
+		}
+	}
+	return false
+}
+
+func appendPath(path []string, items ...string) []string {
+	return append(append(make([]string, 0, len(path)+len(items)), path...), items...)
+}
+
+func valueTypeOf(v pdata.AttributeValue) valueType {
+	switch v.Type() {
+	case pdata.AttributeValueTypeString:
+		return valueTypeString
+	case pdata.AttributeValueTypeInt, pdata.AttributeValueTypeDouble:
+		return valueTypeNumber
+	case pdata.AttributeValueTypeBool:
+		return valueTypeBoolean
+	case pdata.AttributeValueTypeArray:
+		return valueTypeArray
+	case pdata.AttributeValueTypeMap:
+		return valueTypeObject
+	default:
+		return valueTypeNull
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datascrubbingprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func newTestProcessor(t *testing.T, rules ...RuleConfig) *dataScrubbingProcessor {
+	dsp, err := newDataScrubbingProcessor(&Config{Rules: rules})
+	require.NoError(t, err)
+	return dsp
+}
+
+func TestProcessTraces(t *testing.T) {
+	dsp := newTestProcessor(t,
+		RuleConfig{Method: methodRemove, Type: typeAnything, Source: "$http.headers.Authorization"},
+		RuleConfig{Method: methodMask, Type: typeCreditCard},
+		RuleConfig{Method: methodReplace, Type: typeEmail, Source: "$error.value"},
+	)
+
+	td := pdata.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.Attributes().InsertString("http.request.header.authorization", "Bearer token")
+	span.Attributes().InsertString("http.request.header.accept", "text/html")
+	span.Attributes().InsertString(conventions.AttributeHTTPURL, "/pay?card=4242-4242-4242-4242")
+	span.Attributes().InsertInt(conventions.AttributeHTTPStatusCode, 500)
+	event := span.Events().AppendEmpty()
+	event.SetName(conventions.AttributeExceptionEventName)
+	event.Attributes().InsertString(conventions.AttributeExceptionMessage, "no user jane@example.com")
+	event.Attributes().InsertString(conventions.AttributeExceptionType, "jane@example.com")
+
+	_, err := dsp.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"http.request.header.accept":        "text/html",
+		conventions.AttributeHTTPURL:        "/pay?card=*******************",
+		conventions.AttributeHTTPStatusCode: int64(500),
+	}, attributesToMap(span.Attributes()))
+	assert.Equal(t, map[string]interface{}{
+		conventions.AttributeExceptionMessage: "no user [email]",
+		conventions.AttributeExceptionType:    "jane@example.com",
+	}, attributesToMap(event.Attributes()))
+}
+
+func TestProcessLogs(t *testing.T) {
+	dsp := newTestProcessor(t,
+		RuleConfig{Method: methodMask, Type: typeIP, Source: "$message"},
+		RuleConfig{Method: methodRemove, Type: typePassword},
+		RuleConfig{Method: methodHash, Type: typeAnything, Source: "user.ids.*"},
+	)
+
+	ld := pdata.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.Body().SetStringVal("login from 192.168.0.1")
+	lr.Attributes().InsertString("client.ip", "192.168.0.1")
+
+	credentials := pdata.NewAttributeValueMap()
+	credentials.MapVal().InsertString("username", "jane")
+	credentials.MapVal().InsertString("password", "hunter2")
+	lr.Attributes().Insert("credentials", credentials)
+
+	ids := pdata.NewAttributeValueArray()
+	ids.ArrayVal().AppendEmpty().SetStringVal("jane")
+	lr.Attributes().Insert("user.ids", ids)
+
+	_, err := dsp.ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	assert.Equal(t, "login from ***********", lr.Body().StringVal())
+	attrs := attributesToMap(lr.Attributes())
+	assert.Equal(t, "192.168.0.1", attrs["client.ip"])
+	assert.Equal(t, map[string]interface{}{"username": "jane"}, attrs["credentials"])
+	require.Len(t, attrs["user.ids"], 1)
+	assert.Regexp(t, "^[0-9A-F]{40}$", attrs["user.ids"].([]interface{})[0])
+}
+
+func TestProcessRemoveArrayElement(t *testing.T) {
+	dsp := newTestProcessor(t, RuleConfig{Method: methodRemove, Type: typeEmail})
+
+	ld := pdata.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.Body().SetStringVal("jane@example.com")
+	emails := pdata.NewAttributeValueArray()
+	emails.ArrayVal().AppendEmpty().SetStringVal("jane@example.com")
+	emails.ArrayVal().AppendEmpty().SetStringVal("unknown")
+	lr.Attributes().Insert("emails", emails)
+
+	_, err := dsp.ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	assert.Equal(t, pdata.AttributeValueTypeNull, lr.Body().Type())
+	assert.Equal(t, map[string]interface{}{"emails": []interface{}{nil, "unknown"}}, attributesToMap(lr.Attributes()))
+}
+
+func attributesToMap(attrs pdata.AttributeMap) map[string]interface{} {
+	m := map[string]interface{}{}
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		m[k] = valueToInterface(v)
+		return true
+	})
+	return m
+}
+
+func valueToInterface(v pdata.AttributeValue) interface{} {
+	switch v.Type() {
+	case pdata.AttributeValueTypeString:
+		return v.StringVal()
+	case pdata.AttributeValueTypeInt:
+		return v.IntVal()
+	case pdata.AttributeValueTypeDouble:
+		return v.DoubleVal()
+	case pdata.AttributeValueTypeBool:
+		return v.BoolVal()
+	case pdata.AttributeValueTypeMap:
+		return attributesToMap(v.MapVal())
+	case pdata.AttributeValueTypeArray:
+		var values []interface{}
+		for i := 0; i < v.ArrayVal().Len(); i++ {
+			values = append(values, valueToInterface(v.ArrayVal().At(i)))
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datascrubbingprocessor
+
+import (
+	"crypto/sha1" // #nosec G505 -- matches the hash method of Sentry's data scrubbing, not used for security
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The redaction methods of a rule.
+const (
+	methodMask    = "mask"
+	methodRemove  = "remove"
+	methodHash    = "hash"
+	methodReplace = "replace"
+)
+
+// The types of a rule, i.e. what data the rule scrubs.
+const (
+	typeAnything   = "anything"
+	typePattern    = "pattern"
+	typePassword   = "password"
+	typeCreditCard = "creditcard"
+	typeIP         = "ip"
+	typeEmail      = "email"
+	typeIMEI       = "imei"
+	typeMAC        = "mac"
+	typeUUID       = "uuid"
+	typePEMKey     = "pemkey"
+	typeURLAuth    = "url_auth"
+	typeUSSSN      = "usssn"
+	typeUserPath   = "userpath"
+)
+
+// builtinPatterns are the patterns of the builtin rule types of Sentry. If a pattern has
+// a capturing group, only the group is scrubbed.
+var builtinPatterns = map[string]*regexp.Regexp{
+	typeCreditCard: regexp.MustCompile(`\b\d{4}[- ]?\d{4,6}[- ]?\d{4,5}(?:[- ]?\d{4})?\b`),
+	typeIP: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b|` +
+		`(?i)\b(?:[0-9a-f]{1,4}:){7}[0-9a-f]{1,4}\b|(?i)\b(?:[0-9a-f]{1,4}:){1,6}:(?:[0-9a-f]{1,4}\b)?|::1\b`),
+	typeEmail:    regexp.MustCompile(`[a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9-]+(?:\.[a-zA-Z0-9-]+)+`),
+	typeIMEI:     regexp.MustCompile(`\b\d{2}-?\d{6}-?\d{6}-?\d{1,2}\b`),
+	typeMAC:      regexp.MustCompile(`(?i)\b(?:[0-9a-f]{2}[:-]){5}[0-9a-f]{2}\b`),
+	typeUUID:     regexp.MustCompile(`(?i)\b[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}\b`),
+	typePEMKey:   regexp.MustCompile(`(?s)-----BEGIN[A-Z ]* (?:PRIVATE|PUBLIC) KEY-----\s*(.+?)\s*-----END[A-Z ]* (?:PRIVATE|PUBLIC) KEY-----`),
+	typeURLAuth:  regexp.MustCompile(`(?i)\b[a-z][a-z0-9+.-]*://([^@/\s:]+:[^@/\s]+)@`),
+	typeUSSSN:    regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	typeUserPath: regexp.MustCompile(`(?i)(?:/Users/|/home/|[a-z]:\\Users\\)([^/\\\s]+)`),
+}
+
+// passwordKeys matches the keys of values that are scrubbed by the password rule type.
+var passwordKeys = regexp.MustCompile(`(?i)pass|secret|passwd|api_?key|auth|credentials|mysql_pwd|private_?key|token|bearer|session|csrf|xsrf`)
+
+// rule is a compiled data scrubbing rule.
+type rule struct {
+	method      string
+	typ         string
+	pattern     *regexp.Regexp
+	placeholder string
+	selector    selector
+}
+
+func newRule(cfg RuleConfig) (*rule, error) {
+	r := &rule{
+		method:      cfg.Method,
+		typ:         cfg.Type,
+		placeholder: cfg.Placeholder,
+	}
+
+	switch r.method {
+	case methodMask, methodRemove, methodHash, methodReplace:
+	default:
+		return nil, fmt.Errorf("unknown method %q", cfg.Method)
+	}
+
+	switch r.typ {
+	case typeAnything, typePassword:
+	case typePattern:
+		if cfg.Pattern == "" {
+			return nil, fmt.Errorf("rule type %q requires a pattern", typePattern)
+		}
+		pattern, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+		r.pattern = pattern
+	default:
+		pattern, ok := builtinPatterns[r.typ]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q", cfg.Type)
+		}
+		r.pattern = pattern
+	}
+
+	if r.placeholder == "" {
+		r.placeholder = "[" + r.typ + "]"
+	}
+
+	source := cfg.Source
+	if source == "" {
+		source = "$string"
+	}
+	sel, err := parseSelector(source)
+	if err != nil {
+		return nil, err
+	}
+	r.selector = sel
+
+	return r, nil
+}
+
+// applies returns whether the rule applies to the value at the given path.
+func (r *rule) applies(path []string, typ valueType, value string) bool {
+	if !r.selector.matches(path, typ) {
+		return false
+	}
+	switch r.typ {
+	case typeAnything:
+		return true
+	case typePassword:
+		return len(path) > 0 && passwordKeys.MatchString(path[len(path)-1])
+	default:
+		return typ == valueTypeString && r.pattern.MatchString(value)
+	}
+}
+
+// redact returns the scrubbed value. It must only be called for rules that apply to the
+// value and whose method isn't remove.
+func (r *rule) redact(value string) string {
+	if r.pattern == nil {
+		return r.redactMatch(value)
+	}
+
+	hasGroup := r.pattern.NumSubexp() > 0
+	var b strings.Builder
+	last := 0
+	for _, m := range r.pattern.FindAllStringSubmatchIndex(value, -1) {
+		start, end := m[0], m[1]
+		if hasGroup && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(value[last:start])
+		b.WriteString(r.redactMatch(value[start:end]))
+		last = end
+	}
+	b.WriteString(value[last:])
+	return b.String()
+}
+
+func (r *rule) redactMatch(match string) string {
+	switch r.method {
+	case methodMask:
+		return strings.Repeat("*", len([]rune(match)))
+	case methodHash:
+		sum := sha1.Sum([]byte(match)) // #nosec G401
+		return strings.ToUpper(hex.EncodeToString(sum[:]))
+	default:
+		return r.placeholder
+	}
+}
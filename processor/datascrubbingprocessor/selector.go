@@ -0,0 +1,426 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datascrubbingprocessor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// valueType is the type of a value selected by a selector.
+type valueType int
+
+const (
+	valueTypeString valueType = iota
+	valueTypeNumber
+	valueTypeBoolean
+	valueTypeArray
+	valueTypeObject
+	valueTypeNull
+)
+
+// valueTypeSelectors are the selectors that match values by their type.
+var valueTypeSelectors = map[string]valueType{
+	"$string":  valueTypeString,
+	"$number":  valueTypeNumber,
+	"$boolean": valueTypeBoolean,
+	"$array":   valueTypeArray,
+	"$object":  valueTypeObject,
+}
+
+// interfaceSelectors map the Sentry event interfaces to the OpenTelemetry attribute
+// namespaces holding the same data.
+var interfaceSelectors = map[string][]string{
+	"$http":       {"http"},
+	"$user":       {"enduser"},
+	"$error":      {"exception"},
+	"$exception":  {"exception"},
+	"$stacktrace": {"exception", "stacktrace"},
+	"$message":    {"message"},
+	"$logentry":   {"message"},
+}
+
+// interfaceFieldAliases map fields of Sentry event interfaces to the corresponding
+// OpenTelemetry attribute paths, relative to the namespace of the interface.
+var interfaceFieldAliases = map[string]map[string][][]string{
+	"$http": {
+		"headers": {{"request", "header"}, {"response", "header"}},
+		"cookies": {{"request", "header", "cookie"}},
+	},
+	"$error": {
+		"value": {{"message"}},
+	},
+	"$exception": {
+		"value": {{"message"}},
+	},
+}
+
+// selector selects values in the attributes of spans and logs. The syntax follows the
+// selectors of Sentry's advanced data scrubbing, see
+// https://docs.sentry.io/product/data-management-settings/scrubbing/advanced-datascrubbing/.
+type selector interface {
+	// matches returns whether the selector matches the value at the given path.
+	matches(path []string, typ valueType) bool
+}
+
+type orSelector []selector
+
+func (s orSelector) matches(path []string, typ valueType) bool {
+	for _, inner := range s {
+		if inner.matches(path, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+type andSelector []selector
+
+func (s andSelector) matches(path []string, typ valueType) bool {
+	for _, inner := range s {
+		if !inner.matches(path, typ) {
+			return false
+		}
+	}
+	return true
+}
+
+type notSelector struct {
+	inner selector
+}
+
+func (s notSelector) matches(path []string, typ valueType) bool {
+	return !s.inner.matches(path, typ)
+}
+
+// segment matches a single item of a path.
+type segment struct {
+	key      string
+	wildcard bool
+	deep     bool
+	valueTyp *valueType
+}
+
+// pathSelector matches a path item by item. A value is also selected if one of its
+// parents is, so that selecting an object selects all of its fields.
+type pathSelector struct {
+	segments []segment
+}
+
+func (s pathSelector) matches(path []string, typ valueType) bool {
+	for end := 1; end <= len(path); end++ {
+		t := valueTypeObject
+		if end == len(path) {
+			t = typ
+		}
+		if matchSegments(s.segments, path[:end], t) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(segments []segment, path []string, typ valueType) bool {
+	if len(segments) == 0 {
+		return len(path) == 0
+	}
+
+	seg := segments[0]
+	switch {
+	case seg.deep:
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(segments[1:], path[i:], typ) {
+				return true
+			}
+		}
+		return false
+	case seg.valueTyp != nil:
+		// Value type selectors apply to the selected value itself.
+		return len(path) == 0 && len(segments) == 1 && *seg.valueTyp == typ
+	case len(path) == 0:
+		return false
+	case seg.wildcard || keyEqual(seg.key, path[0]):
+		return matchSegments(segments[1:], path[1:], typ)
+	default:
+		return false
+	}
+}
+
+// keyEqual compares keys case insensitively, and treats dashes and underscores as
+// equal, so that HTTP header names match the keys of OpenTelemetry header attributes.
+func keyEqual(a, b string) bool {
+	return normalizeKey(a) == normalizeKey(b)
+}
+
+func normalizeKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+}
+
+// parseSelector parses a selector, e.g. "$http.headers.Authorization || $string && !password".
+func parseSelector(source string) (selector, error) {
+	p := &selectorParser{tokens: tokenize(source)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty selector")
+	}
+
+	s, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", source, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid selector %q: unexpected %q", source, p.tokens[p.pos])
+	}
+	return s, nil
+}
+
+type selectorParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *selectorParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *selectorParser) parseOr() (selector, error) {
+	var or orSelector
+	for {
+		s, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, s)
+		if p.peek() != "||" {
+			break
+		}
+		p.pos++
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *selectorParser) parseAnd() (selector, error) {
+	var and andSelector
+	for {
+		s, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, s)
+		if p.peek() != "&&" {
+			break
+		}
+		p.pos++
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *selectorParser) parseNot() (selector, error) {
+	if p.peek() == "!" {
+		p.pos++
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notSelector{inner: inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *selectorParser) parsePrimary() (selector, error) {
+	token := p.peek()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("unexpected end of selector")
+	case "(":
+		p.pos++
+		s, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return s, nil
+	case ")", "||", "&&", "!":
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+	p.pos++
+	return parsePath(token)
+}
+
+// parsePath parses a path selector. A path starting with a type selector may match at
+// any depth, other paths are anchored at the root of the attributes.
+func parsePath(token string) (selector, error) {
+	items, err := splitPath(token)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, isInterface := interfaceSelectors[items[0]]
+	if !isInterface {
+		segments, err := parseSegments(items)
+		if err != nil {
+			return nil, err
+		}
+		return newPathSelector(segments, !strings.HasPrefix(items[0], "$")), nil
+	}
+
+	rest := items[1:]
+	prefixes := [][]string{namespace}
+	if len(rest) > 0 {
+		if aliases, ok := interfaceFieldAliases[items[0]][normalizeKey(rest[0])]; ok {
+			prefixes = nil
+			for _, alias := range aliases {
+				prefixes = append(prefixes, append(append([]string(nil), namespace...), alias...))
+			}
+			rest = rest[1:]
+		}
+	}
+
+	restSegments, err := parseSegments(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	var or orSelector
+	for _, prefix := range prefixes {
+		var segments []segment
+		for _, key := range prefix {
+			segments = append(segments, segment{key: key})
+		}
+		or = append(or, newPathSelector(append(segments, restSegments...), false))
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func parseSegments(items []string) ([]segment, error) {
+	var segments []segment
+	for i, item := range items {
+		switch {
+		case item == "**":
+			segments = append(segments, segment{deep: true})
+		case item == "*":
+			segments = append(segments, segment{wildcard: true})
+		case strings.HasPrefix(item, "$"):
+			typ, ok := valueTypeSelectors[item]
+			if !ok {
+				if _, ok = interfaceSelectors[item]; ok {
+					return nil, fmt.Errorf("type selector %q must be the first item of a path", item)
+				}
+				return nil, fmt.Errorf("unknown type selector %q", item)
+			}
+			if i != len(items)-1 {
+				return nil, fmt.Errorf("type selector %q must be the last item of a path", item)
+			}
+			segments = append(segments, segment{valueTyp: &typ})
+		default:
+			segments = append(segments, segment{key: item})
+		}
+	}
+	return segments, nil
+}
+
+func newPathSelector(segments []segment, anchored bool) pathSelector {
+	if !anchored {
+		segments = append([]segment{{deep: true}}, segments...)
+	}
+	return pathSelector{segments: segments}
+}
+
+// splitPath splits a path into its items. Items can be quoted with single quotes
+// to select keys containing dots, e.g. extra.'foo.bar'.
+func splitPath(path string) ([]string, error) {
+	var items []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range path {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == '.' && !quoted:
+			items = append(items, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", path)
+	}
+	items = append(items, current.String())
+
+	for _, item := range items {
+		if item == "" {
+			return nil, fmt.Errorf("empty path item in %q", path)
+		}
+	}
+	return items, nil
+}
+
+// tokenize splits a selector into operators, parentheses and paths.
+func tokenize(source string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(source); i++ {
+		c := source[i]
+		if c == '\'' {
+			quoted = !quoted
+			current.WriteByte(c)
+			continue
+		}
+		if quoted {
+			current.WriteByte(c)
+			continue
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			flush()
+		case c == '(' || c == ')' || c == '!':
+			flush()
+			tokens = append(tokens, string(c))
+		case (c == '&' || c == '|') && i+1 < len(source) && source[i+1] == c:
+			flush()
+			tokens = append(tokens, string([]byte{c, c}))
+			i++
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return tokens
+}
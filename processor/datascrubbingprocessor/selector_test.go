@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datascrubbingprocessor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	testCases := []struct {
+		selector string
+		path     string
+		typ      valueType
+		want     bool
+	}{
+		{"$string", "http.url", valueTypeString, true},
+		{"$string", "http.status_code", valueTypeNumber, false},
+		{"$number", "http.status_code", valueTypeNumber, true},
+		{"**", "anything.at.all", valueTypeBoolean, true},
+		{"http.url", "http.url", valueTypeString, true},
+		{"url", "http.url", valueTypeString, false},
+		{"http", "http.url", valueTypeString, true},
+		{"http.*", "http.url", valueTypeString, true},
+		{"*.url", "http.url", valueTypeString, true},
+		{"**.password", "db.credentials.password", valueTypeString, true},
+		{"**.password", "password", valueTypeString, true},
+		{"$http.headers.Authorization", "http.request.header.authorization", valueTypeString, true},
+		{"$http.headers.x-custom-token", "http.response.header.x_custom_token", valueTypeString, true},
+		{"$http.headers", "http.request.header.cookie", valueTypeString, true},
+		{"$http.headers.Authorization", "http.request.header.cookie", valueTypeString, false},
+		{"$http.cookies", "http.request.header.cookie", valueTypeString, true},
+		{"$http.url", "http.url", valueTypeString, true},
+		{"$error.value", "exception.message", valueTypeString, true},
+		{"$stacktrace", "exception.stacktrace", valueTypeString, true},
+		{"$user.id", "enduser.id", valueTypeString, true},
+		{"$message", "message", valueTypeString, true},
+		{"extra.'foo.bar'", "extra.foo.bar", valueTypeString, false},
+		{"$string && !http.url", "http.url", valueTypeString, false},
+		{"$string && !http.url", "http.target", valueTypeString, true},
+		{"http.url || db.statement", "db.statement", valueTypeString, true},
+		{"!(http.url || db.statement) && $string", "db.statement", valueTypeString, false},
+		{"!(http.url || db.statement) && $string", "db.name", valueTypeString, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.selector+"/"+tc.path, func(t *testing.T) {
+			s, err := parseSelector(tc.selector)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, s.matches(strings.Split(tc.path, "."), tc.typ))
+		})
+	}
+}
+
+func TestSelectorQuotedKey(t *testing.T) {
+	s, err := parseSelector("extra.'foo.bar'")
+	require.NoError(t, err)
+	assert.True(t, s.matches([]string{"extra", "foo.bar"}, valueTypeString))
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	for _, selector := range []string{
+		"",
+		"$unknown",
+		"http.$http",
+		"$string.foo",
+		"http..url",
+		"'unterminated",
+		"(http.url",
+		"http.url)",
+		"http.url &&",
+		"|| http.url",
+	} {
+		t.Run(selector, func(t *testing.T) {
+			_, err := parseSelector(selector)
+			assert.Error(t, err)
+		})
+	}
+}
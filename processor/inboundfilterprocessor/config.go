@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inboundfilterprocessor
+
+import (
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines configuration for the inbound filter processor. Each option
+// enables one of Sentry's inbound filters.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// BrowserExtensions filters out errors known to be caused by browser extensions.
+	BrowserExtensions bool `mapstructure:"browser_extensions"`
+
+	// Localhost filters out data coming from localhost.
+	Localhost bool `mapstructure:"localhost"`
+
+	// WebCrawlers filters out data caused by known web crawlers and bots.
+	WebCrawlers bool `mapstructure:"web_crawlers"`
+
+	// HealthChecks filters out traces of health check requests.
+	HealthChecks bool `mapstructure:"health_checks"`
+
+	// LegacyBrowsers lists the legacy browsers whose data is filtered out. Possible values are
+	// ie_pre_9, ie9, ie10, ie11, opera_pre_15, opera_mini_pre_8, android_pre_4 and safari_pre_6.
+	LegacyBrowsers []string `mapstructure:"legacy_browsers"`
+}
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inboundfilterprocessor
+
+import (
+	"context"
+	"sync"
+
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// typeStr is the value of "type" for this processor in the configuration.
+	typeStr config.Type = "inboundfilter"
+)
+
+var consumerCapabilities = consumer.Capabilities{MutatesData: true}
+
+var once sync.Once
+
+// NewFactory returns a new factory for the inbound filter processor.
+func NewFactory() component.ProcessorFactory {
+	once.Do(func() {
+		// TODO: as with other -contrib factories registering metrics, this is causing the error being ignored
+		_ = view.Register(MetricViews()...)
+	})
+
+	return processorhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processorhelper.WithTraces(createTracesProcessor),
+		processorhelper.WithLogs(createLogsProcessor))
+}
+
+// createDefaultConfig enables the same filters as a new Sentry project.
+func createDefaultConfig() config.Processor {
+	return &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
+		BrowserExtensions: true,
+		WebCrawlers:       true,
+	}
+}
+
+func createTracesProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg config.Processor,
+	nextConsumer consumer.Traces) (component.TracesProcessor, error) {
+
+	ifp, err := newInboundFilterProcessor(params.Logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+
+	return processorhelper.NewTracesProcessor(
+		cfg,
+		nextConsumer,
+		ifp,
+		processorhelper.WithCapabilities(consumerCapabilities))
+}
+
+func createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg config.Processor,
+	nextConsumer consumer.Logs) (component.LogsProcessor, error) {
+
+	ifp, err := newInboundFilterProcessor(params.Logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+
+	return processorhelper.NewLogsProcessor(
+		cfg,
+		nextConsumer,
+		ifp,
+		processorhelper.WithCapabilities(consumerCapabilities))
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inboundfilterprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+)
+
+func TestDefaultConfiguration(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	assert.NoError(t, configcheck.ValidateConfig(c))
+	assert.True(t, c.BrowserExtensions)
+	assert.True(t, c.WebCrawlers)
+	assert.False(t, c.Localhost)
+	assert.False(t, c.HealthChecks)
+	assert.Empty(t, c.LegacyBrowsers)
+}
+
+func TestCreateProcessors(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	cfg := factory.CreateDefaultConfig()
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, tp)
+
+	lp, err := factory.CreateLogsProcessor(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, lp)
+}
+
+func TestCreateProcessorUnknownLegacyBrowser(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.LegacyBrowsers = []string{"netscape"}
+
+	_, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewNop())
+	assert.EqualError(t, err, `unknown legacy browser filter "netscape"`)
+}
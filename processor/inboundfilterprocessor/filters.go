@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inboundfilterprocessor
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The names of the filters, used in the configuration and as metric tags.
+const (
+	filterBrowserExtensions = "browser_extensions"
+	filterLocalhost         = "localhost"
+	filterWebCrawlers       = "web_crawlers"
+	filterHealthChecks      = "health_checks"
+	filterLegacyBrowsers    = "legacy_browsers"
+)
+
+// The patterns below follow the inbound filters of Sentry's ingestion pipeline.
+var (
+	browserExtensionMessages = regexp.MustCompile(`(?i)` +
+		`top\.GLOBALS|` +
+		`originalCreateNotification|` +
+		`canvas\.contentDocument|` +
+		`MyApp_RemoveAllHighlights|` +
+		`http://tt\.epicplay\.com|` +
+		`Can't find variable: ZiteReader|` +
+		`jigsaw is not defined|` +
+		`ComboSearch is not defined|` +
+		`http://loading\.retry\.widdit\.com/|` +
+		`atomicFindClose|` +
+		`mobile-web-promotion|` +
+		`conduitPage|` +
+		`ibFindAllVideos|` +
+		`bmi_SafeAddOnload|` +
+		`EBCallBackMessageReceived|` +
+		`_gCrWeb`)
+
+	browserExtensionSources = regexp.MustCompile(`(?i)` +
+		`graph\.facebook\.com|` +
+		`connect\.facebook\.net|` +
+		`eatdifferent\.com\.woopra-ns\.com|` +
+		`static\.woopra\.com/js/woopra\.js|` +
+		`\bchrome(-extension)?://|` +
+		`\bmoz-extension://|` +
+		`\bsafari(-web)?-extension://|` +
+		`\bresource://|` +
+		`\bwebkit-masked-url://|` +
+		`plugin\.js|` +
+		`local\.adguard\.org|` +
+		`127\.0\.0\.1:4001/isrunning|` +
+		`webappstoreextension`)
+
+	webCrawlers = regexp.MustCompile(`(?i)` +
+		`Mediapartners-Google|` +
+		`AdsBot-Google|` +
+		`Googlebot|` +
+		`FeedFetcher-Google|` +
+		`BingBot|` +
+		`BingPreview|` +
+		`Baiduspider|` +
+		`Slurp|` +
+		`Sogou|` +
+		`facebook|` +
+		`ia_archiver|` +
+		`bots?[/\s\);]|` +
+		`spider[/\s\);]|` +
+		`Slack|` +
+		`Calypso AppEngine|` +
+		`pingdom|` +
+		`lyticsbot|` +
+		`AWS Security Scanner|` +
+		`HubSpot Crawler|` +
+		`Bytespider|` +
+		`Better Uptime|` +
+		`Cloudflare-Healthchecks|` +
+		`GTmetrix|` +
+		`BrightEdgeCrawler|` +
+		`Applebot`)
+
+	// webCrawlerExceptions are user agents matching webCrawlers that are not crawlers,
+	// e.g. link previews in Slack.
+	webCrawlerExceptions = regexp.MustCompile(`(?i)Slackbot\s1\.\d+`)
+
+	healthChecks = regexp.MustCompile(`(?i)` +
+		`healthcheck|` +
+		`healthy|` +
+		`heartbeat|` +
+		`(^|/)(live|ready)z?$|` +
+		`/health$|` +
+		`/healthz$|` +
+		`/ping$`)
+
+	ieVersion         = regexp.MustCompile(`MSIE (\d+)\.`)
+	ie11              = regexp.MustCompile(`Trident/7\.0;.*rv:11\.`)
+	operaMiniVersion  = regexp.MustCompile(`Opera Mini/(\d+)`)
+	operaVersion      = regexp.MustCompile(`Opera[/ ](\d+)`)
+	browserVersion    = regexp.MustCompile(`Version/(\d+)`)
+	androidVersion    = regexp.MustCompile(`Android (\d+)`)
+	nonSafariBrowsers = regexp.MustCompile(`Chrome/|Chromium/|CriOS/|Android|Opera|OPR/`)
+)
+
+// legacyBrowserFilters are the filters for legacy browsers, keyed by the names used in the configuration.
+var legacyBrowserFilters = map[string]func(userAgent string) bool{
+	"ie_pre_9":         func(ua string) bool { v, ok := ieMajorVersion(ua); return ok && v < 9 },
+	"ie9":              func(ua string) bool { v, ok := ieMajorVersion(ua); return ok && v == 9 },
+	"ie10":             func(ua string) bool { v, ok := ieMajorVersion(ua); return ok && v == 10 },
+	"ie11":             func(ua string) bool { v, ok := ieMajorVersion(ua); return ok && v == 11 },
+	"opera_pre_15":     func(ua string) bool { v, ok := operaMajorVersion(ua); return ok && v < 15 },
+	"opera_mini_pre_8": func(ua string) bool { v, ok := matchVersion(operaMiniVersion, ua); return ok && v < 8 },
+	"android_pre_4": func(ua string) bool {
+		v, ok := matchVersion(androidVersion, ua)
+		return ok && v < 4 && !strings.Contains(ua, "Chrome/")
+	},
+	"safari_pre_6": func(ua string) bool {
+		if !strings.Contains(ua, "Safari/") || nonSafariBrowsers.MatchString(ua) {
+			return false
+		}
+		v, ok := matchVersion(browserVersion, ua)
+		return ok && v < 6
+	},
+}
+
+// isBrowserExtensionError returns whether an error with the given message
+// and stack trace was caused by a browser extension.
+func isBrowserExtensionError(message, stacktrace string) bool {
+	if message != "" && browserExtensionMessages.MatchString(message) {
+		return true
+	}
+	return stacktrace != "" && browserExtensionSources.MatchString(stacktrace)
+}
+
+// isLocalhostIP returns whether the given IP address is a loopback address.
+func isLocalhostIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.IsLoopback()
+}
+
+// isLocalhostURL returns whether the host of the given URL is localhost.
+func isLocalhostURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return isLocalhostHost(u.Hostname())
+}
+
+// isLocalhostHost returns whether the given host, optionally with a port, is localhost.
+func isLocalhostHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.EqualFold(host, "localhost") || isLocalhostIP(host)
+}
+
+// isWebCrawler returns whether the user agent belongs to a known web crawler.
+func isWebCrawler(userAgent string) bool {
+	return webCrawlers.MatchString(userAgent) && !webCrawlerExceptions.MatchString(userAgent)
+}
+
+// isHealthCheck returns whether the transaction name or URL path belongs to a health check.
+func isHealthCheck(name string) bool {
+	return healthChecks.MatchString(name)
+}
+
+func ieMajorVersion(userAgent string) (int, bool) {
+	if ie11.MatchString(userAgent) {
+		return 11, true
+	}
+	return matchVersion(ieVersion, userAgent)
+}
+
+func operaMajorVersion(userAgent string) (int, bool) {
+	if strings.Contains(userAgent, "Opera Mini/") {
+		return 0, false
+	}
+	if v, ok := matchVersion(operaVersion, userAgent); !ok || v != 9 {
+		return v, ok
+	}
+	// Opera 10 and later report "Opera/9.80" for compatibility and put the actual version into "Version/".
+	if v, ok := matchVersion(browserVersion, userAgent); ok {
+		return v, true
+	}
+	return 9, true
+}
+
+func matchVersion(re *regexp.Regexp, userAgent string) (int, bool) {
+	m := re.FindStringSubmatch(userAgent)
+	if m == nil {
+		return 0, false
+	}
+	v, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inboundfilterprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBrowserExtensionError(t *testing.T) {
+	assert.True(t, isBrowserExtensionError("TypeError: top.GLOBALS is undefined", ""))
+	assert.True(t, isBrowserExtensionError("", "at foo (chrome-extension://abcdef/content.js:1:2)"))
+	assert.True(t, isBrowserExtensionError("", "foo@moz-extension://abcdef/content.js:1:2"))
+	assert.False(t, isBrowserExtensionError("TypeError: x is undefined", "at foo (https://example.com/app.js:1:2)"))
+	assert.False(t, isBrowserExtensionError("", ""))
+}
+
+func TestIsLocalhost(t *testing.T) {
+	assert.True(t, isLocalhostIP("127.0.0.1"))
+	assert.True(t, isLocalhostIP("::1"))
+	assert.False(t, isLocalhostIP("10.0.0.1"))
+	assert.False(t, isLocalhostIP("localhost"))
+
+	assert.True(t, isLocalhostHost("localhost:8080"))
+	assert.True(t, isLocalhostHost("127.0.0.1"))
+	assert.False(t, isLocalhostHost("example.com"))
+
+	assert.True(t, isLocalhostURL("http://localhost:3000/users"))
+	assert.True(t, isLocalhostURL("http://[::1]/users"))
+	assert.False(t, isLocalhostURL("https://example.com/localhost"))
+}
+
+func TestIsWebCrawler(t *testing.T) {
+	assert.True(t, isWebCrawler("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"))
+	assert.True(t, isWebCrawler("Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)"))
+	assert.False(t, isWebCrawler("Slackbot 1.0 (+https://api.slack.com/robots)"))
+	assert.False(t, isWebCrawler("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.93 Safari/537.36"))
+}
+
+func TestIsHealthCheck(t *testing.T) {
+	for _, name := range []string{"GET /healthz", "/health", "HealthCheck", "/readyz", "/livez", "/ready", "GET /live", "live", "GET /ping", "heartbeat"} {
+		assert.True(t, isHealthCheck(name), name)
+	}
+	for _, name := range []string{"GET /users", "/health/report", "/pingback", "GET /api/delivery", "/orders/already-paid", "/live/events", "/readyz/report", "GET /deliver"} {
+		assert.False(t, isHealthCheck(name), name)
+	}
+}
+
+func TestLegacyBrowserFilters(t *testing.T) {
+	testCases := []struct {
+		filter    string
+		userAgent string
+		want      bool
+	}{
+		{"ie_pre_9", "Mozilla/4.0 (compatible; MSIE 8.0; Windows NT 6.1; Trident/4.0)", true},
+		{"ie_pre_9", "Mozilla/5.0 (compatible; MSIE 9.0; Windows NT 6.1; Trident/5.0)", false},
+		{"ie9", "Mozilla/5.0 (compatible; MSIE 9.0; Windows NT 6.1; Trident/5.0)", true},
+		{"ie10", "Mozilla/5.0 (compatible; MSIE 10.0; Windows NT 6.2; Trident/6.0)", true},
+		{"ie11", "Mozilla/5.0 (Windows NT 6.3; Trident/7.0; rv:11.0) like Gecko", true},
+		{"ie11", "Mozilla/5.0 (compatible; MSIE 10.0; Windows NT 6.2; Trident/6.0)", false},
+		{"opera_pre_15", "Opera/9.80 (Windows NT 6.1; WOW64) Presto/2.12.388 Version/12.16", true},
+		{"opera_pre_15", "Mozilla/5.0 (Windows NT 10.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0 Safari/537.36 OPR/76.0", false},
+		{"opera_mini_pre_8", "Opera/9.80 (J2ME/MIDP; Opera Mini/7.1.32052/29.3417; U; en) Presto/2.8.119 Version/11.10", true},
+		{"opera_pre_15", "Opera/9.80 (J2ME/MIDP; Opera Mini/7.1.32052/29.3417; U; en) Presto/2.8.119 Version/11.10", false},
+		{"android_pre_4", "Mozilla/5.0 (Linux; U; Android 2.3.6; en-us) AppleWebKit/533.1 (KHTML, like Gecko) Version/4.0 Mobile Safari/533.1", true},
+		{"android_pre_4", "Mozilla/5.0 (Linux; Android 10) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0 Mobile Safari/537.36", false},
+		{"safari_pre_6", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_6_8) AppleWebKit/534.59.10 (KHTML, like Gecko) Version/5.1.9 Safari/534.59.10", true},
+		{"safari_pre_6", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/14.1 Safari/605.1.15", false},
+		{"safari_pre_6", "Mozilla/5.0 (Linux; U; Android 2.3.6; en-us) AppleWebKit/533.1 (KHTML, like Gecko) Version/4.0 Mobile Safari/533.1", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.filter+"/"+tc.userAgent, func(t *testing.T) {
+			assert.Equal(t, tc.want, legacyBrowserFilters[tc.filter](tc.userAgent))
+		})
+	}
+}
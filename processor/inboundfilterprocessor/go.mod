@@ -0,0 +1,12 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/processor/inboundfilterprocessor
+
+go 1.15
+
+require (
+	github.com/pelletier/go-toml v1.8.0 // indirect
+	github.com/stretchr/testify v1.7.0
+	go.opencensus.io v0.23.0
+	go.opentelemetry.io/collector v0.27.1-0.20210526183029-df76aa36cd12
+	go.uber.org/zap v1.16.0
+	gopkg.in/ini.v1 v1.57.0 // indirect
+)
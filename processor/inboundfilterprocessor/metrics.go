@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inboundfilterprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+var (
+	tagFilterKey = tag.MustNewKey("filter")
+
+	mNumFilteredSpans      = stats.Int64("num_filtered_spans", "Number of spans that were filtered out", stats.UnitDimensionless)
+	mNumFilteredSpanEvents = stats.Int64("num_filtered_span_events", "Number of exception span events that were filtered out", stats.UnitDimensionless)
+	mNumFilteredLogs       = stats.Int64("num_filtered_logs", "Number of log records that were filtered out", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to inbound filtering.
+func MetricViews() []*view.View {
+	var views []*view.View
+	for _, m := range []*stats.Int64Measure{mNumFilteredSpans, mNumFilteredSpanEvents, mNumFilteredLogs} {
+		views = append(views, &view.View{
+			Name:        obsreport.BuildProcessorCustomMetricName(string(typeStr), m.Name()),
+			Measure:     m,
+			Description: m.Description(),
+			TagKeys:     []tag.Key{tagFilterKey},
+			Aggregation: view.Sum(),
+		})
+	}
+	return views
+}
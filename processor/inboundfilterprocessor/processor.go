@@ -0,0 +1,228 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inboundfilterprocessor
+
+import (
+	"context"
+	"fmt"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+// attributeUserAgentOriginal is the newer semantic convention for the user agent.
+const attributeUserAgentOriginal = "user_agent.original"
+
+type inboundFilterProcessor struct {
+	logger *zap.Logger
+
+	browserExtensions bool
+	localhost         bool
+	webCrawlers       bool
+	healthChecks      bool
+	legacyBrowsers    []func(userAgent string) bool
+}
+
+func newInboundFilterProcessor(logger *zap.Logger, cfg *Config) (*inboundFilterProcessor, error) {
+	ifp := &inboundFilterProcessor{
+		logger:            logger,
+		browserExtensions: cfg.BrowserExtensions,
+		localhost:         cfg.Localhost,
+		webCrawlers:       cfg.WebCrawlers,
+		healthChecks:      cfg.HealthChecks,
+	}
+
+	for _, name := range cfg.LegacyBrowsers {
+		filter, ok := legacyBrowserFilters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown legacy browser filter %q", name)
+		}
+		ifp.legacyBrowsers = append(ifp.legacyBrowsers, filter)
+	}
+
+	return ifp, nil
+}
+
+// ProcessTraces filters out the traces of requests matching a request filter, and exception
+// events caused by browser extensions. Request filters apply to all spans of a trace that are
+// part of the same batch, so that no orphaned spans remain.
+func (ifp *inboundFilterProcessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
+	filteredTraces := map[pdata.TraceID]string{}
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if _, ok := filteredTraces[span.TraceID()]; ok {
+					continue
+				}
+				if filter := ifp.matchSpan(span); filter != "" {
+					filteredTraces[span.TraceID()] = filter
+				}
+			}
+		}
+	}
+
+	rss.RemoveIf(func(rs pdata.ResourceSpans) bool {
+		rs.InstrumentationLibrarySpans().RemoveIf(func(ils pdata.InstrumentationLibrarySpans) bool {
+			ils.Spans().RemoveIf(func(span pdata.Span) bool {
+				if filter, ok := filteredTraces[span.TraceID()]; ok {
+					recordFiltered(ctx, mNumFilteredSpans, filter)
+					return true
+				}
+				if ifp.browserExtensions {
+					ifp.filterExceptionEvents(ctx, span)
+				}
+				return false
+			})
+			return ils.Spans().Len() == 0
+		})
+		return rs.InstrumentationLibrarySpans().Len() == 0
+	})
+
+	if len(filteredTraces) > 0 {
+		ifp.logger.Debug("Filtered out traces", zap.Int("count", len(filteredTraces)))
+	}
+
+	return td, nil
+}
+
+// ProcessLogs filters out log records matching any of the enabled filters.
+func (ifp *inboundFilterProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	rls.RemoveIf(func(rl pdata.ResourceLogs) bool {
+		rl.InstrumentationLibraryLogs().RemoveIf(func(ill pdata.InstrumentationLibraryLogs) bool {
+			ill.Logs().RemoveIf(func(lr pdata.LogRecord) bool {
+				filter := ifp.matchRequest(lr.Attributes())
+				if filter == "" && ifp.browserExtensions && isBrowserExtensionError(exceptionFromLog(lr)) {
+					filter = filterBrowserExtensions
+				}
+				if filter == "" {
+					return false
+				}
+				recordFiltered(ctx, mNumFilteredLogs, filter)
+				return true
+			})
+			return ill.Logs().Len() == 0
+		})
+		return rl.InstrumentationLibraryLogs().Len() == 0
+	})
+
+	return ld, nil
+}
+
+// matchSpan returns the name of the filter matching the span, or an empty string.
+func (ifp *inboundFilterProcessor) matchSpan(span pdata.Span) string {
+	if filter := ifp.matchRequest(span.Attributes()); filter != "" {
+		return filter
+	}
+
+	if ifp.healthChecks && (span.ParentSpanID().IsEmpty() || span.Kind() == pdata.SpanKindServer) {
+		if isHealthCheck(span.Name()) {
+			return filterHealthChecks
+		}
+		for _, key := range []string{conventions.AttributeHTTPRoute, conventions.AttributeHTTPTarget} {
+			if v, ok := span.Attributes().Get(key); ok && isHealthCheck(v.StringVal()) {
+				return filterHealthChecks
+			}
+		}
+	}
+
+	return ""
+}
+
+// matchRequest returns the name of the request filter matching the attributes, or an empty string.
+func (ifp *inboundFilterProcessor) matchRequest(attrs pdata.AttributeMap) string {
+	if ifp.localhost && isLocalhostRequest(attrs) {
+		return filterLocalhost
+	}
+
+	userAgent := userAgentFromAttributes(attrs)
+	if userAgent == "" {
+		return ""
+	}
+	if ifp.webCrawlers && isWebCrawler(userAgent) {
+		return filterWebCrawlers
+	}
+	for _, filter := range ifp.legacyBrowsers {
+		if filter(userAgent) {
+			return filterLegacyBrowsers
+		}
+	}
+
+	return ""
+}
+
+// filterExceptionEvents removes the exception events of a span that were caused by browser extensions.
+func (ifp *inboundFilterProcessor) filterExceptionEvents(ctx context.Context, span pdata.Span) {
+	span.Events().RemoveIf(func(event pdata.SpanEvent) bool {
+		if event.Name() != conventions.AttributeExceptionEventName {
+			return false
+		}
+		if !isBrowserExtensionError(stringAttribute(event.Attributes(), conventions.AttributeExceptionMessage),
+			stringAttribute(event.Attributes(), conventions.AttributeExceptionStacktrace)) {
+			return false
+		}
+		recordFiltered(ctx, mNumFilteredSpanEvents, filterBrowserExtensions)
+		return true
+	})
+}
+
+func isLocalhostRequest(attrs pdata.AttributeMap) bool {
+	if ip := stringAttribute(attrs, conventions.AttributeHTTPClientIP); ip != "" && isLocalhostIP(ip) {
+		return true
+	}
+	if host := stringAttribute(attrs, conventions.AttributeHTTPHost); host != "" && isLocalhostHost(host) {
+		return true
+	}
+	if u := stringAttribute(attrs, conventions.AttributeHTTPURL); u != "" && isLocalhostURL(u) {
+		return true
+	}
+	return false
+}
+
+func userAgentFromAttributes(attrs pdata.AttributeMap) string {
+	if ua := stringAttribute(attrs, conventions.AttributeHTTPUserAgent); ua != "" {
+		return ua
+	}
+	return stringAttribute(attrs, attributeUserAgentOriginal)
+}
+
+// exceptionFromLog returns the exception message and stack trace of a log record.
+// The body is used as message if the record doesn't follow the exception conventions.
+func exceptionFromLog(lr pdata.LogRecord) (string, string) {
+	message := stringAttribute(lr.Attributes(), conventions.AttributeExceptionMessage)
+	if message == "" && lr.Body().Type() == pdata.AttributeValueTypeString {
+		message = lr.Body().StringVal()
+	}
+	return message, stringAttribute(lr.Attributes(), conventions.AttributeExceptionStacktrace)
+}
+
+func stringAttribute(attrs pdata.AttributeMap, key string) string {
+	if v, ok := attrs.Get(key); ok && v.Type() == pdata.AttributeValueTypeString {
+		return v.StringVal()
+	}
+	return ""
+}
+
+func recordFiltered(ctx context.Context, measure *stats.Int64Measure, filter string) {
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(tagFilterKey, filter)}, measure.M(1))
+}
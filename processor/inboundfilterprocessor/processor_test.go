@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inboundfilterprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+var (
+	traceID1 = pdata.NewTraceID([16]byte{1})
+	traceID2 = pdata.NewTraceID([16]byte{2})
+)
+
+func newTestProcessor(t *testing.T) *inboundFilterProcessor {
+	ifp, err := newInboundFilterProcessor(zap.NewNop(), &Config{
+		BrowserExtensions: true,
+		Localhost:         true,
+		WebCrawlers:       true,
+		HealthChecks:      true,
+		LegacyBrowsers:    []string{"ie_pre_9"},
+	})
+	require.NoError(t, err)
+	return ifp
+}
+
+func appendSpan(spans pdata.SpanSlice, traceID pdata.TraceID, parentID pdata.SpanID, name string) pdata.Span {
+	span := spans.AppendEmpty()
+	span.SetTraceID(traceID)
+	span.SetParentSpanID(parentID)
+	span.SetName(name)
+	return span
+}
+
+func spanNames(td pdata.Traces) []string {
+	var names []string
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				names = append(names, spans.At(k).Name())
+			}
+		}
+	}
+	return names
+}
+
+func TestProcessTracesFiltersWholeTraces(t *testing.T) {
+	testCases := []struct {
+		name       string
+		rootName   string
+		attributes map[string]string
+	}{
+		{name: "health check", rootName: "GET /healthz"},
+		{name: "health check route", rootName: "GET", attributes: map[string]string{conventions.AttributeHTTPRoute: "/ping"}},
+		{name: "localhost", rootName: "GET /users", attributes: map[string]string{conventions.AttributeHTTPClientIP: "127.0.0.1"}},
+		{name: "web crawler", rootName: "GET /users", attributes: map[string]string{conventions.AttributeHTTPUserAgent: "Googlebot/2.1"}},
+		{name: "legacy browser", rootName: "GET /users", attributes: map[string]string{attributeUserAgentOriginal: "Mozilla/4.0 (compatible; MSIE 7.0; Windows NT 6.0)"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			td := pdata.NewTraces()
+			spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+			root := appendSpan(spans, traceID1, pdata.SpanID{}, tc.rootName)
+			for k, v := range tc.attributes {
+				root.Attributes().InsertString(k, v)
+			}
+			appendSpan(spans, traceID1, pdata.NewSpanID([8]byte{1}), "SELECT users")
+			appendSpan(spans, traceID2, pdata.SpanID{}, "GET /users")
+
+			// Spans of the filtered trace in other resources are dropped as well.
+			otherSpans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+			appendSpan(otherSpans, traceID1, pdata.NewSpanID([8]byte{2}), "GET /inventory")
+
+			result, err := newTestProcessor(t).ProcessTraces(context.Background(), td)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"GET /users"}, spanNames(result))
+			assert.Equal(t, 1, result.ResourceSpans().Len())
+		})
+	}
+}
+
+func TestProcessTracesHealthCheckOnlyForRootSpans(t *testing.T) {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	appendSpan(spans, traceID1, pdata.SpanID{}, "GET /status")
+	appendSpan(spans, traceID1, pdata.NewSpanID([8]byte{1}), "redis ping/healthcheck")
+
+	result, err := newTestProcessor(t).ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /status", "redis ping/healthcheck"}, spanNames(result))
+}
+
+func TestProcessTracesFiltersBrowserExtensionExceptions(t *testing.T) {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	span := appendSpan(spans, traceID1, pdata.SpanID{}, "pageload")
+
+	extension := span.Events().AppendEmpty()
+	extension.SetName(conventions.AttributeExceptionEventName)
+	extension.Attributes().InsertString(conventions.AttributeExceptionStacktrace, "at foo (chrome-extension://abcdef/content.js:1:2)")
+	app := span.Events().AppendEmpty()
+	app.SetName(conventions.AttributeExceptionEventName)
+	app.Attributes().InsertString(conventions.AttributeExceptionMessage, "TypeError: x is undefined")
+	other := span.Events().AppendEmpty()
+	other.SetName("message")
+	other.Attributes().InsertString(conventions.AttributeExceptionMessage, "top.GLOBALS")
+
+	result, err := newTestProcessor(t).ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	events := result.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Events()
+	require.Equal(t, 2, events.Len())
+	message, _ := events.At(0).Attributes().Get(conventions.AttributeExceptionMessage)
+	assert.Equal(t, "TypeError: x is undefined", message.StringVal())
+	assert.Equal(t, "message", events.At(1).Name())
+}
+
+func TestProcessTracesDisabledFilters(t *testing.T) {
+	ifp, err := newInboundFilterProcessor(zap.NewNop(), &Config{})
+	require.NoError(t, err)
+
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	span := appendSpan(spans, traceID1, pdata.SpanID{}, "GET /healthz")
+	span.Attributes().InsertString(conventions.AttributeHTTPUserAgent, "Googlebot/2.1")
+	span.Attributes().InsertString(conventions.AttributeHTTPURL, "http://localhost/healthz")
+
+	result, err := ifp.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /healthz"}, spanNames(result))
+}
+
+func TestProcessLogs(t *testing.T) {
+	ld := pdata.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+
+	extension := logs.AppendEmpty()
+	extension.SetName("extension")
+	extension.Body().SetStringVal("Can't find variable: ZiteReader")
+
+	localhost := logs.AppendEmpty()
+	localhost.SetName("localhost")
+	localhost.Attributes().InsertString(conventions.AttributeHTTPHost, "localhost:8080")
+
+	crawler := logs.AppendEmpty()
+	crawler.SetName("crawler")
+	crawler.Attributes().InsertString(conventions.AttributeHTTPUserAgent, "Mozilla/5.0 (compatible; Baiduspider/2.0)")
+
+	kept := logs.AppendEmpty()
+	kept.SetName("kept")
+	kept.Body().SetStringVal("TypeError: x is undefined")
+
+	result, err := newTestProcessor(t).ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, result.LogRecordCount())
+	assert.Equal(t, "kept", result.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Name())
+}
+
+func TestProcessLogsRemovesEmptyResources(t *testing.T) {
+	ld := pdata.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.Attributes().InsertString(conventions.AttributeHTTPClientIP, "::1")
+
+	result, err := newTestProcessor(t).ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ResourceLogs().Len())
+}
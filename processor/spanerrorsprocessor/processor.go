@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanerrorsprocessor
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+const (
+	sentryLevelKey       = "sentry.level"
+	sentryFingerprintKey = "sentry.fingerprint"
+	sentryTransactionKey = "sentry.transaction"
+	sentryEnvironmentKey = "sentry.environment"
+
+	// defaultExceptionType is used in the fingerprint of errors without an exception type,
+	// e.g. spans that only have an error status.
+	defaultExceptionType = "SpanError"
+)
+
+type processorImp struct {
+	logger *zap.Logger
+	config Config
+
+	logsExporter component.LogsExporter
+	nextConsumer consumer.Traces
+}
+
+func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer consumer.Traces) *processorImp {
+	logger.Info("Building spanerrorsprocessor")
+	pConfig := config.(*Config)
+
+	return &processorImp{
+		logger:       logger,
+		config:       *pConfig,
+		nextConsumer: nextConsumer,
+	}
+}
+
+func (p *processorImp) Start(ctx context.Context, host component.Host) error {
+	p.logger.Info("Starting spanerrorsprocessor")
+	exporters := host.GetExporters()
+
+	var availableLogsExporters []string
+
+	// The available list of exporters come from any configured logs pipelines' exporters.
+	for k, exp := range exporters[config.LogsDataType] {
+		logsExp, ok := exp.(component.LogsExporter)
+		if !ok {
+			return fmt.Errorf("the exporter %q isn't a logs exporter", k.String())
+		}
+
+		availableLogsExporters = append(availableLogsExporters, k.String())
+
+		if k.String() == p.config.LogsExporter {
+			p.logsExporter = logsExp
+			p.logger.Info("Found exporter", zap.String("spanerrors-exporter", p.config.LogsExporter))
+			break
+		}
+	}
+	if p.logsExporter == nil {
+		return fmt.Errorf("failed to find logs exporter: '%s'; please configure logs_exporter from one of: %+v",
+			p.config.LogsExporter, availableLogsExporters)
+	}
+	p.logger.Info("Started spanerrorsprocessor")
+	return nil
+}
+
+func (p *processorImp) Shutdown(ctx context.Context) error {
+	p.logger.Info("Shutting down spanerrorsprocessor")
+	return nil
+}
+
+func (p *processorImp) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements the consumer.Traces interface.
+// It builds error logs from the spans that failed, forwarding these logs to the discovered logs exporter.
+// The original input trace data will be forwarded to the next consumer, unmodified.
+func (p *processorImp) ConsumeTraces(ctx context.Context, traces pdata.Traces) error {
+	logs := buildLogs(traces)
+
+	// Firstly, export logs to avoid being impacted by downstream trace processor errors/latency.
+	if logs.LogRecordCount() > 0 {
+		if err := p.logsExporter.ConsumeLogs(ctx, logs); err != nil {
+			return err
+		}
+	}
+
+	// Forward trace data unmodified.
+	return p.nextConsumer.ConsumeTraces(ctx, traces)
+}
+
+// buildLogs creates a log record for each exception event in the traces, and for
+// each span with an error status that doesn't have any exception event.
+func buildLogs(traces pdata.Traces) pdata.Logs {
+	logs := pdata.NewLogs()
+
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		environment, _ := rs.Resource().Attributes().Get(conventions.AttributeDeploymentEnvironment)
+		var rl pdata.ResourceLogs
+		hasResourceLogs := false
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			var ill pdata.InstrumentationLibraryLogs
+			hasLibraryLogs := false
+
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !hasError(span) {
+					continue
+				}
+
+				if !hasLibraryLogs {
+					if !hasResourceLogs {
+						rl = logs.ResourceLogs().AppendEmpty()
+						rs.Resource().CopyTo(rl.Resource())
+						hasResourceLogs = true
+					}
+					ill = rl.InstrumentationLibraryLogs().AppendEmpty()
+					ils.InstrumentationLibrary().CopyTo(ill.InstrumentationLibrary())
+					hasLibraryLogs = true
+				}
+
+				appendErrorLogs(ill.Logs(), span, environment.StringVal())
+			}
+		}
+	}
+
+	return logs
+}
+
+func hasError(span pdata.Span) bool {
+	if span.Status().Code() == pdata.StatusCodeError {
+		return true
+	}
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		if events.At(i).Name() == conventions.AttributeExceptionEventName {
+			return true
+		}
+	}
+	return false
+}
+
+// appendErrorLogs appends the error logs of a failed span.
+func appendErrorLogs(logs pdata.LogSlice, span pdata.Span, environment string) {
+	found := false
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.Name() != conventions.AttributeExceptionEventName {
+			continue
+		}
+		found = true
+
+		record := newErrorLog(logs, span, event.Timestamp())
+		attrs := record.Attributes()
+		event.Attributes().Range(func(k string, v pdata.AttributeValue) bool {
+			attrs.Upsert(k, v)
+			return true
+		})
+
+		exceptionType := defaultExceptionType
+		if v, ok := event.Attributes().Get(conventions.AttributeExceptionType); ok && v.StringVal() != "" {
+			exceptionType = v.StringVal()
+		}
+		message := span.Status().Message()
+		if v, ok := event.Attributes().Get(conventions.AttributeExceptionMessage); ok && v.StringVal() != "" {
+			message = v.StringVal()
+		}
+		finishErrorLog(record, span, environment, exceptionType, message)
+	}
+
+	if !found {
+		record := newErrorLog(logs, span, span.EndTimestamp())
+		record.Attributes().UpsertString(conventions.AttributeExceptionType, defaultExceptionType)
+		if span.Status().Message() != "" {
+			record.Attributes().UpsertString(conventions.AttributeExceptionMessage, span.Status().Message())
+		}
+		finishErrorLog(record, span, environment, defaultExceptionType, span.Status().Message())
+	}
+}
+
+// newErrorLog appends an error log record that carries the span's context and attributes.
+func newErrorLog(logs pdata.LogSlice, span pdata.Span, timestamp pdata.Timestamp) pdata.LogRecord {
+	record := logs.AppendEmpty()
+	record.SetTimestamp(timestamp)
+	record.SetTraceID(span.TraceID())
+	record.SetSpanID(span.SpanID())
+	record.SetSeverityNumber(pdata.SeverityNumberERROR)
+	record.SetSeverityText("ERROR")
+	span.Attributes().CopyTo(record.Attributes())
+	return record
+}
+
+// finishErrorLog sets the body and the Sentry specific attributes of an error log record.
+// The fingerprint groups errors by exception type and transaction, so that the same
+// failure in different operations is reported as different issues.
+func finishErrorLog(record pdata.LogRecord, span pdata.Span, environment, exceptionType, message string) {
+	if message == "" {
+		message = exceptionType
+	}
+	record.Body().SetStringVal(message)
+
+	attrs := record.Attributes()
+	attrs.UpsertString(sentryLevelKey, "error")
+	attrs.UpsertString(sentryTransactionKey, span.Name())
+	if environment != "" {
+		attrs.UpsertString(sentryEnvironmentKey, environment)
+	}
+
+	fingerprint := pdata.NewAttributeValueArray()
+	fingerprint.ArrayVal().AppendEmpty().SetStringVal(exceptionType)
+	fingerprint.ArrayVal().AppendEmpty().SetStringVal(span.Name())
+	attrs.Upsert(sentryFingerprintKey, fingerprint)
+}
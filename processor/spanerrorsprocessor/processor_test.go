@@ -0,0 +1,184 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanerrorsprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+type logsExporter struct {
+	component.Component
+	consumertest.LogsSink
+}
+
+type mockHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return h.exporters
+}
+
+func newTestProcessor(t *testing.T, next *consumertest.TracesSink) (*processorImp, *logsExporter) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.LogsExporter = "otlp/errors"
+	p := newProcessor(zap.NewNop(), cfg, next)
+
+	exp := &logsExporter{Component: componenthelper.New()}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.LogsDataType: {config.NewIDWithName("otlp", "errors"): exp},
+		},
+	}
+	require.NoError(t, p.Start(context.Background(), host))
+	return p, exp
+}
+
+func TestProcessorStartExporterNotFound(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.LogsExporter = "otlp/errors"
+	p := newProcessor(zap.NewNop(), cfg, consumertest.NewNop())
+
+	err := p.Start(context.Background(), &mockHost{Host: componenttest.NewNopHost()})
+	assert.EqualError(t, err, "failed to find logs exporter: 'otlp/errors'; please configure logs_exporter from one of: []")
+}
+
+func TestConsumeTracesWithoutErrors(t *testing.T) {
+	next := new(consumertest.TracesSink)
+	p, exp := newTestProcessor(t, next)
+
+	traces := pdata.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /users")
+	span.Status().SetCode(pdata.StatusCodeOk)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+	assert.Empty(t, exp.AllLogs())
+	assert.Len(t, next.AllTraces(), 1)
+}
+
+func TestConsumeTracesWithErrors(t *testing.T) {
+	next := new(consumertest.TracesSink)
+	p, exp := newTestProcessor(t, next)
+
+	traces := pdata.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "users")
+	rs.Resource().Attributes().InsertString(conventions.AttributeDeploymentEnvironment, "production")
+	ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+	ils.InstrumentationLibrary().SetName("net/http")
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	spanID := pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+
+	withException := ils.Spans().AppendEmpty()
+	withException.SetName("GET /users")
+	withException.SetTraceID(traceID)
+	withException.SetSpanID(spanID)
+	withException.Attributes().InsertString("http.method", "GET")
+	event := withException.Events().AppendEmpty()
+	event.SetName(conventions.AttributeExceptionEventName)
+	event.SetTimestamp(pdata.Timestamp(1000))
+	event.Attributes().InsertString(conventions.AttributeExceptionType, "ValueError")
+	event.Attributes().InsertString(conventions.AttributeExceptionMessage, "invalid user id")
+	event.Attributes().InsertString(conventions.AttributeExceptionStacktrace, "Traceback...")
+
+	withStatus := ils.Spans().AppendEmpty()
+	withStatus.SetName("SELECT users")
+	withStatus.SetEndTimestamp(pdata.Timestamp(2000))
+	withStatus.Status().SetCode(pdata.StatusCodeError)
+	withStatus.Status().SetMessage("connection refused")
+
+	ok := ils.Spans().AppendEmpty()
+	ok.SetName("GET /health")
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+	assert.Len(t, next.AllTraces(), 1)
+
+	allLogs := exp.AllLogs()
+	require.Len(t, allLogs, 1)
+	require.Equal(t, 2, allLogs[0].LogRecordCount())
+
+	rl := allLogs[0].ResourceLogs().At(0)
+	serviceName, _ := rl.Resource().Attributes().Get(conventions.AttributeServiceName)
+	assert.Equal(t, "users", serviceName.StringVal())
+	ill := rl.InstrumentationLibraryLogs().At(0)
+	assert.Equal(t, "net/http", ill.InstrumentationLibrary().Name())
+
+	exception := ill.Logs().At(0)
+	assert.Equal(t, pdata.Timestamp(1000), exception.Timestamp())
+	assert.Equal(t, traceID, exception.TraceID())
+	assert.Equal(t, spanID, exception.SpanID())
+	assert.Equal(t, pdata.SeverityNumberERROR, exception.SeverityNumber())
+	assert.Equal(t, "invalid user id", exception.Body().StringVal())
+	assertAttributes(t, map[string]string{
+		"http.method":                            "GET",
+		conventions.AttributeExceptionType:       "ValueError",
+		conventions.AttributeExceptionMessage:    "invalid user id",
+		conventions.AttributeExceptionStacktrace: "Traceback...",
+		sentryLevelKey:                           "error",
+		sentryTransactionKey:                     "GET /users",
+		sentryEnvironmentKey:                     "production",
+	}, exception.Attributes())
+	assertFingerprint(t, []string{"ValueError", "GET /users"}, exception.Attributes())
+
+	status := ill.Logs().At(1)
+	assert.Equal(t, pdata.Timestamp(2000), status.Timestamp())
+	assert.Equal(t, "connection refused", status.Body().StringVal())
+	assertAttributes(t, map[string]string{
+		conventions.AttributeExceptionType:    defaultExceptionType,
+		conventions.AttributeExceptionMessage: "connection refused",
+		sentryLevelKey:                        "error",
+		sentryTransactionKey:                  "SELECT users",
+		sentryEnvironmentKey:                  "production",
+	}, status.Attributes())
+	assertFingerprint(t, []string{defaultExceptionType, "SELECT users"}, status.Attributes())
+}
+
+func assertAttributes(t *testing.T, expected map[string]string, attrs pdata.AttributeMap) {
+	actual := map[string]string{}
+	attrs.Range(func(k string, v pdata.AttributeValue) bool {
+		if k != sentryFingerprintKey {
+			actual[k] = v.StringVal()
+		}
+		return true
+	})
+	assert.Equal(t, expected, actual)
+}
+
+func assertFingerprint(t *testing.T, expected []string, attrs pdata.AttributeMap) {
+	v, ok := attrs.Get(sentryFingerprintKey)
+	require.True(t, ok)
+
+	var actual []string
+	for i := 0; i < v.ArrayVal().Len(); i++ {
+		actual = append(actual, v.ArrayVal().At(i).StringVal())
+	}
+	assert.Equal(t, expected, actual)
+}
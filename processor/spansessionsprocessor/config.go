@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spansessionsprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines the configuration options for spansessionsprocessor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// SessionsExporter is the name of the Sentry exporter to send session aggregates to.
+	SessionsExporter string `mapstructure:"sessions_exporter"`
+
+	// BucketInterval is the interval sessions are aggregated in, by their start time.
+	BucketInterval time.Duration `mapstructure:"bucket_interval"`
+
+	// FlushInterval is the interval at which the aggregates are sent to the exporter.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
@@ -0,0 +1,14 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/processor/spansessionsprocessor
+
+go 1.15
+
+require (
+	github.com/open-telemetry/opentelemetry-collector-contrib/exporter/sentryexporter v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/collector v0.27.1-0.20210526183029-df76aa36cd12
+	go.uber.org/zap v1.16.0
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/exporter/sentryexporter => ../../exporter/sentryexporter
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage => ../../extension/storage
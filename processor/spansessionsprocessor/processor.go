@@ -0,0 +1,260 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spansessionsprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/sentryexporter"
+)
+
+// releaseKey identifies the session aggregates of a release in an environment.
+type releaseKey struct {
+	release     string
+	environment string
+}
+
+type processorImp struct {
+	lock   sync.Mutex
+	logger *zap.Logger
+	config Config
+
+	sessionsExporter sentryexporter.SessionsExporter
+	nextConsumer     consumer.Traces
+
+	// Session counts by release and start of the bucket.
+	buckets map[releaseKey]map[time.Time]*sentryexporter.SessionBucket
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer consumer.Traces) (*processorImp, error) {
+	logger.Info("Building spansessionsprocessor")
+	pConfig := config.(*Config)
+
+	if pConfig.BucketInterval <= 0 {
+		return nil, fmt.Errorf("bucket_interval must be positive, got %v", pConfig.BucketInterval)
+	}
+	if pConfig.FlushInterval <= 0 {
+		return nil, fmt.Errorf("flush_interval must be positive, got %v", pConfig.FlushInterval)
+	}
+
+	return &processorImp{
+		logger:       logger,
+		config:       *pConfig,
+		nextConsumer: nextConsumer,
+		buckets:      make(map[releaseKey]map[time.Time]*sentryexporter.SessionBucket),
+		stopCh:       make(chan struct{}),
+	}, nil
+}
+
+func (p *processorImp) Start(ctx context.Context, host component.Host) error {
+	p.logger.Info("Starting spansessionsprocessor")
+	exporters := host.GetExporters()
+
+	var availableSessionsExporters []string
+
+	// The Sentry exporter sends sessions next to the traces it exports.
+	for k, exp := range exporters[config.TracesDataType] {
+		sessionsExp, ok := exp.(sentryexporter.SessionsExporter)
+		if !ok {
+			continue
+		}
+
+		availableSessionsExporters = append(availableSessionsExporters, k.String())
+
+		if k.String() == p.config.SessionsExporter {
+			p.sessionsExporter = sessionsExp
+			p.logger.Info("Found exporter", zap.String("spansessions-exporter", p.config.SessionsExporter))
+			break
+		}
+	}
+	if p.sessionsExporter == nil {
+		return fmt.Errorf("failed to find sessions exporter: '%s'; please configure sessions_exporter from one of: %+v",
+			p.config.SessionsExporter, availableSessionsExporters)
+	}
+
+	p.wg.Add(1)
+	go p.flushPeriodically()
+
+	p.logger.Info("Started spansessionsprocessor")
+	return nil
+}
+
+func (p *processorImp) Shutdown(ctx context.Context) error {
+	p.logger.Info("Shutting down spansessionsprocessor")
+	if p.sessionsExporter == nil {
+		return nil
+	}
+
+	close(p.stopCh)
+	p.wg.Wait()
+	return p.flush(ctx)
+}
+
+func (p *processorImp) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements the consumer.Traces interface.
+// It counts a session for each root server span, which is flushed to the sessions exporter periodically.
+// The original input trace data will be forwarded to the next consumer, unmodified.
+func (p *processorImp) ConsumeTraces(ctx context.Context, traces pdata.Traces) error {
+	p.aggregateSessions(traces)
+
+	// Forward trace data unmodified.
+	return p.nextConsumer.ConsumeTraces(ctx, traces)
+}
+
+// aggregateSessions counts the sessions of the root server spans of releases. A span
+// with an error status counts as an errored session, any other as an exited one.
+func (p *processorImp) aggregateSessions(traces pdata.Traces) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		attrs := rs.Resource().Attributes()
+		release, ok := attrs.Get(conventions.AttributeServiceVersion)
+		if !ok || release.StringVal() == "" {
+			// Sessions without a release are dropped by Sentry.
+			continue
+		}
+		key := releaseKey{release: release.StringVal()}
+		if environment, ok := attrs.Get(conventions.AttributeDeploymentEnvironment); ok {
+			key.environment = environment.StringVal()
+		}
+
+		var spanIDs map[pdata.SpanID]bool
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.Kind() != pdata.SpanKindServer {
+					continue
+				}
+				if parent := span.ParentSpanID(); !parent.IsEmpty() {
+					if spanIDs == nil {
+						spanIDs = resourceSpanIDs(rs)
+					}
+					if spanIDs[parent] {
+						// The request is already counted by a server span it is nested in,
+						// such as the span of a proxy in front of the application.
+						continue
+					}
+				}
+
+				bucket := p.bucket(key, span.StartTimestamp().AsTime())
+				if span.Status().Code() == pdata.StatusCodeError {
+					bucket.Errored++
+				} else {
+					bucket.Exited++
+				}
+			}
+		}
+	}
+}
+
+// resourceSpanIDs returns the IDs of the spans of a resource.
+func resourceSpanIDs(rs pdata.ResourceSpans) map[pdata.SpanID]bool {
+	ids := make(map[pdata.SpanID]bool)
+	ilss := rs.InstrumentationLibrarySpans()
+	for i := 0; i < ilss.Len(); i++ {
+		spans := ilss.At(i).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			if id := spans.At(j).SpanID(); !id.IsEmpty() {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+func (p *processorImp) bucket(key releaseKey, start time.Time) *sentryexporter.SessionBucket {
+	buckets, ok := p.buckets[key]
+	if !ok {
+		buckets = make(map[time.Time]*sentryexporter.SessionBucket)
+		p.buckets[key] = buckets
+	}
+
+	started := start.Truncate(p.config.BucketInterval).UTC()
+	bucket, ok := buckets[started]
+	if !ok {
+		bucket = &sentryexporter.SessionBucket{Started: started}
+		buckets[started] = bucket
+	}
+	return bucket
+}
+
+func (p *processorImp) flushPeriodically() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.flush(context.Background()); err != nil {
+				p.logger.Warn("Failed to export sessions", zap.Error(err))
+			}
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// flush sends the aggregated sessions to the exporter and resets the aggregates.
+func (p *processorImp) flush(ctx context.Context) error {
+	aggregates := p.collectAggregates()
+	if len(aggregates) == 0 {
+		return nil
+	}
+	return p.sessionsExporter.ExportSessions(ctx, aggregates)
+}
+
+func (p *processorImp) collectAggregates() []sentryexporter.SessionAggregates {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	aggregates := make([]sentryexporter.SessionAggregates, 0, len(p.buckets))
+	for key, buckets := range p.buckets {
+		a := sentryexporter.SessionAggregates{Release: key.release, Environment: key.environment}
+		for _, bucket := range buckets {
+			a.Buckets = append(a.Buckets, *bucket)
+		}
+		sort.Slice(a.Buckets, func(i, j int) bool {
+			return a.Buckets[i].Started.Before(a.Buckets[j].Started)
+		})
+		aggregates = append(aggregates, a)
+	}
+	p.buckets = make(map[releaseKey]map[time.Time]*sentryexporter.SessionBucket)
+
+	return aggregates
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spansessionsprocessor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/sentryexporter"
+)
+
+type sessionsExporter struct {
+	component.Component
+	consumertest.TracesSink
+
+	mu         sync.Mutex
+	aggregates [][]sentryexporter.SessionAggregates
+}
+
+func (e *sessionsExporter) ExportSessions(_ context.Context, aggregates []sentryexporter.SessionAggregates) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.aggregates = append(e.aggregates, aggregates)
+	return nil
+}
+
+type mockHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return h.exporters
+}
+
+func newTestProcessor(t *testing.T) (*processorImp, *sessionsExporter) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.SessionsExporter = "sentry"
+	p, err := newProcessor(zap.NewNop(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	exp := &sessionsExporter{Component: componenthelper.New()}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.TracesDataType: {
+				config.NewID("sentry"): exp,
+				config.NewID("nop"):    componenthelper.New(),
+			},
+		},
+	}
+	require.NoError(t, p.Start(context.Background(), host))
+	return p, exp
+}
+
+func TestProcessorStartExporterNotFound(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.SessionsExporter = "sentry"
+	p, err := newProcessor(zap.NewNop(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.TracesDataType: {config.NewID("sentry"): componenthelper.New()},
+		},
+	}
+	err = p.Start(context.Background(), host)
+	assert.EqualError(t, err, "failed to find sessions exporter: 'sentry'; please configure sessions_exporter from one of: []")
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func appendServerSpan(spans pdata.SpanSlice, start time.Time, code pdata.StatusCode) {
+	span := spans.AppendEmpty()
+	span.SetKind(pdata.SpanKindServer)
+	span.SetStartTimestamp(pdata.TimestampFromTime(start))
+	span.Status().SetCode(code)
+}
+
+func TestConsumeTracesAggregatesSessions(t *testing.T) {
+	p, exp := newTestProcessor(t)
+
+	minute := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	traces := pdata.NewTraces()
+
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceVersion, "1.2.3")
+	rs.Resource().Attributes().InsertString(conventions.AttributeDeploymentEnvironment, "production")
+	spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	appendServerSpan(spans, minute.Add(10*time.Second), pdata.StatusCodeOk)
+	appendServerSpan(spans, minute.Add(20*time.Second), pdata.StatusCodeUnset)
+	appendServerSpan(spans, minute.Add(30*time.Second), pdata.StatusCodeError)
+	appendServerSpan(spans, minute.Add(70*time.Second), pdata.StatusCodeUnset)
+	client := spans.AppendEmpty()
+	client.SetKind(pdata.SpanKindClient)
+	client.Status().SetCode(pdata.StatusCodeError)
+
+	// Sessions without a release are dropped.
+	noRelease := traces.ResourceSpans().AppendEmpty()
+	appendServerSpan(noRelease.InstrumentationLibrarySpans().AppendEmpty().Spans(), minute, pdata.StatusCodeOk)
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	require.Len(t, exp.aggregates, 1)
+	assert.Equal(t, []sentryexporter.SessionAggregates{{
+		Release:     "1.2.3",
+		Environment: "production",
+		Buckets: []sentryexporter.SessionBucket{
+			{Started: minute, Exited: 2, Errored: 1},
+			{Started: minute.Add(time.Minute), Exited: 1},
+		},
+	}}, exp.aggregates[0])
+}
+
+func TestConsumeTracesCountsRootServerSpans(t *testing.T) {
+	p, exp := newTestProcessor(t)
+
+	start := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	traces := pdata.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceVersion, "1.2.3")
+	spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+
+	// A proxy span and the nested span of the application count as one request.
+	appendServerSpan(spans, start, pdata.StatusCodeOk)
+	proxy := spans.At(0)
+	proxy.SetSpanID(pdata.NewSpanID([8]byte{1}))
+	proxy.SetParentSpanID(pdata.NewSpanID([8]byte{9}))
+	app := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	appendServerSpan(app, start, pdata.StatusCodeError)
+	app.At(0).SetSpanID(pdata.NewSpanID([8]byte{2}))
+	app.At(0).SetParentSpanID(proxy.SpanID())
+
+	// The parent of a server span called by another service is remote.
+	appendServerSpan(spans, start, pdata.StatusCodeError)
+	spans.At(1).SetSpanID(pdata.NewSpanID([8]byte{3}))
+	spans.At(1).SetParentSpanID(pdata.NewSpanID([8]byte{8}))
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	require.Len(t, exp.aggregates, 1)
+	assert.Equal(t, []sentryexporter.SessionAggregates{{
+		Release: "1.2.3",
+		Buckets: []sentryexporter.SessionBucket{{Started: start, Exited: 1, Errored: 1}},
+	}}, exp.aggregates[0])
+}
+
+func TestFlushResetsAggregates(t *testing.T) {
+	p, exp := newTestProcessor(t)
+
+	traces := pdata.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceVersion, "1.2.3")
+	appendServerSpan(rs.InstrumentationLibrarySpans().AppendEmpty().Spans(), time.Now(), pdata.StatusCodeOk)
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+
+	require.NoError(t, p.flush(context.Background()))
+	require.NoError(t, p.flush(context.Background()))
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	assert.Len(t, exp.aggregates, 1)
+}
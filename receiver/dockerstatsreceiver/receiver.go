@@ -27,7 +27,7 @@ import (
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
 )
 
 var _ component.MetricsReceiver = (*Receiver)(nil)
@@ -84,10 +84,13 @@ func (r *Receiver) Start(ctx context.Context, host component.Host) error {
 	r.obsCtx = obsreport.ReceiverContext(ctx, r.config.ID(), r.transport)
 
 	r.runnerCtx, r.runnerCancel = context.WithCancel(context.Background())
-	r.runner = interval.NewRunner(r.config.CollectionInterval, r)
+	r.runner = interval.NewRunner(interval.Settings{
+		Interval:     r.config.CollectionInterval,
+		InitialDelay: r.config.CollectionInterval,
+	}, r)
 
 	go func() {
-		if err := r.runner.Start(); err != nil {
+		if err := r.runner.Start(context.Background()); err != nil {
 			host.ReportFatalError(err)
 		}
 	}()
@@ -97,8 +100,7 @@ func (r *Receiver) Start(ctx context.Context, host component.Host) error {
 
 func (r *Receiver) Shutdown(ctx context.Context) error {
 	r.runnerCancel()
-	r.runner.Stop()
-	return nil
+	return r.runner.Stop(ctx)
 }
 
 func (r *Receiver) Setup() error {
@@ -24,8 +24,8 @@ import (
 	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
 )
 
 var _ component.MetricsReceiver = (*receiver)(nil)
@@ -60,10 +60,13 @@ func newReceiver(rOptions *receiverOptions,
 // Start the kubelet stats runnable.
 func (r *receiver) Start(ctx context.Context, host component.Host) error {
 	runnable := newRunnable(ctx, r.consumer, r.rest, r.logger, r.options)
-	r.runner = interval.NewRunner(r.options.collectionInterval, runnable)
+	r.runner = interval.NewRunner(interval.Settings{
+		Interval:     r.options.collectionInterval,
+		InitialDelay: r.options.collectionInterval,
+	}, runnable)
 
 	go func() {
-		if err := r.runner.Start(); err != nil {
+		if err := r.runner.Start(context.Background()); err != nil {
 			host.ReportFatalError(err)
 		}
 	}()
@@ -72,6 +75,5 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 
 // Shutdown the kubelet stats runner.
 func (r *receiver) Shutdown(ctx context.Context) error {
-	r.runner.Stop()
-	return nil
+	return r.runner.Stop(ctx)
 }
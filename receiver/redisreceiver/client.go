@@ -15,6 +15,12 @@
 package redisreceiver
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/go-redis/redis/v7"
 )
 
@@ -25,19 +31,205 @@ type client interface {
 	// line delimiter
 	// redis lines are delimited by \r\n, files (for testing) by \n
 	delimiter() string
+	// retrieves the most recent entries of the slow log, at most count
+	retrieveSlowLog(count int64) ([]slowLogEntry, error)
+	// retrieves the number of entries in the slow log
+	retrieveSlowLogLen() (int64, error)
+	// retrieves MEMORY STATS, as key/value pairs like INFO
+	retrieveMemoryStats() (info, error)
+	// retrieves CLIENT LIST, one line per client
+	retrieveClientList() (string, error)
+	// retrieves DBSIZE of each of the databases
+	retrieveDBSizes(dbs []int) (map[int]int64, error)
+	// retrieves the maxclients configuration directive with CONFIG GET
+	retrieveMaxClients() (int64, error)
+	// retrieves CLUSTER INFO, on servers with cluster mode enabled
+	retrieveClusterInfo() (string, error)
+	// retrieves PUBSUB NUMSUB, the number of subscribers of each channel
+	retrievePubSubNumSub(channels []string) (map[string]int64, error)
+	// retrieves XINFO STREAM and XINFO GROUPS of each of the streams
+	retrieveStreams(streams []string) (map[string]streamInfo, error)
+	// retrieves the type, TTL, memory usage and length of each of the keys
+	retrieveKeyStats(keys []string) (map[string]keyStats, error)
+	// counts the keys matching pattern with at most maxCalls SCAN commands
+	retrieveKeyPatternCount(pattern string, count, maxCalls int64) (keyPatternCount, error)
+	// subscribes to the channels matching the patterns, and returns their
+	// messages until the returned function is called
+	psubscribe(patterns []string) (<-chan *redis.Message, func() error, error)
+	// closes the connections of the client
+	close() error
+}
+
+// The commands sent along with INFO, see infoBatcher.
+type infoBatchRequest struct {
+	// The section argument of INFO, the default sections if empty.
+	section string
+	// Whether to send CLUSTER INFO.
+	clusterInfo bool
+	// Whether to send SLOWLOG LEN.
+	slowLogLen bool
+	// The databases whose DBSIZE is sent.
+	dbs []int
+}
+
+// The replies to INFO and the commands of an infoBatchRequest. The commands
+// other than INFO keep their own error, so that one that fails, e.g. when it
+// is denied to an ACL user, does not fail the others.
+type infoBatchReply struct {
+	info           string
+	clusterInfo    string
+	clusterInfoErr error
+	slowLogLen     int64
+	slowLogLenErr  error
+	dbSizes        map[int]int64
+	dbSizesErr     error
+}
+
+// The names of the commands renamed on the server with rename-command, by
+// lowercase name. Implements redis.Hook, renaming the commands a client sends.
+type commandNames map[string]string
+
+var _ redis.Hook = commandNames(nil)
+
+// Replaces the name of each of the commands with the one the server knows it
+// by.
+func (n commandNames) rename(cmds ...redis.Cmder) {
+	for _, cmd := range cmds {
+		args := cmd.Args()
+		if len(args) == 0 {
+			continue
+		}
+		if name, ok := args[0].(string); ok {
+			if renamed, ok := n[strings.ToLower(name)]; ok {
+				args[0] = renamed
+			}
+		}
+	}
+}
+
+func (n commandNames) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	n.rename(cmd)
+	return ctx, nil
+}
+
+func (n commandNames) AfterProcess(context.Context, redis.Cmder) error {
+	return nil
+}
+
+func (n commandNames) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	n.rename(cmds...)
+	return ctx, nil
+}
+
+func (n commandNames) AfterProcessPipeline(context.Context, []redis.Cmder) error {
+	return nil
+}
+
+// Returns whether err is the reply of a server to a command it does not run:
+// a command renamed or disabled with rename-command, a subcommand it does not
+// know, e.g. on an older version, or a command denied to the ACL user.
+func isUnavailableCommandError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.HasPrefix(msg, "noperm") ||
+		strings.HasPrefix(msg, "err unknown command") ||
+		strings.HasPrefix(msg, "err unknown subcommand")
+}
+
+// Implemented by clients that send INFO and the commands of a request in a
+// single round trip.
+type infoBatcher interface {
+	retrieveInfoBatch(req infoBatchRequest) (infoBatchReply, error)
+}
+
+// Retrieves INFO and the commands of req, in a single round trip if c is an
+// infoBatcher and one command after the other otherwise, with the default
+// sections of INFO. The error is that of INFO.
+func retrieveInfoBatch(c client, req infoBatchRequest) (infoBatchReply, error) {
+	if b, ok := c.(infoBatcher); ok {
+		return b.retrieveInfoBatch(req)
+	}
+	var reply infoBatchReply
+	var err error
+	if reply.info, err = c.retrieveInfo(); err != nil {
+		return reply, err
+	}
+	if req.clusterInfo {
+		reply.clusterInfo, reply.clusterInfoErr = c.retrieveClusterInfo()
+	}
+	if req.slowLogLen {
+		reply.slowLogLen, reply.slowLogLenErr = c.retrieveSlowLogLen()
+	}
+	if len(req.dbs) > 0 {
+		reply.dbSizes, reply.dbSizesErr = c.retrieveDBSizes(req.dbs)
+	}
+	return reply, nil
 }
 
 // Wraps a real Redis client, implements `client` interface.
 type redisClient struct {
 	client *redis.Client
+	// Creates a client authenticating with the given password.
+	connect func(password string) *redis.Client
+	// The password in use.
+	password string
+	// Reads the current password, nil if the password is static.
+	passwordSource func() (string, error)
+	// When the password was last read, see refreshPassword.
+	passwordRead time.Time
+	// Bounds each command, including waiting for a connection, 0 if unbounded.
+	timeout time.Duration
+	// The commands renamed on the server. Applied by a hook, except on the
+	// single connection of retrieveInfoBatch, which has no hooks.
+	commands commandNames
 }
 
 var _ client = (*redisClient)(nil)
+var _ infoBatcher = (*redisClient)(nil)
 
-// Creates a new real Redis client from the passed-in redis.Options.
-func newRedisClient(options *redis.Options) client {
+// Creates a new real Redis client from the passed-in redis.Options. If
+// passwordSource is not nil, it is called for the current password when Redis
+// rejects the password in use. The ReadTimeout of the options also bounds each
+// command as a whole.
+func newRedisClient(options *redis.Options, passwordSource func() (string, error), commands commandNames) client {
+	return newReconnectingClient(options.Password, options.ReadTimeout, func(password string) *redis.Client {
+		o := *options
+		o.Password = password
+		return redis.NewClient(&o)
+	}, passwordSource, commands)
+}
+
+// Creates a new real Redis client connecting to the master found through
+// Sentinel, and following it on failover.
+func newFailoverRedisClient(options *redis.FailoverOptions, passwordSource func() (string, error), commands commandNames) client {
+	return newReconnectingClient(options.Password, options.ReadTimeout, func(password string) *redis.Client {
+		o := *options
+		o.Password = password
+		return redis.NewFailoverClient(&o)
+	}, passwordSource, commands)
+}
+
+// Creates a client whose commands are sent with the names the server knows
+// them by, see commandNames.
+func newReconnectingClient(password string, timeout time.Duration, connect func(password string) *redis.Client, passwordSource func() (string, error), commands commandNames) *redisClient {
+	if len(commands) > 0 {
+		connectWithNames := connect
+		connect = func(password string) *redis.Client {
+			c := connectWithNames(password)
+			c.AddHook(commands)
+			return c
+		}
+	}
 	return &redisClient{
-		client: redis.NewClient(options),
+		client:         connect(password),
+		connect:        connect,
+		password:       password,
+		passwordSource: passwordSource,
+		passwordRead:   time.Now(),
+		timeout:        timeout,
+		commands:       commands,
 	}
 }
 
@@ -48,5 +240,476 @@ func (c *redisClient) delimiter() string {
 
 // Retrieve Redis INFO. We retrieve all of the 'sections'.
 func (c *redisClient) retrieveInfo() (string, error) {
-	return c.client.Info().Result()
+	var str string
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		str, err = c.client.WithContext(ctx).Info().Result()
+		return err
+	})
+	return str, err
+}
+
+// Retrieve INFO and the commands of req in a single round trip. They are sent
+// on one connection, since the databases are selected in turn for DBSIZE, and
+// the database of the client is selected again at the end.
+func (c *redisClient) retrieveInfoBatch(req infoBatchRequest) (infoBatchReply, error) {
+	var reply infoBatchReply
+	err := c.withReauth(func() error {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		conn := c.client.WithContext(ctx).Conn()
+		defer conn.Close()
+
+		var info, clusterInfo *redis.StringCmd
+		var slowLogLen *redis.Cmd
+		var selects []*redis.StatusCmd
+		dbSizes := make(map[int]*redis.IntCmd, len(req.dbs))
+		_, err := conn.Pipelined(func(pipe redis.Pipeliner) error {
+			var sections []string
+			if req.section != "" {
+				sections = append(sections, req.section)
+			}
+			info = pipe.Info(sections...)
+			c.commands.rename(info)
+			if req.clusterInfo {
+				clusterInfo = pipe.ClusterInfo()
+				c.commands.rename(clusterInfo)
+			}
+			if req.slowLogLen {
+				slowLogLen = pipe.Do("slowlog", "len")
+				c.commands.rename(slowLogLen)
+			}
+			if len(req.dbs) > 0 {
+				for _, db := range req.dbs {
+					selects = append(selects, pipe.Select(db))
+					dbSizes[db] = pipe.DBSize()
+					c.commands.rename(selects[len(selects)-1], dbSizes[db])
+				}
+				selects = append(selects, pipe.Select(c.client.Options().DB))
+				c.commands.rename(selects[len(selects)-1])
+			}
+			return nil
+		})
+		if _, ok := err.(redis.Error); err != nil && !ok {
+			return err
+		}
+		// The connection would go back to the pool on another database.
+		if len(selects) > 0 && selects[len(selects)-1].Err() != nil {
+			discardConn(conn)
+		}
+
+		reply = infoBatchReply{}
+		if reply.info, err = info.Result(); err != nil {
+			return err
+		}
+		if clusterInfo != nil {
+			reply.clusterInfo, reply.clusterInfoErr = clusterInfo.Result()
+		}
+		if slowLogLen != nil {
+			reply.slowLogLen, reply.slowLogLenErr = slowLogLen.Int64()
+		}
+		if len(req.dbs) > 0 {
+			// A DBSIZE after a failed SELECT is that of another database.
+			for _, cmd := range selects {
+				if reply.dbSizesErr == nil {
+					reply.dbSizesErr = cmd.Err()
+				}
+			}
+			reply.dbSizes = make(map[int]int64, len(req.dbs))
+			for db, cmd := range dbSizes {
+				if reply.dbSizesErr == nil {
+					reply.dbSizesErr = cmd.Err()
+				}
+				reply.dbSizes[db] = cmd.Val()
+			}
+		}
+		return nil
+	})
+	return reply, err
+}
+
+// Closes the connection of conn instead of returning it to the pool when conn
+// is closed. go-redis only closes the pooled connections that fail, so a PING
+// is sent with an expired deadline, failing before it is written.
+func discardConn(conn *redis.Conn) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancel()
+	_ = conn.ProcessContext(ctx, redis.NewStatusCmd("ping"))
+}
+
+// Retrieve SLOWLOG GET. go-redis has no typed command for it.
+func (c *redisClient) retrieveSlowLog(count int64) ([]slowLogEntry, error) {
+	var res interface{}
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		res, err = c.client.DoContext(ctx, "slowlog", "get", count).Result()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseSlowLog(res)
+}
+
+// Retrieve SLOWLOG LEN.
+func (c *redisClient) retrieveSlowLogLen() (int64, error) {
+	var n int64
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		n, err = c.client.DoContext(ctx, "slowlog", "len").Int64()
+		return err
+	})
+	return n, err
+}
+
+// Retrieve MEMORY STATS. go-redis has no typed command for it.
+func (c *redisClient) retrieveMemoryStats() (info, error) {
+	var res interface{}
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		res, err = c.client.DoContext(ctx, "memory", "stats").Result()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseMemoryStats(res)
+}
+
+// Retrieve CLIENT LIST.
+func (c *redisClient) retrieveClientList() (string, error) {
+	var str string
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		str, err = c.client.WithContext(ctx).ClientList().Result()
+		return err
+	})
+	return str, err
+}
+
+// Retrieve the maxclients configuration directive, for servers that do not
+// report it in INFO.
+func (c *redisClient) retrieveMaxClients() (int64, error) {
+	var res []interface{}
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		res, err = c.client.WithContext(ctx).ConfigGet("maxclients").Result()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(res) != 2 {
+		return 0, fmt.Errorf("unexpected config get reply %v", res)
+	}
+	str, _ := res[1].(string)
+	return strconv.ParseInt(str, 10, 64)
+}
+
+// Retrieve DBSIZE of each database, selecting them in turn on one connection in
+// a single round trip. The database of the client is selected again at the
+// end, since the connection is returned to the pool.
+func (c *redisClient) retrieveDBSizes(dbs []int) (map[int]int64, error) {
+	sizes := make(map[int]int64, len(dbs))
+	err := c.withReauth(func() error {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		conn := c.client.WithContext(ctx).Conn()
+		defer conn.Close()
+
+		cmds := make(map[int]*redis.IntCmd, len(dbs))
+		_, err := conn.Pipelined(func(pipe redis.Pipeliner) error {
+			for _, db := range dbs {
+				pipe.Select(db)
+				cmds[db] = pipe.DBSize()
+			}
+			pipe.Select(c.client.Options().DB)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for db, cmd := range cmds {
+			sizes[db] = cmd.Val()
+		}
+		return nil
+	})
+	return sizes, err
+}
+
+// Retrieve the number of subscribers of each of the channels, not counting
+// pattern subscriptions.
+func (c *redisClient) retrievePubSubNumSub(channels []string) (map[string]int64, error) {
+	var counts map[string]int64
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		counts, err = c.client.WithContext(ctx).PubSubNumSub(channels...).Result()
+		return err
+	})
+	return counts, err
+}
+
+// Retrieve the type, TTL and memory usage of each of the keys in a first round
+// trip, and their length, with the command for their type, in a second one.
+// Keys that do not exist are left out, and so are the values of commands that
+// fail, e.g. MEMORY USAGE before Redis 4.
+func (c *redisClient) retrieveKeyStats(keys []string) (map[string]keyStats, error) {
+	stats := make(map[string]keyStats, len(keys))
+	err := c.withReauth(func() error {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		client := c.client.WithContext(ctx)
+
+		types := make([]*redis.StatusCmd, len(keys))
+		ttls := make([]*redis.DurationCmd, len(keys))
+		memory := make([]*redis.IntCmd, len(keys))
+		_, err := client.Pipelined(func(pipe redis.Pipeliner) error {
+			for i, key := range keys {
+				types[i] = pipe.Type(key)
+				ttls[i] = pipe.PTTL(key)
+				memory[i] = pipe.MemoryUsage(key)
+			}
+			return nil
+		})
+		if _, ok := err.(redis.Error); err != nil && !ok {
+			return err
+		}
+
+		lengths := make([]*redis.IntCmd, len(keys))
+		_, err = client.Pipelined(func(pipe redis.Pipeliner) error {
+			for i, key := range keys {
+				if err := types[i].Err(); err != nil {
+					// In cluster mode, keys of other nodes are redirected.
+					if isRedirectError(err) {
+						continue
+					}
+					return err
+				}
+				lengths[i] = queueKeyLength(pipe, types[i].Val(), key)
+			}
+			return nil
+		})
+		if _, ok := err.(redis.Error); err != nil && !ok {
+			return err
+		}
+
+		for i, key := range keys {
+			if types[i].Err() != nil || types[i].Val() == "none" {
+				continue
+			}
+			s := keyStats{keyType: types[i].Val()}
+			if ttl, err := ttls[i].Result(); err == nil && ttl >= 0 {
+				s.ttlSeconds, s.hasTTL = int64(ttl/time.Second), true
+			}
+			if usage, err := memory[i].Result(); err == nil {
+				s.memoryUsage, s.hasMemory = usage, true
+			}
+			if lengths[i] != nil {
+				if length, err := lengths[i].Result(); err == nil {
+					s.length, s.hasLength = length, true
+				}
+			}
+			stats[key] = s
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Retrieve XINFO STREAM and XINFO GROUPS of each of the streams in a single
+// round trip. Streams that do not exist, or are held by another cluster node,
+// are left out.
+func (c *redisClient) retrieveStreams(streams []string) (map[string]streamInfo, error) {
+	infos := make(map[string]streamInfo, len(streams))
+	err := c.withReauth(func() error {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+
+		streamCmds := make([]*redis.Cmd, len(streams))
+		groupsCmds := make([]*redis.Cmd, len(streams))
+		_, err := c.client.WithContext(ctx).Pipelined(func(pipe redis.Pipeliner) error {
+			for i, stream := range streams {
+				streamCmds[i] = pipe.Do("xinfo", "stream", stream)
+				groupsCmds[i] = pipe.Do("xinfo", "groups", stream)
+			}
+			return nil
+		})
+		if _, ok := err.(redis.Error); err != nil && !ok {
+			return err
+		}
+
+		for i, stream := range streams {
+			streamRes, err := streamCmds[i].Result()
+			if err != nil {
+				if isRedirectError(err) || strings.HasPrefix(err.Error(), "ERR no such key") {
+					continue
+				}
+				return err
+			}
+			groupsRes, err := groupsCmds[i].Result()
+			if err != nil {
+				return err
+			}
+			info, err := parseStreamInfo(streamRes, groupsRes)
+			if err != nil {
+				return err
+			}
+			infos[stream] = info
+		}
+		return nil
+	})
+	return infos, err
+}
+
+// Subscribe to the channels matching the patterns, waiting for the reply to
+// report errors, e.g. when the channels are denied to an ACL user. The
+// subscription reconnects on its own when the connection drops.
+func (c *redisClient) psubscribe(patterns []string) (<-chan *redis.Message, func() error, error) {
+	var ps *redis.PubSub
+	err := c.withReauth(func() error {
+		ps = c.client.PSubscribe(patterns...)
+		if _, err := ps.ReceiveTimeout(c.timeout); err != nil {
+			_ = ps.Close()
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return ps.Channel(), ps.Close, nil
+}
+
+// Returns whether err is a MOVED or ASK redirection to another cluster node.
+func isRedirectError(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "MOVED ") || strings.HasPrefix(msg, "ASK ")
+}
+
+// Count the keys matching pattern with SCAN, visiting about count keys per
+// command and running at most maxCalls commands, each bounded by the timeout.
+// SCAN may return a key more than once, so the keys are deduplicated.
+func (c *redisClient) retrieveKeyPatternCount(pattern string, count, maxCalls int64) (keyPatternCount, error) {
+	var result keyPatternCount
+	err := c.withReauth(func() error {
+		keys := map[string]struct{}{}
+		var cursor uint64
+		for calls := int64(0); calls < maxCalls; calls++ {
+			page, next, err := c.scan(cursor, pattern, count)
+			if err != nil {
+				return err
+			}
+			for _, key := range page {
+				keys[key] = struct{}{}
+			}
+			if cursor = next; cursor == 0 {
+				result = keyPatternCount{matches: int64(len(keys)), complete: true}
+				return nil
+			}
+		}
+		result = keyPatternCount{matches: int64(len(keys))}
+		return nil
+	})
+	return result, err
+}
+
+func (c *redisClient) scan(cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	ctx, cancel := c.commandContext()
+	defer cancel()
+	return c.client.WithContext(ctx).Scan(cursor, pattern, count).Result()
+}
+
+// Retrieve the CLUSTER NODES description of the cluster topology.
+func (c *redisClient) retrieveClusterNodes() (string, error) {
+	var str string
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		str, err = c.client.WithContext(ctx).ClusterNodes().Result()
+		return err
+	})
+	return str, err
+}
+
+// Retrieve CLUSTER INFO, the state of the cluster as seen by the server.
+func (c *redisClient) retrieveClusterInfo() (string, error) {
+	var str string
+	err := c.withReauth(func() (err error) {
+		ctx, cancel := c.commandContext()
+		defer cancel()
+		str, err = c.client.WithContext(ctx).ClusterInfo().Result()
+		return err
+	})
+	return str, err
+}
+
+// Returns the context of a command, with the deadline of the timeout.
+func (c *redisClient) commandContext() (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.Background(), func() {}
+	}
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+func (c *redisClient) close() error {
+	return c.client.Close()
+}
+
+// The interval at which the password is read again, see refreshPassword.
+const passwordRefreshInterval = time.Minute
+
+// withReauth calls f, and calls it again with a new connection if Redis
+// rejected the password and a different one is available.
+func (c *redisClient) withReauth(f func() error) error {
+	c.refreshPassword()
+	err := f()
+	if err == nil || c.passwordSource == nil || !isAuthError(err) {
+		return err
+	}
+
+	password, pErr := c.passwordSource()
+	if pErr != nil || password == c.password {
+		return err
+	}
+
+	c.reconnect(password)
+	return f()
+}
+
+// Reads the password again if it was last read more than
+// passwordRefreshInterval ago, and reconnects if it changed, so that
+// passwords that expire, like IAM authentication tokens, are replaced before
+// Redis rejects them.
+func (c *redisClient) refreshPassword() {
+	if c.passwordSource == nil || time.Since(c.passwordRead) < passwordRefreshInterval {
+		return
+	}
+	c.passwordRead = time.Now()
+	if password, err := c.passwordSource(); err == nil && password != c.password {
+		c.reconnect(password)
+	}
+}
+
+// Replaces the client with one authenticating with the password.
+func (c *redisClient) reconnect(password string) {
+	c.password = password
+	c.passwordRead = time.Now()
+	_ = c.client.Close()
+	c.client = c.connect(password)
+}
+
+// isAuthError returns whether Redis rejected the credentials or requires them.
+func isAuthError(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "WRONGPASS") ||
+		strings.HasPrefix(msg, "NOAUTH") ||
+		strings.Contains(msg, "invalid password") ||
+		strings.Contains(msg, "invalid username-password pair")
 }
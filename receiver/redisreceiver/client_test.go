@@ -15,12 +15,22 @@
 package redisreceiver
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"net"
 	"path"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/go-redis/redis/v7"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,6 +54,91 @@ func (fakeClient) retrieveInfo() (string, error) {
 	return readFile("info")
 }
 
+func (fakeClient) retrieveSlowLog(count int64) ([]slowLogEntry, error) {
+	entries, err := parseSlowLog(testSlowLog)
+	if int64(len(entries)) > count {
+		entries = entries[:count]
+	}
+	return entries, err
+}
+
+func (fakeClient) retrieveSlowLogLen() (int64, error) {
+	return int64(len(testSlowLog)), nil
+}
+
+func (fakeClient) retrieveMemoryStats() (info, error) {
+	return parseMemoryStats(testMemoryStats)
+}
+
+func (fakeClient) retrieveClientList() (string, error) {
+	return readFile("client_list")
+}
+
+func (fakeClient) retrieveDBSizes(dbs []int) (map[int]int64, error) {
+	sizes := make(map[int]int64, len(dbs))
+	for _, db := range dbs {
+		sizes[db] = int64(db)
+	}
+	return sizes, nil
+}
+
+func (fakeClient) retrieveKeyPatternCount(pattern string, count, maxCalls int64) (keyPatternCount, error) {
+	return keyPatternCount{matches: int64(len(pattern)), complete: pattern != "*"}, nil
+}
+
+func (fakeClient) retrieveKeyStats(keys []string) (map[string]keyStats, error) {
+	stats := map[string]keyStats{}
+	for _, key := range keys {
+		switch key {
+		case "queue":
+			stats[key] = keyStats{keyType: "list", memoryUsage: 1024, hasMemory: true, length: 12, hasLength: true}
+		case "cache":
+			stats[key] = keyStats{keyType: "string", ttlSeconds: 300, hasTTL: true, memoryUsage: 64, hasMemory: true, length: 5, hasLength: true}
+		}
+	}
+	return stats, nil
+}
+
+func (fakeClient) retrievePubSubNumSub(channels []string) (map[string]int64, error) {
+	counts := map[string]int64{}
+	for i, channel := range channels {
+		counts[channel] = int64(i)
+	}
+	return counts, nil
+}
+
+func (fakeClient) retrieveStreams(streams []string) (map[string]streamInfo, error) {
+	infos := map[string]streamInfo{}
+	for _, stream := range streams {
+		if stream == "events" {
+			infos[stream] = streamInfo{
+				length:          10,
+				lastGeneratedID: "1526569495631-0",
+				groups: []streamGroup{
+					{name: "workers", consumers: 2, pending: 3, lastDeliveredID: "1526569495000-1", lag: 4, hasLag: true},
+				},
+			}
+		}
+	}
+	return infos, nil
+}
+
+func (fakeClient) retrieveMaxClients() (int64, error) {
+	return 4, nil
+}
+
+func (fakeClient) retrieveClusterInfo() (string, error) {
+	return testClusterInfo, nil
+}
+
+func (fakeClient) psubscribe([]string) (<-chan *redis.Message, func() error, error) {
+	return nil, nil, errors.New("not supported")
+}
+
+func (fakeClient) close() error {
+	return nil
+}
+
 func readFile(fname string) (string, error) {
 	file, err := ioutil.ReadFile(path.Join("testdata", fname+".txt"))
 	if err != nil {
@@ -58,3 +153,277 @@ func TestRetrieveInfo(t *testing.T) {
 	require.Nil(t, err)
 	require.True(t, strings.HasPrefix(res, "# Server"))
 }
+
+func TestIsAuthError(t *testing.T) {
+	assert.True(t, isAuthError(errors.New("WRONGPASS invalid username-password pair or user is disabled.")))
+	assert.True(t, isAuthError(errors.New("NOAUTH Authentication required.")))
+	assert.True(t, isAuthError(errors.New("ERR invalid password")))
+	assert.False(t, isAuthError(errors.New("dial tcp: connection refused")))
+}
+
+func TestWithReauth(t *testing.T) {
+	password := "rotated"
+	c := newRedisClient(&redis.Options{Password: "old"}, func() (string, error) {
+		return password, nil
+	}, nil).(*redisClient)
+
+	calls := 0
+	err := c.withReauth(func() error {
+		calls++
+		if c.password != "rotated" {
+			return errors.New("WRONGPASS invalid username-password pair")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "rotated", c.password)
+
+	// The password did not change, so there is no point in trying again.
+	calls = 0
+	err = c.withReauth(func() error {
+		calls++
+		return errors.New("WRONGPASS invalid username-password pair")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// Other errors are returned as they are.
+	password = "rotated-again"
+	calls = 0
+	err = c.withReauth(func() error {
+		calls++
+		return errors.New("connection refused")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRefreshPassword(t *testing.T) {
+	password := "token-1"
+	c := newRedisClient(&redis.Options{Password: password}, func() (string, error) {
+		return password, nil
+	}, nil).(*redisClient)
+
+	password = "token-2"
+	require.NoError(t, c.withReauth(func() error { return nil }))
+	assert.Equal(t, "token-1", c.password, "the password was read recently")
+
+	// Once the password is old, it is replaced before running the command.
+	c.passwordRead = time.Now().Add(-passwordRefreshInterval)
+	require.NoError(t, c.withReauth(func() error {
+		assert.Equal(t, "token-2", c.password)
+		return nil
+	}))
+}
+
+func TestRetrieveInfoTimeout(t *testing.T) {
+	// Accepts connections but never answers, like a hung server.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	c := newRedisClient(&redis.Options{
+		Addr:        l.Addr().String(),
+		ReadTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	}, nil, nil)
+	defer c.close()
+
+	start := time.Now()
+	_, err = c.retrieveInfo()
+	assert.Error(t, err)
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
+
+// Answers the commands of each connection with reply, until l is closed, and
+// counts the connections.
+func serveRESP(t *testing.T, l net.Listener, reply func(args []string) string) *int32 {
+	var conns int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&conns, 1)
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					args, err := readRESPCommand(r)
+					if err != nil {
+						return
+					}
+					if _, err = conn.Write([]byte(reply(args))); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return &conns
+}
+
+// Reads a command sent as an array of bulk strings.
+func readRESPCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.ToLower(strings.TrimSpace(arg))
+	}
+	return args, nil
+}
+
+func TestRetrieveInfoBatch(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	var mu sync.Mutex
+	var commands []string
+	conns := serveRESP(t, l, func(args []string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, strings.Join(args, " "))
+		switch strings.Join(args, " ") {
+		case "info everything":
+			return "$15\r\nredis_version:6\r\n"
+		case "cluster info":
+			return "-NOPERM this user has no permissions to run the 'cluster' command\r\n"
+		case "slowlog len":
+			return ":3\r\n"
+		case "dbsize":
+			return fmt.Sprintf(":%d\r\n", len(commands))
+		default:
+			return "+OK\r\n"
+		}
+	})
+
+	c := newRedisClient(&redis.Options{Addr: l.Addr().String(), DB: 2, ReadTimeout: time.Second}, nil, nil)
+	defer c.close()
+	reply, err := retrieveInfoBatch(c, infoBatchRequest{section: "everything", clusterInfo: true, slowLogLen: true, dbs: []int{0, 1}})
+	require.NoError(t, err)
+	assert.Equal(t, "redis_version:6", reply.info)
+	// A command that is denied does not fail the others.
+	assert.Error(t, reply.clusterInfoErr)
+	assert.NoError(t, reply.slowLogLenErr)
+	assert.EqualValues(t, 3, reply.slowLogLen)
+	assert.NoError(t, reply.dbSizesErr)
+	assert.Equal(t, map[int]int64{0: 6, 1: 8}, reply.dbSizes)
+
+	// The connection selects the database of the client first, and again after
+	// the databases of the request.
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"select 2", "info everything", "cluster info", "slowlog len",
+		"select 0", "dbsize", "select 1", "dbsize", "select 2",
+	}, commands)
+	assert.EqualValues(t, 1, atomic.LoadInt32(conns))
+}
+
+func TestRetrieveInfoBatchRestoreFailure(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	var selects int32
+	conns := serveRESP(t, l, func(args []string) string {
+		switch strings.Join(args, " ") {
+		case "info":
+			return "$15\r\nredis_version:6\r\n"
+		case "dbsize":
+			return ":1\r\n"
+		case "select 2":
+			// The first SELECT initializes the connection, the second restores it.
+			if atomic.AddInt32(&selects, 1) == 2 {
+				return "-ERR invalid DB index\r\n"
+			}
+			return "+OK\r\n"
+		default:
+			return "+OK\r\n"
+		}
+	})
+
+	c := newRedisClient(&redis.Options{Addr: l.Addr().String(), DB: 2, ReadTimeout: time.Second}, nil, nil)
+	defer c.close()
+	reply, err := retrieveInfoBatch(c, infoBatchRequest{dbs: []int{0}})
+	require.NoError(t, err)
+	assert.Error(t, reply.dbSizesErr)
+
+	// The connection on database 0 is closed, so the next command has a new
+	// one, which selects database 2.
+	_, err = c.retrieveInfo()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(conns))
+	assert.EqualValues(t, 3, atomic.LoadInt32(&selects))
+}
+
+func TestRenamedCommands(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	var mu sync.Mutex
+	var commands []string
+	serveRESP(t, l, func(args []string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, strings.Join(args, " "))
+		switch args[0] {
+		case "x-info":
+			return "$15\r\nredis_version:6\r\n"
+		case "slowlog":
+			return "-ERR unknown command `slowlog`, with args beginning with: `len`, \r\n"
+		case "x-config":
+			return "*2\r\n$10\r\nmaxclients\r\n$5\r\n10000\r\n"
+		default:
+			return "+OK\r\n"
+		}
+	})
+
+	c := newRedisClient(&redis.Options{Addr: l.Addr().String(), ReadTimeout: time.Second}, nil, commandNames{"info": "x-info", "config": "x-config"})
+	defer c.close()
+	reply, err := retrieveInfoBatch(c, infoBatchRequest{slowLogLen: true})
+	require.NoError(t, err)
+	assert.Equal(t, "redis_version:6", reply.info)
+	assert.True(t, isUnavailableCommandError(reply.slowLogLenErr))
+	max, err := c.retrieveMaxClients()
+	require.NoError(t, err)
+	assert.EqualValues(t, 10000, max)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"x-info", "slowlog len", "x-config get maxclients"}, commands)
+}
+
+func TestIsUnavailableCommandError(t *testing.T) {
+	assert.True(t, isUnavailableCommandError(errors.New("ERR unknown command `CONFIG`, with args beginning with: `GET`, `maxclients`, ")))
+	assert.True(t, isUnavailableCommandError(errors.New("ERR unknown command 'SLOWLOG'")))
+	assert.True(t, isUnavailableCommandError(errors.New("ERR Unknown subcommand or wrong number of arguments for 'STATS'. Try MEMORY HELP.")))
+	assert.True(t, isUnavailableCommandError(errors.New("NOPERM this user has no permissions to run the 'memory' command or its subcommand")))
+	assert.False(t, isUnavailableCommandError(errors.New("ERR SELECT is not allowed in cluster mode")))
+	assert.False(t, isUnavailableCommandError(nil))
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The names of the metrics of client saturation.
+var clientSaturationMetricNames = []string{"redis/clients/max", "redis/clients/utilization"}
+
+// The states of clients, in the order metrics are built.
+var clientStates = []string{"normal", "blocked", "pubsub", "replica", "master", "monitor"}
+
+// The age and idle time buckets of clients, in the order metrics are built,
+// with their upper bound in seconds.
+var clientTimeBuckets = []struct {
+	name  string
+	upper int64
+}{
+	{"<1m", 60},
+	{"1m-1h", 60 * 60},
+	{"1h-1d", 24 * 60 * 60},
+	{">=1d", -1},
+}
+
+// Counts of the clients returned by CLIENT LIST.
+type clientStats struct {
+	byState map[string]int64
+	byAge   map[string]int64
+	byIdle  map[string]int64
+	// Clients with client side caching enabled.
+	tracking int64
+}
+
+// Parses the reply of CLIENT LIST, one line per client with space separated
+// fields, e.g. "id=3 addr=127.0.0.1:56770 age=10 idle=0 flags=N sub=0 psub=0".
+func parseClientList(str string) (*clientStats, error) {
+	stats := &clientStats{
+		byState: make(map[string]int64),
+		byAge:   make(map[string]int64),
+		byIdle:  make(map[string]int64),
+	}
+	for _, line := range strings.Split(str, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := make(map[string]string)
+		for _, pairStr := range strings.Fields(line) {
+			pair := strings.SplitN(pairStr, "=", 2)
+			if len(pair) == 2 {
+				fields[pair[0]] = pair[1]
+			}
+		}
+
+		age, err := strconv.ParseInt(fields["age"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected client age in '%s'", line)
+		}
+		idle, err := strconv.ParseInt(fields["idle"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected client idle time in '%s'", line)
+		}
+
+		flags := fields["flags"]
+		stats.byState[clientState(flags, fields["sub"], fields["psub"])]++
+		stats.byAge[clientTimeBucket(age)]++
+		stats.byIdle[clientTimeBucket(idle)]++
+		if strings.Contains(flags, "t") {
+			stats.tracking++
+		}
+	}
+	return stats, nil
+}
+
+// Returns the state of a client from its flags and subscription counts.
+func clientState(flags, sub, psub string) string {
+	switch {
+	case strings.Contains(flags, "S"):
+		return "replica"
+	case strings.Contains(flags, "M"):
+		return "master"
+	case strings.Contains(flags, "O"):
+		return "monitor"
+	case strings.Contains(flags, "b"):
+		return "blocked"
+	case strings.Contains(flags, "P") || (sub != "" && sub != "0") || (psub != "" && psub != "0"):
+		return "pubsub"
+	}
+	return "normal"
+}
+
+func clientTimeBucket(seconds int64) string {
+	for _, b := range clientTimeBuckets {
+		if b.upper < 0 || seconds < b.upper {
+			return b.name
+		}
+	}
+	return clientTimeBuckets[len(clientTimeBuckets)-1].name
+}
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestParseClientList(t *testing.T) {
+	str, err := readFile("client_list")
+	require.NoError(t, err)
+	stats, err := parseClientList(str)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"normal": 2, "blocked": 1, "pubsub": 1, "replica": 1}, stats.byState)
+	assert.Equal(t, map[string]int64{"<1m": 1, "1m-1h": 2, "1h-1d": 1, ">=1d": 1}, stats.byAge)
+	assert.Equal(t, map[string]int64{"<1m": 3, "1m-1h": 1, ">=1d": 1}, stats.byIdle)
+	assert.EqualValues(t, 1, stats.tracking)
+
+	_, err = parseClientList("id=3 addr=127.0.0.1:56770 age=x idle=0 flags=N")
+	assert.Error(t, err)
+}
+
+func TestBuildClientListMetrics(t *testing.T) {
+	stats, err := parseClientList("id=3 age=10 idle=0 flags=N sub=0 psub=1\r\n")
+	require.NoError(t, err)
+	ms := buildClientListMetrics(stats, newTimeBundle(time.Now(), 100))
+	require.Equal(t, len(clientStates)+2*len(clientTimeBuckets)+1, ms.Len())
+
+	pubsub := ms.At(2)
+	assert.Equal(t, "redis/clients/by_state", pubsub.Name())
+	pt := pubsub.IntGauge().DataPoints().At(0)
+	state, _ := pt.LabelsMap().Get("state")
+	assert.Equal(t, "pubsub", state)
+	assert.EqualValues(t, 1, pt.Value())
+}
+
+func TestClientSaturationMetrics(t *testing.T) {
+	ms := buildClientSaturationMetrics(3, 4, testTimeBundle())
+	require.Equal(t, 2, ms.Len())
+	assert.Equal(t, "redis/clients/max", ms.At(0).Name())
+	assert.Equal(t, int64(4), ms.At(0).IntGauge().DataPoints().At(0).Value())
+	assert.Equal(t, "redis/clients/utilization", ms.At(1).Name())
+	assert.Equal(t, 0.75, ms.At(1).DoubleGauge().DataPoints().At(0).Value())
+
+	// maxclients cannot be 0, but the ratio is not divided by it anyway.
+	assert.Equal(t, 1, buildClientSaturationMetrics(3, 0, testTimeBundle()).Len())
+}
+
+func TestRedisScraperClientSaturationFromInfo(t *testing.T) {
+	node := newRedisNode(maxClientsInfoClient{newFakeClient()}, nil)
+	scraper := newTestScraper(staticNodes{node}, "", nil)
+	rms := scrapeMetrics(t, scraper)
+
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() == "redis/clients/max" {
+			assert.Equal(t, int64(10000), ms.At(i).IntGauge().DataPoints().At(0).Value())
+			return
+		}
+	}
+	t.Fatal("redis/clients/max not found")
+}
+
+// A client of a Redis 7 server, which reports maxclients in INFO.
+type maxClientsInfoClient struct {
+	*fakeClient
+}
+
+func (c maxClientsInfoClient) retrieveInfo() (string, error) {
+	str, err := c.fakeClient.retrieveInfo()
+	return strings.Replace(str, "connected_clients:1", "connected_clients:1"+c.delimiter()+"maxclients:10000", 1), err
+}
+
+func (maxClientsInfoClient) retrieveMaxClients() (int64, error) {
+	return 0, errors.New("CONFIG is disabled")
+}
+
+func TestRedisScraperClientListOptIn(t *testing.T) {
+	c := &clientListCountingClient{fakeClient: newFakeClient()}
+	scraper := newTestScraper(staticNodes{newRedisNode(c, nil)}, "", nil)
+	names := metricNames(scrapeMetrics(t, scraper))
+	assert.False(t, names["redis/clients/by_state"])
+	assert.Zero(t, c.calls, "CLIENT LIST is not run by default")
+
+	scraper = newTestScraper(staticNodes{newRedisNode(c, nil)}, "", map[string]MetricSettings{
+		"redis/clients/by_state": {Enabled: true},
+	})
+	names = metricNames(scrapeMetrics(t, scraper))
+	assert.True(t, names["redis/clients/by_state"])
+	assert.False(t, names["redis/clients/by_age"])
+	assert.Equal(t, 1, c.calls)
+}
+
+// Returns the names of the metrics of the first resource.
+func metricNames(rms pdata.ResourceMetricsSlice) map[string]bool {
+	names := map[string]bool{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		names[ms.At(i).Name()] = true
+	}
+	return names
+}
+
+// A client that counts the runs of CLIENT LIST.
+type clientListCountingClient struct {
+	*fakeClient
+	calls int
+}
+
+func (c *clientListCountingClient) retrieveClientList() (string, error) {
+	c.calls++
+	return c.fakeClient.retrieveClientList()
+}
@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+const nodeIDAttribute = "redis.node.id"
+
+// Queries the topology and state of a Redis Cluster. Can be faked for testing.
+type clusterTopology interface {
+	// Returns the CLUSTER NODES description of the cluster.
+	clusterNodes() (string, error)
+	// Returns CLUSTER INFO, in the same line format as INFO.
+	clusterInfo() (string, error)
+	close() error
+}
+
+// Queries the first seed node that answers.
+type redisClusterTopology struct {
+	seeds []*redisClient
+}
+
+var _ clusterTopology = (*redisClusterTopology)(nil)
+
+func (t *redisClusterTopology) clusterNodes() (string, error) {
+	return t.query((*redisClient).retrieveClusterNodes)
+}
+
+func (t *redisClusterTopology) clusterInfo() (string, error) {
+	return t.query((*redisClient).retrieveClusterInfo)
+}
+
+func (t *redisClusterTopology) query(f func(c *redisClient) (string, error)) (string, error) {
+	if len(t.seeds) == 0 {
+		return "", errors.New("no cluster addresses")
+	}
+	var err error
+	for _, c := range t.seeds {
+		var str string
+		if str, err = f(c); err == nil {
+			return str, nil
+		}
+	}
+	return "", err
+}
+
+func (t *redisClusterTopology) close() error {
+	var err error
+	for _, c := range t.seeds {
+		if cErr := c.close(); cErr != nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// A node of a Redis Cluster, as described by CLUSTER NODES.
+type clusterNode struct {
+	id   string
+	addr string
+	role string
+}
+
+// Parses the reply of CLUSTER NODES into the nodes that are reachable. Each
+// line describes a node: "<id> <ip:port@cport[,hostname]> <flags> <master>
+// <ping-sent> <pong-recv> <config-epoch> <link-state> <slot>...".
+func parseClusterNodes(str string) []clusterNode {
+	var nodes []clusterNode
+	for _, line := range strings.Split(str, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
+		}
+
+		addr := fields[1]
+		if i := strings.IndexAny(addr, "@,"); i >= 0 {
+			addr = addr[:i]
+		}
+		if strings.HasPrefix(addr, ":") || fields[7] != "connected" {
+			continue
+		}
+
+		role := ""
+		failed := false
+		for _, flag := range strings.Split(fields[2], ",") {
+			switch flag {
+			case "master":
+				role = roleMaster
+			case "slave":
+				role = roleReplica
+			case "fail", "noaddr", "handshake":
+				failed = true
+			}
+		}
+		if failed || role == "" {
+			continue
+		}
+		nodes = append(nodes, clusterNode{id: fields[0], addr: addr, role: role})
+	}
+	return nodes
+}
+
+// Parses CLUSTER INFO. cluster_state is "ok" or "fail", so it is added as
+// cluster_state_ok, 1 or 0, to be read like the other numeric values.
+func parseClusterInfo(str string) info {
+	inf := parseInfoLines(str)
+	if state, ok := inf["cluster_state"]; ok {
+		inf["cluster_state_ok"] = "0"
+		if state == "ok" {
+			inf["cluster_state_ok"] = "1"
+		}
+	}
+	return inf
+}
+
+// Implemented by node sources of a Redis Cluster, to add cluster-level metrics.
+type clusterInfoSource interface {
+	clusterInfo() (info, error)
+}
+
+// Scrapes every reachable node of a Redis Cluster, discovered on each run.
+type clusterNodes struct {
+	topology  clusterTopology
+	newClient func(addr string) client
+
+	mu    sync.Mutex
+	known map[string]*redisNode
+}
+
+var _ nodeSource = (*clusterNodes)(nil)
+var _ clusterInfoSource = (*clusterNodes)(nil)
+
+func newClusterNodes(topology clusterTopology, newClient func(addr string) client) *clusterNodes {
+	return &clusterNodes{
+		topology:  topology,
+		newClient: newClient,
+		known:     make(map[string]*redisNode),
+	}
+}
+
+func (c *clusterNodes) nodes() ([]*redisNode, error) {
+	str, err := c.topology.clusterNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var nodes []*redisNode
+	current := make(map[string]bool)
+	for _, n := range parseClusterNodes(str) {
+		current[n.id] = true
+		node, ok := c.known[n.id]
+		if ok && node.attributes[nodeAddressAttribute] != n.addr {
+			// The node moved, connect to its new address.
+			_ = node.client.close()
+			ok = false
+		}
+		if !ok {
+			node = newRedisNode(c.newClient(n.addr), map[string]string{
+				nodeIDAttribute:      n.id,
+				nodeAddressAttribute: n.addr,
+			})
+			c.known[n.id] = node
+		}
+		// Roles change on failover.
+		node.attributes[nodeRoleAttribute] = n.role
+		nodes = append(nodes, node)
+	}
+
+	// Forget nodes that failed or left the cluster.
+	for id, node := range c.known {
+		if !current[id] {
+			_ = node.client.close()
+			delete(c.known, id)
+		}
+	}
+	return nodes, nil
+}
+
+// Returns the state of the cluster, for the cluster-level metrics.
+func (c *clusterNodes) clusterInfo() (info, error) {
+	str, err := c.topology.clusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterInfo(str), nil
+}
+
+func (c *clusterNodes) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for id, node := range c.known {
+		if cErr := node.client.close(); cErr != nil {
+			err = cErr
+		}
+		delete(c.known, id)
+	}
+	if cErr := c.topology.close(); cErr != nil {
+		err = cErr
+	}
+	return err
+}
@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClusterNodes = `07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected
+67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002,redis-2 master - 0 1426238316232 2 connected 5461-10922
+292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 127.0.0.1:30003@31003 master - 0 1426238318243 3 connected 10923-16383
+6ec23923021cf3ffec47632106199cb7f496ce01 127.0.0.1:30005@31005 slave,fail 67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 0 1426238316232 5 connected
+824fe116063bc5fcf9f4ffd895bc17aee7731ac3 127.0.0.1:30006@31006 slave 292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 0 1426238317741 6 disconnected
+e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460
+`
+
+const testClusterInfo = "cluster_state:ok\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:16384\r\n" +
+	"cluster_slots_pfail:0\r\ncluster_slots_fail:0\r\ncluster_known_nodes:6\r\ncluster_size:3\r\n"
+
+func TestParseClusterNodes(t *testing.T) {
+	assert.Equal(t, []clusterNode{
+		{id: "07c37dfeb235213a872192d90877d0cd55635b91", addr: "127.0.0.1:30004", role: roleReplica},
+		{id: "67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1", addr: "127.0.0.1:30002", role: roleMaster},
+		{id: "292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f", addr: "127.0.0.1:30003", role: roleMaster},
+		{id: "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca", addr: "127.0.0.1:30001", role: roleMaster},
+	}, parseClusterNodes(testClusterNodes))
+}
+
+func TestParseClusterInfo(t *testing.T) {
+	inf := parseClusterInfo(testClusterInfo)
+	assert.Equal(t, "1", inf["cluster_state_ok"])
+	assert.Equal(t, "16384", inf["cluster_slots_assigned"])
+
+	inf = parseClusterInfo("cluster_state:fail\r\n")
+	assert.Equal(t, "0", inf["cluster_state_ok"])
+}
+
+type fakeClusterTopology struct {
+	nodes  string
+	closed bool
+}
+
+func (t *fakeClusterTopology) clusterNodes() (string, error) {
+	return t.nodes, nil
+}
+
+func (t *fakeClusterTopology) clusterInfo() (string, error) {
+	return testClusterInfo, nil
+}
+
+func (t *fakeClusterTopology) close() error {
+	t.closed = true
+	return nil
+}
+
+func TestClusterNodes(t *testing.T) {
+	topology := &fakeClusterTopology{nodes: testClusterNodes}
+	clients := map[string]*closeTrackingClient{}
+	source := newClusterNodes(topology, func(addr string) client {
+		c := &closeTrackingClient{}
+		clients[addr] = c
+		return c
+	})
+
+	nodes, err := source.nodes()
+	require.NoError(t, err)
+	require.Len(t, nodes, 4)
+	assert.Equal(t, map[string]string{
+		nodeIDAttribute:      "07c37dfeb235213a872192d90877d0cd55635b91",
+		nodeAddressAttribute: "127.0.0.1:30004",
+		nodeRoleAttribute:    roleReplica,
+	}, nodes[0].attributes)
+	replica := nodes[0]
+
+	// The replica is promoted, and the failed master is removed.
+	topology.nodes = `07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004 master - 0 1426238317239 4 connected 0-5460
+67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002 master - 0 1426238316232 2 connected 5461-10922
+292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 127.0.0.1:30013@31013 master - 0 1426238318243 3 connected 10923-16383
+`
+	nodes, err = source.nodes()
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+	assert.Same(t, replica, nodes[0], "nodes keep their state")
+	assert.Equal(t, roleMaster, nodes[0].attributes[nodeRoleAttribute])
+	assert.Equal(t, "127.0.0.1:30013", nodes[2].attributes[nodeAddressAttribute])
+	assert.True(t, clients["127.0.0.1:30001"].closed)
+	assert.True(t, clients["127.0.0.1:30003"].closed, "the client of a moved node is replaced")
+
+	require.NoError(t, source.close())
+	assert.True(t, clients["127.0.0.1:30004"].closed)
+	assert.True(t, topology.closed)
+}
+
+func TestRedisScraperCluster(t *testing.T) {
+	source := newClusterNodes(&fakeClusterTopology{nodes: testClusterNodes}, func(string) client {
+		return newFakeClient()
+	})
+	scraper := newTestScraper(source, "my-redis", nil)
+	rms := scrapeMetrics(t, scraper)
+
+	require.Equal(t, 5, rms.Len())
+
+	cluster := rms.At(4)
+	_, ok := cluster.Resource().Attributes().Get(nodeAddressAttribute)
+	assert.False(t, ok)
+	ms := cluster.InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 7, ms.Len())
+	assert.Equal(t, "redis/cluster/state", ms.At(0).Name())
+	assert.EqualValues(t, 1, ms.At(0).IntGauge().DataPoints().At(0).Value())
+	assert.Equal(t, "redis/cluster/slots", ms.At(1).Name())
+	assert.EqualValues(t, 16384, ms.At(1).IntGauge().DataPoints().At(0).Value())
+}
+
+// A client of a server with cluster mode enabled.
+type clusterEnabledClient struct {
+	*fakeClient
+}
+
+func (c clusterEnabledClient) retrieveInfo() (string, error) {
+	str, err := c.fakeClient.retrieveInfo()
+	return strings.Replace(str, "cluster_enabled:0", "cluster_enabled:1", 1), err
+}
+
+func TestRedisScraperDetectsClusterMode(t *testing.T) {
+	for _, c := range []struct {
+		client   client
+		expected bool
+	}{
+		{newFakeClient(), false},
+		{clusterEnabledClient{newFakeClient()}, true},
+	} {
+		scraper := newTestScraper(staticNodes{newRedisNode(c.client, nil)}, "my-redis", nil)
+		rms := scrapeMetrics(t, scraper)
+
+		require.Equal(t, 1, rms.Len())
+		names := map[string]int{}
+		ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for i := 0; i < ms.Len(); i++ {
+			names[ms.At(i).Name()]++
+		}
+		if !c.expected {
+			assert.Zero(t, names["redis/cluster/state"])
+			continue
+		}
+		assert.Equal(t, 1, names["redis/cluster/state"])
+		assert.Equal(t, 4, names["redis/cluster/slots"])
+		assert.Equal(t, 1, names["redis/cluster/known_nodes"])
+		assert.Equal(t, 1, names["redis/cluster/size"])
+	}
+}
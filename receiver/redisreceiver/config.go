@@ -15,25 +15,702 @@
 package redisreceiver
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
-	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
 )
 
 type Config struct {
-	config.ReceiverSettings `mapstructure:",squash"`
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
 	// TODO: Use one of the configs from core.
-	// The target endpoint.
+	// The target endpoint, host:port or unix:///path/to/redis.sock.
 	Endpoint string `mapstructure:"endpoint"`
-	// The duration between Redis metric fetches.
-	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+	// The duration between the start of the receiver and the first fetch.
+	InitialDelay time.Duration `mapstructure:"initial_delay"`
+	// Bounds each round trip to Redis, including connecting, so that a hung
+	// server does not stall the receiver. 0 means no timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
 	// The logical name of the Redis server. This value will be added as a
-	// "service.name" Resource label.
+	// "service.name" Resource label. If not set, it is derived from the
+	// endpoint, see serviceName.
 	ServiceName string `mapstructure:"service_name"`
 
-	// TODO allow users to add additional resource key value pairs?
+	// Optional static attributes added to the resource of every metric and
+	// log, e.g. the environment or the team owning the server.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+
+	// Optional username of a Redis 6+ ACL user. If not set, the password
+	// authenticates the default user.
+	Username string `mapstructure:"username"`
 
 	// Optional password. Must match the password specified in the
-	// requirepass server configuration option.
+	// requirepass server configuration option, or the password of the ACL user.
 	Password string `mapstructure:"password"`
+
+	// Optional file containing the password. It is read again every minute,
+	// and when Redis rejects the password, so that rotated passwords are
+	// picked up.
+	PasswordFile string `mapstructure:"password_file"`
+
+	// Optional environment variable containing the password. Like the file,
+	// it is read again when Redis rejects the password.
+	PasswordEnv string `mapstructure:"password_env"`
+
+	// Optional IAM authentication of an ElastiCache or MemoryDB user, as an
+	// alternative to the password. Username is the id of the user.
+	IAMAuth *IAMAuthConfig `mapstructure:"iam_auth"`
+
+	// Optional Microsoft Entra ID authentication to Azure Cache for Redis, as
+	// an alternative to the access keys. Username is the object id of the
+	// service principal or managed identity.
+	EntraAuth *EntraAuthConfig `mapstructure:"entra_auth"`
+
+	// Optional TLS settings. If not set, connections are not encrypted.
+	TLS *configtls.TLSClientSetting `mapstructure:"tls"`
+
+	// Optional additional Redis servers scraped by the receiver, each with its
+	// own credentials and TLS settings.
+	Endpoints []EndpointConfig `mapstructure:"endpoints"`
+
+	// Optional Sentinel settings. If set, the receiver scrapes the master found
+	// through Sentinel, following it on failover, and Endpoint is not used.
+	Sentinel *SentinelConfig `mapstructure:"sentinel"`
+
+	// Optional Redis Cluster settings. If set, the receiver discovers the nodes
+	// of the cluster and scrapes each of them, and Endpoint is not used.
+	Cluster *ClusterConfig `mapstructure:"cluster"`
+
+	// Optional Redis Enterprise settings. If set, the metrics are scraped from
+	// the REST API of the Redis Enterprise cluster instead of the Redis
+	// endpoints. Logs are still read from Endpoint.
+	Enterprise *EnterpriseConfig `mapstructure:"enterprise"`
+
+	// Optional settings of individual metrics, keyed by metric name, to
+	// disable default metrics or enable optional ones.
+	Metrics map[string]MetricSettings `mapstructure:"metrics"`
+
+	// Optional DBSIZE settings, to report the number of keys of databases that
+	// are missing from the keyspace section of INFO.
+	DBSize DBSizeConfig `mapstructure:"dbsize"`
+
+	// Optional filter of the databases whose keyspace metrics are reported.
+	Keyspace KeyspaceConfig `mapstructure:"keyspace"`
+
+	// Optional pub/sub settings.
+	PubSub PubSubConfig `mapstructure:"pubsub"`
+
+	// Optional streams whose length and consumer groups are reported.
+	Streams []string `mapstructure:"streams"`
+
+	// Optional keys whose TTL, memory usage and length are reported.
+	Keys []string `mapstructure:"keys"`
+
+	// Optional key patterns whose matching keys are counted with SCAN.
+	KeyPatterns KeyPatternsConfig `mapstructure:"key_patterns"`
+
+	// Settings of the INFO command.
+	Info InfoConfig `mapstructure:"info"`
+
+	// Settings of the slow log entries emitted in logs pipelines.
+	SlowLog SlowLogConfig `mapstructure:"slowlog"`
+
+	// Optional settings of the keyspace notifications emitted in logs
+	// pipelines.
+	KeyspaceNotifications KeyspaceNotificationsConfig `mapstructure:"keyspace_notifications"`
+
+	// Settings of the backoff between attempts to reconnect to a node that
+	// cannot be scraped.
+	Reconnect ReconnectConfig `mapstructure:"reconnect"`
+
+	// How nodes that are replicas are scraped.
+	Replica ReplicaConfig `mapstructure:"replica"`
+
+	// The names of the commands renamed on the server with rename-command, by
+	// name, e.g. config: "a8f2c1-config". Commands disabled on the server, or
+	// denied to the ACL user, are not sent again to a node once it rejects
+	// them.
+	RenamedCommands map[string]string `mapstructure:"renamed_commands"`
+
+	// Whether metric names, units and types follow the semantic conventions
+	// instead of the names the receiver used so far, which existing
+	// dashboards rely on. The settings of Metrics still use the latter.
+	SemanticConventions bool `mapstructure:"semantic_conventions"`
+}
+
+// DBSizeConfig defines which databases are queried with DBSIZE.
+type DBSizeConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// The databases to report. Defaults to the 16 databases of the default
+	// server configuration.
+	Databases []int `mapstructure:"databases"`
+}
+
+// databases returns the databases to report.
+func (c *DBSizeConfig) databases() []int {
+	if len(c.Databases) > 0 {
+		return c.Databases
+	}
+	dbs := make([]int, redisMaxDbs)
+	for i := range dbs {
+		dbs[i] = i
+	}
+	return dbs
+}
+
+// KeyspaceConfig defines the databases whose keyspace metrics, the number of
+// keys, of keys with an expiry and their average TTL, are reported.
+type KeyspaceConfig struct {
+	// The databases reported. All of them if empty.
+	Databases []int `mapstructure:"databases"`
+	// The databases left out, e.g. noisy ones.
+	ExcludeDatabases []int `mapstructure:"exclude_databases"`
+}
+
+// includes returns whether the keyspace metrics of a database are reported.
+func (c *KeyspaceConfig) includes(db int) bool {
+	for _, d := range c.ExcludeDatabases {
+		if d == db {
+			return false
+		}
+	}
+	if len(c.Databases) == 0 {
+		return true
+	}
+	for _, d := range c.Databases {
+		if d == db {
+			return true
+		}
+	}
+	return false
+}
+
+// PubSubConfig defines the pub/sub channels whose subscribers are counted.
+type PubSubConfig struct {
+	// The channels whose number of subscribers is reported.
+	Channels []string `mapstructure:"channels"`
+}
+
+// KeyPatternsConfig defines the key patterns whose matching keys are counted,
+// and the budget of the SCAN commands run to count them.
+type KeyPatternsConfig struct {
+	// The glob-style patterns of SCAN MATCH, e.g. "session:*".
+	Patterns []string `mapstructure:"patterns"`
+	// The COUNT hint of each SCAN command, i.e. about how many keys it visits.
+	Count int64 `mapstructure:"count"`
+	// The maximum number of SCAN commands run per pattern and node on each run.
+	// When it is reached before the whole keyspace is scanned, the count is
+	// partial.
+	MaxCalls int64 `mapstructure:"max_calls"`
+}
+
+// SlowLogConfig defines how the slow log is fetched.
+type SlowLogConfig struct {
+	// The number of most recent entries fetched with SLOWLOG GET on each run.
+	// Entries beyond it that were added since the previous run are missed.
+	MaxEntries int64 `mapstructure:"max_entries"`
+}
+
+// InfoConfig defines how INFO is queried.
+type InfoConfig struct {
+	// The section argument of INFO, e.g. everything or default.
+	Section string `mapstructure:"section"`
+	// Enables reporting the numeric fields that the receiver does not know,
+	// e.g. those added by a newer Redis version, as gauges named after them.
+	UnknownFields bool `mapstructure:"unknown_fields"`
+}
+
+// KeyspaceNotificationsConfig defines the keyspace notifications emitted as log
+// records. The server must be configured to publish them, see
+// notify-keyspace-events.
+type KeyspaceNotificationsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// The events emitted. Defaults to expired and evicted.
+	Events []string `mapstructure:"events"`
+	// Glob-style patterns of the keys whose events are emitted. Defaults to
+	// all keys.
+	Patterns []string `mapstructure:"patterns"`
+	// The maximum number of events of a node buffered between runs. Further
+	// events are dropped.
+	MaxEvents int `mapstructure:"max_events"`
+}
+
+// events returns the events to emit.
+func (c *KeyspaceNotificationsConfig) events() []string {
+	if len(c.Events) > 0 {
+		return c.Events
+	}
+	return []string{"expired", "evicted"}
+}
+
+// patterns returns the patterns of the keys whose events are emitted.
+func (c *KeyspaceNotificationsConfig) patterns() []string {
+	if len(c.Patterns) > 0 {
+		return c.Patterns
+	}
+	return []string{"*"}
+}
+
+// ReconnectConfig defines how long a node that cannot be scraped is skipped.
+// The delay starts at InitialInterval and doubles with each consecutive
+// failure, up to MaxInterval.
+type ReconnectConfig struct {
+	// The delay after the first failure. 0 disables the backoff, so that the
+	// node is attempted on every run.
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// The maximum delay.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+}
+
+// Returns the renamed commands by lowercase name, nil if none are.
+func (cfg *Config) commandNames() commandNames {
+	if len(cfg.RenamedCommands) == 0 {
+		return nil
+	}
+	names := make(commandNames, len(cfg.RenamedCommands))
+	for command, name := range cfg.RenamedCommands {
+		names[strings.ToLower(command)] = name
+	}
+	return names
+}
+
+// IAMAuthConfig defines how the IAM authentication tokens of an ElastiCache
+// or MemoryDB user are generated. The tokens are signed with the AWS
+// credentials of the collector.
+type IAMAuthConfig struct {
+	// The name of the ElastiCache replication group or serverless cache, or
+	// of the MemoryDB cluster.
+	CacheName string `mapstructure:"cache_name"`
+	// The AWS region of the cache.
+	Region string `mapstructure:"region"`
+	// Either elasticache, the default, or memorydb.
+	Service string `mapstructure:"service"`
+	// Whether the cache is an ElastiCache serverless cache.
+	Serverless bool `mapstructure:"serverless"`
+	// Optional role assumed to sign the tokens.
+	RoleARN string `mapstructure:"role_arn"`
+}
+
+// service returns the signing name of the service of the cache.
+func (c *IAMAuthConfig) service() string {
+	if c.Service == "" {
+		return "elasticache"
+	}
+	return c.Service
+}
+
+func (c *IAMAuthConfig) validate(cfg *Config) error {
+	switch {
+	case cfg.Username == "":
+		return errors.New("iam_auth requires username, the id of the user")
+	case cfg.Password != "" || cfg.PasswordFile != "" || cfg.PasswordEnv != "":
+		return errors.New("iam_auth cannot be combined with password, password_file or password_env")
+	case cfg.TLS == nil || cfg.TLS.Insecure:
+		return errors.New("iam_auth requires tls")
+	case c.CacheName == "":
+		return errors.New("iam_auth cache_name must be set")
+	case c.Region == "":
+		return errors.New("iam_auth region must be set")
+	case c.service() != "elasticache" && c.service() != "memorydb":
+		return fmt.Errorf("iam_auth service must be elasticache or memorydb, not %q", c.Service)
+	case c.Serverless && c.service() != "elasticache":
+		return errors.New("iam_auth serverless is only supported by elasticache")
+	}
+	return nil
+}
+
+// EntraAuthConfig defines how the Microsoft Entra ID access tokens used to
+// authenticate to Azure Cache for Redis are requested. Exactly one of
+// client_secret, federated_token_file and managed_identity must be set.
+type EntraAuthConfig struct {
+	// The tenant of the service principal. Not used by managed identities.
+	TenantID string `mapstructure:"tenant_id"`
+	// The application id of the service principal, or of the user-assigned
+	// managed identity. The system-assigned identity is used if empty.
+	ClientID string `mapstructure:"client_id"`
+	// The secret of the service principal.
+	ClientSecret string `mapstructure:"client_secret"`
+	// The file containing the federated token of a workload identity, e.g. the
+	// AZURE_FEDERATED_TOKEN_FILE on AKS. It is read again for every token.
+	FederatedTokenFile string `mapstructure:"federated_token_file"`
+	// Whether the managed identity of the host is used.
+	ManagedIdentity bool `mapstructure:"managed_identity"`
+	// The Microsoft Entra endpoint, https://login.microsoftonline.com by
+	// default. Sovereign clouds have their own.
+	AuthorityHost string `mapstructure:"authority_host"`
+}
+
+// authorityHost returns the Microsoft Entra endpoint of the tokens.
+func (c *EntraAuthConfig) authorityHost() string {
+	if c.AuthorityHost == "" {
+		return "https://login.microsoftonline.com"
+	}
+	return c.AuthorityHost
+}
+
+func (c *EntraAuthConfig) validate(cfg *Config) error {
+	set := 0
+	for _, b := range []bool{c.ClientSecret != "", c.FederatedTokenFile != "", c.ManagedIdentity} {
+		if b {
+			set++
+		}
+	}
+	switch {
+	case cfg.Username == "":
+		return errors.New("entra_auth requires username, the object id of the principal")
+	case cfg.Password != "" || cfg.PasswordFile != "" || cfg.PasswordEnv != "":
+		return errors.New("entra_auth cannot be combined with password, password_file or password_env")
+	case cfg.IAMAuth != nil:
+		return errors.New("only one of iam_auth and entra_auth can be set")
+	case cfg.TLS == nil || cfg.TLS.Insecure:
+		return errors.New("entra_auth requires tls")
+	case set != 1:
+		return errors.New("entra_auth requires exactly one of client_secret, federated_token_file and managed_identity")
+	case !c.ManagedIdentity && (c.TenantID == "" || c.ClientID == ""):
+		return errors.New("entra_auth tenant_id and client_id must be set")
+	}
+	if u, err := url.Parse(c.authorityHost()); err != nil || u.Host == "" {
+		return fmt.Errorf("entra_auth authority_host %q is not a URL", c.AuthorityHost)
+	}
+	return nil
+}
+
+// ReplicaConfig defines how nodes whose INFO reports them as replicas are
+// scraped. The role is checked on every scrape, so that the same settings
+// apply after a failover.
+type ReplicaConfig struct {
+	// Whether the monitored keys, streams and key patterns are queried on
+	// replicas. These commands walk the keyspace on the main thread, delaying
+	// the writes replicated from the master.
+	KeyCommands bool `mapstructure:"key_commands"`
+	// Whether the metrics that only apply to masters, e.g. expired and evicted
+	// keys, are left out for replicas.
+	SuppressMasterMetrics bool `mapstructure:"suppress_master_metrics"`
+}
+
+// MetricSettings defines whether a metric is produced.
+type MetricSettings struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// EndpointConfig defines a Redis server scraped in addition to Endpoint.
+// Settings that are not set are taken from the receiver.
+type EndpointConfig struct {
+	// The target endpoint, host:port or unix:///path/to/redis.sock.
+	Endpoint string `mapstructure:"endpoint"`
+	// Optional logical name of the server, overriding the one of the receiver.
+	// If neither is set, it is derived from the endpoint.
+	ServiceName string `mapstructure:"service_name"`
+	// Optional static resource attributes of the server, added to those of the
+	// receiver.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+
+	Username     string                      `mapstructure:"username"`
+	Password     string                      `mapstructure:"password"`
+	PasswordFile string                      `mapstructure:"password_file"`
+	PasswordEnv  string                      `mapstructure:"password_env"`
+	TLS          *configtls.TLSClientSetting `mapstructure:"tls"`
+}
+
+// serviceName returns the service name of the receiver, derived from the
+// endpoint, the Sentinel master name or the first cluster address if it is not
+// set.
+func (cfg *Config) serviceName() string {
+	switch {
+	case cfg.ServiceName != "":
+		return cfg.ServiceName
+	case cfg.Sentinel != nil:
+		return cfg.Sentinel.MasterName
+	case cfg.Cluster != nil && len(cfg.Cluster.Addresses) > 0:
+		return endpointServiceName(cfg.Cluster.Addresses[0])
+	case cfg.Enterprise != nil && cfg.Endpoint == "":
+		return cfg.Enterprise.Endpoint
+	default:
+		return endpointServiceName(cfg.Endpoint)
+	}
+}
+
+// resourceAttributes returns the attributes added to every resource: the
+// configured ones and the service name.
+func (cfg *Config) resourceAttributes() map[string]string {
+	attributes := make(map[string]string, len(cfg.ResourceAttributes)+1)
+	for k, v := range cfg.ResourceAttributes {
+		attributes[k] = v
+	}
+	attributes[serviceNameAttribute] = cfg.serviceName()
+	return attributes
+}
+
+// endpointServiceName returns the service name derived from an endpoint, as a
+// URL, e.g. redis://localhost:6379 or unix:///var/run/redis/redis.sock.
+func endpointServiceName(endpoint string) string {
+	if endpoint == "" || strings.HasPrefix(endpoint, "unix://") {
+		return endpoint
+	}
+	return "redis://" + endpoint
+}
+
+// hasPassword returns whether the endpoint has its own password settings.
+func (e *EndpointConfig) hasPassword() bool {
+	return e.Password != "" || e.PasswordFile != "" || e.PasswordEnv != ""
+}
+
+// SentinelConfig defines how the master is discovered through Redis Sentinel.
+type SentinelConfig struct {
+	// The host:port addresses of the Sentinel instances.
+	Addresses []string `mapstructure:"addresses"`
+	// The name of the master, as configured in Sentinel.
+	MasterName string `mapstructure:"master_name"`
+	// Optional credentials for Sentinel. The username and password of the
+	// receiver are used for the Redis servers.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// Replicas enables scraping the replicas of the master as well.
+	Replicas bool `mapstructure:"replicas"`
+	// Monitor enables reporting the state of the Sentinel instances, and of
+	// the masters they monitor.
+	Monitor bool `mapstructure:"monitor"`
+}
+
+// ClusterConfig defines how the nodes of a Redis Cluster are discovered.
+type ClusterConfig struct {
+	// The host:port addresses of nodes to query for the cluster topology. The
+	// other nodes are discovered from them.
+	Addresses []string `mapstructure:"addresses"`
+}
+
+// EnterpriseConfig defines how the REST API of a Redis Enterprise cluster is
+// queried for the metrics of its databases, shards and nodes.
+type EnterpriseConfig struct {
+	// The URL of the REST API, e.g. https://cluster.example.com:9443.
+	Endpoint string `mapstructure:"endpoint"`
+	// The credentials of a user of the cluster, e.g. with the DB Viewer or
+	// Cluster Viewer role.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// TLS settings of the REST API, separate from those of the Redis
+	// endpoints. The cluster certificate is self-signed by default.
+	TLS configtls.TLSClientSetting `mapstructure:"tls"`
+}
+
+func (c *EnterpriseConfig) validate(cfg *Config) error {
+	u, err := url.Parse(c.Endpoint)
+	switch {
+	case c.Endpoint == "":
+		return errors.New("enterprise endpoint must be set")
+	case err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "":
+		return fmt.Errorf("enterprise endpoint %q is not an http or https URL", c.Endpoint)
+	case c.Username == "" || c.Password == "":
+		return errors.New("enterprise username and password must be set")
+	case cfg.Sentinel != nil || cfg.Cluster != nil || len(cfg.Endpoints) > 0:
+		return errors.New("enterprise cannot be combined with sentinel, cluster or endpoints")
+	}
+	return nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.CollectionInterval <= 0 {
+		return errors.New("collection_interval must be positive")
+	}
+	if cfg.InitialDelay < 0 {
+		return errors.New("initial_delay must not be negative")
+	}
+	if cfg.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	for _, db := range cfg.DBSize.Databases {
+		if db < 0 {
+			return fmt.Errorf("invalid dbsize database %d", db)
+		}
+	}
+	for _, dbs := range [][]int{cfg.Keyspace.Databases, cfg.Keyspace.ExcludeDatabases} {
+		for _, db := range dbs {
+			if db < 0 || db >= redisMaxDbs {
+				return fmt.Errorf("invalid keyspace database %d", db)
+			}
+		}
+	}
+	for _, channel := range cfg.PubSub.Channels {
+		if channel == "" {
+			return errors.New("pubsub channels must not be empty")
+		}
+	}
+	for _, stream := range cfg.Streams {
+		if stream == "" {
+			return errors.New("streams must not be empty")
+		}
+	}
+	for _, key := range cfg.Keys {
+		if key == "" {
+			return errors.New("keys must not be empty")
+		}
+	}
+	for _, pattern := range cfg.KeyPatterns.Patterns {
+		if pattern == "" {
+			return errors.New("key_patterns patterns must not be empty")
+		}
+	}
+	for _, event := range cfg.KeyspaceNotifications.Events {
+		if event == "" {
+			return errors.New("keyspace_notifications events must not be empty")
+		}
+	}
+	for _, pattern := range cfg.KeyspaceNotifications.Patterns {
+		if pattern == "" {
+			return errors.New("keyspace_notifications patterns must not be empty")
+		}
+	}
+	if cfg.KeyspaceNotifications.MaxEvents <= 0 {
+		return errors.New("keyspace_notifications max_events must be positive")
+	}
+	if cfg.KeyPatterns.Count <= 0 {
+		return errors.New("key_patterns count must be positive")
+	}
+	if cfg.KeyPatterns.MaxCalls <= 0 {
+		return errors.New("key_patterns max_calls must be positive")
+	}
+	if cfg.SlowLog.MaxEntries <= 0 {
+		return errors.New("slowlog max_entries must be positive")
+	}
+	for command, name := range cfg.RenamedCommands {
+		if command == "" || name == "" || strings.ContainsAny(command+name, " \t\r\n") {
+			return fmt.Errorf("renamed_commands: invalid name %q for command %q", name, command)
+		}
+	}
+	if cfg.IAMAuth != nil {
+		if err := cfg.IAMAuth.validate(cfg); err != nil {
+			return err
+		}
+	}
+	if cfg.EntraAuth != nil {
+		if err := cfg.EntraAuth.validate(cfg); err != nil {
+			return err
+		}
+	}
+	if cfg.Enterprise != nil {
+		if err := cfg.Enterprise.validate(cfg); err != nil {
+			return err
+		}
+	}
+	if cfg.Info.Section == "" {
+		return errors.New("info section must be set")
+	}
+	if cfg.Reconnect.InitialInterval < 0 {
+		return errors.New("reconnect initial_interval must not be negative")
+	}
+	if cfg.Reconnect.MaxInterval < cfg.Reconnect.InitialInterval {
+		return errors.New("reconnect max_interval must not be less than initial_interval")
+	}
+	if _, ok := cfg.ResourceAttributes[serviceNameAttribute]; ok {
+		return errors.New("resource_attributes cannot set service.name, use service_name instead")
+	}
+	for _, e := range cfg.Endpoints {
+		if _, ok := e.ResourceAttributes[serviceNameAttribute]; ok {
+			return fmt.Errorf("endpoint %s: resource_attributes cannot set service.name, use service_name instead", e.Endpoint)
+		}
+	}
+	known := knownMetricNames()
+	for name := range cfg.Metrics {
+		if !known[name] {
+			return fmt.Errorf("unknown metric %q", name)
+		}
+	}
+	if _, err := cfg.passwordSource(); err != nil {
+		return err
+	}
+	if cfg.Sentinel != nil {
+		if len(cfg.Sentinel.Addresses) == 0 {
+			return errors.New("sentinel requires at least one address")
+		}
+		if cfg.Sentinel.MasterName == "" {
+			return errors.New("sentinel requires a master_name")
+		}
+	}
+	if err := validateEndpoint(cfg.Endpoint); err != nil {
+		return err
+	}
+	for _, e := range cfg.Endpoints {
+		if e.Endpoint == "" {
+			return errors.New("endpoints require an endpoint")
+		}
+		if err := validateEndpoint(e.Endpoint); err != nil {
+			return err
+		}
+		if _, err := newPasswordSource(e.Password, e.PasswordFile, e.PasswordEnv); err != nil {
+			return fmt.Errorf("endpoint %s: %w", e.Endpoint, err)
+		}
+	}
+	if len(cfg.Endpoints) > 0 && (cfg.Sentinel != nil || cfg.Cluster != nil) {
+		return errors.New("endpoints cannot be combined with sentinel or cluster")
+	}
+	if cfg.Cluster != nil {
+		if cfg.Sentinel != nil {
+			return errors.New("only one of sentinel and cluster can be set")
+		}
+		if len(cfg.Cluster.Addresses) == 0 {
+			return errors.New("cluster requires at least one address")
+		}
+	}
+	return nil
+}
+
+func validateEndpoint(endpoint string) error {
+	if network, addr := parseEndpoint(endpoint); network == "unix" && addr == "" {
+		return fmt.Errorf("endpoint %s has no socket path", endpoint)
+	}
+	return nil
+}
+
+// passwordSource returns a function reading the current password from the
+// configured file or environment variable, or nil if the password is static.
+func (cfg *Config) passwordSource() (func() (string, error), error) {
+	if cfg.IAMAuth != nil {
+		source, err := newIAMAuthTokenSource(cfg.Username, cfg.IAMAuth)
+		if err != nil {
+			return nil, fmt.Errorf("iam_auth: %w", err)
+		}
+		return source.password, nil
+	}
+	if cfg.EntraAuth != nil {
+		return newEntraTokenSource(cfg.EntraAuth).password, nil
+	}
+	return newPasswordSource(cfg.Password, cfg.PasswordFile, cfg.PasswordEnv)
+}
+
+func newPasswordSource(password, passwordFile, passwordEnv string) (func() (string, error), error) {
+	set := 0
+	for _, s := range []string{password, passwordFile, passwordEnv} {
+		if s != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, errors.New("only one of password, password_file and password_env can be set")
+	}
+
+	switch {
+	case passwordFile != "":
+		return func() (string, error) {
+			b, err := ioutil.ReadFile(passwordFile)
+			if err != nil {
+				return "", fmt.Errorf("failed to read password file: %w", err)
+			}
+			return strings.TrimRight(string(b), "\r\n"), nil
+		}, nil
+	case passwordEnv != "":
+		return func() (string, error) {
+			password, ok := os.LookupEnv(passwordEnv)
+			if !ok {
+				return "", fmt.Errorf("password environment variable %s is not set", passwordEnv)
+			}
+			return password, nil
+		}, nil
+	}
+	return nil, nil
 }
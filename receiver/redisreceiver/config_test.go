@@ -0,0 +1,392 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings:   config.NewReceiverSettings(config.NewID(typeStr)),
+				CollectionInterval: 30 * time.Second,
+			},
+			Endpoint:           "localhost:6379",
+			ServiceName:        "my-redis",
+			ResourceAttributes: map[string]string{"deployment.environment": "production"},
+			InitialDelay:       5 * time.Second,
+			Timeout:            2 * time.Second,
+			DBSize:             DBSizeConfig{Enabled: true, Databases: []int{0, 1, 2}},
+			Keyspace:           KeyspaceConfig{ExcludeDatabases: []int{15}},
+			PubSub:             PubSubConfig{Channels: []string{"events"}},
+			Streams:            []string{"events"},
+			Keys:               []string{"jobs:queue", "config:cache"},
+			KeyPatterns: KeyPatternsConfig{
+				Patterns: []string{"session:*", "cache:*"},
+				Count:    1000,
+				MaxCalls: 10,
+			},
+			Info:    InfoConfig{Section: "default", UnknownFields: true},
+			SlowLog: SlowLogConfig{MaxEntries: 32},
+			KeyspaceNotifications: KeyspaceNotificationsConfig{
+				Enabled:   true,
+				Events:    []string{"expired"},
+				Patterns:  []string{"session:*"},
+				MaxEvents: 500,
+			},
+			Reconnect: ReconnectConfig{
+				InitialInterval: 5 * time.Second,
+				MaxInterval:     time.Minute,
+			},
+			Replica:             ReplicaConfig{SuppressMasterMetrics: true},
+			SemanticConventions: true,
+			RenamedCommands:     map[string]string{"config": "a8f2c1-config"},
+			Username:            "monitoring",
+			Password:            "test",
+			Metrics: map[string]MetricSettings{
+				"redis/cpu/time":  {Enabled: false},
+				"redis/maxmemory": {Enabled: true},
+			},
+		},
+		cfg.Receivers[config.NewID(typeStr)],
+	)
+
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "sentinel")),
+				CollectionInterval: 10 * time.Second,
+			},
+			ServiceName:           "my-redis",
+			InitialDelay:          time.Second,
+			Timeout:               5 * time.Second,
+			KeyPatterns:           KeyPatternsConfig{Count: 100, MaxCalls: 100},
+			Info:                  InfoConfig{Section: "everything"},
+			SlowLog:               SlowLogConfig{MaxEntries: 128},
+			KeyspaceNotifications: KeyspaceNotificationsConfig{MaxEvents: 1000},
+			Reconnect:             ReconnectConfig{InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute},
+			Sentinel: &SentinelConfig{
+				Addresses:  []string{"sentinel-1:26379", "sentinel-2:26379"},
+				MasterName: "mymaster",
+				Password:   "sentinel",
+				Replicas:   true,
+				Monitor:    true,
+			},
+		},
+		cfg.Receivers[config.NewIDWithName(typeStr, "sentinel")],
+	)
+
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "cluster")),
+				CollectionInterval: 10 * time.Second,
+			},
+			ServiceName:           "my-redis",
+			InitialDelay:          time.Second,
+			Timeout:               5 * time.Second,
+			KeyPatterns:           KeyPatternsConfig{Count: 100, MaxCalls: 100},
+			Info:                  InfoConfig{Section: "everything"},
+			SlowLog:               SlowLogConfig{MaxEntries: 128},
+			KeyspaceNotifications: KeyspaceNotificationsConfig{MaxEvents: 1000},
+			Reconnect:             ReconnectConfig{InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute},
+			Cluster: &ClusterConfig{
+				Addresses: []string{"redis-1:6379", "redis-2:6379"},
+			},
+		},
+		cfg.Receivers[config.NewIDWithName(typeStr, "cluster")],
+	)
+
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "endpoints")),
+				CollectionInterval: 10 * time.Second,
+			},
+			ServiceName:           "my-redis",
+			InitialDelay:          time.Second,
+			Timeout:               5 * time.Second,
+			KeyPatterns:           KeyPatternsConfig{Count: 100, MaxCalls: 100},
+			Info:                  InfoConfig{Section: "everything"},
+			SlowLog:               SlowLogConfig{MaxEntries: 128},
+			KeyspaceNotifications: KeyspaceNotificationsConfig{MaxEvents: 1000},
+			Reconnect:             ReconnectConfig{InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute},
+			PasswordFile:          "/etc/redis/password",
+			Endpoints: []EndpointConfig{
+				{Endpoint: "redis-1:6379"},
+				{
+					Endpoint:           "redis-2:6380",
+					ServiceName:        "my-other-redis",
+					ResourceAttributes: map[string]string{"team": "payments"},
+					Username:           "monitoring",
+					Password:           "other",
+					TLS: &configtls.TLSClientSetting{
+						TLSSetting: configtls.TLSSetting{
+							CAFile:   "/etc/redis/ca.pem",
+							CertFile: "/etc/redis/client.pem",
+							KeyFile:  "/etc/redis/client-key.pem",
+						},
+						ServerName: "redis.internal",
+					},
+				},
+			},
+		},
+		cfg.Receivers[config.NewIDWithName(typeStr, "endpoints")],
+	)
+
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "elasticache")),
+				CollectionInterval: 10 * time.Second,
+			},
+			Endpoint:              "master.my-cache.abc123.use1.cache.amazonaws.com:6379",
+			InitialDelay:          time.Second,
+			Timeout:               5 * time.Second,
+			KeyPatterns:           KeyPatternsConfig{Count: 100, MaxCalls: 100},
+			Info:                  InfoConfig{Section: "everything"},
+			SlowLog:               SlowLogConfig{MaxEntries: 128},
+			KeyspaceNotifications: KeyspaceNotificationsConfig{MaxEvents: 1000},
+			Reconnect:             ReconnectConfig{InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute},
+			Username:              "monitoring",
+			IAMAuth: &IAMAuthConfig{
+				CacheName: "my-cache",
+				Region:    "us-east-1",
+			},
+			TLS: &configtls.TLSClientSetting{},
+		},
+		cfg.Receivers[config.NewIDWithName(typeStr, "elasticache")],
+	)
+
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "azure")),
+				CollectionInterval: 10 * time.Second,
+			},
+			Endpoint:              "my-cache.redis.cache.windows.net:6380",
+			InitialDelay:          time.Second,
+			Timeout:               5 * time.Second,
+			KeyPatterns:           KeyPatternsConfig{Count: 100, MaxCalls: 100},
+			Info:                  InfoConfig{Section: "everything"},
+			SlowLog:               SlowLogConfig{MaxEntries: 128},
+			KeyspaceNotifications: KeyspaceNotificationsConfig{MaxEvents: 1000},
+			Reconnect:             ReconnectConfig{InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute},
+			Username:              "4c3e7a51-2f0b-4a8e-9d3c-7b1f0e6a2d94",
+			EntraAuth: &EntraAuthConfig{
+				ClientID:        "0f6a8b3c-5d21-4e7f-a9c4-1b2d3e4f5a6b",
+				ManagedIdentity: true,
+			},
+			TLS: &configtls.TLSClientSetting{},
+		},
+		cfg.Receivers[config.NewIDWithName(typeStr, "azure")],
+	)
+
+	assert.Equal(t,
+		&Config{
+			ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+				ReceiverSettings:   config.NewReceiverSettings(config.NewIDWithName(typeStr, "enterprise")),
+				CollectionInterval: 10 * time.Second,
+			},
+			InitialDelay:          time.Second,
+			Timeout:               5 * time.Second,
+			KeyPatterns:           KeyPatternsConfig{Count: 100, MaxCalls: 100},
+			Info:                  InfoConfig{Section: "everything"},
+			SlowLog:               SlowLogConfig{MaxEntries: 128},
+			KeyspaceNotifications: KeyspaceNotificationsConfig{MaxEvents: 1000},
+			Reconnect:             ReconnectConfig{InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute},
+			Enterprise: &EnterpriseConfig{
+				Endpoint: "https://cluster.example.com:9443",
+				Username: "monitoring@example.com",
+				Password: "secret",
+				TLS:      configtls.TLSClientSetting{InsecureSkipVerify: true},
+			},
+		},
+		cfg.Receivers[config.NewIDWithName(typeStr, "enterprise")],
+	)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6379"}).validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "unix:///var/run/redis/redis.sock"}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "unix://"}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", PubSub: PubSubConfig{Channels: []string{""}}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Streams: []string{""}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Keys: []string{""}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", KeyPatterns: KeyPatternsConfig{Patterns: []string{""}}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", DBSize: DBSizeConfig{Enabled: true, Databases: []int{-1}}}).validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6379", Keyspace: KeyspaceConfig{Databases: []int{0, 3}, ExcludeDatabases: []int{15}}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Keyspace: KeyspaceConfig{Databases: []int{16}}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Keyspace: KeyspaceConfig{ExcludeDatabases: []int{-1}}}).validate())
+	assert.NoError(t, validConfig(Config{Sentinel: &SentinelConfig{Addresses: []string{"localhost:26379"}, MasterName: "mymaster"}}).validate())
+	assert.Error(t, validConfig(Config{Sentinel: &SentinelConfig{MasterName: "mymaster"}}).validate())
+	assert.Error(t, validConfig(Config{Sentinel: &SentinelConfig{Addresses: []string{"localhost:26379"}}}).validate())
+	assert.Error(t, validConfig(Config{Password: "a", PasswordFile: "b"}).validate())
+	assert.NoError(t, validConfig(Config{Cluster: &ClusterConfig{Addresses: []string{"localhost:6379"}}}).validate())
+	assert.NoError(t, validConfig(Config{Endpoints: []EndpointConfig{{Endpoint: "localhost:6379"}}}).validate())
+	assert.Error(t, validConfig(Config{Endpoints: []EndpointConfig{{}}}).validate())
+	assert.Error(t, validConfig(Config{Endpoints: []EndpointConfig{{Endpoint: "localhost:6379", Password: "a", PasswordEnv: "B"}}}).validate())
+	assert.Error(t, validConfig(Config{
+		Endpoints: []EndpointConfig{{Endpoint: "localhost:6379"}},
+		Cluster:   &ClusterConfig{Addresses: []string{"localhost:6379"}},
+	}).validate())
+	assert.Error(t, validConfig(Config{Cluster: &ClusterConfig{}}).validate())
+	assert.Error(t, validConfig(Config{
+		Cluster:  &ClusterConfig{Addresses: []string{"localhost:6379"}},
+		Sentinel: &SentinelConfig{Addresses: []string{"localhost:26379"}, MasterName: "mymaster"},
+	}).validate())
+	assert.Error(t, (&Config{Endpoint: "localhost:6379"}).validate(), "collection_interval is not set")
+	tls := &configtls.TLSClientSetting{}
+	iamAuth := &IAMAuthConfig{CacheName: "my-cache", Region: "us-east-1"}
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6379", Username: "monitoring", TLS: tls, IAMAuth: iamAuth}).validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6379", Username: "monitoring", TLS: tls, IAMAuth: &IAMAuthConfig{CacheName: "my-cluster", Region: "us-east-1", Service: "memorydb"}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", TLS: tls, IAMAuth: iamAuth}).validate(), "username is not set")
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Username: "monitoring", IAMAuth: iamAuth}).validate(), "tls is not set")
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Username: "monitoring", Password: "a", TLS: tls, IAMAuth: iamAuth}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Username: "monitoring", TLS: tls, IAMAuth: &IAMAuthConfig{Region: "us-east-1"}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Username: "monitoring", TLS: tls, IAMAuth: &IAMAuthConfig{CacheName: "my-cache"}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Username: "monitoring", TLS: tls, IAMAuth: &IAMAuthConfig{CacheName: "my-cache", Region: "us-east-1", Service: "s3"}}).validate())
+	secret := &EntraAuthConfig{TenantID: "contoso", ClientID: "app", ClientSecret: "secret"}
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", TLS: tls, EntraAuth: secret}).validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", TLS: tls, EntraAuth: &EntraAuthConfig{ManagedIdentity: true}}).validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", TLS: tls, EntraAuth: &EntraAuthConfig{TenantID: "contoso", ClientID: "app", FederatedTokenFile: "/var/run/token"}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6380", TLS: tls, EntraAuth: secret}).validate(), "username is not set")
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", EntraAuth: secret}).validate(), "tls is not set")
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", Password: "a", TLS: tls, EntraAuth: secret}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", TLS: tls, EntraAuth: secret, IAMAuth: iamAuth}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", TLS: tls, EntraAuth: &EntraAuthConfig{}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", TLS: tls, EntraAuth: &EntraAuthConfig{ClientSecret: "secret", ManagedIdentity: true}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", TLS: tls, EntraAuth: &EntraAuthConfig{ClientID: "app", ClientSecret: "secret"}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6380", Username: "principal", TLS: tls, EntraAuth: &EntraAuthConfig{TenantID: "contoso", ClientID: "app", ClientSecret: "secret", AuthorityHost: "login"}}).validate())
+	enterprise := &EnterpriseConfig{Endpoint: "https://cluster.example.com:9443", Username: "monitoring@example.com", Password: "secret"}
+	assert.NoError(t, validConfig(Config{Enterprise: enterprise}).validate())
+	assert.Error(t, validConfig(Config{Enterprise: &EnterpriseConfig{Username: "monitoring@example.com", Password: "secret"}}).validate())
+	assert.Error(t, validConfig(Config{Enterprise: &EnterpriseConfig{Endpoint: "cluster.example.com:9443", Username: "monitoring@example.com", Password: "secret"}}).validate())
+	assert.Error(t, validConfig(Config{Enterprise: &EnterpriseConfig{Endpoint: "https://cluster.example.com:9443", Username: "monitoring@example.com"}}).validate())
+	assert.Error(t, validConfig(Config{Enterprise: enterprise, Cluster: &ClusterConfig{Addresses: []string{"localhost:6379"}}}).validate())
+	negativeDelay := validConfig(Config{Endpoint: "localhost:6379"})
+	negativeDelay.InitialDelay = -time.Second
+	assert.Error(t, negativeDelay.validate())
+	assert.NoError(t, validConfig(Config{Metrics: map[string]MetricSettings{"redis/db/keys": {}}}).validate())
+	assert.Error(t, validConfig(Config{Metrics: map[string]MetricSettings{"redis/cpu/tim": {}}}).validate())
+	negativeTimeout := validConfig(Config{Endpoint: "localhost:6379"})
+	negativeTimeout.Timeout = -time.Second
+	assert.Error(t, negativeTimeout.validate())
+	noSection := validConfig(Config{Endpoint: "localhost:6379"})
+	noSection.Info.Section = ""
+	assert.Error(t, noSection.validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6379", ResourceAttributes: map[string]string{"team": "payments"}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", ResourceAttributes: map[string]string{"service.name": "my-redis"}}).validate())
+	assert.Error(t, validConfig(Config{Endpoints: []EndpointConfig{{Endpoint: "localhost:6379", ResourceAttributes: map[string]string{"service.name": "my-redis"}}}}).validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6379", Reconnect: ReconnectConfig{InitialInterval: time.Second, MaxInterval: time.Minute}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Reconnect: ReconnectConfig{InitialInterval: -time.Second}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", Reconnect: ReconnectConfig{InitialInterval: time.Minute, MaxInterval: time.Second}}).validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6379", KeyspaceNotifications: KeyspaceNotificationsConfig{Enabled: true, Patterns: []string{"session:*"}}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", KeyspaceNotifications: KeyspaceNotificationsConfig{Events: []string{""}}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", KeyspaceNotifications: KeyspaceNotificationsConfig{Patterns: []string{""}}}).validate())
+	assert.NoError(t, validConfig(Config{Endpoint: "localhost:6379", RenamedCommands: map[string]string{"config": "a8f2c1-config"}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", RenamedCommands: map[string]string{"config": ""}}).validate())
+	assert.Error(t, validConfig(Config{Endpoint: "localhost:6379", RenamedCommands: map[string]string{"config": "config get"}}).validate())
+	noMaxEvents := validConfig(Config{Endpoint: "localhost:6379"})
+	noMaxEvents.KeyspaceNotifications.MaxEvents = 0
+	assert.Error(t, noMaxEvents.validate())
+}
+
+func TestConfigServiceName(t *testing.T) {
+	assert.Equal(t, "my-redis", (&Config{Endpoint: "localhost:6379", ServiceName: "my-redis"}).serviceName())
+	assert.Equal(t, "redis://localhost:6379", (&Config{Endpoint: "localhost:6379"}).serviceName())
+	assert.Equal(t, "unix:///var/run/redis/redis.sock", (&Config{Endpoint: "unix:///var/run/redis/redis.sock"}).serviceName())
+	assert.Equal(t, "mymaster", (&Config{Sentinel: &SentinelConfig{MasterName: "mymaster"}}).serviceName())
+	assert.Equal(t, "redis://redis-1:6379", (&Config{Cluster: &ClusterConfig{Addresses: []string{"redis-1:6379", "redis-2:6379"}}}).serviceName())
+
+	cfg := &Config{ServiceName: "my-redis", ResourceAttributes: map[string]string{"team": "payments"}}
+	assert.Equal(t, map[string]string{"team": "payments", "service.name": "my-redis"}, cfg.resourceAttributes())
+}
+
+// validConfig returns cfg with the default collection interval and initial
+// delay.
+func validConfig(cfg Config) *Config {
+	defaults := createDefaultConfig().(*Config)
+	cfg.CollectionInterval = defaults.CollectionInterval
+	cfg.InitialDelay = defaults.InitialDelay
+	cfg.KeyPatterns.Count = defaults.KeyPatterns.Count
+	cfg.KeyPatterns.MaxCalls = defaults.KeyPatterns.MaxCalls
+	cfg.Info.Section = defaults.Info.Section
+	cfg.SlowLog = defaults.SlowLog
+	cfg.KeyspaceNotifications.MaxEvents = defaults.KeyspaceNotifications.MaxEvents
+	return &cfg
+}
+
+func TestPasswordSource(t *testing.T) {
+	source, err := (&Config{Password: "static"}).passwordSource()
+	require.NoError(t, err)
+	assert.Nil(t, source)
+
+	_, err = (&Config{Password: "static", PasswordEnv: "REDIS_PASSWORD"}).passwordSource()
+	assert.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, ioutil.WriteFile(file, []byte("first\n"), 0600))
+	source, err = (&Config{PasswordFile: file}).passwordSource()
+	require.NoError(t, err)
+	password, err := source()
+	require.NoError(t, err)
+	assert.Equal(t, "first", password)
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("second"), 0600))
+	password, err = source()
+	require.NoError(t, err)
+	assert.Equal(t, "second", password)
+
+	require.NoError(t, os.Remove(file))
+	_, err = source()
+	assert.Error(t, err)
+
+	const env = "REDISRECEIVER_TEST_PASSWORD"
+	source, err = (&Config{PasswordEnv: env}).passwordSource()
+	require.NoError(t, err)
+	_, err = source()
+	assert.Error(t, err)
+
+	os.Setenv(env, "from-env")
+	defer os.Unsetenv(env)
+	password, err = source()
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", password)
+}
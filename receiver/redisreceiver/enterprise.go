@@ -0,0 +1,295 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/internal/metadata"
+)
+
+// The names of the metrics of Redis Enterprise databases, shards and nodes.
+var (
+	enterpriseDatabaseMetricNames = []string{
+		"redis/enterprise/database/connections",
+		"redis/enterprise/database/evictions",
+		"redis/enterprise/database/expirations",
+		"redis/enterprise/database/keys",
+		"redis/enterprise/database/latency",
+		"redis/enterprise/database/memory/limit",
+		"redis/enterprise/database/memory/used",
+		"redis/enterprise/database/network/io",
+		"redis/enterprise/database/requests",
+		"redis/enterprise/database/shards",
+	}
+	enterpriseShardMetricNames = []string{
+		"redis/enterprise/shard/keys",
+		"redis/enterprise/shard/memory/used",
+		"redis/enterprise/shard/requests",
+	}
+	enterpriseNodeMetricNames = []string{
+		"redis/enterprise/node/connections",
+		"redis/enterprise/node/cpu/utilization",
+		"redis/enterprise/node/memory/available",
+		"redis/enterprise/node/memory/free",
+		"redis/enterprise/node/requests",
+		"redis/enterprise/node/storage/available",
+	}
+)
+
+// The uid of a database, shard or node, a number or a string depending on
+// the endpoint and version of the REST API.
+type enterpriseUID string
+
+func (u *enterpriseUID) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*u = enterpriseUID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*u = enterpriseUID(n)
+	return nil
+}
+
+// A database of the cluster, from /v1/bdbs.
+type enterpriseDatabase struct {
+	UID         enterpriseUID `json:"uid"`
+	Name        string        `json:"name"`
+	MemorySize  int64         `json:"memory_size"`
+	ShardsCount int64         `json:"shards_count"`
+}
+
+// A shard of a database, from /v1/shards.
+type enterpriseShard struct {
+	UID    enterpriseUID `json:"uid"`
+	BDBUID enterpriseUID `json:"bdb_uid"`
+	Role   string        `json:"role"`
+}
+
+// The last statistics of databases, shards or nodes, keyed by uid. Only the
+// numeric fields are kept.
+type enterpriseStats map[string]map[string]float64
+
+// Queries the REST API of a Redis Enterprise cluster with basic
+// authentication.
+type enterpriseClient struct {
+	endpoint string
+	username string
+	password string
+	client   *http.Client
+}
+
+func newEnterpriseClient(cfg *EnterpriseConfig, timeout time.Duration) (*enterpriseClient, error) {
+	tlsConfig, err := cfg.TLS.LoadTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load enterprise TLS config: %w", err)
+	}
+	return &enterpriseClient{
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+func (c *enterpriseClient) databases() ([]enterpriseDatabase, error) {
+	var dbs []enterpriseDatabase
+	err := c.get("/v1/bdbs", &dbs)
+	return dbs, err
+}
+
+func (c *enterpriseClient) shards() ([]enterpriseShard, error) {
+	var shards []enterpriseShard
+	err := c.get("/v1/shards", &shards)
+	return shards, err
+}
+
+// Returns the last statistics of the bdbs, shards or nodes.
+func (c *enterpriseClient) stats(kind string) (enterpriseStats, error) {
+	var res map[string]map[string]interface{}
+	if err := c.get("/v1/"+kind+"/stats/last", &res); err != nil {
+		return nil, err
+	}
+	stats := make(enterpriseStats, len(res))
+	for uid, fields := range res {
+		values := make(map[string]float64, len(fields))
+		for k, v := range fields {
+			if f, ok := v.(float64); ok {
+				values[k] = f
+			}
+		}
+		stats[uid] = values
+	}
+	return stats, nil
+}
+
+func (c *enterpriseClient) get(path string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("redis enterprise %s returned status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unexpected redis enterprise %s reply: %w", path, err)
+	}
+	return nil
+}
+
+func (c *enterpriseClient) close() {
+	c.client.CloseIdleConnections()
+}
+
+// A metric taken from a field of the statistics.
+type enterpriseStat struct {
+	field  string
+	name   string
+	labels map[string]string
+}
+
+// Appends the metrics of the fields present in stats, the int ones rounded.
+func appendEnterpriseStats(stats map[string]float64, metrics []enterpriseStat, labels map[string]string, t *timeBundle, ms pdata.MetricSlice) {
+	for _, s := range metrics {
+		v, ok := stats[s.field]
+		if !ok {
+			continue
+		}
+		m := &redisMetric{name: s.name, labels: labels}
+		if len(s.labels) > 0 {
+			m.labels = make(map[string]string, len(labels)+len(s.labels))
+			for k, l := range labels {
+				m.labels[k] = l
+			}
+			for k, l := range s.labels {
+				m.labels[k] = l
+			}
+		}
+		dest := ms.AppendEmpty()
+		metadata.M.ByName(s.name).Init(dest)
+		if dest.DataType() == pdata.MetricDataTypeIntGauge {
+			initIntMetric(m, int64(math.Round(v)), t, dest)
+		} else {
+			initDoubleMetric(m, v, t, dest)
+		}
+	}
+}
+
+var enterpriseDatabaseStats = []enterpriseStat{
+	{field: "conns", name: "redis/enterprise/database/connections"},
+	{field: "evicted_objects", name: "redis/enterprise/database/evictions"},
+	{field: "expired_objects", name: "redis/enterprise/database/expirations"},
+	{field: "no_of_keys", name: "redis/enterprise/database/keys"},
+	{field: "avg_latency", name: "redis/enterprise/database/latency"},
+	{field: "used_memory", name: "redis/enterprise/database/memory/used"},
+	{field: "ingress_bytes", name: "redis/enterprise/database/network/io", labels: map[string]string{"direction": "received"}},
+	{field: "egress_bytes", name: "redis/enterprise/database/network/io", labels: map[string]string{"direction": "transmitted"}},
+	{field: "total_req", name: "redis/enterprise/database/requests"},
+}
+
+var enterpriseShardStats = []enterpriseStat{
+	{field: "no_of_keys", name: "redis/enterprise/shard/keys"},
+	{field: "used_memory", name: "redis/enterprise/shard/memory/used"},
+	{field: "total_req", name: "redis/enterprise/shard/requests"},
+}
+
+var enterpriseNodeStats = []enterpriseStat{
+	{field: "conns", name: "redis/enterprise/node/connections"},
+	{field: "cpu_user", name: "redis/enterprise/node/cpu/utilization", labels: map[string]string{"state": "user"}},
+	{field: "cpu_system", name: "redis/enterprise/node/cpu/utilization", labels: map[string]string{"state": "system"}},
+	{field: "cpu_idle", name: "redis/enterprise/node/cpu/utilization", labels: map[string]string{"state": "idle"}},
+	{field: "available_memory", name: "redis/enterprise/node/memory/available"},
+	{field: "free_memory", name: "redis/enterprise/node/memory/free"},
+	{field: "total_req", name: "redis/enterprise/node/requests"},
+	{field: "ephemeral_storage_avail", name: "redis/enterprise/node/storage/available", labels: map[string]string{"volume": "ephemeral"}},
+	{field: "persistent_storage_avail", name: "redis/enterprise/node/storage/available", labels: map[string]string{"volume": "persistent"}},
+}
+
+// Builds the metrics of each database, labeled with its name, from its
+// settings and last statistics.
+func buildEnterpriseDatabaseMetrics(dbs []enterpriseDatabase, stats enterpriseStats, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	for _, db := range dbs {
+		labels := map[string]string{"database": db.Name}
+		initIntMetric(&redisMetric{name: "redis/enterprise/database/memory/limit", labels: labels}, db.MemorySize, t, ms.AppendEmpty())
+		initIntMetric(&redisMetric{name: "redis/enterprise/database/shards", labels: labels}, db.ShardsCount, t, ms.AppendEmpty())
+		appendEnterpriseStats(stats[string(db.UID)], enterpriseDatabaseStats, labels, t, ms)
+	}
+	return ms
+}
+
+// Builds the metrics of each shard, labeled with its uid, role and the name
+// of its database.
+func buildEnterpriseShardMetrics(shards []enterpriseShard, dbs []enterpriseDatabase, stats enterpriseStats, t *timeBundle) pdata.MetricSlice {
+	names := make(map[enterpriseUID]string, len(dbs))
+	for _, db := range dbs {
+		names[db.UID] = db.Name
+	}
+	ms := pdata.NewMetricSlice()
+	for _, shard := range shards {
+		role := shard.Role
+		if role == "slave" {
+			role = roleReplica
+		}
+		labels := map[string]string{
+			"database": names[shard.BDBUID],
+			"shard":    string(shard.UID),
+			"role":     role,
+		}
+		appendEnterpriseStats(stats[string(shard.UID)], enterpriseShardStats, labels, t, ms)
+	}
+	return ms
+}
+
+// Builds the metrics of each node, labeled with its uid.
+func buildEnterpriseNodeMetrics(stats enterpriseStats, t *timeBundle) pdata.MetricSlice {
+	uids := make([]string, 0, len(stats))
+	for uid := range stats {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	ms := pdata.NewMetricSlice()
+	for _, uid := range uids {
+		appendEnterpriseStats(stats[uid], enterpriseNodeStats, map[string]string{"node": uid}, t, ms)
+	}
+	return ms
+}
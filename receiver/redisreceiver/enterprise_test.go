@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+)
+
+// The replies of the REST API of a cluster with one database of two shards
+// on one node, as of Redis Enterprise 6.
+var enterpriseReplies = map[string]string{
+	"/v1/bdbs":              `[{"uid":1,"name":"sessions","memory_size":1073741824,"shards_count":1,"status":"active"}]`,
+	"/v1/bdbs/stats/last":   `{"1":{"interval":"1sec","stime":"2021-06-01T12:00:00Z","conns":12.0,"no_of_keys":2048.0,"used_memory":5242880.0,"avg_latency":0.00025,"total_req":150.5,"ingress_bytes":4096.0,"egress_bytes":8192.0,"evicted_objects":0.0,"expired_objects":1.5}}`,
+	"/v1/shards":            `[{"uid":"1","bdb_uid":1,"node_uid":"1","role":"master"},{"uid":"2","bdb_uid":1,"node_uid":"1","role":"slave"}]`,
+	"/v1/shards/stats/last": `{"1":{"no_of_keys":1024.0,"used_memory":2621440.0,"total_req":150.5},"2":{"no_of_keys":1024.0,"used_memory":2621440.0,"total_req":0.0}}`,
+	"/v1/nodes/stats/last":  `{"1":{"conns":12.0,"cpu_user":0.02,"cpu_system":0.01,"cpu_idle":0.97,"free_memory":3221225472.0,"available_memory":2147483648.0,"total_req":150.5,"ephemeral_storage_avail":10737418240.0,"persistent_storage_avail":21474836480.0}}`,
+}
+
+// Serves the replies to the requests with the credentials of the monitoring
+// user, failing those to the paths in failing.
+func newEnterpriseServer(t *testing.T, failing ...string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "monitoring@example.com" || password != "secret" {
+			http.Error(w, `{"error_code":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		for _, path := range failing {
+			if r.URL.Path == path {
+				http.Error(w, `{"error_code":"internal"}`, http.StatusInternalServerError)
+				return
+			}
+		}
+		reply, ok := enterpriseReplies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newEnterpriseTestScraper(t *testing.T, endpoint, password string) *redisScraper {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Enterprise = &EnterpriseConfig{Endpoint: endpoint, Username: "monitoring@example.com", Password: password}
+	scraper := newRedisScraper(cfg, zap.NewNop())
+	require.NoError(t, scraper.start(context.Background(), nil))
+	t.Cleanup(func() { require.NoError(t, scraper.shutdown(context.Background())) })
+	return scraper
+}
+
+// Returns the data points of the metrics, keyed by name, as their labels and
+// values.
+func enterpriseDataPoints(ms pdata.MetricSlice) map[string][]map[string]interface{} {
+	points := map[string][]map[string]interface{}{}
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		point := map[string]interface{}{}
+		switch m.DataType() {
+		case pdata.MetricDataTypeIntGauge:
+			dp := m.IntGauge().DataPoints().At(0)
+			dp.LabelsMap().Range(func(k, v string) bool {
+				point[k] = v
+				return true
+			})
+			point["value"] = dp.Value()
+		case pdata.MetricDataTypeDoubleGauge:
+			dp := m.DoubleGauge().DataPoints().At(0)
+			dp.LabelsMap().Range(func(k, v string) bool {
+				point[k] = v
+				return true
+			})
+			point["value"] = dp.Value()
+		}
+		points[m.Name()] = append(points[m.Name()], point)
+	}
+	return points
+}
+
+func TestEnterpriseScraper(t *testing.T) {
+	server := newEnterpriseServer(t)
+	scraper := newEnterpriseTestScraper(t, server.URL+"/", "secret")
+
+	rms := scrapeMetrics(t, scraper)
+	require.Equal(t, 1, rms.Len())
+	serviceName, _ := rms.At(0).Resource().Attributes().Get(serviceNameAttribute)
+	assert.Equal(t, server.URL+"/", serviceName.StringVal())
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	// 11 database, 6 shard and 9 node metrics.
+	assert.Equal(t, 26, ms.Len())
+
+	points := enterpriseDataPoints(ms)
+	assert.Equal(t, []map[string]interface{}{{"database": "sessions", "value": int64(1073741824)}}, points["redis/enterprise/database/memory/limit"])
+	assert.Equal(t, []map[string]interface{}{{"database": "sessions", "value": int64(2048)}}, points["redis/enterprise/database/keys"])
+	assert.Equal(t, []map[string]interface{}{{"database": "sessions", "value": 0.00025}}, points["redis/enterprise/database/latency"])
+	assert.Equal(t, []map[string]interface{}{
+		{"database": "sessions", "direction": "received", "value": 4096.0},
+		{"database": "sessions", "direction": "transmitted", "value": 8192.0},
+	}, points["redis/enterprise/database/network/io"])
+	assert.Equal(t, []map[string]interface{}{
+		{"database": "sessions", "shard": "1", "role": "master", "value": 150.5},
+		{"database": "sessions", "shard": "2", "role": "replica", "value": 0.0},
+	}, points["redis/enterprise/shard/requests"])
+	assert.Equal(t, []map[string]interface{}{
+		{"node": "1", "state": "user", "value": 0.02},
+		{"node": "1", "state": "system", "value": 0.01},
+		{"node": "1", "state": "idle", "value": 0.97},
+	}, points["redis/enterprise/node/cpu/utilization"])
+	assert.Equal(t, []map[string]interface{}{
+		{"node": "1", "volume": "ephemeral", "value": int64(10737418240)},
+		{"node": "1", "volume": "persistent", "value": int64(21474836480)},
+	}, points["redis/enterprise/node/storage/available"])
+}
+
+func TestEnterpriseScraperMetricSettings(t *testing.T) {
+	server := newEnterpriseServer(t, "/v1/shards", "/v1/shards/stats/last")
+	scraper := newEnterpriseTestScraper(t, server.URL, "secret")
+	scraper.metricSettings = map[string]MetricSettings{}
+	for _, name := range enterpriseShardMetricNames {
+		scraper.metricSettings[name] = MetricSettings{Enabled: false}
+	}
+	scraper.metricSettings["redis/enterprise/node/requests"] = MetricSettings{Enabled: false}
+
+	// The shards are not queried.
+	ms := scrapeMetrics(t, scraper).At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	assert.Equal(t, 11+8, ms.Len())
+	assert.NotContains(t, enterpriseDataPoints(ms), "redis/enterprise/node/requests")
+}
+
+func TestEnterpriseScraperPartialFailure(t *testing.T) {
+	server := newEnterpriseServer(t, "/v1/nodes/stats/last")
+	scraper := newEnterpriseTestScraper(t, server.URL, "secret")
+
+	rms, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "status 500")
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	assert.Equal(t, 11+6, ms.Len())
+}
+
+func TestEnterpriseScraperUnauthorized(t *testing.T) {
+	server := newEnterpriseServer(t)
+	scraper := newEnterpriseTestScraper(t, server.URL, "wrong")
+
+	rms, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.False(t, scrapererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "status 401")
+	assert.Equal(t, 0, rms.Len())
+}
+
+func TestEnterpriseUID(t *testing.T) {
+	var shards []enterpriseShard
+	require.NoError(t, json.Unmarshal([]byte(`[{"uid":"3","bdb_uid":2},{"uid":4,"bdb_uid":"2"}]`), &shards))
+	assert.Equal(t, []enterpriseShard{{UID: "3", BDBUID: "2"}, {UID: "4", BDBUID: "2"}}, shards)
+	assert.Error(t, json.Unmarshal([]byte(`[{"uid":{}}]`), &shards))
+}
@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// The scope, or resource, of the tokens of Azure Cache for Redis.
+	entraRedisResource = "https://redis.azure.com"
+	// The endpoint of the managed identities of Azure hosts.
+	entraIMDSEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+	// A new token is requested once the previous one expires in less than
+	// this, well before Azure Cache for Redis closes the connections
+	// authenticated with it.
+	entraTokenRefreshMargin = 10 * time.Minute
+	// Bounds each token request.
+	entraTokenTimeout = 30 * time.Second
+)
+
+// Requests the Microsoft Entra ID access tokens of a service principal or
+// managed identity for Azure Cache for Redis, which are used as its password.
+type entraTokenSource struct {
+	source oauth2.TokenSource
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func newEntraTokenSource(cfg *EntraAuthConfig) *entraTokenSource {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: entraTokenTimeout})
+	if cfg.ManagedIdentity {
+		return &entraTokenSource{source: &imdsTokenSource{
+			endpoint: entraIMDSEndpoint,
+			clientID: cfg.ClientID,
+			client:   &http.Client{Timeout: entraTokenTimeout},
+		}}
+	}
+	credentials := &clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     strings.TrimSuffix(cfg.authorityHost(), "/") + "/" + url.PathEscape(cfg.TenantID) + "/oauth2/v2.0/token",
+		Scopes:       []string{entraRedisResource + "/.default"},
+		AuthStyle:    oauth2.AuthStyleInParams,
+	}
+	if cfg.FederatedTokenFile == "" {
+		return &entraTokenSource{source: credentials.TokenSource(ctx)}
+	}
+	// Workload identity: the token of the Kubernetes service account, read
+	// again for each request since it is rotated, is the client assertion.
+	return &entraTokenSource{source: &assertionTokenSource{
+		ctx:         ctx,
+		credentials: credentials,
+		file:        cfg.FederatedTokenFile,
+	}}
+}
+
+// Returns the current token, requesting a new one if it expires within
+// entraTokenRefreshMargin. Implements a password source.
+func (s *entraTokenSource) password() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && time.Until(s.token.Expiry) > entraTokenRefreshMargin {
+		return s.token.AccessToken, nil
+	}
+	token, err := s.source.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get Microsoft Entra ID token: %w", err)
+	}
+	s.token = token
+	return token.AccessToken, nil
+}
+
+// Requests tokens with a client assertion read from a file.
+type assertionTokenSource struct {
+	ctx         context.Context
+	credentials *clientcredentials.Config
+	file        string
+}
+
+func (s *assertionTokenSource) Token() (*oauth2.Token, error) {
+	assertion, err := ioutil.ReadFile(filepath.Clean(s.file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read federated token file: %w", err)
+	}
+	credentials := *s.credentials
+	credentials.EndpointParams = url.Values{
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+	return credentials.Token(s.ctx)
+}
+
+// Requests the tokens of the managed identity of the host from the instance
+// metadata service.
+type imdsTokenSource struct {
+	endpoint string
+	// Selects a user-assigned identity, the system-assigned one if empty.
+	clientID string
+	client   *http.Client
+}
+
+func (s *imdsTokenSource) Token() (*oauth2.Token, error) {
+	query := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {entraRedisResource},
+	}
+	if s.clientID != "" {
+		query.Set("client_id", s.clientID)
+	}
+	req, err := http.NewRequest(http.MethodGet, s.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("managed identity token request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	// The expiry is a string of the number of seconds since the epoch.
+	var reply struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+	}
+	if err = json.Unmarshal(body, &reply); err != nil {
+		return nil, fmt.Errorf("unexpected managed identity token reply: %w", err)
+	}
+	if reply.AccessToken == "" {
+		return nil, errors.New("managed identity token reply has no access token")
+	}
+	expiresOn, err := strconv.ParseInt(reply.ExpiresOn, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected managed identity token expiry %q", reply.ExpiresOn)
+	}
+	return &oauth2.Token{AccessToken: reply.AccessToken, Expiry: time.Unix(expiresOn, 0)}, nil
+}
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// Serves the token endpoint of the contoso tenant, recording the forms of
+// the requests.
+func newEntraTokenServer(t *testing.T, forms *[]map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/contoso/oauth2/v2.0/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		form := map[string]string{}
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		*forms = append(*forms, form)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"token_type":"Bearer","expires_in":3599,"access_token":"token-%d"}`, len(*forms))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEntraTokenClientSecret(t *testing.T) {
+	var forms []map[string]string
+	server := newEntraTokenServer(t, &forms)
+	s := newEntraTokenSource(&EntraAuthConfig{TenantID: "contoso", ClientID: "app", ClientSecret: "secret", AuthorityHost: server.URL})
+
+	token, err := s.password()
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+	require.Len(t, forms, 1)
+	assert.Equal(t, map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     "app",
+		"client_secret": "secret",
+		"scope":         "https://redis.azure.com/.default",
+	}, forms[0])
+}
+
+func TestEntraTokenFederated(t *testing.T) {
+	var forms []map[string]string
+	server := newEntraTokenServer(t, &forms)
+	file := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, ioutil.WriteFile(file, []byte("assertion-1\n"), 0600))
+	s := newEntraTokenSource(&EntraAuthConfig{TenantID: "contoso", ClientID: "app", FederatedTokenFile: file, AuthorityHost: server.URL})
+
+	_, err := s.password()
+	require.NoError(t, err)
+	require.Len(t, forms, 1)
+	assert.Equal(t, "assertion-1", forms[0]["client_assertion"])
+	assert.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", forms[0]["client_assertion_type"])
+	assert.NotContains(t, forms[0], "client_secret")
+
+	// The rotated assertion is read for the next token.
+	require.NoError(t, ioutil.WriteFile(file, []byte("assertion-2\n"), 0600))
+	s.token = nil
+	_, err = s.password()
+	require.NoError(t, err)
+	require.Len(t, forms, 2)
+	assert.Equal(t, "assertion-2", forms[1]["client_assertion"])
+}
+
+func TestEntraTokenManagedIdentity(t *testing.T) {
+	expiresOn := time.Now().Add(time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.Header.Get("Metadata"))
+		assert.Equal(t, "2018-02-01", r.URL.Query().Get("api-version"))
+		assert.Equal(t, "https://redis.azure.com", r.URL.Query().Get("resource"))
+		if r.URL.Query().Get("client_id") != "identity" {
+			http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"token","expires_on":"%d","resource":"https://redis.azure.com","token_type":"Bearer"}`, expiresOn)
+	}))
+	defer server.Close()
+
+	s := &imdsTokenSource{endpoint: server.URL, clientID: "identity", client: server.Client()}
+	token, err := s.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+	assert.Equal(t, time.Unix(expiresOn, 0), token.Expiry)
+
+	s.clientID = ""
+	_, err = s.Token()
+	assert.Error(t, err)
+}
+
+type countingTokenSource struct {
+	tokens int
+	expiry time.Time
+}
+
+func (s *countingTokenSource) Token() (*oauth2.Token, error) {
+	s.tokens++
+	return &oauth2.Token{AccessToken: strconv.Itoa(s.tokens), Expiry: s.expiry}, nil
+}
+
+func TestEntraTokenRefresh(t *testing.T) {
+	source := &countingTokenSource{expiry: time.Now().Add(time.Hour)}
+	s := &entraTokenSource{source: source}
+
+	token, err := s.password()
+	require.NoError(t, err)
+	assert.Equal(t, "1", token)
+	token, err = s.password()
+	require.NoError(t, err)
+	assert.Equal(t, "1", token, "the token is reused")
+
+	s.token.Expiry = time.Now().Add(entraTokenRefreshMargin - time.Minute)
+	token, err = s.password()
+	require.NoError(t, err)
+	assert.Equal(t, "2", token, "a new token is requested before the previous one expires")
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The prefix of the Errorstats keys of the INFO command, followed by the error
+// prefix, e.g. "errorstat_WRONGTYPE".
+const errorStatsPrefix = "errorstat_"
+
+// Turns an errorstats value (the part after the colon e.g. "count=3") into the
+// number of errors.
+func parseErrorStatsString(str string) (int64, error) {
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.Split(pairStr, "=")
+		if len(pair) == 2 && pair[0] == "count" {
+			return strconv.ParseInt(pair[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("unexpected errorstats value '%s'", str)
+}
+
+// Returns the error prefixes with stats in INFO, sorted so that metrics are
+// built in a stable order.
+func (i info) errorStatsPrefixes() []string {
+	var prefixes []string
+	for key := range i {
+		if strings.HasPrefix(key, errorStatsPrefix) {
+			prefixes = append(prefixes, strings.TrimPrefix(key, errorStatsPrefix))
+		}
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestParseErrorStats(t *testing.T) {
+	count, err := parseErrorStatsString("count=3")
+	require.Nil(t, err)
+	require.EqualValues(t, 3, count)
+
+	for _, str := range []string{"", "count=", "count", "calls=3"} {
+		_, err = parseErrorStatsString(str)
+		require.NotNil(t, err, str)
+	}
+}
+
+func TestBuildErrorStatsMetrics(t *testing.T) {
+	inf := info{
+		"errorstat_WRONGTYPE": "count=3",
+		"errorstat_ERR":       "count=5",
+		"errorstat_OOM":       "count=x",
+	}
+	ms, warnings := inf.buildErrorStatsMetrics(newTimeBundle(time.Now(), 100))
+	assert.Len(t, warnings, 1)
+	require.Equal(t, 2, ms.Len())
+
+	for i, want := range []struct {
+		prefix string
+		count  int64
+	}{{"ERR", 5}, {"WRONGTYPE", 3}} {
+		m := ms.At(i)
+		assert.Equal(t, "redis/errors", m.Name())
+		require.Equal(t, pdata.MetricDataTypeIntSum, m.DataType())
+		assert.True(t, m.IntSum().IsMonotonic())
+		pt := m.IntSum().DataPoints().At(0)
+		prefix, _ := pt.LabelsMap().Get("prefix")
+		assert.Equal(t, want.prefix, prefix)
+		assert.Equal(t, want.count, pt.Value())
+	}
+}
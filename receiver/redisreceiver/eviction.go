@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The names of the metrics of memory pressure and evictions.
+var evictionMetricNames = []string{"redis/memory/used_ratio", "redis/keys/evicted_rate"}
+
+// The name of the rate of key expirations.
+const expiredRateMetricName = "redis/keys/expired_rate"
+
+// Builds the ratio of used memory to maxmemory, if set, and the rate of
+// evictions since the previous scrape of the node, both labeled with the
+// eviction policy. The rate is left out on the first scrape and after a
+// restart. Returns metrics and parsing errors, to be treated as warnings, if
+// there were any.
+func (n *redisNode) buildEvictionMetrics(i info) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+	labels := map[string]string{"policy": i["maxmemory_policy"]}
+
+	maxMemory, err := strconv.ParseInt(i["maxmemory"], 10, 64)
+	if err != nil {
+		warnings = append(warnings, err)
+	}
+	usedMemory, err := strconv.ParseInt(i["used_memory"], 10, 64)
+	if err != nil {
+		warnings = append(warnings, err)
+	}
+	if warnings == nil && maxMemory > 0 {
+		initDoubleMetric(&redisMetric{
+			name:   evictionMetricNames[0],
+			labels: labels,
+		}, float64(usedMemory)/float64(maxMemory), n.timeBundle, outMS.AppendEmpty())
+	}
+
+	evicted, err := strconv.ParseInt(i["evicted_keys"], 10, 64)
+	if err != nil {
+		warnings = append(warnings, err)
+		return outMS, warnings
+	}
+	if rate, ok := n.counterRate("evicted_keys", evicted, n.timeBundle.current); ok {
+		initDoubleMetric(&redisMetric{
+			name:   evictionMetricNames[1],
+			labels: labels,
+		}, rate, n.timeBundle, outMS.AppendEmpty())
+	}
+	return outMS, warnings
+}
+
+// Builds the rate of key expirations since the previous scrape of the node,
+// left out on the first scrape and after a restart. A spike shows keys set
+// with the same TTL expiring at once.
+func (n *redisNode) buildExpiredRateMetric(i info) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+	expired, err := strconv.ParseInt(i["expired_keys"], 10, 64)
+	if err != nil {
+		return outMS, []error{err}
+	}
+	if rate, ok := n.counterRate("expired_keys", expired, n.timeBundle.current); ok {
+		initDoubleMetric(&redisMetric{
+			name: expiredRateMetricName,
+		}, rate, n.timeBundle, outMS.AppendEmpty())
+	}
+	return outMS, nil
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildEvictionMetrics(t *testing.T) {
+	node := newRedisNode(newFakeClient(), nil)
+	node.timeBundle = newTimeBundle(time.Unix(1000, 0), 100)
+	inf := info{
+		"used_memory":      "750",
+		"maxmemory":        "1000",
+		"maxmemory_policy": "allkeys-lru",
+		"evicted_keys":     "10",
+	}
+
+	// The first scrape has no rate.
+	ms, warnings := node.buildEvictionMetrics(inf)
+	require.Nil(t, warnings)
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "redis/memory/used_ratio", ms.At(0).Name())
+	pt := ms.At(0).DoubleGauge().DataPoints().At(0)
+	assert.Equal(t, 0.75, pt.Value())
+	policy, _ := pt.LabelsMap().Get("policy")
+	assert.Equal(t, "allkeys-lru", policy)
+
+	node.timeBundle.update(time.Unix(1010, 0), 110)
+	inf["evicted_keys"] = "60"
+	ms, warnings = node.buildEvictionMetrics(inf)
+	require.Nil(t, warnings)
+	require.Equal(t, 2, ms.Len())
+	assert.Equal(t, "redis/keys/evicted_rate", ms.At(1).Name())
+	assert.Equal(t, 5.0, ms.At(1).DoubleGauge().DataPoints().At(0).Value())
+
+	// The counter is reset by a restart.
+	node.timeBundle.update(time.Unix(1020, 0), 5)
+	inf["evicted_keys"] = "1"
+	ms, _ = node.buildEvictionMetrics(inf)
+	assert.Equal(t, 1, ms.Len())
+}
+
+func TestBuildEvictionMetricsUnlimited(t *testing.T) {
+	node := newRedisNode(newFakeClient(), nil)
+	node.timeBundle = testTimeBundle()
+	ms, warnings := node.buildEvictionMetrics(info{"used_memory": "750", "maxmemory": "0", "evicted_keys": "0"})
+	require.Nil(t, warnings)
+	assert.Equal(t, 0, ms.Len())
+
+	_, warnings = node.buildEvictionMetrics(info{"used_memory": "750", "maxmemory": "x", "evicted_keys": "0"})
+	assert.Len(t, warnings, 1)
+}
+
+func TestBuildExpiredRateMetric(t *testing.T) {
+	node := newRedisNode(newFakeClient(), nil)
+	node.timeBundle = newTimeBundle(time.Unix(1000, 0), 100)
+	ms, warnings := node.buildExpiredRateMetric(info{"expired_keys": "100"})
+	require.Nil(t, warnings)
+	assert.Equal(t, 0, ms.Len())
+
+	node.timeBundle.update(time.Unix(1004, 0), 104)
+	ms, warnings = node.buildExpiredRateMetric(info{"expired_keys": "300"})
+	require.Nil(t, warnings)
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "redis/keys/expired_rate", ms.At(0).Name())
+	assert.Equal(t, 50.0, ms.At(0).DoubleGauge().DataPoints().At(0).Value())
+
+	_, warnings = node.buildExpiredRateMetric(info{})
+	assert.Len(t, warnings, 1)
+}
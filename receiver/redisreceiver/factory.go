@@ -14,6 +14,8 @@
 
 package redisreceiver
 
+//go:generate mdatagen metadata.yaml
+
 import (
 	"context"
 	"time"
@@ -22,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
 )
 
 const (
@@ -33,13 +36,35 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() config.Receiver {
 	return &Config{
-		ReceiverSettings:   config.NewReceiverSettings(config.NewID(typeStr)),
-		CollectionInterval: 10 * time.Second,
+		ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+			ReceiverSettings:   config.NewReceiverSettings(config.NewID(typeStr)),
+			CollectionInterval: 10 * time.Second,
+		},
+		InitialDelay: time.Second,
+		Timeout:      5 * time.Second,
+		KeyPatterns: KeyPatternsConfig{
+			Count:    100,
+			MaxCalls: 100,
+		},
+		Info: InfoConfig{
+			Section: "everything",
+		},
+		SlowLog: SlowLogConfig{
+			MaxEntries: 128,
+		},
+		KeyspaceNotifications: KeyspaceNotificationsConfig{
+			MaxEvents: 1000,
+		},
+		Reconnect: ReconnectConfig{
+			InitialInterval: 10 * time.Second,
+			MaxInterval:     5 * time.Minute,
+		},
 	}
 }
 
@@ -50,6 +75,33 @@ func createMetricsReceiver(
 	consumer consumer.Metrics,
 ) (component.MetricsReceiver, error) {
 	oCfg := cfg.(*Config)
+	if err := oCfg.validate(); err != nil {
+		return nil, err
+	}
+
+	scraper := newRedisScraper(oCfg, params.Logger)
+	return scraperhelper.NewScraperControllerReceiver(
+		&oCfg.ScraperControllerSettings, params.Logger, consumer,
+		scraperhelper.AddResourceMetricsScraper(scraperhelper.NewResourceMetricsScraper(
+			oCfg.ID(),
+			scraper.scrape,
+			scraperhelper.WithStart(scraper.start),
+			scraperhelper.WithShutdown(scraper.shutdown),
+		)),
+		scraperhelper.WithTickerChannel(scraper.ticks),
+	)
+}
+
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	oCfg := cfg.(*Config)
+	if err := oCfg.validate(); err != nil {
+		return nil, err
+	}
 
-	return newRedisReceiver(params.Logger, oCfg, consumer), nil
+	return newRedisLogsReceiver(params.Logger, oCfg, consumer), nil
 }
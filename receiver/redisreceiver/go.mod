@@ -3,6 +3,7 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisr
 go 1.15
 
 require (
+	github.com/aws/aws-sdk-go v1.38.45
 	github.com/go-redis/redis/v7 v7.4.0
 	github.com/onsi/ginkgo v1.14.1 // indirect
 	github.com/onsi/gomega v1.10.2 // indirect
@@ -11,6 +12,7 @@ require (
 	github.com/stretchr/testify v1.7.0
 	go.opentelemetry.io/collector v0.27.1-0.20210526183029-df76aa36cd12
 	go.uber.org/zap v1.16.0
+	golang.org/x/oauth2 v0.0.0-20210323180902-22b0adad7558
 	gopkg.in/ini.v1 v1.57.0 // indirect
 )
 
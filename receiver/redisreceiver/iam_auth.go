@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+const (
+	// IAM authentication tokens are valid for 15 minutes.
+	iamAuthTokenTTL = 15 * time.Minute
+	// A new token is generated once the previous one is older than this, so
+	// that connections are never opened with a token about to expire.
+	iamAuthTokenRefresh = 10 * time.Minute
+)
+
+// Generates the IAM authentication tokens of an ElastiCache or MemoryDB user,
+// which are used as its password. A token is a SigV4 presigned request to
+// connect to the cache, without its scheme.
+type iamAuthTokenSource struct {
+	userID     string
+	cacheName  string
+	service    string
+	region     string
+	serverless bool
+	signer     *v4.Signer
+
+	mu        sync.Mutex
+	token     string
+	generated time.Time
+}
+
+func newIAMAuthTokenSource(userID string, cfg *IAMAuthConfig) (*iamAuthTokenSource, error) {
+	creds, err := iamAuthCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &iamAuthTokenSource{
+		userID:     userID,
+		cacheName:  strings.ToLower(cfg.CacheName),
+		service:    cfg.service(),
+		region:     cfg.Region,
+		serverless: cfg.Serverless,
+		signer:     v4.NewSigner(creds),
+	}, nil
+}
+
+// Returns the credentials of the default chain, e.g. of the environment or of
+// the instance profile, or of the role they assume if RoleARN is set.
+func iamAuthCredentials(cfg *IAMAuthConfig) (*credentials.Credentials, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config: aws.Config{Region: aws.String(cfg.Region)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RoleARN != "" {
+		return stscreds.NewCredentials(sess, cfg.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = "otel-collector-redis-" + strconv.FormatInt(time.Now().Unix(), 10)
+		}), nil
+	}
+	if sess.Config.Credentials == nil {
+		return nil, errors.New("no AWS credentials exist")
+	}
+	return sess.Config.Credentials, nil
+}
+
+// Returns the current token, generating a new one if it is older than
+// iamAuthTokenRefresh. Implements a password source.
+func (s *iamAuthTokenSource) password() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.token != "" && now.Sub(s.generated) < iamAuthTokenRefresh {
+		return s.token, nil
+	}
+	token, err := s.generate(now)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.generated = token, now
+	return token, nil
+}
+
+// Presigns the request to connect to the cache as the user, valid for
+// iamAuthTokenTTL from now.
+func (s *iamAuthTokenSource) generate(now time.Time) (string, error) {
+	query := url.Values{
+		"Action": {"connect"},
+		"User":   {s.userID},
+	}
+	if s.serverless {
+		query.Set("ResourceType", "ServerlessCache")
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://"+s.cacheName+"/?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err = s.signer.Presign(req, nil, s.service, s.region, iamAuthTokenTTL, now); err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(req.URL.String(), "http://"), nil
+}
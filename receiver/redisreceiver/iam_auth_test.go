@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIAMAuthTokenSource(serverless bool) *iamAuthTokenSource {
+	return &iamAuthTokenSource{
+		userID:     "monitoring",
+		cacheName:  "my-cache",
+		service:    "elasticache",
+		region:     "us-east-1",
+		serverless: serverless,
+		signer:     v4.NewSigner(credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", "")),
+	}
+}
+
+func TestIAMAuthToken(t *testing.T) {
+	s := newTestIAMAuthTokenSource(false)
+	token, err := s.generate(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, "my-cache/?"), token)
+
+	query, err := url.ParseQuery(strings.TrimPrefix(token, "my-cache/?"))
+	require.NoError(t, err)
+	assert.Equal(t, "connect", query.Get("Action"))
+	assert.Equal(t, "monitoring", query.Get("User"))
+	assert.Equal(t, "AWS4-HMAC-SHA256", query.Get("X-Amz-Algorithm"))
+	assert.Equal(t, "AKIDEXAMPLE/20210601/us-east-1/elasticache/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20210601T120000Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "900", query.Get("X-Amz-Expires"))
+	assert.NotEmpty(t, query.Get("X-Amz-Signature"))
+	assert.Empty(t, query.Get("ResourceType"))
+
+	token, err = newTestIAMAuthTokenSource(true).generate(time.Now())
+	require.NoError(t, err)
+	query, err = url.ParseQuery(strings.TrimPrefix(token, "my-cache/?"))
+	require.NoError(t, err)
+	assert.Equal(t, "ServerlessCache", query.Get("ResourceType"))
+}
+
+func TestIAMAuthTokenRefresh(t *testing.T) {
+	s := newTestIAMAuthTokenSource(false)
+	token, err := s.password()
+	require.NoError(t, err)
+	generated := s.generated
+
+	again, err := s.password()
+	require.NoError(t, err)
+	assert.Equal(t, token, again)
+	assert.Equal(t, generated, s.generated, "the token is reused")
+
+	s.generated = generated.Add(-iamAuthTokenRefresh)
+	_, err = s.password()
+	require.NoError(t, err)
+	assert.True(t, s.generated.After(generated.Add(-iamAuthTokenRefresh)), "a new token is generated before the previous one expires")
+}
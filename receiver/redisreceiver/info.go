@@ -18,10 +18,14 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
+// The number of databases of a Redis server with the default configuration.
+const redisMaxDbs = 16
+
 // A map of the INFO data returned from Redis.
 type info map[string]string
 
@@ -50,17 +54,33 @@ func (i info) buildFixedMetrics(metrics []*redisMetric, t *timeBundle) (pdms pda
 	return pdms, warnings
 }
 
+// Like buildFixedMetrics, but leaves out the metrics whose key is missing, e.g.
+// those only reported by some Redis versions.
+func (i info) buildPresentMetrics(metrics []*redisMetric, t *timeBundle) (pdms pdata.MetricSlice, warnings []error) {
+	present := make([]*redisMetric, 0, len(metrics))
+	for _, m := range metrics {
+		if _, ok := i[m.key]; ok {
+			present = append(present, m)
+		}
+	}
+	return i.buildFixedMetrics(present, t)
+}
+
 // Builds metrics from any 'keyspace' metrics in Redis INFO:
-// e.g. "db0:keys=1,expires=2, avg_ttl=3". Returns metrics and parsing
-// errors, to be treated as warnings, if there were any.
-func (i info) buildKeyspaceMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+// e.g. "db0:keys=1,expires=2, avg_ttl=3", of the databases include
+// accepts, all if nil. Returns metrics and parsing errors, to be treated as
+// warnings, if there were any.
+func (i info) buildKeyspaceMetrics(t *timeBundle, include func(db int) bool) (outMS pdata.MetricSlice, warnings []error) {
 	outMS = pdata.NewMetricSlice()
-	const RedisMaxDbs = 16
-	for db := 0; db < RedisMaxDbs; db++ {
+	for db := 0; db < redisMaxDbs; db++ {
+		if include != nil && !include(db) {
+			continue
+		}
 		key := "db" + strconv.Itoa(db)
 		str, ok := i[key]
 		if !ok {
-			break
+			// Empty databases are left out.
+			continue
 		}
 		keyspace, parsingError := parseKeyspaceString(db, str)
 		if parsingError != nil {
@@ -73,6 +93,66 @@ func (i info) buildKeyspaceMetrics(t *timeBundle) (outMS pdata.MetricSlice, warn
 	return outMS, warnings
 }
 
+// Builds metrics from any 'latencystats' metrics in Redis INFO (Redis 7):
+// e.g. "latency_percentiles_usec_get:p50=1.003,p99=2.007,p99.9=3.007", one
+// gauge per command and percentile. Returns metrics and parsing errors, to be
+// treated as warnings, if there were any.
+func (i info) buildLatencyStatsMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+	for _, cmd := range i.latencyStatsCommands() {
+		ls, parsingError := parseLatencyStatsString(cmd, i[latencyStatsPrefix+cmd])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		ms := buildLatencyStatsMetrics(ls, t)
+		ms.MoveAndAppendTo(outMS)
+	}
+	return outMS, warnings
+}
+
+// Builds metrics from any 'errorstats' metrics in Redis INFO: e.g.
+// "errorstat_WRONGTYPE:count=3", one cumulative count per error prefix.
+// Returns metrics and parsing errors, to be treated as warnings, if there
+// were any.
+func (i info) buildErrorStatsMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+	for _, prefix := range i.errorStatsPrefixes() {
+		count, parsingError := parseErrorStatsString(i[errorStatsPrefix+prefix])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		initErrorStatsMetric(prefix, count, t, outMS.AppendEmpty())
+	}
+	return outMS, warnings
+}
+
+// Parses the key:value lines of a reply like INFO, with CRLF or LF line
+// endings, e.g. CLUSTER INFO.
+func parseInfoLines(str string) info {
+	inf := info{}
+	for _, line := range strings.Split(str, "\n") {
+		pair := strings.Split(strings.TrimSuffix(line, "\r"), ":")
+		if len(pair) == 2 {
+			inf[pair[0]] = pair[1]
+		}
+	}
+	return inf
+}
+
+// Returns the databases that are not in the keyspace section of Redis INFO,
+// among dbs.
+func (i info) missingKeyspaceDbs(dbs []int) []int {
+	var missing []int
+	for _, db := range dbs {
+		if _, ok := i["db"+strconv.Itoa(db)]; !ok {
+			missing = append(missing, db)
+		}
+	}
+	return missing
+}
+
 func (i info) getUptimeInSeconds() (int, error) {
 	const uptimeKey = "uptime_in_seconds"
 	uptimeStr, ok := i[uptimeKey]
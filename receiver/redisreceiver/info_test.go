@@ -27,3 +27,10 @@ func TestGetUptime(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, 104946, uptime)
 }
+
+func TestMissingKeyspaceDbs(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, _ := svc.info()
+	require.Equal(t, []int{2, 5}, info.missingKeyspaceDbs([]int{0, 1, 2, 5}))
+	require.Nil(t, info.missingKeyspaceDbs([]int{0, 1}))
+}
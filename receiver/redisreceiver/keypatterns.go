@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The names of the metrics built from the key pattern counts.
+var keyPatternMetricNames = []string{"redis/keys/matching", "redis/keys/matching_complete"}
+
+// The number of keys matching a pattern found with SCAN, and whether the whole
+// keyspace was scanned within the budget. If not, matches is a lower bound.
+type keyPatternCount struct {
+	matches  int64
+	complete bool
+}
+
+// Builds the number of matching keys of each pattern, and whether it was fully
+// counted, in the order of patterns.
+func buildKeyPatternMetrics(patterns []string, counts map[string]keyPatternCount, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	for _, pattern := range patterns {
+		c, ok := counts[pattern]
+		if !ok {
+			continue
+		}
+		initIntMetric(&redisMetric{
+			name:   keyPatternMetricNames[0],
+			labels: map[string]string{"pattern": pattern},
+		}, c.matches, t, ms.AppendEmpty())
+		var complete int64
+		if c.complete {
+			complete = 1
+		}
+		initIntMetric(&redisMetric{
+			name:   keyPatternMetricNames[1],
+			labels: map[string]string{"pattern": pattern},
+		}, complete, t, ms.AppendEmpty())
+	}
+	return ms
+}
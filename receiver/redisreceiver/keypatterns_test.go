@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildKeyPatternMetrics(t *testing.T) {
+	counts := map[string]keyPatternCount{
+		"session:*": {matches: 42, complete: true},
+		"cache:*":   {matches: 1000},
+	}
+	ms := buildKeyPatternMetrics([]string{"session:*", "cache:*", "missing:*"}, counts, testTimeBundle())
+	require.Equal(t, 4, ms.Len())
+
+	expected := []struct {
+		name    string
+		pattern string
+		value   int64
+	}{
+		{"redis/keys/matching", "session:*", 42},
+		{"redis/keys/matching_complete", "session:*", 1},
+		{"redis/keys/matching", "cache:*", 1000},
+		{"redis/keys/matching_complete", "cache:*", 0},
+	}
+	for i, e := range expected {
+		assert.Equal(t, e.name, ms.At(i).Name())
+		pt := ms.At(i).IntGauge().DataPoints().At(0)
+		pattern, ok := pt.LabelsMap().Get("pattern")
+		assert.True(t, ok)
+		assert.Equal(t, e.pattern, pattern)
+		assert.Equal(t, e.value, pt.Value())
+	}
+}
+
+func TestRedisScraperKeyPatterns(t *testing.T) {
+	settings := map[string]MetricSettings{"redis/keys/matching_complete": {Enabled: false}}
+	scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "", settings)
+	scraper.keyPatterns = KeyPatternsConfig{Patterns: []string{"session:*", "*"}, Count: 100, MaxCalls: 10}
+	rms := scrapeMetrics(t, scraper)
+
+	matches := map[string]int64{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		assert.NotEqual(t, "redis/keys/matching_complete", ms.At(i).Name())
+		if ms.At(i).Name() != "redis/keys/matching" {
+			continue
+		}
+		pt := ms.At(i).IntGauge().DataPoints().At(0)
+		pattern, _ := pt.LabelsMap().Get("pattern")
+		matches[pattern] = pt.Value()
+	}
+	// The fake client matches as many keys as the pattern has characters.
+	assert.Equal(t, map[string]int64{"session:*": 9, "*": 1}, matches)
+}
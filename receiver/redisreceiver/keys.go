@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"github.com/go-redis/redis/v7"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The names of the metrics built from the monitored keys.
+var keyMetricNames = []string{"redis/key/ttl", "redis/key/memory_usage", "redis/key/length"}
+
+// The type, TTL, memory usage and length of a monitored key. Values that could
+// not be retrieved are left out, e.g. the TTL of a key with no expiry.
+type keyStats struct {
+	keyType     string
+	ttlSeconds  int64
+	hasTTL      bool
+	memoryUsage int64
+	hasMemory   bool
+	length      int64
+	hasLength   bool
+}
+
+// Queues the command returning the length of a key of the given type, which
+// depends on the type, or returns nil for unknown types.
+func queueKeyLength(pipe redis.Pipeliner, keyType, key string) *redis.IntCmd {
+	switch keyType {
+	case "string":
+		return pipe.StrLen(key)
+	case "list":
+		return pipe.LLen(key)
+	case "set":
+		return pipe.SCard(key)
+	case "zset":
+		return pipe.ZCard(key)
+	case "hash":
+		return pipe.HLen(key)
+	case "stream":
+		return pipe.XLen(key)
+	}
+	return nil
+}
+
+// Builds the metrics of each of the keys that exist, in the order of keys.
+func buildKeyMetrics(keys []string, stats map[string]keyStats, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	for _, key := range keys {
+		s, ok := stats[key]
+		if !ok {
+			continue
+		}
+		labels := map[string]string{"key": key, "type": s.keyType}
+		if s.hasTTL {
+			initIntMetric(&redisMetric{
+				name:   keyMetricNames[0],
+				labels: labels,
+			}, s.ttlSeconds, t, ms.AppendEmpty())
+		}
+		if s.hasMemory {
+			initIntMetric(&redisMetric{
+				name:   keyMetricNames[1],
+				labels: labels,
+			}, s.memoryUsage, t, ms.AppendEmpty())
+		}
+		if s.hasLength {
+			initIntMetric(&redisMetric{
+				name:   keyMetricNames[2],
+				labels: labels,
+			}, s.length, t, ms.AppendEmpty())
+		}
+	}
+	return ms
+}
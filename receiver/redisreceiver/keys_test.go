@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildKeyMetrics(t *testing.T) {
+	stats := map[string]keyStats{
+		"queue": {keyType: "list", memoryUsage: 1024, hasMemory: true, length: 12, hasLength: true},
+		"cache": {keyType: "string", ttlSeconds: 300, hasTTL: true},
+	}
+	ms := buildKeyMetrics([]string{"queue", "missing", "cache"}, stats, testTimeBundle())
+	require.Equal(t, 3, ms.Len())
+
+	expected := []struct {
+		name    string
+		key     string
+		keyType string
+		value   int64
+	}{
+		{"redis/key/memory_usage", "queue", "list", 1024},
+		{"redis/key/length", "queue", "list", 12},
+		{"redis/key/ttl", "cache", "string", 300},
+	}
+	for i, e := range expected {
+		assert.Equal(t, e.name, ms.At(i).Name())
+		pt := ms.At(i).IntGauge().DataPoints().At(0)
+		key, _ := pt.LabelsMap().Get("key")
+		assert.Equal(t, e.key, key)
+		keyType, _ := pt.LabelsMap().Get("type")
+		assert.Equal(t, e.keyType, keyType)
+		assert.Equal(t, e.value, pt.Value())
+	}
+}
+
+func TestRedisScraperKeys(t *testing.T) {
+	settings := map[string]MetricSettings{"redis/key/memory_usage": {Enabled: false}}
+	scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "", settings)
+	scraper.keys = []string{"queue", "cache", "missing"}
+	rms := scrapeMetrics(t, scraper)
+
+	var names []string
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		if name := ms.At(i).Name(); name == "redis/key/ttl" || name == "redis/key/memory_usage" || name == "redis/key/length" {
+			names = append(names, name)
+		}
+	}
+	assert.Equal(t, []string{"redis/key/length", "redis/key/ttl", "redis/key/length"}, names)
+}
+
+func TestIsRedirectError(t *testing.T) {
+	assert.True(t, isRedirectError(errors.New("MOVED 3999 127.0.0.1:6381")))
+	assert.True(t, isRedirectError(errors.New("ASK 3999 127.0.0.1:6381")))
+	assert.False(t, isRedirectError(errors.New("NOAUTH Authentication required.")))
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The prefix of the Latencystats keys of the INFO command, followed by the
+// command name, e.g. "latency_percentiles_usec_get".
+const latencyStatsPrefix = "latency_percentiles_usec_"
+
+// Holds a field returned by the Latencystats section of the INFO command
+// (Redis 7): e.g. "latency_percentiles_usec_get:p50=1.003,p99=2.007,p99.9=3.007"
+type latencyStats struct {
+	cmd string
+	// Latency in microseconds by percentile, e.g. "p99.9".
+	percentiles map[string]float64
+}
+
+// Turns a latencystats value (the part after the colon
+// e.g. "p50=1.003,p99=2.007,p99.9=3.007") into a latencyStats struct
+func parseLatencyStatsString(cmd string, str string) (*latencyStats, error) {
+	ls := latencyStats{cmd: cmd, percentiles: make(map[string]float64)}
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.Split(pairStr, "=")
+		if len(pair) != 2 || !strings.HasPrefix(pair[0], "p") {
+			return nil, fmt.Errorf(
+				"unexpected latencystats pair '%s'",
+				pairStr,
+			)
+		}
+		val, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		ls.percentiles[pair[0]] = val
+	}
+	return &ls, nil
+}
+
+// Returns the commands with latency stats in INFO, sorted so that metrics are
+// built in a stable order.
+func (i info) latencyStatsCommands() []string {
+	var cmds []string
+	for key := range i {
+		if strings.HasPrefix(key, latencyStatsPrefix) {
+			cmds = append(cmds, strings.TrimPrefix(key, latencyStatsPrefix))
+		}
+	}
+	sort.Strings(cmds)
+	return cmds
+}
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLatencyStats(t *testing.T) {
+	ls, err := parseLatencyStatsString("get", "p50=1.003,p99=2.007,p99.9=3.007")
+	require.Nil(t, err)
+	require.Equal(t, "get", ls.cmd)
+	require.Equal(t, map[string]float64{"p50": 1.003, "p99": 2.007, "p99.9": 3.007}, ls.percentiles)
+}
+
+func TestParseMalformedLatencyStats(t *testing.T) {
+	tests := []struct{ name, stats string }{
+		{"missing value", "p50=1.003,p99="},
+		{"missing equals", "p50=1.003,p99"},
+		{"not a percentile", "p50=1.003,calls=2"},
+		{"not a number", "p50=fast"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseLatencyStatsString("get", test.stats)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestBuildLatencyStatsMetrics(t *testing.T) {
+	inf := info{
+		"latency_percentiles_usec_set":        "p50=2,p99=4",
+		"latency_percentiles_usec_get":        "p50=1",
+		"latency_percentiles_usec_bad":        "p50",
+		"latency_percentiles_usec_config|get": "p99.9=3",
+	}
+	ms, warnings := inf.buildLatencyStatsMetrics(newTimeBundle(time.Now(), 100))
+	assert.Len(t, warnings, 1)
+	require.Equal(t, 4, ms.Len())
+
+	var got []string
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		assert.Equal(t, "redis/commands/latency", m.Name())
+		assert.Equal(t, "us", m.Unit())
+		pt := m.DoubleGauge().DataPoints().At(0)
+		cmd, _ := pt.LabelsMap().Get("cmd")
+		percentile, _ := pt.LabelsMap().Get("percentile")
+		got = append(got, cmd+" "+percentile)
+	}
+	assert.Equal(t, []string{"config|get p99.9", "get p50", "set p50", "set p99"}, got)
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Parses the reply of MEMORY STATS, a flat list of keys and values, into an
+// `info` map so that metrics can be built like those of INFO. Integers are
+// formatted, floats are already strings, and the nested statistics of each
+// database (e.g. "db.0") are flattened with the database as prefix, e.g.
+// "db.0.overhead.hashtable.main".
+func parseMemoryStats(res interface{}) (info, error) {
+	fields, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected memory stats reply %T", res)
+	}
+	return addMemoryStats(info{}, "", fields)
+}
+
+func addMemoryStats(inf info, prefix string, fields []interface{}) (info, error) {
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("unexpected memory stats reply of %d fields", len(fields))
+	}
+	for i := 0; i < len(fields); i += 2 {
+		key, ok := fields[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected memory stats key %v", fields[i])
+		}
+		key = prefix + key
+		switch v := fields[i+1].(type) {
+		case int64:
+			inf[key] = strconv.FormatInt(v, 10)
+		case string:
+			inf[key] = v
+		case []interface{}:
+			if _, err := addMemoryStats(inf, key+".", v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return inf, nil
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// A MEMORY STATS reply of Redis 6.
+var testMemoryStats = []interface{}{
+	"peak.allocated", int64(1037504),
+	"total.allocated", int64(995232),
+	"startup.allocated", int64(803208),
+	"replication.backlog", int64(0),
+	"clients.slaves", int64(0),
+	"clients.normal", int64(20512),
+	"aof.buffer", int64(0),
+	"lua.caches", int64(0),
+	"db.0", []interface{}{
+		"overhead.hashtable.main", int64(72),
+		"overhead.hashtable.expires", int64(0),
+	},
+	"overhead.total", int64(823792),
+	"keys.count", int64(1),
+	"keys.bytes-per-key", int64(171440),
+	"dataset.bytes", int64(171440),
+	"dataset.percentage", "89.163284301757812",
+	"peak.percentage", "95.925437927246094",
+	"allocator.allocated", int64(1018352),
+	"fragmentation", "6.7021336555480957",
+	"fragmentation.bytes", int64(5851520),
+}
+
+func TestParseMemoryStats(t *testing.T) {
+	stats, err := parseMemoryStats(testMemoryStats)
+	require.NoError(t, err)
+	assert.Equal(t, "1037504", stats["peak.allocated"])
+	assert.Equal(t, "89.163284301757812", stats["dataset.percentage"])
+	assert.Equal(t, "72", stats["db.0.overhead.hashtable.main"])
+
+	_, err = parseMemoryStats("OK")
+	assert.Error(t, err)
+	_, err = parseMemoryStats([]interface{}{"peak.allocated"})
+	assert.Error(t, err)
+	_, err = parseMemoryStats([]interface{}{int64(1), int64(2)})
+	assert.Error(t, err)
+}
+
+func TestBuildMemoryStatsMetrics(t *testing.T) {
+	stats, err := parseMemoryStats(testMemoryStats)
+	require.NoError(t, err)
+	ms, warnings := stats.buildFixedMetrics(getMemoryStatsMetrics(), newTimeBundle(time.Now(), 100))
+	assert.Nil(t, warnings)
+	require.Equal(t, len(getMemoryStatsMetrics()), ms.Len())
+
+	values := map[string]float64{}
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.DataType() == pdata.MetricDataTypeDoubleGauge {
+			values[m.Name()] = m.DoubleGauge().DataPoints().At(0).Value()
+		} else {
+			values[m.Name()] = float64(m.IntGauge().DataPoints().At(0).Value())
+		}
+	}
+	assert.Equal(t, float64(1037504), values["redis/memory/stats/peak_allocated"])
+	assert.Equal(t, float64(171440), values["redis/memory/stats/bytes_per_key"])
+	assert.InDelta(t, 6.70, values["redis/memory/stats/fragmentation_ratio"], 0.01)
+}
@@ -15,18 +15,19 @@
 package redisreceiver
 
 import (
-	"go.opentelemetry.io/collector/consumer/pdata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/internal/metadata"
 )
 
 // Called once at startup. Returns all of the metrics (except keyspace)
-// we want to extract from Redis INFO.
-func getDefaultRedisMetrics() []*redisMetric {
+// we want to extract from Redis INFO, whether enabled by default or not.
+func getRedisMetrics() []*redisMetric {
 	return []*redisMetric{
 		uptimeInSeconds(),
 
 		usedCPUSys(),
 		usedCPUSysChildren(),
 		usedCPUUser(),
+		usedCPUUserChildren(),
 
 		connectedClients(),
 
@@ -67,289 +68,512 @@ func getDefaultRedisMetrics() []*redisMetric {
 		replBacklogFirstByteOffset(),
 
 		masterReplOffset(),
+
+		maxmemory(),
+		usedMemoryDataset(),
+		usedMemoryOverhead(),
+		memFragmentationBytes(),
+		pubsubChannels(),
+		pubsubPatterns(),
 	}
 }
 
 func uptimeInSeconds() *redisMetric {
 	return &redisMetric{
-		key:         "uptime_in_seconds",
-		name:        "redis/uptime",
-		units:       "s",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		desc:        "Number of seconds since Redis server start",
+		key:  "uptime_in_seconds",
+		name: "redis/uptime",
 	}
 }
 
 func usedCPUSys() *redisMetric {
 	return &redisMetric{
-		key:         "used_cpu_sys",
-		name:        "redis/cpu/time",
-		units:       "s",
-		pdType:      pdata.MetricDataTypeDoubleSum,
-		isMonotonic: true,
-		labels:      map[string]string{"state": "sys"},
-		desc:        "System CPU consumed by the Redis server in seconds since server start",
+		key:    "used_cpu_sys",
+		name:   "redis/cpu/time",
+		labels: map[string]string{"state": "sys"},
 	}
 }
 
 func usedCPUUser() *redisMetric {
 	return &redisMetric{
-		key:         "used_cpu_user",
-		name:        "redis/cpu/time",
-		units:       "s",
-		pdType:      pdata.MetricDataTypeDoubleSum,
-		isMonotonic: true,
-		labels:      map[string]string{"state": "user"},
-		desc:        "User CPU consumed by the Redis server in seconds since server start",
+		key:    "used_cpu_user",
+		name:   "redis/cpu/time",
+		labels: map[string]string{"state": "user"},
 	}
 }
 
 func usedCPUSysChildren() *redisMetric {
 	return &redisMetric{
-		key:         "used_cpu_sys_children",
-		name:        "redis/cpu/time",
-		units:       "s",
-		pdType:      pdata.MetricDataTypeDoubleSum,
-		isMonotonic: true,
-		labels:      map[string]string{"state": "children"},
-		desc:        "User CPU consumed by the background processes in seconds since server start",
+		key:    "used_cpu_sys_children",
+		name:   "redis/cpu/time",
+		labels: map[string]string{"state": "sys_children"},
+	}
+}
+
+func usedCPUUserChildren() *redisMetric {
+	return &redisMetric{
+		key:    "used_cpu_user_children",
+		name:   "redis/cpu/time",
+		labels: map[string]string{"state": "user_children"},
 	}
 }
 
 func connectedClients() *redisMetric {
 	return &redisMetric{
-		key:         "connected_clients",
-		name:        "redis/clients/connected",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: false,
-		desc:        "Number of client connections (excluding connections from replicas)",
+		key:  "connected_clients",
+		name: "redis/clients/connected",
 	}
 }
 
 func clientRecentMaxInputBuffer() *redisMetric {
 	return &redisMetric{
-		key:    "client_recent_max_input_buffer",
-		name:   "redis/clients/max_input_buffer",
-		pdType: pdata.MetricDataTypeIntGauge,
-		desc:   "Biggest input buffer among current client connections",
+		key:  "client_recent_max_input_buffer",
+		name: "redis/clients/max_input_buffer",
 	}
 }
 
 func clientRecentMaxOutputBuffer() *redisMetric {
 	return &redisMetric{
-		key:    "client_recent_max_output_buffer",
-		name:   "redis/clients/max_output_buffer",
-		pdType: pdata.MetricDataTypeIntGauge,
-		desc:   "Longest output list among current client connections",
+		key:  "client_recent_max_output_buffer",
+		name: "redis/clients/max_output_buffer",
 	}
 }
 
 func blockedClients() *redisMetric {
 	return &redisMetric{
-		key:         "blocked_clients",
-		name:        "redis/clients/blocked",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: false,
-		desc:        "Number of clients pending on a blocking call",
+		key:  "blocked_clients",
+		name: "redis/clients/blocked",
 	}
 }
 
 func expiredKeys() *redisMetric {
 	return &redisMetric{
-		key:         "expired_keys",
-		name:        "redis/keys/expired",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		desc:        "Total number of key expiration events",
+		key:  "expired_keys",
+		name: "redis/keys/expired",
 	}
 }
 
 func evictedKeys() *redisMetric {
 	return &redisMetric{
-		key:         "evicted_keys",
-		name:        "redis/keys/evicted",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		desc:        "Number of evicted keys due to maxmemory limit",
+		key:  "evicted_keys",
+		name: "redis/keys/evicted",
 	}
 }
 
 func totalConnectionsReceived() *redisMetric {
 	return &redisMetric{
-		key:         "total_connections_received",
-		name:        "redis/connections/received",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		desc:        "Total number of connections accepted by the server",
+		key:  "total_connections_received",
+		name: "redis/connections/received",
 	}
 }
 
 func rejectedConnections() *redisMetric {
 	return &redisMetric{
-		key:         "rejected_connections",
-		name:        "redis/connections/rejected",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		desc:        "Number of connections rejected because of maxclients limit",
+		key:  "rejected_connections",
+		name: "redis/connections/rejected",
 	}
 }
 
 func usedMemory() *redisMetric {
 	return &redisMetric{
-		key:    "used_memory",
-		name:   "redis/memory/used",
-		units:  "By",
-		pdType: pdata.MetricDataTypeIntGauge,
-		desc:   "Total number of bytes allocated by Redis using its allocator",
+		key:  "used_memory",
+		name: "redis/memory/used",
 	}
 }
 
 func usedMemoryPeak() *redisMetric {
 	return &redisMetric{
-		key:    "used_memory_peak",
-		name:   "redis/memory/peak",
-		pdType: pdata.MetricDataTypeIntGauge,
-		units:  "By",
-		desc:   "Peak memory consumed by Redis (in bytes)",
+		key:  "used_memory_peak",
+		name: "redis/memory/peak",
 	}
 }
 
 func usedMemoryRss() *redisMetric {
 	return &redisMetric{
-		key:    "used_memory_rss",
-		name:   "redis/memory/rss",
-		pdType: pdata.MetricDataTypeIntGauge,
-		units:  "By",
-		desc:   "Number of bytes that Redis allocated as seen by the operating system",
+		key:  "used_memory_rss",
+		name: "redis/memory/rss",
 	}
 }
 
 func usedMemoryLua() *redisMetric {
 	return &redisMetric{
-		key:    "used_memory_lua",
-		name:   "redis/memory/lua",
-		pdType: pdata.MetricDataTypeIntGauge,
-		units:  "By",
-		desc:   "Number of bytes used by the Lua engine",
+		key:  "used_memory_lua",
+		name: "redis/memory/lua",
 	}
 }
 
 func memFragmentationRatio() *redisMetric {
 	return &redisMetric{
-		key:    "mem_fragmentation_ratio",
-		name:   "redis/memory/fragmentation_ratio",
-		pdType: pdata.MetricDataTypeDoubleGauge,
-		desc:   "Ratio between used_memory_rss and used_memory",
+		key:  "mem_fragmentation_ratio",
+		name: "redis/memory/fragmentation_ratio",
 	}
 }
 
 func rdbChangesSinceLastSave() *redisMetric {
 	return &redisMetric{
-		key:         "rdb_changes_since_last_save",
-		name:        "redis/rdb/changes_since_last_save",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: false,
-		desc:        "Number of changes since the last dump",
+		key:  "rdb_changes_since_last_save",
+		name: "redis/rdb/changes_since_last_save",
 	}
 }
 
 func instantaneousOpsPerSec() *redisMetric {
 	return &redisMetric{
-		key:    "instantaneous_ops_per_sec",
-		name:   "redis/commands",
-		pdType: pdata.MetricDataTypeIntGauge,
-		units:  "{ops}/s",
-		desc:   "Number of commands processed per second",
+		key:  "instantaneous_ops_per_sec",
+		name: "redis/commands",
 	}
 }
 
 func totalCommandsProcessed() *redisMetric {
 	return &redisMetric{
-		key:         "total_commands_processed",
-		name:        "redis/commands/processed",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		desc:        "Total number of commands processed by the server",
+		key:  "total_commands_processed",
+		name: "redis/commands/processed",
 	}
 }
 
 func totalNetInputBytes() *redisMetric {
 	return &redisMetric{
-		key:         "total_net_input_bytes",
-		name:        "redis/net/input",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		units:       "By",
-		desc:        "The total number of bytes read from the network",
+		key:  "total_net_input_bytes",
+		name: "redis/net/input",
 	}
 }
 
 func totalNetOutputBytes() *redisMetric {
 	return &redisMetric{
-		key:         "total_net_output_bytes",
-		name:        "redis/net/output",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		units:       "By",
-		desc:        "The total number of bytes written to the network",
+		key:  "total_net_output_bytes",
+		name: "redis/net/output",
 	}
 }
 
 func keyspaceHits() *redisMetric {
 	return &redisMetric{
-		key:         "keyspace_hits",
-		name:        "redis/keyspace/hits",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		desc:        "Number of successful lookup of keys in the main dictionary",
+		key:  "keyspace_hits",
+		name: "redis/keyspace/hits",
 	}
 }
 
 func keyspaceMisses() *redisMetric {
 	return &redisMetric{
-		key:         "keyspace_misses",
-		name:        "redis/keyspace/misses",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: true,
-		desc:        "Number of failed lookup of keys in the main dictionary",
+		key:  "keyspace_misses",
+		name: "redis/keyspace/misses",
 	}
 }
 
 func latestForkUsec() *redisMetric {
 	return &redisMetric{
-		key:    "latest_fork_usec",
-		name:   "redis/latest_fork",
-		pdType: pdata.MetricDataTypeIntGauge,
-		units:  "us",
-		desc:   "Duration of the latest fork operation in microseconds",
+		key:  "latest_fork_usec",
+		name: "redis/latest_fork",
 	}
 }
 
 func connectedSlaves() *redisMetric {
 	return &redisMetric{
-		key:         "connected_slaves",
-		name:        "redis/slaves/connected",
-		pdType:      pdata.MetricDataTypeIntSum,
-		isMonotonic: false,
-		desc:        "Number of connected replicas",
+		key:  "connected_slaves",
+		name: "redis/slaves/connected",
 	}
 }
 
 func replBacklogFirstByteOffset() *redisMetric {
 	return &redisMetric{
-		key:    "repl_backlog_first_byte_offset",
-		name:   "redis/replication/backlog_first_byte_offset",
-		pdType: pdata.MetricDataTypeIntGauge,
-		desc:   "The master offset of the replication backlog buffer",
+		key:  "repl_backlog_first_byte_offset",
+		name: "redis/replication/backlog_first_byte_offset",
 	}
 }
 
 func masterReplOffset() *redisMetric {
 	return &redisMetric{
-		key:    "master_repl_offset",
-		name:   "redis/replication/offset",
-		pdType: pdata.MetricDataTypeIntGauge,
-		desc:   "The server's current replication offset",
+		key:  "master_repl_offset",
+		name: "redis/replication/offset",
+	}
+}
+
+// Called once at startup. Returns the metrics of the memory allocator and of
+// lazy freeing, extracted from Redis INFO when present: the allocator metrics
+// are only reported by Redis 5 and later with jemalloc.
+func getAllocatorMetrics() []*redisMetric {
+	return []*redisMetric{
+		allocatorBytes("allocator_allocated", "allocated"),
+		allocatorBytes("allocator_active", "active"),
+		allocatorBytes("allocator_resident", "resident"),
+		{
+			key:  "allocator_frag_ratio",
+			name: "redis/memory/allocator/fragmentation_ratio",
+		},
+		{
+			key:  "allocator_rss_ratio",
+			name: "redis/memory/allocator/rss_ratio",
+		},
+		{
+			key:  "lazyfree_pending_objects",
+			name: "redis/memory/lazyfree_pending_objects",
+		},
+	}
+}
+
+// Called once at startup. Returns the metrics of client side caching, extracted
+// from Redis INFO when present: they are only reported by Redis 6 and later.
+func getTrackingMetrics() []*redisMetric {
+	return []*redisMetric{
+		{
+			key:  "tracking_clients",
+			name: "redis/tracking/clients",
+		},
+		{
+			key:  "tracking_total_keys",
+			name: "redis/tracking/keys",
+		},
+		{
+			key:  "tracking_total_items",
+			name: "redis/tracking/items",
+		},
+		{
+			key:  "tracking_total_prefixes",
+			name: "redis/tracking/prefixes",
+		},
+	}
+}
+
+// Called once at startup. Returns the metrics we want to extract from Redis
+// MEMORY STATS.
+func getMemoryStatsMetrics() []*redisMetric {
+	return []*redisMetric{
+		memoryStatsBytes("peak.allocated", "peak_allocated"),
+		memoryStatsBytes("total.allocated", "total_allocated"),
+		memoryStatsBytes("startup.allocated", "startup_allocated"),
+		memoryStatsBytes("replication.backlog", "replication_backlog"),
+		memoryStatsClients("clients.normal", "normal"),
+		memoryStatsClients("clients.slaves", "replica"),
+		memoryStatsBytes("aof.buffer", "aof_buffer"),
+		memoryStatsBytes("lua.caches", "lua_caches"),
+		memoryStatsBytes("overhead.total", "overhead"),
+		memoryStatsBytes("dataset.bytes", "dataset"),
+		memoryStatsKeys(),
+		memoryStatsBytesPerKey(),
+		memoryStatsRatio("dataset.percentage", "dataset_percentage"),
+		memoryStatsRatio("fragmentation", "fragmentation_ratio"),
+	}
+}
+
+// The names of the metrics built from the keyspace lines of Redis INFO.
+var keyspaceMetricNames = []string{"redis/db/keys", "redis/db/expires", "redis/db/avg_ttl"}
+
+// The names of the metrics built from the latencystats and errorstats lines of
+// Redis INFO.
+const (
+	latencyStatsMetricName = "redis/commands/latency"
+	errorStatsMetricName   = "redis/errors"
+)
+
+// The name of the metric reporting whether a node could be scraped.
+const upMetricName = "redis/up"
+
+const restartsMetricName = "redis/restarts"
+
+// The metrics that only apply to masters, left out for replicas if
+// replica.suppress_master_metrics is set. Replicas neither expire nor evict
+// keys themselves, but apply the deletions of their master.
+var masterOnlyMetricNames = []string{
+	"redis/keys/evicted",
+	"redis/keys/evicted_rate",
+	"redis/keys/expired",
+	"redis/keys/expired_rate",
+	"redis/slaves/connected",
+	"redis/replication/replica/lag",
+	"redis/replication/replica/offset",
+	"redis/replication/replica/offset_lag",
+	"redis/replication/replica/online",
+}
+
+// The name of the metric built from PUBSUB NUMSUB.
+const pubSubSubscribersMetricName = "redis/pubsub/subscribers"
+
+// The names of the metrics built from CLIENT LIST.
+var clientListMetricNames = []string{"redis/clients/by_state", "redis/clients/by_age", "redis/clients/by_idle", "redis/clients/tracking"}
+
+// The names of the metrics built from the slow log.
+var slowLogMetricNames = []string{"redis/slowlog/length", "redis/slowlog/entries", "redis/slowlog/max_duration"}
+
+// The names of the slow log metrics built from SLOWLOG LEN and from SLOWLOG
+// GET, to only run the commands they are built from.
+var (
+	slowLogLenMetricNames = slowLogMetricNames[:1]
+	slowLogGetMetricNames = slowLogMetricNames[1:]
+)
+
+// Returns the metrics to extract, those that are enabled.
+func enabledMetrics(settings map[string]MetricSettings, metrics []*redisMetric) []*redisMetric {
+	var enabled []*redisMetric
+	for _, m := range metrics {
+		if metricEnabled(settings, m.name) {
+			enabled = append(enabled, m)
+		}
+	}
+	return enabled
+}
+
+// The metrics that are disabled by default: those rarely needed, and those
+// built from commands that are costly on the server, CLIENT LIST and the slow
+// log.
+var disabledByDefault = map[string]bool{
+	metadata.M.RedisMaxmemory.Name():           true,
+	metadata.M.RedisMemoryDataset.Name():       true,
+	metadata.M.RedisMemoryOverhead.Name():      true,
+	metadata.M.RedisMemoryFragmentation.Name(): true,
+	metadata.M.RedisPubsubChannels.Name():      true,
+	metadata.M.RedisPubsubPatterns.Name():      true,
+	metadata.M.RedisClientsByState.Name():      true,
+	metadata.M.RedisClientsByAge.Name():        true,
+	metadata.M.RedisClientsByIdle.Name():       true,
+	metadata.M.RedisClientsTracking.Name():     true,
+	metadata.M.RedisSlowlogLength.Name():       true,
+	metadata.M.RedisSlowlogEntries.Name():      true,
+	metadata.M.RedisSlowlogMaxDuration.Name():  true,
+}
+
+// Returns whether a metric is enabled in settings, or else by default.
+// Metrics that are not defined in metadata.yaml, built from the fields of
+// INFO that the receiver does not know, are enabled.
+func metricEnabled(settings map[string]MetricSettings, name string) bool {
+	if s, ok := settings[name]; ok {
+		return s.Enabled
+	}
+	return !disabledByDefault[name]
+}
+
+// Returns the names of all metrics the receiver can produce, as defined in
+// metadata.yaml.
+func knownMetricNames() map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range metadata.M.Names() {
+		names[name] = true
+	}
+	return names
+}
+
+// Called once at startup in cluster mode. Returns the cluster-level metrics
+// we want to extract from Redis CLUSTER INFO.
+func getClusterRedisMetrics() []*redisMetric {
+	return []*redisMetric{
+		clusterStateOK(),
+
+		clusterSlots("assigned"),
+		clusterSlots("ok"),
+		clusterSlots("pfail"),
+		clusterSlots("fail"),
+
+		clusterKnownNodes(),
+		clusterSize(),
+	}
+}
+
+func clusterStateOK() *redisMetric {
+	return &redisMetric{
+		key:  "cluster_state_ok",
+		name: "redis/cluster/state",
+	}
+}
+
+func clusterSlots(state string) *redisMetric {
+	return &redisMetric{
+		key:    "cluster_slots_" + state,
+		name:   "redis/cluster/slots",
+		labels: map[string]string{"state": state},
+	}
+}
+
+func clusterKnownNodes() *redisMetric {
+	return &redisMetric{
+		key:  "cluster_known_nodes",
+		name: "redis/cluster/known_nodes",
+	}
+}
+
+func clusterSize() *redisMetric {
+	return &redisMetric{
+		key:  "cluster_size",
+		name: "redis/cluster/size",
+	}
+}
+
+func maxmemory() *redisMetric {
+	return &redisMetric{
+		key:  "maxmemory",
+		name: "redis/maxmemory",
+	}
+}
+
+func usedMemoryDataset() *redisMetric {
+	return &redisMetric{
+		key:  "used_memory_dataset",
+		name: "redis/memory/dataset",
+	}
+}
+
+func usedMemoryOverhead() *redisMetric {
+	return &redisMetric{
+		key:  "used_memory_overhead",
+		name: "redis/memory/overhead",
+	}
+}
+
+func memFragmentationBytes() *redisMetric {
+	return &redisMetric{
+		key:  "mem_fragmentation_bytes",
+		name: "redis/memory/fragmentation",
+	}
+}
+
+func pubsubChannels() *redisMetric {
+	return &redisMetric{
+		key:  "pubsub_channels",
+		name: "redis/pubsub/channels",
+	}
+}
+
+func pubsubPatterns() *redisMetric {
+	return &redisMetric{
+		key:  "pubsub_patterns",
+		name: "redis/pubsub/patterns",
+	}
+}
+
+func allocatorBytes(key, name string) *redisMetric {
+	return &redisMetric{
+		key:  key,
+		name: "redis/memory/allocator/" + name,
+	}
+}
+
+func memoryStatsBytes(key, name string) *redisMetric {
+	return &redisMetric{
+		key:  key,
+		name: "redis/memory/stats/" + name,
+	}
+}
+
+func memoryStatsClients(key, clientType string) *redisMetric {
+	return &redisMetric{
+		key:    key,
+		name:   "redis/memory/stats/clients",
+		labels: map[string]string{"type": clientType},
+	}
+}
+
+func memoryStatsKeys() *redisMetric {
+	return &redisMetric{
+		key:  "keys.count",
+		name: "redis/memory/stats/keys",
+	}
+}
+
+func memoryStatsBytesPerKey() *redisMetric {
+	return &redisMetric{
+		key:  "keys.bytes-per-key",
+		name: "redis/memory/stats/bytes_per_key",
+	}
+}
+
+func memoryStatsRatio(key, name string) *redisMetric {
+	return &redisMetric{
+		key:  key,
+		name: "redis/memory/stats/" + name,
 	}
 }
@@ -18,21 +18,60 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/internal/metadata"
 )
 
 func TestDefaultMetrics(t *testing.T) {
-	for _, metric := range getDefaultRedisMetrics() {
+	metrics := getRedisMetrics()
+	metrics = append(metrics, getClusterRedisMetrics()...)
+	metrics = append(metrics, getAllocatorMetrics()...)
+	metrics = append(metrics, getTrackingMetrics()...)
+	for _, metric := range metrics {
 		require.True(t, len(metric.key) > 0)
 		require.True(t, len(metric.name) > 0)
 		require.True(t, strings.HasPrefix(metric.name, "redis/"))
-		require.True(
-			t,
-			metric.pdType == pdata.MetricDataTypeIntSum ||
-				metric.pdType == pdata.MetricDataTypeIntGauge ||
-				metric.pdType == pdata.MetricDataTypeDoubleSum ||
-				metric.pdType == pdata.MetricDataTypeDoubleGauge,
-		)
+		require.NotNil(t, metadata.M.ByName(metric.name), metric.name)
+	}
+}
+
+func TestEnabledMetrics(t *testing.T) {
+	// redis/maxmemory and redis/pubsub/channels are disabled by default, and
+	// redis/info/foo is not defined in metadata.yaml.
+	metrics := []*redisMetric{
+		{name: "redis/uptime"}, {name: "redis/cpu/time"}, {name: "redis/cpu/time"},
+		{name: "redis/maxmemory"}, {name: "redis/pubsub/channels"}, {name: "redis/info/foo"},
+	}
+
+	assert.Equal(t, []*redisMetric{metrics[0], metrics[1], metrics[2], metrics[5]}, enabledMetrics(nil, metrics))
+
+	enabled := enabledMetrics(map[string]MetricSettings{
+		"redis/cpu/time":        {Enabled: false},
+		"redis/maxmemory":       {Enabled: true},
+		"redis/pubsub/channels": {Enabled: false},
+	}, metrics)
+	assert.Equal(t, []*redisMetric{metrics[0], metrics[3], metrics[5]}, enabled)
+}
+
+func TestCPUMetrics(t *testing.T) {
+	pdm := metadata.M.RedisCpuTime.New()
+	assert.Equal(t, pdata.MetricDataTypeDoubleSum, pdm.DataType())
+	assert.True(t, pdm.DoubleSum().IsMonotonic())
+
+	keys := map[string]string{}
+	for _, metric := range getRedisMetrics() {
+		if metric.name != "redis/cpu/time" {
+			continue
+		}
+		keys[metric.labels["state"]] = metric.key
 	}
+	assert.Equal(t, map[string]string{
+		"sys":           "used_cpu_sys",
+		"user":          "used_cpu_user",
+		"sys_children":  "used_cpu_sys_children",
+		"user_children": "used_cpu_user_children",
+	}, keys)
 }
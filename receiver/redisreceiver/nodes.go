@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Resource attributes identifying the node of metrics, when the receiver
+// scrapes more than one Redis server.
+const (
+	nodeAddressAttribute = "redis.node.address"
+	nodeRoleAttribute    = "redis.node.role"
+
+	// Set by nodes whose service name differs from the one of the receiver.
+	serviceNameAttribute = "service.name"
+
+	roleMaster  = "master"
+	roleReplica = "replica"
+	// The role of Sentinel instances, when they are monitored.
+	roleSentinel = "sentinel"
+)
+
+// A Redis server scraped by the receiver.
+type redisNode struct {
+	client client
+	svc    *redisSvc
+	// Added to the resource of the metrics of the node.
+	attributes map[string]string
+	// Created on the first scrape of the node.
+	timeBundle *timeBundle
+	// The id of the most recent slow log entry emitted, if any.
+	lastSlowLogID  int64
+	seenSlowLogIDs bool
+	// The counters of the previous scrape by INFO key, to report rates.
+	lastCounters map[string]counterSample
+	// The number of consecutive failed attempts to scrape the node, the error
+	// of the last one, and the time before which it is not attempted again.
+	failures int
+	lastErr  error
+	retryAt  time.Time
+	// Whether INFO reported cluster mode as enabled on the previous scrape,
+	// to send CLUSTER INFO along with the next INFO.
+	clusterEnabled bool
+	// The commands the node rejected as unknown or denied, which are not sent
+	// to it again, by lowercase name.
+	unavailableCommands map[string]bool
+	// The role INFO reported on the previous scrape, roleMaster or
+	// roleReplica, which takes precedence over the role it was discovered
+	// with.
+	role string
+}
+
+// The value of a counter at a point in time.
+type counterSample struct {
+	value int64
+	time  time.Time
+}
+
+// Returns the role of a node from the role field of INFO, or "" if missing.
+func infoRole(inf info) string {
+	switch inf["role"] {
+	case "master":
+		return roleMaster
+	case "slave":
+		return roleReplica
+	}
+	return ""
+}
+
+// Returns whether the node rejected the command before, see commandFailed.
+func (n *redisNode) commandUnavailable(command string) bool {
+	return n.unavailableCommands[command]
+}
+
+// Logs the error of a command of the node. If the node does not run the
+// command, because it is renamed, disabled or denied, the command is marked as
+// unavailable so that it is not sent to the node again.
+func (n *redisNode) commandFailed(logger *zap.Logger, command, msg string, err error) {
+	if !isUnavailableCommandError(err) {
+		logger.Warn(msg, zap.Any("node", n.attributes), zap.Error(err))
+		return
+	}
+	if n.unavailableCommands == nil {
+		n.unavailableCommands = make(map[string]bool)
+	}
+	n.unavailableCommands[command] = true
+	logger.Warn(
+		msg+", not sending the command to the node again",
+		zap.String("command", command),
+		zap.Any("node", n.attributes),
+		zap.Error(err),
+	)
+}
+
+func newRedisNode(client client, attributes map[string]string) *redisNode {
+	return &redisNode{
+		client:       client,
+		svc:          newRedisSvc(client),
+		attributes:   attributes,
+		lastCounters: make(map[string]counterSample),
+	}
+}
+
+// Records the value of a counter and returns its rate per second since the
+// previous call for the same key. There is no rate on the first call, nor when
+// the counter was reset, e.g. by a restart.
+func (n *redisNode) counterRate(key string, value int64, now time.Time) (float64, bool) {
+	last, ok := n.lastCounters[key]
+	n.lastCounters[key] = counterSample{value: value, time: now}
+	if !ok || value < last.value || !now.After(last.time) {
+		return 0, false
+	}
+	return float64(value-last.value) / now.Sub(last.time).Seconds(), true
+}
+
+// Returns whether the node is skipped at now, after failed attempts to scrape
+// it.
+func (n *redisNode) backingOff(now time.Time) bool {
+	return n.failures > 0 && now.Before(n.retryAt)
+}
+
+// Records a failed attempt to scrape the node. It is skipped for a delay that
+// doubles with each consecutive failure. The client reconnects on the next
+// attempt, as connections that failed are not reused.
+func (n *redisNode) recordFailure(err error, now time.Time, cfg ReconnectConfig) {
+	n.failures++
+	n.lastErr = err
+	delay := cfg.InitialInterval
+	for i := 1; i < n.failures && delay < cfg.MaxInterval; i++ {
+		delay *= 2
+	}
+	if delay > cfg.MaxInterval {
+		delay = cfg.MaxInterval
+	}
+	n.retryAt = now.Add(delay)
+}
+
+// Records a successful attempt to scrape the node, resetting the backoff.
+func (n *redisNode) recordSuccess() {
+	n.failures = 0
+	n.lastErr = nil
+}
+
+// Provides the Redis servers to scrape on each run.
+type nodeSource interface {
+	// Returns the nodes to scrape now. Nodes that are returned again keep their
+	// state, e.g. their server start time.
+	nodes() ([]*redisNode, error)
+	// Closes the clients of all nodes.
+	close() error
+}
+
+// Always scrapes the same servers.
+type staticNodes []*redisNode
+
+var _ nodeSource = (staticNodes)(nil)
+
+func (s staticNodes) nodes() ([]*redisNode, error) {
+	return s, nil
+}
+
+func (s staticNodes) close() error {
+	var err error
+	for _, n := range s {
+		if cErr := n.client.close(); cErr != nil {
+			err = cErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,273 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
+)
+
+// The prefix of the channels of keyspace notifications, followed by the
+// database, "__:" and the key.
+const keyspaceChannelPrefix = "__keyspace@"
+
+// An event of a key received as a keyspace notification.
+type keyspaceEvent struct {
+	time  time.Time
+	db    int
+	key   string
+	event string
+}
+
+// Parses a message of a __keyspace@<db>__:<key> channel, whose payload is the
+// event. Returns false for other messages.
+func parseKeyspaceNotification(msg *redis.Message, now time.Time) (keyspaceEvent, bool) {
+	rest := strings.TrimPrefix(msg.Channel, keyspaceChannelPrefix)
+	if rest == msg.Channel {
+		return keyspaceEvent{}, false
+	}
+	i := strings.Index(rest, "__:")
+	if i < 0 {
+		return keyspaceEvent{}, false
+	}
+	db, err := strconv.Atoi(rest[:i])
+	if err != nil {
+		return keyspaceEvent{}, false
+	}
+	return keyspaceEvent{time: now, db: db, key: rest[i+len("__:"):], event: msg.Payload}, true
+}
+
+// The keyspace notifications received from a node since they were last
+// emitted.
+type keyspaceSubscription struct {
+	close func() error
+
+	mu      sync.Mutex
+	events  []keyspaceEvent
+	dropped int
+	// Whether the subscription ended, e.g. because the client was replaced
+	// after the password was rotated.
+	done bool
+}
+
+// Buffers the events of the messages until the channel is closed, keeping at
+// most maxEvents events.
+func (s *keyspaceSubscription) receive(messages <-chan *redis.Message, events map[string]bool, maxEvents int) {
+	for msg := range messages {
+		e, ok := parseKeyspaceNotification(msg, time.Now())
+		if !ok || !events[e.event] {
+			continue
+		}
+		s.mu.Lock()
+		if len(s.events) < maxEvents {
+			s.events = append(s.events, e)
+		} else {
+			s.dropped++
+		}
+		s.mu.Unlock()
+	}
+	s.mu.Lock()
+	s.done = true
+	s.mu.Unlock()
+}
+
+// Returns the events buffered since the previous call, and the number of
+// events dropped.
+func (s *keyspaceSubscription) take() (events []keyspaceEvent, dropped int, done bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events, dropped = s.events, s.dropped
+	s.events, s.dropped = nil, 0
+	return events, dropped, s.done
+}
+
+var _ interval.Runnable = (*keyspaceEventsRunnable)(nil)
+
+// Runs intermittently, subscribing to the keyspace notifications of each Redis
+// node it has not subscribed to yet, and feeding the events received since the
+// previous run to a logsConsumer.
+type keyspaceEventsRunnable struct {
+	id           config.ComponentID
+	ctx          context.Context
+	logsConsumer consumer.Logs
+	nodes        nodeSource
+	logger       *zap.Logger
+	// The attributes added to the resource of every node.
+	resourceAttributes map[string]string
+	// The channel patterns subscribed to, and the events emitted.
+	channels  []string
+	events    map[string]bool
+	maxEvents int
+	obsrecv   *obsreport.Receiver
+
+	mu            sync.Mutex
+	subscriptions map[*redisNode]*keyspaceSubscription
+	closed        bool
+}
+
+func newKeyspaceEventsRunnable(
+	ctx context.Context,
+	id config.ComponentID,
+	nodes nodeSource,
+	resourceAttributes map[string]string,
+	cfg KeyspaceNotificationsConfig,
+	logsConsumer consumer.Logs,
+	logger *zap.Logger,
+) *keyspaceEventsRunnable {
+	var channels []string
+	for _, pattern := range cfg.patterns() {
+		channels = append(channels, keyspaceChannelPrefix+"*__:"+pattern)
+	}
+	events := make(map[string]bool)
+	for _, event := range cfg.events() {
+		events[event] = true
+	}
+	return &keyspaceEventsRunnable{
+		id:                 id,
+		ctx:                ctx,
+		logsConsumer:       logsConsumer,
+		nodes:              nodes,
+		logger:             logger,
+		resourceAttributes: resourceAttributes,
+		channels:           channels,
+		events:             events,
+		maxEvents:          cfg.MaxEvents,
+		obsrecv:            obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: id, Transport: transport}),
+		subscriptions:      make(map[*redisNode]*keyspaceSubscription),
+	}
+}
+
+func (r *keyspaceEventsRunnable) Setup() error {
+	return nil
+}
+
+// Run is called periodically, subscribing to the nodes that are new, or whose
+// subscription ended, and sending the events received since the previous run
+// to the next consumer as log records, with one ResourceLogs per node.
+func (r *keyspaceEventsRunnable) Run() error {
+	const dataFormat = "redis_keyspace_notifications"
+	ctx := r.obsrecv.StartLogsReceiveOp(r.ctx)
+
+	nodes, err := r.nodes.nodes()
+	if err != nil {
+		r.obsrecv.EndLogsReceiveOp(ctx, dataFormat, 0, err)
+		return nil
+	}
+
+	r.mu.Lock()
+	ld := pdata.NewLogs()
+	r.subscribe(nodes)
+	for node, s := range r.subscriptions {
+		events, dropped, done := s.take()
+		if dropped > 0 {
+			r.logger.Warn("dropped redis keyspace notifications", zap.Any("node", node.attributes), zap.Int("dropped", dropped))
+		}
+		if done {
+			delete(r.subscriptions, node)
+		}
+		if len(events) > 0 {
+			r.appendEvents(node, events, ld.ResourceLogs())
+		}
+	}
+	r.mu.Unlock()
+
+	if ld.LogRecordCount() == 0 {
+		r.obsrecv.EndLogsReceiveOp(ctx, dataFormat, 0, nil)
+		return nil
+	}
+	err = r.logsConsumer.ConsumeLogs(r.ctx, ld)
+	r.obsrecv.EndLogsReceiveOp(ctx, dataFormat, ld.LogRecordCount(), err)
+	return nil
+}
+
+// Subscribes to the nodes without a subscription, and ends the subscriptions
+// of the nodes that are gone, e.g. removed from the cluster.
+func (r *keyspaceEventsRunnable) subscribe(nodes []*redisNode) {
+	if r.closed {
+		return
+	}
+	current := make(map[*redisNode]bool, len(nodes))
+	for _, node := range nodes {
+		current[node] = true
+		if _, ok := r.subscriptions[node]; ok {
+			continue
+		}
+		messages, closeFn, err := node.client.psubscribe(r.channels)
+		if err != nil {
+			r.logger.Warn("failed to subscribe to redis keyspace notifications", zap.Any("node", node.attributes), zap.Error(err))
+			continue
+		}
+		s := &keyspaceSubscription{close: closeFn}
+		r.subscriptions[node] = s
+		go s.receive(messages, r.events, r.maxEvents)
+	}
+	for node, s := range r.subscriptions {
+		if !current[node] {
+			_ = s.close()
+			delete(r.subscriptions, node)
+		}
+	}
+}
+
+// Appends the events of a node as log records.
+func (r *keyspaceEventsRunnable) appendEvents(node *redisNode, events []keyspaceEvent, rls pdata.ResourceLogsSlice) {
+	rl := rls.AppendEmpty()
+	rattrs := rl.Resource().Attributes()
+	for k, v := range r.resourceAttributes {
+		rattrs.InsertString(k, v)
+	}
+	for k, v := range node.attributes {
+		rattrs.UpsertString(k, v)
+	}
+	logs := rl.InstrumentationLibraryLogs().AppendEmpty().Logs()
+	for _, e := range events {
+		keyspaceEventToLogRecord(e, logs.AppendEmpty())
+	}
+}
+
+// Ends the subscriptions. Nodes are not subscribed to afterwards.
+func (r *keyspaceEventsRunnable) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	for node, s := range r.subscriptions {
+		_ = s.close()
+		delete(r.subscriptions, node)
+	}
+}
+
+func keyspaceEventToLogRecord(e keyspaceEvent, lr pdata.LogRecord) {
+	lr.SetName("redis.keyspace_notification")
+	lr.SetTimestamp(pdata.TimestampFromTime(e.time))
+	lr.Body().SetStringVal(e.event + " " + e.key)
+
+	attrs := lr.Attributes()
+	attrs.InsertString("db.system", "redis")
+	attrs.InsertInt("db.redis.database_index", int64(e.db))
+	attrs.InsertString("redis.key", e.key)
+	attrs.InsertString("redis.event", e.event)
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func TestParseKeyspaceNotification(t *testing.T) {
+	now := time.Unix(1622505600, 0)
+	e, ok := parseKeyspaceNotification(&redis.Message{Channel: "__keyspace@2__:session:1", Payload: "expired"}, now)
+	require.True(t, ok)
+	assert.Equal(t, keyspaceEvent{time: now, db: 2, key: "session:1", event: "expired"}, e)
+
+	_, ok = parseKeyspaceNotification(&redis.Message{Channel: "__keyevent@2__:expired", Payload: "session:1"}, now)
+	assert.False(t, ok)
+	_, ok = parseKeyspaceNotification(&redis.Message{Channel: "__keyspace@x__:session:1", Payload: "expired"}, now)
+	assert.False(t, ok)
+}
+
+func TestKeyspaceSubscriptionMaxEvents(t *testing.T) {
+	messages := make(chan *redis.Message, 4)
+	messages <- &redis.Message{Channel: "__keyspace@0__:a", Payload: "expired"}
+	messages <- &redis.Message{Channel: "__keyspace@0__:b", Payload: "set"}
+	messages <- &redis.Message{Channel: "__keyspace@0__:c", Payload: "evicted"}
+	messages <- &redis.Message{Channel: "__keyspace@0__:d", Payload: "expired"}
+	close(messages)
+
+	s := &keyspaceSubscription{}
+	s.receive(messages, map[string]bool{"expired": true, "evicted": true}, 2)
+	events, dropped, done := s.take()
+	require.Len(t, events, 2)
+	assert.Equal(t, "a", events[0].key)
+	assert.Equal(t, "c", events[1].key)
+	assert.Equal(t, 1, dropped, "events that are not emitted are not dropped")
+	assert.True(t, done)
+
+	events, dropped, _ = s.take()
+	assert.Empty(t, events)
+	assert.Zero(t, dropped)
+}
+
+// Delivers the messages sent to its channel, one subscription at a time.
+type keyspaceClient struct {
+	fakeClient
+	patterns      []string
+	messages      chan *redis.Message
+	subscriptions int
+	err           error
+}
+
+func (c *keyspaceClient) psubscribe(patterns []string) (<-chan *redis.Message, func() error, error) {
+	if c.err != nil {
+		return nil, nil, c.err
+	}
+	c.patterns = patterns
+	c.subscriptions++
+	c.messages = make(chan *redis.Message)
+	messages := c.messages
+	return messages, func() error {
+		close(messages)
+		return nil
+	}, nil
+}
+
+func TestKeyspaceEventsRunnable(t *testing.T) {
+	c := &keyspaceClient{}
+	consumer := new(consumertest.LogsSink)
+	nodes := staticNodes{newRedisNode(c, map[string]string{nodeAddressAttribute: "localhost:6379"})}
+	cfg := KeyspaceNotificationsConfig{Enabled: true, Patterns: []string{"session:*"}, MaxEvents: 10}
+	runner := newKeyspaceEventsRunnable(context.Background(), config.NewID(typeStr), nodes, map[string]string{serviceNameAttribute: "my-redis"}, cfg, consumer, zap.NewNop())
+
+	require.NoError(t, runner.Run())
+	assert.Equal(t, []string{"__keyspace@*__:session:*"}, c.patterns)
+	assert.Zero(t, consumer.LogRecordsCount())
+
+	c.messages <- &redis.Message{Channel: "__keyspace@0__:session:1", Payload: "expired"}
+	c.messages <- &redis.Message{Channel: "__keyspace@0__:session:2", Payload: "del"}
+	c.messages <- &redis.Message{Channel: "__keyspace@1__:session:3", Payload: "evicted"}
+	require.Eventually(t, func() bool {
+		require.NoError(t, runner.Run())
+		return consumer.LogRecordsCount() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	rl := consumer.AllLogs()[0].ResourceLogs().At(0)
+	v, ok := rl.Resource().Attributes().Get(nodeAddressAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "localhost:6379", v.StringVal())
+	v, ok = rl.Resource().Attributes().Get(serviceNameAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "my-redis", v.StringVal())
+
+	var records []pdata.LogRecord
+	for _, ld := range consumer.AllLogs() {
+		logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < logs.Len(); i++ {
+			records = append(records, logs.At(i))
+		}
+	}
+	lr := records[len(records)-1]
+	assert.Equal(t, "redis.keyspace_notification", lr.Name())
+	assert.Equal(t, "evicted session:3", lr.Body().StringVal())
+	attrs := lr.Attributes()
+	v, _ = attrs.Get("db.redis.database_index")
+	assert.EqualValues(t, 1, v.IntVal())
+	v, _ = attrs.Get("redis.key")
+	assert.Equal(t, "session:3", v.StringVal())
+	v, _ = attrs.Get("redis.event")
+	assert.Equal(t, "evicted", v.StringVal())
+
+	// Ended subscriptions are replaced.
+	runner.mu.Lock()
+	_ = runner.subscriptions[nodes[0]].close()
+	runner.mu.Unlock()
+	require.Eventually(t, func() bool {
+		require.NoError(t, runner.Run())
+		return c.subscriptions == 2
+	}, time.Second, 10*time.Millisecond)
+
+	runner.close()
+	require.NoError(t, runner.Run())
+	assert.Equal(t, 2, c.subscriptions, "nodes are not subscribed to after close")
+}
+
+func TestKeyspaceEventsRunnableSubscribeError(t *testing.T) {
+	c := &keyspaceClient{err: errors.New("NOPERM")}
+	consumer := new(consumertest.LogsSink)
+	nodes := staticNodes{newRedisNode(c, nil)}
+	cfg := KeyspaceNotificationsConfig{Enabled: true, MaxEvents: 10}
+	runner := newKeyspaceEventsRunnable(context.Background(), config.NewID(typeStr), nodes, nil, cfg, consumer, zap.NewNop())
+
+	require.NoError(t, runner.Run())
+	assert.Empty(t, runner.subscriptions)
+	assert.Zero(t, consumer.LogRecordsCount())
+}
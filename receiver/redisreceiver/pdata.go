@@ -15,7 +15,13 @@
 package redisreceiver
 
 import (
+	"sort"
+	"strconv"
+	"time"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/internal/metadata"
 )
 
 func newResourceMetrics(ms pdata.MetricSlice, serviceName string) pdata.ResourceMetrics {
@@ -43,16 +49,36 @@ func initKeyspaceKeysMetric(k *keyspace, t *timeBundle, dest pdata.Metric) {
 	m := &redisMetric{
 		name:   "redis/db/keys",
 		labels: map[string]string{"db": k.db},
-		pdType: pdata.MetricDataTypeIntGauge,
 	}
 	initIntMetric(m, int64(k.keys), t, dest)
 }
 
+// Builds the number of keys of a database from DBSIZE, like the keyspace keys
+// metric.
+// Builds the metric reporting whether a node could be scraped. The node may
+// never have been scraped, so only the current time is used.
+func buildUpMetric(up bool, now time.Time, dest pdata.Metric) {
+	var value int64
+	if up {
+		value = 1
+	}
+	initIntMetric(&redisMetric{name: upMetricName}, value, &timeBundle{current: now}, dest)
+}
+
+// Builds the count of restarts, which starts at the first scrape of the node
+// rather than at the start of the server.
+func buildRestartsMetric(t *timeBundle, dest pdata.Metric) {
+	initIntMetric(&redisMetric{name: restartsMetricName}, t.restarts(), &timeBundle{serverStart: t.created, current: t.current}, dest)
+}
+
+func buildDBSizeMetric(db int, size int64, t *timeBundle, dest pdata.Metric) {
+	initKeyspaceKeysMetric(&keyspace{db: strconv.Itoa(db), keys: int(size)}, t, dest)
+}
+
 func initKeyspaceExpiresMetric(k *keyspace, t *timeBundle, dest pdata.Metric) {
 	m := &redisMetric{
 		name:   "redis/db/expires",
 		labels: map[string]string{"db": k.db},
-		pdType: pdata.MetricDataTypeIntGauge,
 	}
 	initIntMetric(m, int64(k.expires), t, dest)
 }
@@ -60,24 +86,123 @@ func initKeyspaceExpiresMetric(k *keyspace, t *timeBundle, dest pdata.Metric) {
 func initKeyspaceTTLMetric(k *keyspace, t *timeBundle, dest pdata.Metric) {
 	m := &redisMetric{
 		name:   "redis/db/avg_ttl",
-		units:  "ms",
 		labels: map[string]string{"db": k.db},
-		pdType: pdata.MetricDataTypeIntGauge,
 	}
 	initIntMetric(m, int64(k.avgTTL), t, dest)
 }
 
+func buildLatencyStatsMetrics(ls *latencyStats, t *timeBundle) pdata.MetricSlice {
+	percentiles := make([]string, 0, len(ls.percentiles))
+	for p := range ls.percentiles {
+		percentiles = append(percentiles, p)
+	}
+	sort.Strings(percentiles)
+
+	ms := pdata.NewMetricSlice()
+	for _, p := range percentiles {
+		m := &redisMetric{
+			name:   latencyStatsMetricName,
+			labels: map[string]string{"cmd": ls.cmd, "percentile": p},
+		}
+		initDoubleMetric(m, ls.percentiles[p], t, ms.AppendEmpty())
+	}
+	return ms
+}
+
+func initErrorStatsMetric(prefix string, count int64, t *timeBundle, dest pdata.Metric) {
+	m := &redisMetric{
+		name:   errorStatsMetricName,
+		labels: map[string]string{"prefix": prefix},
+	}
+	initIntMetric(m, count, t, dest)
+}
+
+// Builds the number of subscribers of each of the channels, in their order.
+func buildPubSubSubscriberMetrics(channels []string, counts map[string]int64, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	for _, channel := range channels {
+		initIntMetric(&redisMetric{
+			name:   pubSubSubscribersMetricName,
+			labels: map[string]string{"channel": channel},
+		}, counts[channel], t, ms.AppendEmpty())
+	}
+	return ms
+}
+
+// Builds the slow log length, the number of entries logged since the previous
+// run, and the longest duration among them, 0 if there are none.
+func buildSlowLogMetrics(length int64, newEntries []slowLogEntry, t *timeBundle) pdata.MetricSlice {
+	var maxDuration time.Duration
+	for _, e := range newEntries {
+		if e.duration > maxDuration {
+			maxDuration = e.duration
+		}
+	}
+
+	ms := pdata.NewMetricSlice()
+	initIntMetric(&redisMetric{
+		name: slowLogMetricNames[0],
+	}, length, t, ms.AppendEmpty())
+	initIntMetric(&redisMetric{
+		name: slowLogMetricNames[1],
+	}, int64(len(newEntries)), t, ms.AppendEmpty())
+	initIntMetric(&redisMetric{
+		name: slowLogMetricNames[2],
+	}, maxDuration.Microseconds(), t, ms.AppendEmpty())
+	return ms
+}
+
+// Builds the client counts by state, age and idle time buckets, with a point
+// for every state and bucket, and the number of tracking clients.
+func buildClientListMetrics(stats *clientStats, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	for _, state := range clientStates {
+		initIntMetric(&redisMetric{
+			name:   clientListMetricNames[0],
+			labels: map[string]string{"state": state},
+		}, stats.byState[state], t, ms.AppendEmpty())
+	}
+	for _, b := range clientTimeBuckets {
+		initIntMetric(&redisMetric{
+			name:   clientListMetricNames[1],
+			labels: map[string]string{"age": b.name},
+		}, stats.byAge[b.name], t, ms.AppendEmpty())
+	}
+	for _, b := range clientTimeBuckets {
+		initIntMetric(&redisMetric{
+			name:   clientListMetricNames[2],
+			labels: map[string]string{"idle": b.name},
+		}, stats.byIdle[b.name], t, ms.AppendEmpty())
+	}
+	initIntMetric(&redisMetric{
+		name: clientListMetricNames[3],
+	}, stats.tracking, t, ms.AppendEmpty())
+	return ms
+}
+
+// Builds the maximum number of clients, and the ratio of connected clients to
+// it.
+func buildClientSaturationMetrics(connected, max int64, t *timeBundle) pdata.MetricSlice {
+	ms := pdata.NewMetricSlice()
+	initIntMetric(&redisMetric{
+		name: clientSaturationMetricNames[0],
+	}, max, t, ms.AppendEmpty())
+	if max > 0 {
+		initDoubleMetric(&redisMetric{
+			name: clientSaturationMetricNames[1],
+		}, float64(connected)/float64(max), t, ms.AppendEmpty())
+	}
+	return ms
+}
+
 func initIntMetric(m *redisMetric, value int64, t *timeBundle, dest pdata.Metric) {
-	redisMetricToPDM(m, dest)
+	metadata.M.ByName(m.name).Init(dest)
 
 	var pt pdata.IntDataPoint
-	if m.pdType == pdata.MetricDataTypeIntGauge {
+	if dest.DataType() == pdata.MetricDataTypeIntGauge {
 		pt = dest.IntGauge().DataPoints().AppendEmpty()
-	} else if m.pdType == pdata.MetricDataTypeIntSum {
-		sum := dest.IntSum()
-		sum.SetIsMonotonic(m.isMonotonic)
-		sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
-		pt = sum.DataPoints().AppendEmpty()
+	} else if dest.DataType() == pdata.MetricDataTypeIntSum {
+		pt = dest.IntSum().DataPoints().AppendEmpty()
 		pt.SetStartTimestamp(pdata.TimestampFromTime(t.serverStart))
 	}
 	pt.SetValue(value)
@@ -86,26 +211,16 @@ func initIntMetric(m *redisMetric, value int64, t *timeBundle, dest pdata.Metric
 }
 
 func initDoubleMetric(m *redisMetric, value float64, t *timeBundle, dest pdata.Metric) {
-	redisMetricToPDM(m, dest)
+	metadata.M.ByName(m.name).Init(dest)
 
 	var pt pdata.DoubleDataPoint
-	if m.pdType == pdata.MetricDataTypeDoubleGauge {
+	if dest.DataType() == pdata.MetricDataTypeDoubleGauge {
 		pt = dest.DoubleGauge().DataPoints().AppendEmpty()
-	} else if m.pdType == pdata.MetricDataTypeDoubleSum {
-		sum := dest.DoubleSum()
-		sum.SetIsMonotonic(m.isMonotonic)
-		sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
-		pt = sum.DataPoints().AppendEmpty()
+	} else if dest.DataType() == pdata.MetricDataTypeDoubleSum {
+		pt = dest.DoubleSum().DataPoints().AppendEmpty()
 		pt.SetStartTimestamp(pdata.TimestampFromTime(t.serverStart))
 	}
 	pt.SetValue(value)
 	pt.SetTimestamp(pdata.TimestampFromTime(t.current))
 	pt.LabelsMap().InitFromMap(m.labels)
 }
-
-func redisMetricToPDM(m *redisMetric, dest pdata.Metric) {
-	dest.SetDataType(m.pdType)
-	dest.SetName(m.name)
-	dest.SetDescription(m.desc)
-	dest.SetUnit(m.units)
-}
@@ -95,17 +95,48 @@ func TestMissingMetric(t *testing.T) {
 }
 
 func TestAllMetrics(t *testing.T) {
-	redisMetrics := getDefaultRedisMetrics()
+	redisMetrics := getRedisMetrics()
 	ms, warnings, err := testFetchMetrics(redisMetrics)
 	require.NoError(t, err)
 	require.Nil(t, warnings)
 	require.Equal(t, len(redisMetrics), ms.Len())
 }
 
+func TestKeyspaceMetricsSkipsMissingDbs(t *testing.T) {
+	info := info{
+		"db0": "keys=1,expires=2,avg_ttl=3",
+		"db3": "keys=4,expires=5,avg_ttl=6",
+	}
+	ms, errs := info.buildKeyspaceMetrics(testTimeBundle(), nil)
+	require.Nil(t, errs)
+	assert.Equal(t, 6, ms.Len())
+}
+
+func TestKeyspaceMetricsFilter(t *testing.T) {
+	info := info{
+		"db0": "keys=1,expires=2,avg_ttl=3",
+		"db3": "keys=4,expires=5,avg_ttl=6",
+		"db5": "keys=7,expires=8,avg_ttl=9",
+	}
+	include := (&KeyspaceConfig{Databases: []int{0, 3}}).includes
+	ms, errs := info.buildKeyspaceMetrics(testTimeBundle(), include)
+	require.Nil(t, errs)
+	require.Equal(t, 6, ms.Len())
+	db, _ := ms.At(3).IntGauge().DataPoints().At(0).LabelsMap().Get("db")
+	assert.Equal(t, "3", db)
+
+	include = (&KeyspaceConfig{ExcludeDatabases: []int{3}}).includes
+	ms, errs = info.buildKeyspaceMetrics(testTimeBundle(), include)
+	require.Nil(t, errs)
+	require.Equal(t, 6, ms.Len())
+	db, _ = ms.At(3).IntGauge().DataPoints().At(0).LabelsMap().Get("db")
+	assert.Equal(t, "5", db)
+}
+
 func TestKeyspaceMetrics(t *testing.T) {
 	svc := newRedisSvc(newFakeClient())
 	info, _ := svc.info()
-	ms, errs := info.buildKeyspaceMetrics(testTimeBundle())
+	ms, errs := info.buildKeyspaceMetrics(testTimeBundle(), nil)
 	require.Nil(t, errs)
 
 	// 2 dbs * 3 metrics each
@@ -155,19 +186,19 @@ func TestNewPDM(t *testing.T) {
 	tb := testTimeBundle()
 
 	pdm := pdata.NewMetric()
-	initIntMetric(&redisMetric{pdType: pdata.MetricDataTypeIntGauge}, 0, tb, pdm)
+	initIntMetric(&redisMetric{name: "redis/db/keys"}, 0, tb, pdm)
 	assert.Equal(t, pdata.Timestamp(0), pdm.IntGauge().DataPoints().At(0).StartTimestamp())
 
 	pdm = pdata.NewMetric()
-	initIntMetric(&redisMetric{pdType: pdata.MetricDataTypeIntSum}, 0, tb, pdm)
+	initIntMetric(&redisMetric{name: "redis/uptime"}, 0, tb, pdm)
 	assert.Equal(t, serverStartTime, pdm.IntSum().DataPoints().At(0).StartTimestamp())
 
 	pdm = pdata.NewMetric()
-	initDoubleMetric(&redisMetric{pdType: pdata.MetricDataTypeDoubleGauge}, 0, tb, pdm)
+	initDoubleMetric(&redisMetric{name: "redis/memory/fragmentation_ratio"}, 0, tb, pdm)
 	assert.Equal(t, pdata.Timestamp(0), pdm.DoubleGauge().DataPoints().At(0).StartTimestamp())
 
 	pdm = pdata.NewMetric()
-	initDoubleMetric(&redisMetric{pdType: pdata.MetricDataTypeDoubleSum}, 0, tb, pdm)
+	initDoubleMetric(&redisMetric{name: "redis/cpu/time"}, 0, tb, pdm)
 	assert.Equal(t, serverStartTime, pdm.DoubleSum().DataPoints().At(0).StartTimestamp())
 }
 
@@ -209,3 +240,62 @@ func testGetMetricDataErr(metric *redisMetric, serverName string) (pdata.Resourc
 func testTimeBundle() *timeBundle {
 	return newTimeBundle(time.Unix(1000, 0), 100)
 }
+
+func TestPubSubSubscriberMetrics(t *testing.T) {
+	counts := map[string]int64{"events": 3}
+	ms := buildPubSubSubscriberMetrics([]string{"events", "alerts"}, counts, testTimeBundle())
+	require.Equal(t, 2, ms.Len())
+	for i, e := range []struct {
+		channel string
+		value   int64
+	}{{"events", 3}, {"alerts", 0}} {
+		assert.Equal(t, "redis/pubsub/subscribers", ms.At(i).Name())
+		pt := ms.At(i).IntGauge().DataPoints().At(0)
+		channel, _ := pt.LabelsMap().Get("channel")
+		assert.Equal(t, e.channel, channel)
+		assert.Equal(t, e.value, pt.Value())
+	}
+}
+
+func TestBuildPresentMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, _ := svc.info()
+	delete(info, "allocator_active")
+	ms, warnings := info.buildPresentMetrics(getAllocatorMetrics(), testTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, len(getAllocatorMetrics())-1, ms.Len())
+	for i := 0; i < ms.Len(); i++ {
+		assert.NotEqual(t, "redis/memory/allocator/active", ms.At(i).Name())
+	}
+	assert.Equal(t, "redis/memory/allocator/resident", ms.At(1).Name())
+	assert.Equal(t, int64(8687616), ms.At(1).IntGauge().DataPoints().At(0).Value())
+}
+
+func TestBuildTrackingMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	info, _ := svc.info()
+	// Redis 5 has no client side caching.
+	ms, warnings := info.buildPresentMetrics(getTrackingMetrics(), testTimeBundle())
+	require.Nil(t, warnings)
+	assert.Equal(t, 0, ms.Len())
+
+	info["tracking_clients"] = "2"
+	info["tracking_total_keys"] = "120"
+	info["tracking_total_items"] = "150"
+	info["tracking_total_prefixes"] = "0"
+	ms, warnings = info.buildPresentMetrics(getTrackingMetrics(), testTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, 4, ms.Len())
+	for i, e := range []struct {
+		name  string
+		value int64
+	}{
+		{"redis/tracking/clients", 2},
+		{"redis/tracking/keys", 120},
+		{"redis/tracking/items", 150},
+		{"redis/tracking/prefixes", 0},
+	} {
+		assert.Equal(t, e.name, ms.At(i).Name())
+		assert.Equal(t, e.value, ms.At(i).IntGauge().DataPoints().At(0).Value())
+	}
+}
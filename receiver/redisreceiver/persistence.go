@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The names of the metrics built from the persistence section of INFO, beyond
+// the fixed ones.
+var persistenceMetricNames = []string{
+	"redis/rdb/last_save_age",
+	"redis/rdb/bgsave_in_progress",
+	"redis/rdb/last_bgsave_ok",
+	"redis/rdb/last_bgsave_duration",
+	"redis/rdb/current_bgsave_duration",
+	"redis/aof/enabled",
+	"redis/aof/rewrite_in_progress",
+	"redis/aof/last_rewrite_ok",
+	"redis/aof/last_write_ok",
+	"redis/aof/last_rewrite_duration",
+	"redis/aof/current_size",
+	"redis/aof/base_size",
+}
+
+// Builds the metrics of RDB snapshots and of the AOF from the persistence
+// section of INFO. Durations are left out when there was no such operation,
+// which Redis reports as -1, and the AOF sizes when the AOF is disabled.
+// Returns metrics and parsing errors, to be treated as warnings, if there were
+// any.
+func (i info) buildPersistenceMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+
+	if str, ok := i["rdb_last_save_time"]; ok {
+		lastSave, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			warnings = append(warnings, err)
+		} else {
+			initIntMetric(&redisMetric{
+				name: persistenceMetricNames[0],
+			}, t.current.Unix()-lastSave, t, outMS.AppendEmpty())
+		}
+	}
+
+	for _, m := range []*redisMetric{
+		{
+			key:  "rdb_bgsave_in_progress",
+			name: persistenceMetricNames[1],
+		},
+		{
+			key:  "rdb_last_bgsave_time_sec",
+			name: persistenceMetricNames[3],
+		},
+		{
+			key:  "rdb_current_bgsave_time_sec",
+			name: persistenceMetricNames[4],
+		},
+		{
+			key:  "aof_enabled",
+			name: persistenceMetricNames[5],
+		},
+		{
+			key:  "aof_rewrite_in_progress",
+			name: persistenceMetricNames[6],
+		},
+		{
+			key:  "aof_last_rewrite_time_sec",
+			name: persistenceMetricNames[9],
+		},
+		{
+			key:  "aof_current_size",
+			name: persistenceMetricNames[10],
+		},
+		{
+			key:  "aof_base_size",
+			name: persistenceMetricNames[11],
+		},
+	} {
+		str, ok := i[m.key]
+		if !ok || str == "-1" {
+			continue
+		}
+		pdm, err := m.parseMetric(str, t)
+		if err != nil {
+			warnings = append(warnings, err)
+			continue
+		}
+		outMS.Append(pdm)
+	}
+
+	for _, s := range []struct {
+		key  string
+		name string
+	}{
+		{"rdb_last_bgsave_status", persistenceMetricNames[2]},
+		{"aof_last_bgrewrite_status", persistenceMetricNames[7]},
+		{"aof_last_write_status", persistenceMetricNames[8]},
+	} {
+		status, ok := i[s.key]
+		if !ok {
+			continue
+		}
+		statusOK := int64(0)
+		if status == "ok" {
+			statusOK = 1
+		}
+		initIntMetric(&redisMetric{
+			name: s.name,
+		}, statusOK, t, outMS.AppendEmpty())
+	}
+	return outMS, warnings
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPersistenceMetrics(t *testing.T) {
+	info := info{
+		"rdb_last_save_time":          "900",
+		"rdb_bgsave_in_progress":      "1",
+		"rdb_last_bgsave_status":      "err",
+		"rdb_last_bgsave_time_sec":    "3",
+		"rdb_current_bgsave_time_sec": "2",
+		"aof_enabled":                 "1",
+		"aof_rewrite_in_progress":     "0",
+		"aof_last_rewrite_time_sec":   "-1",
+		"aof_last_bgrewrite_status":   "ok",
+		"aof_last_write_status":       "ok",
+		"aof_current_size":            "4096",
+		"aof_base_size":               "1024",
+	}
+	ms, warnings := info.buildPersistenceMetrics(newTimeBundle(time.Unix(1000, 0), 100))
+	require.Nil(t, warnings)
+
+	values := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		values[ms.At(i).Name()] = ms.At(i).IntGauge().DataPoints().At(0).Value()
+	}
+	assert.Equal(t, map[string]int64{
+		"redis/rdb/last_save_age":           100,
+		"redis/rdb/bgsave_in_progress":      1,
+		"redis/rdb/last_bgsave_ok":          0,
+		"redis/rdb/last_bgsave_duration":    3,
+		"redis/rdb/current_bgsave_duration": 2,
+		"redis/aof/enabled":                 1,
+		"redis/aof/rewrite_in_progress":     0,
+		"redis/aof/last_rewrite_ok":         1,
+		"redis/aof/last_write_ok":           1,
+		"redis/aof/current_size":            4096,
+		"redis/aof/base_size":               1024,
+	}, values)
+}
+
+func TestBuildPersistenceMetricsWarnings(t *testing.T) {
+	info := info{"rdb_last_save_time": "never", "aof_enabled": "yes"}
+	ms, warnings := info.buildPersistenceMetrics(testTimeBundle())
+	assert.Len(t, warnings, 2)
+	assert.Equal(t, 0, ms.Len())
+}
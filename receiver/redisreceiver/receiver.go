@@ -16,45 +16,68 @@ package redisreceiver
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/go-redis/redis/v7"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
 )
 
+const unixScheme = "unix://"
+
+// Emits the slow log entries of Redis, and optionally its keyspace
+// notifications, as log records. Metrics are scraped by
+// the scraper controller, see redisScraper.
 type redisReceiver struct {
 	logger         *zap.Logger
 	config         *Config
-	consumer       consumer.Metrics
+	logsConsumer   consumer.Logs
 	intervalRunner *interval.Runner
+	nodes          nodeSource
+	keyspaceEvents *keyspaceEventsRunnable
 }
 
-func newRedisReceiver(
+func newRedisLogsReceiver(
 	logger *zap.Logger,
 	config *Config,
-	consumer consumer.Metrics,
+	logsConsumer consumer.Logs,
 ) *redisReceiver {
 	return &redisReceiver{
-		logger:   logger,
-		config:   config,
-		consumer: consumer,
+		logger:       logger,
+		config:       config,
+		logsConsumer: logsConsumer,
 	}
 }
 
 // Set up and kick off the interval runner.
 func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
-	c := newRedisClient(&redis.Options{
-		Addr:     r.config.Endpoint,
-		Password: r.config.Password,
-	})
-	redisRunnable := newRedisRunnable(ctx, r.config.ID(), c, r.config.ServiceName, r.consumer, r.logger)
-	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
+	nodes, err := r.config.nodeSource()
+	if err != nil {
+		return err
+	}
+	r.nodes = nodes
+
+	runnables := []interval.Runnable{
+		newSlowLogRunnable(ctx, r.config.ID(), r.nodes, r.config.resourceAttributes(), r.config.SlowLog.MaxEntries, r.logsConsumer, r.logger),
+	}
+	if r.config.KeyspaceNotifications.Enabled {
+		r.keyspaceEvents = newKeyspaceEventsRunnable(ctx, r.config.ID(), r.nodes, r.config.resourceAttributes(), r.config.KeyspaceNotifications, r.logsConsumer, r.logger)
+		runnables = append(runnables, r.keyspaceEvents)
+	}
+	r.intervalRunner = interval.NewRunner(interval.Settings{
+		Interval:     r.config.CollectionInterval,
+		InitialDelay: r.config.InitialDelay,
+	}, runnables...)
 
 	go func() {
-		if err := r.intervalRunner.Start(); err != nil {
+		if err := r.intervalRunner.Start(context.Background()); err != nil {
 			host.ReportFatalError(err)
 		}
 	}()
@@ -63,6 +86,226 @@ func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
 }
 
 func (r *redisReceiver) Shutdown(ctx context.Context) error {
-	r.intervalRunner.Stop()
+	if r.intervalRunner != nil {
+		// Waits for the current run, so that the nodes are not closed while in use.
+		if err := r.intervalRunner.Stop(ctx); err != nil {
+			return err
+		}
+	}
+	if r.keyspaceEvents != nil {
+		r.keyspaceEvents.close()
+	}
+	if r.nodes != nil {
+		return r.nodes.close()
+	}
 	return nil
 }
+
+// Returns the nodes to scrape: the master found through Sentinel, the nodes of
+// the cluster, or the configured endpoints.
+func (cfg *Config) nodeSource() (nodeSource, error) {
+	passwordSource, err := cfg.passwordSource()
+	if err != nil {
+		return nil, err
+	}
+	password, err := currentPassword(cfg.Password, passwordSource)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := loadTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cfg.Sentinel != nil:
+		return cfg.sentinelNodes(password, passwordSource, tlsConfig), nil
+	case cfg.Cluster != nil:
+		return cfg.clusterNodes(password, passwordSource, tlsConfig), nil
+	default:
+		nodes, err := cfg.staticNodes(password, passwordSource, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		return nodes, nil
+	}
+}
+
+// Returns the endpoint of the receiver and the additional endpoints. When
+// there is more than one, the metrics of each carry its address.
+func (cfg *Config) staticNodes(password string, passwordSource func() (string, error), tlsConfig *tls.Config) (staticNodes, error) {
+	var nodes staticNodes
+	if cfg.Endpoint != "" || len(cfg.Endpoints) == 0 {
+		var attributes map[string]string
+		if len(cfg.Endpoints) > 0 {
+			attributes = map[string]string{nodeAddressAttribute: cfg.Endpoint}
+		}
+		network, addr := parseEndpoint(cfg.Endpoint)
+		nodes = append(nodes, newRedisNode(newRedisClient(&redis.Options{
+			Network:      network,
+			Addr:         addr,
+			Username:     cfg.Username,
+			Password:     password,
+			TLSConfig:    tlsConfig,
+			DialTimeout:  cfg.Timeout,
+			ReadTimeout:  cfg.Timeout,
+			WriteTimeout: cfg.Timeout,
+		}, passwordSource, cfg.commandNames()), attributes))
+	}
+
+	for _, e := range cfg.Endpoints {
+		node, err := cfg.endpointNode(e, password, passwordSource, tlsConfig)
+		if err != nil {
+			_ = nodes.close()
+			return nil, fmt.Errorf("endpoint %s: %w", e.Endpoint, err)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// Creates the node of an additional endpoint, falling back to the settings of
+// the receiver for those it does not set.
+func (cfg *Config) endpointNode(e EndpointConfig, password string, passwordSource func() (string, error), tlsConfig *tls.Config) (*redisNode, error) {
+	username := e.Username
+	if username == "" {
+		username = cfg.Username
+	}
+	if e.hasPassword() {
+		var err error
+		if passwordSource, err = newPasswordSource(e.Password, e.PasswordFile, e.PasswordEnv); err != nil {
+			return nil, err
+		}
+		if password, err = currentPassword(e.Password, passwordSource); err != nil {
+			return nil, err
+		}
+	}
+	if e.TLS != nil {
+		var err error
+		if tlsConfig, err = loadTLSConfig(e.TLS); err != nil {
+			return nil, err
+		}
+	}
+
+	attributes := make(map[string]string, len(e.ResourceAttributes)+2)
+	for k, v := range e.ResourceAttributes {
+		attributes[k] = v
+	}
+	attributes[nodeAddressAttribute] = e.Endpoint
+	switch {
+	case e.ServiceName != "":
+		attributes[serviceNameAttribute] = e.ServiceName
+	case cfg.ServiceName == "":
+		attributes[serviceNameAttribute] = endpointServiceName(e.Endpoint)
+	}
+	network, addr := parseEndpoint(e.Endpoint)
+	return newRedisNode(newRedisClient(&redis.Options{
+		Network:      network,
+		Addr:         addr,
+		Username:     username,
+		Password:     password,
+		TLSConfig:    tlsConfig,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+	}, passwordSource, cfg.commandNames()), attributes), nil
+}
+
+func (cfg *Config) sentinelNodes(password string, passwordSource func() (string, error), tlsConfig *tls.Config) *sentinelNodes {
+	sentinel := cfg.Sentinel
+	master := newFailoverRedisClient(&redis.FailoverOptions{
+		MasterName:       sentinel.MasterName,
+		SentinelAddrs:    sentinel.Addresses,
+		SentinelUsername: sentinel.Username,
+		SentinelPassword: sentinel.Password,
+		Username:         cfg.Username,
+		Password:         password,
+		TLSConfig:        tlsConfig,
+		DialTimeout:      cfg.Timeout,
+		ReadTimeout:      cfg.Timeout,
+		WriteTimeout:     cfg.Timeout,
+	}, passwordSource, cfg.commandNames())
+
+	var newReplicaClient func(addr string) client
+	if sentinel.Replicas {
+		newReplicaClient = func(addr string) client {
+			return newRedisClient(&redis.Options{
+				Addr:         addr,
+				Username:     cfg.Username,
+				Password:     password,
+				TLSConfig:    tlsConfig,
+				DialTimeout:  cfg.Timeout,
+				ReadTimeout:  cfg.Timeout,
+				WriteTimeout: cfg.Timeout,
+			}, passwordSource, cfg.commandNames())
+		}
+	}
+
+	nodes := newSentinelNodes(sentinel.MasterName, newRedisSentinel(sentinel, cfg.Timeout), master, newReplicaClient)
+	nodes.monitorSentinels = sentinel.Monitor
+	return nodes
+}
+
+func (cfg *Config) clusterNodes(password string, passwordSource func() (string, error), tlsConfig *tls.Config) *clusterNodes {
+	newClient := func(addr string) *redisClient {
+		return newRedisClient(&redis.Options{
+			Addr:         addr,
+			Username:     cfg.Username,
+			Password:     password,
+			TLSConfig:    tlsConfig,
+			DialTimeout:  cfg.Timeout,
+			ReadTimeout:  cfg.Timeout,
+			WriteTimeout: cfg.Timeout,
+		}, passwordSource, cfg.commandNames()).(*redisClient)
+	}
+
+	topology := &redisClusterTopology{}
+	for _, addr := range cfg.Cluster.Addresses {
+		topology.seeds = append(topology.seeds, newClient(addr))
+	}
+	return newClusterNodes(topology, func(addr string) client {
+		return newClient(addr)
+	})
+}
+
+// Returns the network and address of an endpoint, either host:port or a Unix
+// domain socket given as unix:///path/to/redis.sock.
+func parseEndpoint(endpoint string) (network, addr string) {
+	if strings.HasPrefix(endpoint, unixScheme) {
+		return "unix", strings.TrimPrefix(endpoint, unixScheme)
+	}
+	return "tcp", endpoint
+}
+
+// Returns the password to connect with, read from passwordSource if set.
+func currentPassword(password string, passwordSource func() (string, error)) (string, error) {
+	if passwordSource == nil {
+		return password, nil
+	}
+	return passwordSource()
+}
+
+// Returns the TLS configuration of the settings, nil if TLS is not enabled.
+// The client certificate, if any, is loaded again on each handshake, so that
+// rotating it does not require restarting the collector.
+func loadTLSConfig(settings *configtls.TLSClientSetting) (*tls.Config, error) {
+	if settings == nil {
+		return nil, nil
+	}
+	tlsConfig, err := settings.LoadTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS config: %w", err)
+	}
+	if tlsConfig != nil && settings.CertFile != "" {
+		certFile, keyFile := settings.CertFile, settings.KeyFile
+		tlsConfig.Certificates = nil
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(filepath.Clean(certFile), filepath.Clean(keyFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
+			}
+			return &cert, nil
+		}
+	}
+	return tlsConfig, nil
+}
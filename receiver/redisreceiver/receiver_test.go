@@ -0,0 +1,230 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+func TestStaticNodes(t *testing.T) {
+	require.NoError(t, os.Setenv("TEST_REDIS_PASSWORD", "from-env"))
+	defer os.Unsetenv("TEST_REDIS_PASSWORD")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "redis-1:6379"
+	cfg.Username = "monitoring"
+	cfg.Password = "secret"
+	cfg.Endpoints = []EndpointConfig{
+		{Endpoint: "redis-2:6379"},
+		{
+			Endpoint:           "redis-3:6379",
+			ServiceName:        "other-redis",
+			ResourceAttributes: map[string]string{"team": "payments", nodeAddressAttribute: "ignored"},
+			Username:           "other",
+			PasswordEnv:        "TEST_REDIS_PASSWORD",
+			TLS:                &configtls.TLSClientSetting{},
+		},
+	}
+
+	passwordSource, err := cfg.passwordSource()
+	require.NoError(t, err)
+	nodes, err := cfg.staticNodes(cfg.Password, passwordSource, nil)
+	require.NoError(t, err)
+	defer nodes.close()
+	require.Len(t, nodes, 3)
+
+	assert.Equal(t, map[string]string{nodeAddressAttribute: "redis-1:6379"}, nodes[0].attributes)
+	// Without a service name, it is derived from the endpoint.
+	assert.Equal(t, map[string]string{
+		nodeAddressAttribute: "redis-2:6379",
+		serviceNameAttribute: "redis://redis-2:6379",
+	}, nodes[1].attributes)
+	assert.Equal(t, map[string]string{
+		nodeAddressAttribute: "redis-3:6379",
+		serviceNameAttribute: "other-redis",
+		"team":               "payments",
+	}, nodes[2].attributes)
+
+	inherited := nodes[1].client.(*redisClient).client.Options()
+	assert.Equal(t, "monitoring", inherited.Username)
+	assert.Equal(t, "secret", inherited.Password)
+	assert.Nil(t, inherited.TLSConfig)
+
+	own := nodes[2].client.(*redisClient).client.Options()
+	assert.Equal(t, "other", own.Username)
+	assert.Equal(t, "from-env", own.Password)
+	assert.NotNil(t, own.TLSConfig)
+}
+
+func TestStaticNodesSingleEndpoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:6379"
+
+	nodes, err := cfg.staticNodes("", nil, nil)
+	require.NoError(t, err)
+	defer nodes.close()
+	require.Len(t, nodes, 1)
+	assert.Nil(t, nodes[0].attributes)
+}
+
+func TestParseEndpoint(t *testing.T) {
+	network, addr := parseEndpoint("localhost:6379")
+	assert.Equal(t, "tcp", network)
+	assert.Equal(t, "localhost:6379", addr)
+
+	network, addr = parseEndpoint("unix:///var/run/redis/redis.sock")
+	assert.Equal(t, "unix", network)
+	assert.Equal(t, "/var/run/redis/redis.sock", addr)
+}
+
+func TestStaticNodesUnixSocket(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "unix:///var/run/redis/redis.sock"
+
+	nodes, err := cfg.staticNodes("", nil, nil)
+	require.NoError(t, err)
+	defer nodes.close()
+	options := nodes[0].client.(*redisClient).client.Options()
+	assert.Equal(t, "unix", options.Network)
+	assert.Equal(t, "/var/run/redis/redis.sock", options.Addr)
+}
+
+// Writes a self-signed certificate for name and its key in dir, and returns
+// their paths.
+func writeTestCertificate(t *testing.T, dir, name string) (certFile, keyFile string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".pem")
+	keyFile = filepath.Join(dir, name+"-key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+	return certFile, keyFile
+}
+
+func TestLoadTLSConfigClientCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redisreceiver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeTestCertificate(t, dir, "client")
+
+	tlsConfig, err := loadTLSConfig(&configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CertFile: certFile, KeyFile: keyFile},
+		ServerName: "redis.internal",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "redis.internal", tlsConfig.ServerName)
+
+	cert, err := tlsConfig.GetClientCertificate(nil)
+	require.NoError(t, err)
+	first, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	// A rotated certificate is picked up on the next handshake.
+	rotatedCert, rotatedKey := writeTestCertificate(t, dir, "rotated")
+	require.NoError(t, os.Rename(rotatedCert, certFile))
+	require.NoError(t, os.Rename(rotatedKey, keyFile))
+	cert, err = tlsConfig.GetClientCertificate(nil)
+	require.NoError(t, err)
+	rotated, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.NotEqual(t, first.SerialNumber, rotated.SerialNumber)
+	assert.Equal(t, "rotated", rotated.Subject.CommonName)
+
+	_, err = loadTLSConfig(&configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CertFile: certFile},
+	})
+	assert.Error(t, err, "a certificate needs its key")
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redisreceiver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	// The proxy in front of Redis presents a certificate for redis.internal.
+	serverCertFile, serverKeyFile := writeTestCertificate(t, dir, "redis.internal")
+	clientCertFile, clientKeyFile := writeTestCertificate(t, dir, "client")
+
+	serverCert, err := tls.LoadX509KeyPair(serverCertFile, serverKeyFile)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCA, err := ioutil.ReadFile(clientCertFile)
+	require.NoError(t, err)
+	require.True(t, clientCAs.AppendCertsFromPEM(clientCA))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	l = tls.NewListener(l, &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	defer l.Close()
+	serveRESP(t, l, func(args []string) string {
+		return "$15\r\nredis_version:6\r\n"
+	})
+
+	connect := func(settings configtls.TLSClientSetting) error {
+		tlsConfig, err := loadTLSConfig(&settings)
+		require.NoError(t, err)
+		c := newRedisClient(&redis.Options{Addr: l.Addr().String(), TLSConfig: tlsConfig, ReadTimeout: time.Second, MaxRetries: -1}, nil, nil)
+		defer c.close()
+		_, err = c.retrieveInfo()
+		return err
+	}
+
+	assert.NoError(t, connect(configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: serverCertFile, CertFile: clientCertFile, KeyFile: clientKeyFile},
+		ServerName: "redis.internal",
+	}))
+	// The address of the proxy is not in the certificate.
+	assert.Error(t, connect(configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: serverCertFile, CertFile: clientCertFile, KeyFile: clientKeyFile},
+	}))
+	// The server requires a client certificate.
+	assert.Error(t, connect(configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: serverCertFile},
+		ServerName: "redis.internal",
+	}))
+}
@@ -18,18 +18,18 @@ import (
 	"strconv"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/internal/metadata"
 )
 
 // An intermediate data type that allows us to define at startup which metrics to
 // convert (from the string-string map we get from redisSvc) and how to convert them.
+// The description, unit and type of the metric are those of its name in
+// metadata.yaml.
 type redisMetric struct {
-	key         string
-	name        string
-	units       string
-	desc        string
-	labels      map[string]string
-	pdType      pdata.MetricDataType
-	isMonotonic bool
+	key    string
+	name   string
+	labels map[string]string
 }
 
 // Parse a numeric string to build a metric based on this redisMetric. The
@@ -37,7 +37,8 @@ type redisMetric struct {
 func (m *redisMetric) parseMetric(strVal string, t *timeBundle) (pdata.Metric, error) {
 	var err error
 	pdm := pdata.NewMetric()
-	switch m.pdType {
+	metadata.M.ByName(m.name).Init(pdm)
+	switch pdm.DataType() {
 	case pdata.MetricDataTypeIntSum:
 		var val int64
 		val, err = strToInt64Point(strVal)
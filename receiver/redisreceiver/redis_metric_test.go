@@ -20,7 +20,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 func TestParseMetric_PointTimestamp(t *testing.T) {
@@ -50,13 +49,14 @@ func TestParseMetric_Labels(t *testing.T) {
 }
 
 func TestParseMetric_Errors(t *testing.T) {
-	for _, dataType := range []pdata.MetricDataType{
-		pdata.MetricDataTypeIntSum,
-		pdata.MetricDataTypeIntGauge,
-		pdata.MetricDataTypeDoubleSum,
-		pdata.MetricDataTypeDoubleGauge,
+	// One metric of each data type.
+	for _, name := range []string{
+		"redis/uptime",
+		"redis/db/keys",
+		"redis/cpu/time",
+		"redis/memory/fragmentation_ratio",
 	} {
-		m := redisMetric{pdType: dataType}
+		m := redisMetric{name: name}
 		_, err := m.parseMetric("foo", &timeBundle{})
 		assert.Error(t, err)
 	}
@@ -39,6 +39,20 @@ func (p *redisSvc) info() (info, error) {
 	if err != nil {
 		return nil, err
 	}
+	return p.parseInfo(str), nil
+}
+
+// Calls the Redis INFO command on the client along with the commands of req,
+// and returns an `info` map and the replies to the commands.
+func (p *redisSvc) infoBatch(req infoBatchRequest) (info, infoBatchReply, error) {
+	reply, err := retrieveInfoBatch(p.client, req)
+	if err != nil {
+		return nil, reply, err
+	}
+	return p.parseInfo(reply.info), reply, nil
+}
+
+func (p *redisSvc) parseInfo(str string) info {
 	lines := strings.Split(str, p.delimiter)
 	attrs := make(map[string]string)
 	for _, line := range lines {
@@ -50,5 +64,5 @@ func (p *redisSvc) info() (info, error) {
 			attrs[pair[0]] = pair[1]
 		}
 	}
-	return attrs, nil
+	return attrs
 }
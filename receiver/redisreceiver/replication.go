@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The names of the metrics built from the replication section of INFO, beyond
+// the fixed ones.
+var replicationMetricNames = []string{
+	"redis/replication/replica/offset",
+	"redis/replication/replica/offset_lag",
+	"redis/replication/replica/lag",
+	"redis/replication/replica/online",
+	"redis/replication/master_link_up",
+	"redis/replication/master_last_io",
+	"redis/replication/slave_offset",
+}
+
+// Holds a replica line of the Replication section of the INFO command of a
+// master: e.g. "slave0:ip=10.0.0.2,port=6379,state=online,offset=1234,lag=0"
+type replicaInfo struct {
+	addr   string
+	state  string
+	offset int64
+	// Seconds since the last ack of the replica.
+	lag int64
+}
+
+// Turns a replica value (the part after the colon
+// e.g. "ip=10.0.0.2,port=6379,state=online,offset=1234,lag=0") into a
+// replicaInfo struct
+func parseReplicaString(str string) (*replicaInfo, error) {
+	fields := make(map[string]string)
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.Split(pairStr, "=")
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("unexpected replica pair '%s'", pairStr)
+		}
+		fields[pair[0]] = pair[1]
+	}
+	if fields["ip"] == "" || fields["port"] == "" {
+		return nil, fmt.Errorf("replica without address '%s'", str)
+	}
+
+	ri := replicaInfo{
+		addr:  net.JoinHostPort(fields["ip"], fields["port"]),
+		state: fields["state"],
+	}
+	var err error
+	if ri.offset, err = strconv.ParseInt(fields["offset"], 10, 64); err != nil {
+		return nil, err
+	}
+	// lag is only reported by Redis 3 and later.
+	if lag, ok := fields["lag"]; ok {
+		if ri.lag, err = strconv.ParseInt(lag, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return &ri, nil
+}
+
+// Builds the metrics of each replica of a master, from the "slaveN" lines, and
+// of the link to the master of a replica. Returns metrics and parsing errors,
+// to be treated as warnings, if there were any.
+func (i info) buildReplicationMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+
+	masterOffset, masterOffsetErr := strconv.ParseInt(i["master_repl_offset"], 10, 64)
+	for n := 0; ; n++ {
+		str, ok := i["slave"+strconv.Itoa(n)]
+		if !ok {
+			break
+		}
+		ri, err := parseReplicaString(str)
+		if err != nil {
+			warnings = append(warnings, err)
+			continue
+		}
+		labels := map[string]string{"replica": ri.addr}
+		online := int64(0)
+		if ri.state == "online" {
+			online = 1
+		}
+		initIntMetric(&redisMetric{
+			name:   replicationMetricNames[0],
+			labels: labels,
+		}, ri.offset, t, outMS.AppendEmpty())
+		if masterOffsetErr == nil {
+			initIntMetric(&redisMetric{
+				name:   replicationMetricNames[1],
+				labels: labels,
+			}, masterOffset-ri.offset, t, outMS.AppendEmpty())
+		}
+		initIntMetric(&redisMetric{
+			name:   replicationMetricNames[2],
+			labels: labels,
+		}, ri.lag, t, outMS.AppendEmpty())
+		initIntMetric(&redisMetric{
+			name:   replicationMetricNames[3],
+			labels: labels,
+		}, online, t, outMS.AppendEmpty())
+	}
+
+	// Only replicas report the link to their master.
+	if status, ok := i["master_link_status"]; ok {
+		up := int64(0)
+		if status == "up" {
+			up = 1
+		}
+		initIntMetric(&redisMetric{
+			name: replicationMetricNames[4],
+		}, up, t, outMS.AppendEmpty())
+
+		for _, m := range []*redisMetric{
+			{
+				key:  "master_last_io_seconds_ago",
+				name: replicationMetricNames[5],
+			},
+			{
+				key:  "slave_repl_offset",
+				name: replicationMetricNames[6],
+			},
+		} {
+			str, ok := i[m.key]
+			if !ok {
+				continue
+			}
+			pdm, err := m.parseMetric(str, t)
+			if err != nil {
+				warnings = append(warnings, err)
+				continue
+			}
+			outMS.Append(pdm)
+		}
+	}
+	return outMS, warnings
+}
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestParseReplica(t *testing.T) {
+	ri, err := parseReplicaString("ip=10.0.0.2,port=6379,state=online,offset=1234,lag=1")
+	require.Nil(t, err)
+	require.Equal(t, &replicaInfo{addr: "10.0.0.2:6379", state: "online", offset: 1234, lag: 1}, ri)
+
+	for _, str := range []string{
+		"ip=10.0.0.2,port=6379,state=online,offset=x,lag=1",
+		"ip=10.0.0.2,state=online,offset=1234",
+		"ip=10.0.0.2,port",
+	} {
+		_, err = parseReplicaString(str)
+		require.NotNil(t, err, str)
+	}
+}
+
+// Returns the values of the metrics by name and replica label.
+func replicationValues(ms pdata.MetricSlice) map[string]int64 {
+	values := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		pt := ms.At(i).IntGauge().DataPoints().At(0)
+		replica, _ := pt.LabelsMap().Get("replica")
+		values[ms.At(i).Name()+" "+replica] = pt.Value()
+	}
+	return values
+}
+
+func TestBuildReplicationMetricsMaster(t *testing.T) {
+	inf := info{
+		"role":               "master",
+		"master_repl_offset": "2000",
+		"slave0":             "ip=10.0.0.2,port=6379,state=online,offset=2000,lag=0",
+		"slave1":             "ip=10.0.0.3,port=6379,state=wait_bgsave,offset=1500,lag=3",
+		"slave3":             "ip=10.0.0.5,port=6379,state=online,offset=1,lag=0",
+	}
+	ms, warnings := inf.buildReplicationMetrics(newTimeBundle(time.Now(), 100))
+	assert.Nil(t, warnings)
+	assert.Equal(t, map[string]int64{
+		"redis/replication/replica/offset 10.0.0.2:6379":     2000,
+		"redis/replication/replica/offset_lag 10.0.0.2:6379": 0,
+		"redis/replication/replica/lag 10.0.0.2:6379":        0,
+		"redis/replication/replica/online 10.0.0.2:6379":     1,
+		"redis/replication/replica/offset 10.0.0.3:6379":     1500,
+		"redis/replication/replica/offset_lag 10.0.0.3:6379": 500,
+		"redis/replication/replica/lag 10.0.0.3:6379":        3,
+		"redis/replication/replica/online 10.0.0.3:6379":     0,
+	}, replicationValues(ms))
+}
+
+func TestBuildReplicationMetricsReplica(t *testing.T) {
+	inf := info{
+		"role":                       "slave",
+		"master_link_status":         "down",
+		"master_last_io_seconds_ago": "-1",
+		"slave_repl_offset":          "1500",
+	}
+	ms, warnings := inf.buildReplicationMetrics(newTimeBundle(time.Now(), 100))
+	assert.Nil(t, warnings)
+	assert.Equal(t, map[string]int64{
+		"redis/replication/master_link_up ": 0,
+		"redis/replication/master_last_io ": -1,
+		"redis/replication/slave_offset ":   1500,
+	}, replicationValues(ms))
+
+	ms, warnings = info{}.buildReplicationMetrics(newTimeBundle(time.Now(), 100))
+	assert.Nil(t, warnings)
+	assert.Equal(t, 0, ms.Len())
+}
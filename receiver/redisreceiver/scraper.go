@@ -0,0 +1,746 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+)
+
+// Fetches info from Redis and creates metrics/datapoints on each scrape of the
+// scraper controller.
+type redisScraper struct {
+	config         *Config
+	nodes          nodeSource
+	redisMetrics   []*redisMetric
+	clusterMetrics []*redisMetric
+	memoryMetrics  []*redisMetric
+	// The allocator and client side caching metrics, extracted when present.
+	allocatorMetrics []*redisMetric
+	trackingMetrics  []*redisMetric
+	logger           *zap.Logger
+	// The attributes added to every resource, including service.name.
+	resourceAttributes map[string]string
+	metricSettings     map[string]MetricSettings
+	// The keys of INFO the receiver knows, nil unless the other numeric fields
+	// are reported.
+	knownInfoKeys map[string]bool
+	// The databases queried with DBSIZE, nil if disabled.
+	dbSizeDatabases []int
+	// The pub/sub channels whose subscribers are counted.
+	pubSubChannels []string
+	// The monitored streams.
+	streams []string
+	// The monitored keys.
+	keys []string
+	// The key patterns counted with SCAN, and the SCAN budget.
+	keyPatterns KeyPatternsConfig
+	// The number of slow log entries fetched on each scrape.
+	slowLogMaxEntries int64
+	// The REST API of the Redis Enterprise cluster, nil unless it is scraped
+	// instead of the nodes.
+	enterprise *enterpriseClient
+	// The ticks of the scraper controller, and the channel closing them.
+	ticks chan time.Time
+	done  chan struct{}
+}
+
+// Builds a data structure of all of the keys, types, converters and such to
+// later extract data from Redis. The nodes are created on start.
+func newRedisScraper(config *Config, logger *zap.Logger) *redisScraper {
+	r := &redisScraper{
+		config:             config,
+		logger:             logger,
+		resourceAttributes: config.resourceAttributes(),
+		metricSettings:     config.Metrics,
+		pubSubChannels:     config.PubSub.Channels,
+		streams:            config.Streams,
+		keys:               config.Keys,
+		keyPatterns:        config.KeyPatterns,
+		slowLogMaxEntries:  config.SlowLog.MaxEntries,
+		ticks:              make(chan time.Time),
+		done:               make(chan struct{}),
+	}
+	if config.Info.UnknownFields {
+		r.knownInfoKeys = knownInfoKeys()
+	}
+	if config.DBSize.Enabled {
+		// Empty databases that are filtered out are not queried either.
+		r.dbSizeDatabases = []int{}
+		for _, db := range config.DBSize.databases() {
+			if config.Keyspace.includes(db) {
+				r.dbSizeDatabases = append(r.dbSizeDatabases, db)
+			}
+		}
+	}
+	r.redisMetrics = enabledMetrics(r.metricSettings, getRedisMetrics())
+	r.memoryMetrics = enabledMetrics(r.metricSettings, getMemoryStatsMetrics())
+	r.allocatorMetrics = enabledMetrics(r.metricSettings, getAllocatorMetrics())
+	r.trackingMetrics = enabledMetrics(r.metricSettings, getTrackingMetrics())
+	r.clusterMetrics = enabledMetrics(r.metricSettings, getClusterRedisMetrics())
+	return r
+}
+
+// Creates the nodes to scrape and starts the ticks of the scraper controller.
+func (r *redisScraper) start(_ context.Context, _ component.Host) error {
+	if r.config.Enterprise != nil {
+		enterprise, err := newEnterpriseClient(r.config.Enterprise, r.config.Timeout)
+		if err != nil {
+			return err
+		}
+		r.enterprise = enterprise
+		go r.tick(r.config.InitialDelay, r.config.CollectionInterval)
+		return nil
+	}
+	nodes, err := r.config.nodeSource()
+	if err != nil {
+		return err
+	}
+	r.nodes = nodes
+	go r.tick(r.config.InitialDelay, r.config.CollectionInterval)
+	return nil
+}
+
+func (r *redisScraper) shutdown(context.Context) error {
+	close(r.done)
+	if r.enterprise != nil {
+		r.enterprise.close()
+	}
+	if r.nodes != nil {
+		return r.nodes.close()
+	}
+	return nil
+}
+
+// Sends the ticks of the scraper controller. The controller of the collector
+// first scrapes after a whole collection interval, so the first tick is sent
+// after the initial delay instead.
+func (r *redisScraper) tick(initialDelay, interval time.Duration) {
+	timer := time.NewTimer(initialDelay)
+	defer timer.Stop()
+	var now time.Time
+	select {
+	case now = <-timer.C:
+	case <-r.done:
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case r.ticks <- now:
+		case <-r.done:
+			return
+		}
+		select {
+		case now = <-ticker.C:
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// Queries each Redis node and builds one ResourceMetrics per node, and one for
+// the cluster-level metrics in cluster mode. Nodes that cannot be scraped are
+// skipped. Skipped nodes, and sections of INFO that cannot be parsed, are
+// reported as a partial scrape error, with the metrics that were scraped.
+func (r *redisScraper) scrape(context.Context) (pdata.ResourceMetricsSlice, error) {
+	if r.enterprise != nil {
+		return r.scrapeEnterprise()
+	}
+	rms := pdata.NewResourceMetricsSlice()
+	nodes, err := r.nodes.nodes()
+	if err != nil {
+		return rms, err
+	}
+
+	var errs scrapererror.ScrapeErrors
+	var nodeErrs []error
+	for _, node := range nodes {
+		now := time.Now()
+		if node.backingOff(now) {
+			// Not attempted again before the backoff ends, reporting the last
+			// error.
+			err = fmt.Errorf("skipping redis node after %d failed attempts: %w", node.failures, node.lastErr)
+		} else if err = r.scrapeNode(node, rms, &errs); err != nil {
+			node.recordFailure(err, now, r.config.Reconnect)
+			if len(nodes) > 1 {
+				r.logger.Warn("failed to scrape redis node", zap.Any("node", node.attributes), zap.Error(err))
+			}
+		} else {
+			node.recordSuccess()
+		}
+		if err != nil {
+			nodeErrs = append(nodeErrs, err)
+			r.appendNodeDown(node, now, rms)
+		}
+	}
+	if c, ok := r.nodes.(clusterInfoSource); ok {
+		if err = r.scrapeCluster(c, rms, &errs); err != nil {
+			errs.AddPartial(len(r.clusterMetrics), fmt.Errorf("failed to scrape redis cluster info: %w", err))
+		}
+	}
+	if s, ok := r.nodes.(sentinelInstanceSource); ok {
+		r.scrapeSentinels(s, rms, &errs)
+	}
+
+	// Unless redis/up is reported for the nodes that failed, there is nothing
+	// to export.
+	if rms.Len() == 0 && len(nodeErrs) > 0 {
+		return rms, consumererror.Combine(nodeErrs)
+	}
+	for _, err := range nodeErrs {
+		// The metrics of the node are missing.
+		errs.AddPartial(len(r.redisMetrics), err)
+	}
+	return rms, errs.Combine()
+}
+
+// Queries a node and appends its metrics. First builds 'fixed' metrics
+// (non-keyspace metrics) defined at startup time. Then builds 'keyspace'
+// metrics if there are any keyspace lines returned by Redis. There should be
+// one keyspace line per active Redis database, of which there can be 16.
+// Returns an error if the node cannot be scraped, and adds the errors parsing
+// sections of INFO to errs. CLUSTER INFO, SLOWLOG LEN and DBSIZE are sent in
+// the same round trip as INFO.
+func (r *redisScraper) scrapeNode(node *redisNode, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) error {
+	// Without cluster settings, a node with cluster mode enabled reports the
+	// state of the cluster as it sees it.
+	_, isCluster := r.nodes.(clusterInfoSource)
+	nodeClusterInfo := !isCluster && len(r.clusterMetrics) > 0
+	req := infoBatchRequest{
+		section:     r.config.Info.Section,
+		clusterInfo: nodeClusterInfo && node.clusterEnabled && !node.commandUnavailable("cluster"),
+		slowLogLen:  r.anyEnabled(slowLogLenMetricNames) && !node.commandUnavailable("slowlog"),
+	}
+	if !node.commandUnavailable("dbsize") {
+		req.dbs = r.dbSizeDatabases
+	}
+	inf, batch, err := node.svc.infoBatch(req)
+	if err != nil {
+		return err
+	}
+
+	uptime, err := inf.getUptimeInSeconds()
+	if err != nil {
+		return err
+	}
+
+	node.role = infoRole(inf)
+	// Commands walking the keyspace are not run on replicas, unless enabled.
+	keyCommands := node.role != roleReplica || r.config.Replica.KeyCommands
+
+	if node.timeBundle == nil {
+		node.timeBundle = newTimeBundle(time.Now(), uptime)
+	} else if node.timeBundle.update(time.Now(), uptime) {
+		r.logger.Info("Detected a restart of the Redis server", zap.Any("node", node.attributes))
+	}
+
+	ilm := r.appendNodeResource(node, rms).InstrumentationLibraryMetrics().AppendEmpty()
+	if r.anyEnabled([]string{upMetricName}) {
+		buildUpMetric(true, node.timeBundle.current, ilm.Metrics().AppendEmpty())
+	}
+	if r.anyEnabled([]string{restartsMetricName}) {
+		buildRestartsMetric(node.timeBundle, ilm.Metrics().AppendEmpty())
+	}
+	fixedMS, warnings := inf.buildFixedMetrics(r.redisMetrics, node.timeBundle)
+	fixedMS.MoveAndAppendTo(ilm.Metrics())
+	addParseErrors(errs, "info", warnings)
+
+	keyspaceMS, warnings := inf.buildKeyspaceMetrics(node.timeBundle, r.config.Keyspace.includes)
+	addParseErrors(errs, "keyspace", warnings)
+	if missing := inf.missingKeyspaceDbs(r.dbSizeDatabases); len(missing) > 0 {
+		if batch.dbSizesErr != nil {
+			node.commandFailed(r.logger, "dbsize", "failed to fetch redis dbsize", batch.dbSizesErr)
+		} else if req.dbs != nil {
+			for _, db := range missing {
+				buildDBSizeMetric(db, batch.dbSizes[db], node.timeBundle, keyspaceMS.AppendEmpty())
+			}
+		}
+	}
+	r.removeDisabled(keyspaceMS)
+	keyspaceMS.MoveAndAppendTo(ilm.Metrics())
+
+	errorMS, warnings := inf.buildErrorStatsMetrics(node.timeBundle)
+	addParseErrors(errs, "errorstats", warnings)
+	r.removeDisabled(errorMS)
+	errorMS.MoveAndAppendTo(ilm.Metrics())
+
+	replicationMS, warnings := inf.buildReplicationMetrics(node.timeBundle)
+	addParseErrors(errs, "replication", warnings)
+	r.removeDisabled(replicationMS)
+	replicationMS.MoveAndAppendTo(ilm.Metrics())
+
+	node.clusterEnabled = inf["cluster_enabled"] == "1"
+	if nodeClusterInfo && node.clusterEnabled && !node.commandUnavailable("cluster") {
+		if !req.clusterInfo {
+			// Cluster mode was not enabled on the previous scrape.
+			batch.clusterInfo, batch.clusterInfoErr = node.client.retrieveClusterInfo()
+		}
+		if err := r.scrapeNodeClusterInfo(node, batch, ilm.Metrics(), errs); err != nil {
+			node.commandFailed(r.logger, "cluster", "failed to fetch redis cluster info", err)
+		}
+	}
+
+	allocatorMS, warnings := inf.buildPresentMetrics(r.allocatorMetrics, node.timeBundle)
+	addParseErrors(errs, "allocator", warnings)
+	allocatorMS.MoveAndAppendTo(ilm.Metrics())
+
+	trackingMS, warnings := inf.buildPresentMetrics(r.trackingMetrics, node.timeBundle)
+	addParseErrors(errs, "tracking", warnings)
+	trackingMS.MoveAndAppendTo(ilm.Metrics())
+
+	evictionMS, warnings := node.buildEvictionMetrics(inf)
+	addParseErrors(errs, "memory and eviction", warnings)
+	r.removeDisabled(evictionMS)
+	evictionMS.MoveAndAppendTo(ilm.Metrics())
+
+	expiredMS, warnings := node.buildExpiredRateMetric(inf)
+	addParseErrors(errs, "expired keys", warnings)
+	r.removeDisabled(expiredMS)
+	expiredMS.MoveAndAppendTo(ilm.Metrics())
+
+	persistenceMS, warnings := inf.buildPersistenceMetrics(node.timeBundle)
+	addParseErrors(errs, "persistence", warnings)
+	r.removeDisabled(persistenceMS)
+	persistenceMS.MoveAndAppendTo(ilm.Metrics())
+
+	latencyMS, warnings := inf.buildLatencyStatsMetrics(node.timeBundle)
+	addParseErrors(errs, "latencystats", warnings)
+	r.removeDisabled(latencyMS)
+	latencyMS.MoveAndAppendTo(ilm.Metrics())
+
+	if r.knownInfoKeys != nil {
+		inf.buildUnknownFieldMetrics(r.knownInfoKeys, node.timeBundle).MoveAndAppendTo(ilm.Metrics())
+	}
+
+	if len(r.memoryMetrics) > 0 && !node.commandUnavailable("memory") {
+		if err := r.scrapeMemoryStats(node, ilm.Metrics()); err != nil {
+			node.commandFailed(r.logger, "memory", "failed to fetch redis memory stats", err)
+		}
+	}
+
+	if r.anyEnabled(clientSaturationMetricNames) {
+		if err := r.scrapeClientSaturation(node, inf, ilm.Metrics()); err != nil {
+			node.commandFailed(r.logger, "config", "failed to fetch redis maxclients", err)
+		}
+	}
+
+	if r.anyEnabled(clientListMetricNames) && !node.commandUnavailable("client") {
+		if err := r.scrapeClientList(node, ilm.Metrics()); err != nil {
+			node.commandFailed(r.logger, "client", "failed to fetch redis client list", err)
+		}
+	}
+
+	if len(r.pubSubChannels) > 0 && r.anyEnabled([]string{pubSubSubscribersMetricName}) && !node.commandUnavailable("pubsub") {
+		if err := r.scrapePubSub(node, ilm.Metrics()); err != nil {
+			node.commandFailed(r.logger, "pubsub", "failed to fetch redis pubsub numsub", err)
+		}
+	}
+
+	if keyCommands && len(r.streams) > 0 && r.anyEnabled(streamMetricNames) && !node.commandUnavailable("xinfo") {
+		if err := r.scrapeStreams(node, ilm.Metrics()); err != nil {
+			node.commandFailed(r.logger, "xinfo", "failed to fetch redis stream info", err)
+		}
+	}
+
+	if keyCommands && len(r.keys) > 0 && r.anyEnabled(keyMetricNames) && !node.commandUnavailable("type") {
+		if err := r.scrapeKeys(node, ilm.Metrics()); err != nil {
+			node.commandFailed(r.logger, "type", "failed to fetch redis key stats", err)
+		}
+	}
+
+	if keyCommands && len(r.keyPatterns.Patterns) > 0 && r.anyEnabled(keyPatternMetricNames) && !node.commandUnavailable("scan") {
+		if err := r.scrapeKeyPatterns(node, ilm.Metrics()); err != nil {
+			node.commandFailed(r.logger, "scan", "failed to scan redis key patterns", err)
+		}
+	}
+
+	if r.anyEnabled(slowLogMetricNames) && !node.commandUnavailable("slowlog") {
+		slowLogMS, err := r.scrapeSlowLog(node, batch)
+		if err != nil {
+			// e.g. an ACL user without the slowlog command.
+			node.commandFailed(r.logger, "slowlog", "failed to fetch redis slowlog", err)
+		} else {
+			r.removeDisabled(slowLogMS)
+			slowLogMS.MoveAndAppendTo(ilm.Metrics())
+		}
+	}
+
+	if node.role == roleReplica && r.config.Replica.SuppressMasterMetrics {
+		removeMetrics(ilm.Metrics(), masterOnlyMetricNames)
+	}
+	r.convertMetrics(ilm.Metrics(), node.timeBundle.serverStart)
+
+	return nil
+}
+
+// Appends a resource identifying a node, with the role INFO last reported.
+func (r *redisScraper) appendNodeResource(node *redisNode, rms pdata.ResourceMetricsSlice) pdata.ResourceMetrics {
+	rm := r.appendResource(rms)
+	rattrs := rm.Resource().Attributes()
+	for k, v := range node.attributes {
+		rattrs.UpsertString(k, v)
+	}
+	if node.role != "" {
+		rattrs.UpsertString(nodeRoleAttribute, node.role)
+	}
+	return rm
+}
+
+// Appends a resource with the attributes of the receiver.
+func (r *redisScraper) appendResource(rms pdata.ResourceMetricsSlice) pdata.ResourceMetrics {
+	rm := rms.AppendEmpty()
+	rattrs := rm.Resource().Attributes()
+	for k, v := range r.resourceAttributes {
+		rattrs.InsertString(k, v)
+	}
+	return rm
+}
+
+// Appends the resource of a node that could not be scraped, with redis/up as
+// its only metric.
+func (r *redisScraper) appendNodeDown(node *redisNode, now time.Time, rms pdata.ResourceMetricsSlice) {
+	if !r.anyEnabled([]string{upMetricName}) {
+		return
+	}
+	ilm := r.appendNodeResource(node, rms).InstrumentationLibraryMetrics().AppendEmpty()
+	buildUpMetric(false, now, ilm.Metrics().AppendEmpty())
+	r.convertMetrics(ilm.Metrics(), now)
+}
+
+// Converts the metrics to the semantic conventions if enabled, with start as
+// the start of the sums converted from gauges.
+func (r *redisScraper) convertMetrics(ms pdata.MetricSlice, start time.Time) {
+	if r.config.SemanticConventions {
+		toSemanticConventions(ms, start)
+	}
+}
+
+// Adds the errors parsing a section of INFO as a partial scrape error, with
+// one missing metric per error.
+func addParseErrors(errs *scrapererror.ScrapeErrors, section string, warnings []error) {
+	if len(warnings) > 0 {
+		errs.AddPartial(len(warnings), fmt.Errorf("errors parsing %s: %w", section, consumererror.Combine(warnings)))
+	}
+}
+
+// Queries MEMORY STATS of a node and appends the memory stats metrics.
+func (r *redisScraper) scrapeMemoryStats(node *redisNode, dest pdata.MetricSlice) error {
+	stats, err := node.client.retrieveMemoryStats()
+	if err != nil {
+		return err
+	}
+	ms, warnings := stats.buildFixedMetrics(r.memoryMetrics, node.timeBundle)
+	// The keys vary between Redis versions, so missing keys are expected.
+	if warnings != nil {
+		r.logger.Debug(
+			"errors parsing redis memory stats",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
+	ms.MoveAndAppendTo(dest)
+	return nil
+}
+
+// Queries CLIENT LIST of a node and appends the client breakdown metrics.
+func (r *redisScraper) scrapeClientList(node *redisNode, dest pdata.MetricSlice) error {
+	str, err := node.client.retrieveClientList()
+	if err != nil {
+		return err
+	}
+	stats, err := parseClientList(str)
+	if err != nil {
+		return err
+	}
+	ms := buildClientListMetrics(stats, node.timeBundle)
+	r.removeDisabled(ms)
+	ms.MoveAndAppendTo(dest)
+	return nil
+}
+
+// Queries the number of subscribers of the pub/sub channels of a node.
+func (r *redisScraper) scrapePubSub(node *redisNode, dest pdata.MetricSlice) error {
+	counts, err := node.client.retrievePubSubNumSub(r.pubSubChannels)
+	if err != nil {
+		return err
+	}
+	buildPubSubSubscriberMetrics(r.pubSubChannels, counts, node.timeBundle).MoveAndAppendTo(dest)
+	return nil
+}
+
+// Queries the monitored streams of a node.
+func (r *redisScraper) scrapeStreams(node *redisNode, dest pdata.MetricSlice) error {
+	infos, err := node.client.retrieveStreams(r.streams)
+	if err != nil {
+		return err
+	}
+	ms, warnings := buildStreamMetrics(r.streams, infos, node.timeBundle)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing stream ids",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
+	r.removeDisabled(ms)
+	ms.MoveAndAppendTo(dest)
+	return nil
+}
+
+// Queries the monitored keys of a node.
+func (r *redisScraper) scrapeKeys(node *redisNode, dest pdata.MetricSlice) error {
+	stats, err := node.client.retrieveKeyStats(r.keys)
+	if err != nil {
+		return err
+	}
+	ms := buildKeyMetrics(r.keys, stats, node.timeBundle)
+	r.removeDisabled(ms)
+	ms.MoveAndAppendTo(dest)
+	return nil
+}
+
+// Counts the keys of a node matching each of the key patterns. The patterns
+// that were counted are reported even if another one fails.
+func (r *redisScraper) scrapeKeyPatterns(node *redisNode, dest pdata.MetricSlice) error {
+	counts := make(map[string]keyPatternCount, len(r.keyPatterns.Patterns))
+	var err error
+	for _, pattern := range r.keyPatterns.Patterns {
+		c, scanErr := node.client.retrieveKeyPatternCount(pattern, r.keyPatterns.Count, r.keyPatterns.MaxCalls)
+		if scanErr != nil {
+			err = scanErr
+			continue
+		}
+		counts[pattern] = c
+	}
+	ms := buildKeyPatternMetrics(r.keyPatterns.Patterns, counts, node.timeBundle)
+	r.removeDisabled(ms)
+	ms.MoveAndAppendTo(dest)
+	return err
+}
+
+// Builds the client saturation metrics of a node, from maxclients in INFO on
+// Redis 7 and later, or from CONFIG GET otherwise.
+func (r *redisScraper) scrapeClientSaturation(node *redisNode, inf info, dest pdata.MetricSlice) error {
+	connected, err := strconv.ParseInt(inf["connected_clients"], 10, 64)
+	if err != nil {
+		return err
+	}
+	var max int64
+	if str, ok := inf["maxclients"]; ok {
+		max, err = strconv.ParseInt(str, 10, 64)
+	} else if node.commandUnavailable("config") {
+		return nil
+	} else {
+		max, err = node.client.retrieveMaxClients()
+	}
+	if err != nil {
+		return err
+	}
+	ms := buildClientSaturationMetrics(connected, max, node.timeBundle)
+	r.removeDisabled(ms)
+	ms.MoveAndAppendTo(dest)
+	return nil
+}
+
+// Returns whether any of the metrics is enabled, to not run the command they
+// are built from otherwise.
+func (r *redisScraper) anyEnabled(names []string) bool {
+	for _, name := range names {
+		if metricEnabled(r.metricSettings, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Queries the slow log of a node and builds the slow log metrics, with the
+// length of the slow log sent along with INFO. SLOWLOG GET is only run if the
+// metrics of the new entries are enabled. Entries logged before the first run
+// are not counted as new.
+func (r *redisScraper) scrapeSlowLog(node *redisNode, batch infoBatchReply) (pdata.MetricSlice, error) {
+	length, err := batch.slowLogLen, batch.slowLogLenErr
+	if err != nil {
+		return pdata.MetricSlice{}, err
+	}
+	var newEntries []slowLogEntry
+	if r.anyEnabled(slowLogGetMetricNames) {
+		entries, err := node.client.retrieveSlowLog(r.slowLogMaxEntries)
+		if err != nil {
+			return pdata.MetricSlice{}, err
+		}
+		first := !node.seenSlowLogIDs
+		newEntries = node.newSlowLogEntries(entries)
+		if first {
+			newEntries = nil
+		}
+	}
+	return buildSlowLogMetrics(length, newEntries, node.timeBundle), nil
+}
+
+// Removes the metrics with the given names.
+func removeMetrics(ms pdata.MetricSlice, names []string) {
+	remove := make(map[string]bool, len(names))
+	for _, name := range names {
+		remove[name] = true
+	}
+	ms.RemoveIf(func(m pdata.Metric) bool {
+		return remove[m.Name()]
+	})
+}
+
+// Removes the metrics disabled in the settings, for metrics that are built
+// from INFO lines that vary rather than from redisMetrics.
+func (r *redisScraper) removeDisabled(ms pdata.MetricSlice) {
+	ms.RemoveIf(func(m pdata.Metric) bool {
+		return !metricEnabled(r.metricSettings, m.Name())
+	})
+}
+
+// Queries the state of the cluster and appends the cluster-level metrics, with
+// a resource that identifies the service but no node.
+func (r *redisScraper) scrapeCluster(c clusterInfoSource, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) error {
+	inf, err := c.clusterInfo()
+	if err != nil {
+		return err
+	}
+
+	rm := r.appendResource(rms)
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	// CLUSTER INFO has no uptime, and all cluster metrics are gauges.
+	t := newTimeBundle(time.Now(), 0)
+	ms, warnings := inf.buildFixedMetrics(r.clusterMetrics, t)
+	addParseErrors(errs, "cluster info", warnings)
+	ms.MoveAndAppendTo(ilm.Metrics())
+	r.convertMetrics(ilm.Metrics(), t.serverStart)
+	return nil
+}
+
+// Builds the metrics from CLUSTER INFO of a node with cluster mode enabled.
+func (r *redisScraper) scrapeNodeClusterInfo(node *redisNode, batch infoBatchReply, dest pdata.MetricSlice, errs *scrapererror.ScrapeErrors) error {
+	str, err := batch.clusterInfo, batch.clusterInfoErr
+	if err != nil {
+		return err
+	}
+	ms, warnings := parseClusterInfo(str).buildFixedMetrics(r.clusterMetrics, node.timeBundle)
+	addParseErrors(errs, "cluster info", warnings)
+	ms.MoveAndAppendTo(dest)
+	return nil
+}
+
+// Adds one ResourceMetrics for each Sentinel instance that answers.
+func (r *redisScraper) scrapeSentinels(s sentinelInstanceSource, rms pdata.ResourceMetricsSlice, errs *scrapererror.ScrapeErrors) {
+	instances, err := s.sentinelInstances()
+	if err != nil {
+		r.logger.Warn("failed to scrape redis sentinel", zap.Error(err))
+	}
+	for i := range instances {
+		rm := r.appendResource(rms)
+		rattrs := rm.Resource().Attributes()
+		rattrs.UpsertString(nodeAddressAttribute, instances[i].addr)
+		rattrs.UpsertString(nodeRoleAttribute, roleSentinel)
+		ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+		// All Sentinel metrics are gauges.
+		t := newTimeBundle(time.Now(), 0)
+		ms, warnings := instances[i].buildMetrics(t)
+		addParseErrors(errs, "sentinel info", warnings)
+		r.removeDisabled(ms)
+		ms.MoveAndAppendTo(ilm.Metrics())
+		r.convertMetrics(ilm.Metrics(), t.serverStart)
+	}
+}
+
+// Queries the REST API of the Redis Enterprise cluster and builds one
+// ResourceMetrics with the metrics of its databases, shards and nodes. The
+// metrics of the requests that fail are missing, reported as a partial scrape
+// error unless all of them failed.
+func (r *redisScraper) scrapeEnterprise() (pdata.ResourceMetricsSlice, error) {
+	rms := pdata.NewResourceMetricsSlice()
+	// The statistics are levels and rates, all reported as gauges.
+	t := newTimeBundle(time.Now(), 0)
+	ms := pdata.NewMetricSlice()
+	var errs scrapererror.ScrapeErrors
+	var failures []error
+
+	databases := r.anyEnabled(enterpriseDatabaseMetricNames)
+	shards := r.anyEnabled(enterpriseShardMetricNames)
+	var dbs []enterpriseDatabase
+	if databases || shards {
+		var err error
+		if dbs, err = r.enterprise.databases(); err != nil {
+			err = fmt.Errorf("failed to fetch redis enterprise databases: %w", err)
+			errs.AddPartial(len(enterpriseDatabaseMetricNames)+len(enterpriseShardMetricNames), err)
+			failures = append(failures, err)
+			databases, shards = false, false
+		}
+	}
+	if databases {
+		if stats, err := r.enterprise.stats("bdbs"); err != nil {
+			err = fmt.Errorf("failed to fetch redis enterprise database stats: %w", err)
+			errs.AddPartial(len(enterpriseDatabaseMetricNames), err)
+			failures = append(failures, err)
+		} else {
+			buildEnterpriseDatabaseMetrics(dbs, stats, t).MoveAndAppendTo(ms)
+		}
+	}
+	if shards {
+		if err := r.scrapeEnterpriseShards(dbs, t, ms); err != nil {
+			err = fmt.Errorf("failed to fetch redis enterprise shard stats: %w", err)
+			errs.AddPartial(len(enterpriseShardMetricNames), err)
+			failures = append(failures, err)
+		}
+	}
+	if r.anyEnabled(enterpriseNodeMetricNames) {
+		if stats, err := r.enterprise.stats("nodes"); err != nil {
+			err = fmt.Errorf("failed to fetch redis enterprise node stats: %w", err)
+			errs.AddPartial(len(enterpriseNodeMetricNames), err)
+			failures = append(failures, err)
+		} else {
+			buildEnterpriseNodeMetrics(stats, t).MoveAndAppendTo(ms)
+		}
+	}
+
+	if ms.Len() == 0 && len(failures) > 0 {
+		return rms, consumererror.Combine(failures)
+	}
+	r.removeDisabled(ms)
+	ilm := r.appendResource(rms).InstrumentationLibraryMetrics().AppendEmpty()
+	ms.MoveAndAppendTo(ilm.Metrics())
+	r.convertMetrics(ilm.Metrics(), t.serverStart)
+	return rms, errs.Combine()
+}
+
+// Queries the shards of the Redis Enterprise cluster and appends their
+// metrics.
+func (r *redisScraper) scrapeEnterpriseShards(dbs []enterpriseDatabase, t *timeBundle, ms pdata.MetricSlice) error {
+	shards, err := r.enterprise.shards()
+	if err != nil {
+		return err
+	}
+	stats, err := r.enterprise.stats("shards")
+	if err != nil {
+		return err
+	}
+	buildEnterpriseShardMetrics(shards, dbs, stats, t).MoveAndAppendTo(ms)
+	return nil
+}
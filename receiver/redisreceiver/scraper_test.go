@@ -0,0 +1,438 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+)
+
+// Creates a scraper of the nodes, with the default configuration otherwise.
+func newTestScraper(nodes nodeSource, serviceName string, metricSettings map[string]MetricSettings) *redisScraper {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ServiceName = serviceName
+	cfg.Metrics = metricSettings
+	scraper := newRedisScraper(cfg, zap.NewNop())
+	scraper.nodes = nodes
+	return scraper
+}
+
+func scrapeMetrics(t *testing.T, scraper *redisScraper) pdata.ResourceMetricsSlice {
+	rms, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	return rms
+}
+
+func TestRedisScraper(t *testing.T) {
+	scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "", nil)
+	md := pdata.NewMetrics()
+	scrapeMetrics(t, scraper).MoveAndAppendTo(md.ResourceMetrics())
+	metricCount, _ := md.MetricAndDataPointCount()
+	// + 6 because there are two keyspace entries each of which has three metrics,
+	// + 2 for the two errorstats entries, + 6 because there are two
+	// latencystats entries each of which has three percentiles, + the memory
+	// stats metrics, + 2 for the client saturation metrics, + 7 for the
+	// persistence metrics other than the durations and AOF sizes, + the
+	// allocator metrics, + 1 for redis/up and + 1 for redis/restarts
+	require.Equal(t, len(enabledMetrics(nil, getRedisMetrics()))+6+2+6+len(getMemoryStatsMetrics())+2+7+len(getAllocatorMetrics())+1+1, metricCount)
+}
+
+func TestRedisScraperDBSize(t *testing.T) {
+	scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "", nil)
+	scraper.dbSizeDatabases = []int{0, 1, 2, 3}
+	rms := scrapeMetrics(t, scraper)
+
+	keys := map[string]int64{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() != "redis/db/keys" {
+			continue
+		}
+		pt := ms.At(i).IntGauge().DataPoints().At(0)
+		db, _ := pt.LabelsMap().Get("db")
+		keys[db] = pt.Value()
+	}
+	// db0 and db1 are in the keyspace section, the others come from DBSIZE.
+	assert.Equal(t, map[string]int64{"0": 1, "1": 4, "2": 2, "3": 3}, keys)
+}
+
+func TestRedisScraperKeyspaceFilter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DBSize = DBSizeConfig{Enabled: true, Databases: []int{0, 1, 2, 3}}
+	cfg.Keyspace = KeyspaceConfig{ExcludeDatabases: []int{1, 3}}
+	scraper := newRedisScraper(cfg, zap.NewNop())
+	scraper.nodes = staticNodes{newRedisNode(newFakeClient(), nil)}
+	assert.Equal(t, []int{0, 2}, scraper.dbSizeDatabases)
+	rms := scrapeMetrics(t, scraper)
+
+	dbs := map[string]bool{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() != "redis/db/keys" {
+			continue
+		}
+		db, _ := ms.At(i).IntGauge().DataPoints().At(0).LabelsMap().Get("db")
+		dbs[db] = true
+	}
+	assert.Equal(t, map[string]bool{"0": true, "2": true}, dbs)
+}
+
+func TestRedisScraperPubSub(t *testing.T) {
+	settings := map[string]MetricSettings{
+		"redis/pubsub/channels": {Enabled: true},
+		"redis/pubsub/patterns": {Enabled: true},
+	}
+	scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "", settings)
+	scraper.pubSubChannels = []string{"events", "alerts"}
+	rms := scrapeMetrics(t, scraper)
+
+	names := map[string]int{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		names[ms.At(i).Name()]++
+	}
+	assert.Equal(t, 1, names["redis/pubsub/channels"])
+	assert.Equal(t, 1, names["redis/pubsub/patterns"])
+	assert.Equal(t, 2, names["redis/pubsub/subscribers"])
+}
+
+func TestRedisScraperNodes(t *testing.T) {
+	nodes := staticNodes{
+		newRedisNode(newFakeClient(), map[string]string{nodeRoleAttribute: roleMaster}),
+		newRedisNode(&replicaClient{fakeClient: newFakeClient()}, map[string]string{nodeRoleAttribute: roleMaster}),
+	}
+	scraper := newTestScraper(nodes, "my-redis", nil)
+	rms := scrapeMetrics(t, scraper)
+
+	require.Equal(t, 2, rms.Len())
+	for i, role := range []string{roleMaster, roleReplica} {
+		attrs := rms.At(i).Resource().Attributes()
+		v, ok := attrs.Get(nodeRoleAttribute)
+		require.True(t, ok)
+		assert.Equal(t, role, v.StringVal())
+		v, ok = attrs.Get("service.name")
+		require.True(t, ok)
+		assert.Equal(t, "my-redis", v.StringVal())
+	}
+	assert.NotNil(t, nodes[0].timeBundle)
+}
+
+func TestRedisScraperResourceAttributes(t *testing.T) {
+	nodes := staticNodes{
+		newRedisNode(newFakeClient(), map[string]string{"team": "payments"}),
+		newRedisNode(newFakeClient(), map[string]string{serviceNameAttribute: "other-redis", "team": "search"}),
+	}
+	scraper := newTestScraper(nodes, "my-redis", nil)
+	scraper.resourceAttributes["team"] = "platform"
+	scraper.resourceAttributes["deployment.environment"] = "production"
+	rms := scrapeMetrics(t, scraper)
+
+	require.Equal(t, 2, rms.Len())
+	for i, expected := range []map[string]string{
+		{"service.name": "my-redis", "team": "payments", "deployment.environment": "production", nodeRoleAttribute: roleMaster},
+		{"service.name": "other-redis", "team": "search", "deployment.environment": "production", nodeRoleAttribute: roleMaster},
+	} {
+		attrs := map[string]string{}
+		rms.At(i).Resource().Attributes().Range(func(k string, v pdata.AttributeValue) bool {
+			attrs[k] = v.StringVal()
+			return true
+		})
+		assert.Equal(t, expected, attrs)
+	}
+}
+
+// A client of a replica, counting the commands that walk the keyspace.
+type replicaClient struct {
+	*fakeClient
+	keyCommands int
+}
+
+func (c *replicaClient) retrieveInfo() (string, error) {
+	str, err := c.fakeClient.retrieveInfo()
+	return strings.Replace(str, "role:master", "role:slave", 1), err
+}
+
+func (c *replicaClient) retrieveKeyStats(keys []string) (map[string]keyStats, error) {
+	c.keyCommands++
+	return c.fakeClient.retrieveKeyStats(keys)
+}
+
+func (c *replicaClient) retrieveStreams(streams []string) (map[string]streamInfo, error) {
+	c.keyCommands++
+	return c.fakeClient.retrieveStreams(streams)
+}
+
+func (c *replicaClient) retrieveKeyPatternCount(pattern string, count, maxCalls int64) (keyPatternCount, error) {
+	c.keyCommands++
+	return c.fakeClient.retrieveKeyPatternCount(pattern, count, maxCalls)
+}
+
+func TestRedisScraperReplica(t *testing.T) {
+	for _, c := range []struct {
+		name                  string
+		keyCommands           bool
+		suppressMasterMetrics bool
+	}{
+		{name: "default"},
+		{name: "key commands", keyCommands: true},
+		{name: "suppress master metrics", suppressMasterMetrics: true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			client := &replicaClient{fakeClient: newFakeClient()}
+			scraper := newTestScraper(staticNodes{newRedisNode(client, nil)}, "my-redis", nil)
+			scraper.streams = []string{"events"}
+			scraper.keys = []string{"jobs:queue"}
+			scraper.keyPatterns.Patterns = []string{"session:*"}
+			scraper.config.Replica = ReplicaConfig{KeyCommands: c.keyCommands, SuppressMasterMetrics: c.suppressMasterMetrics}
+			rms := scrapeMetrics(t, scraper)
+
+			v, ok := rms.At(0).Resource().Attributes().Get(nodeRoleAttribute)
+			require.True(t, ok)
+			assert.Equal(t, roleReplica, v.StringVal())
+
+			if c.keyCommands {
+				assert.Equal(t, 3, client.keyCommands)
+			} else {
+				assert.Zero(t, client.keyCommands)
+			}
+
+			names := map[string]bool{}
+			ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			for i := 0; i < ms.Len(); i++ {
+				names[ms.At(i).Name()] = true
+			}
+			assert.Equal(t, !c.suppressMasterMetrics, names["redis/keys/evicted"])
+			assert.Equal(t, !c.suppressMasterMetrics, names["redis/keys/expired"])
+			assert.True(t, names["redis/keyspace/hits"])
+		})
+	}
+}
+
+// A client of a server on which MEMORY is disabled, counting the attempts to
+// run it.
+type restrictedClient struct {
+	*fakeClient
+	memoryCalls int
+}
+
+func (c *restrictedClient) retrieveMemoryStats() (info, error) {
+	c.memoryCalls++
+	return nil, errors.New("ERR unknown command `MEMORY`, with args beginning with: `STATS`, ")
+}
+
+func TestRedisScraperUnavailableCommand(t *testing.T) {
+	c := &restrictedClient{fakeClient: newFakeClient()}
+	scraper := newTestScraper(staticNodes{newRedisNode(c, nil)}, "my-redis", nil)
+
+	for i := 0; i < 2; i++ {
+		ms := scrapeMetrics(t, scraper).At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < ms.Len(); j++ {
+			assert.False(t, strings.HasPrefix(ms.At(j).Name(), "redis/memory/stats/"))
+		}
+	}
+	assert.Equal(t, 1, c.memoryCalls, "the command is not sent again once rejected")
+}
+
+type unreachableClient struct {
+	fakeClient
+}
+
+func (unreachableClient) retrieveInfo() (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func TestRedisScraperNodeFailure(t *testing.T) {
+	nodes := staticNodes{
+		newRedisNode(newFakeClient(), map[string]string{nodeAddressAttribute: "redis-1:6379"}),
+		newRedisNode(unreachableClient{}, map[string]string{nodeAddressAttribute: "redis-2:6379"}),
+	}
+	scraper := newTestScraper(nodes, "", nil)
+	rms, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	require.Equal(t, 2, rms.Len())
+	assertUp(t, rms.At(0), 1)
+	assertUp(t, rms.At(1), 0)
+	assert.Equal(t, 1, rms.At(1).InstrumentationLibraryMetrics().At(0).Metrics().Len())
+
+	// Without redis/up there is nothing to export when no node is scraped.
+	settings := map[string]MetricSettings{upMetricName: {Enabled: false}}
+	scraper = newTestScraper(staticNodes{newRedisNode(unreachableClient{}, nil)}, "", settings)
+	rms, err = scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.False(t, scrapererror.IsPartialScrapeError(err))
+	assert.Equal(t, 0, rms.Len())
+}
+
+// Asserts the value of redis/up in the resource metrics of a node.
+func assertUp(t *testing.T, rm pdata.ResourceMetrics, expected int64) {
+	ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() == upMetricName {
+			assert.Equal(t, expected, ms.At(i).IntGauge().DataPoints().At(0).Value())
+			return
+		}
+	}
+	t.Errorf("no %s metric", upMetricName)
+}
+
+type countingUnreachableClient struct {
+	unreachableClient
+	calls *int
+}
+
+func (c countingUnreachableClient) retrieveInfo() (string, error) {
+	*c.calls++
+	return c.unreachableClient.retrieveInfo()
+}
+
+func TestRedisScraperReconnectBackoff(t *testing.T) {
+	calls := 0
+	node := newRedisNode(countingUnreachableClient{calls: &calls}, nil)
+	scraper := newTestScraper(staticNodes{node}, "", nil)
+	scraper.config.Reconnect = ReconnectConfig{InitialInterval: time.Hour, MaxInterval: 4 * time.Hour}
+
+	for i := 0; i < 2; i++ {
+		rms, err := scraper.scrape(context.Background())
+		require.Error(t, err)
+		assert.True(t, scrapererror.IsPartialScrapeError(err))
+		require.Equal(t, 1, rms.Len())
+		assertUp(t, rms.At(0), 0)
+	}
+	// The node is not attempted again before the backoff ends.
+	assert.Equal(t, 1, calls)
+
+	node.retryAt = time.Now()
+	_, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, 2, calls)
+	// The delay doubles with each consecutive failure.
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), node.retryAt, time.Minute)
+
+	node.client = newFakeClient()
+	node.svc = newRedisSvc(node.client)
+	node.retryAt = time.Now()
+	rms := scrapeMetrics(t, scraper)
+	assertUp(t, rms.At(0), 1)
+	assert.Equal(t, 0, node.failures)
+}
+
+type malformedInfoClient struct {
+	fakeClient
+}
+
+func (malformedInfoClient) retrieveInfo() (string, error) {
+	info, err := readFile("info")
+	return strings.Replace(info, "used_memory:", "used_memory:x", 1), err
+}
+
+func TestRedisScraperMalformedInfo(t *testing.T) {
+	scraper := newTestScraper(staticNodes{newRedisNode(malformedInfoClient{}, nil)}, "", nil)
+	rms, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	require.True(t, scrapererror.IsPartialScrapeError(err))
+	// used_memory is also the numerator of redis/memory/used_ratio.
+	assert.Equal(t, 2, err.(scrapererror.PartialScrapeError).Failed)
+
+	// The other metrics are still scraped.
+	require.Equal(t, 1, rms.Len())
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	names := map[string]bool{}
+	for i := 0; i < ms.Len(); i++ {
+		names[ms.At(i).Name()] = true
+	}
+	assert.False(t, names["redis/memory/used"])
+	assert.True(t, names["redis/memory/rss"])
+}
+
+func TestRedisScraperInitialDelay(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.InitialDelay = 10 * time.Millisecond
+	cfg.CollectionInterval = time.Hour
+	scraper := newRedisScraper(cfg, zap.NewNop())
+	require.NoError(t, scraper.start(context.Background(), nil))
+
+	select {
+	case <-scraper.ticks:
+	case <-time.After(time.Second):
+		t.Fatal("no tick after the initial delay")
+	}
+	require.NoError(t, scraper.shutdown(context.Background()))
+}
+
+func TestRedisScraperMetricSettings(t *testing.T) {
+	settings := map[string]MetricSettings{
+		"redis/cpu/time":  {Enabled: false},
+		"redis/db/keys":   {Enabled: false},
+		"redis/maxmemory": {Enabled: true},
+	}
+	scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "", settings)
+	rms := scrapeMetrics(t, scraper)
+
+	names := map[string]bool{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		names[ms.At(i).Name()] = true
+	}
+	assert.False(t, names["redis/cpu/time"])
+	assert.False(t, names["redis/db/keys"])
+	assert.True(t, names["redis/db/expires"])
+	assert.True(t, names["redis/maxmemory"])
+	assert.False(t, names["redis/memory/dataset"], "optional metrics are disabled by default")
+	// 4 of the default metrics are redis/cpu/time, and 2 of the keyspace metrics redis/db/keys.
+	assert.Equal(t, len(enabledMetrics(nil, getRedisMetrics()))-4+6-2+2+6+len(getMemoryStatsMetrics())+2+7+len(getAllocatorMetrics())+1+1+1, ms.Len())
+}
+
+// A client that records the requests sent along with INFO.
+type batchingClient struct {
+	client
+	requests []infoBatchRequest
+}
+
+func (c *batchingClient) retrieveInfoBatch(req infoBatchRequest) (infoBatchReply, error) {
+	c.requests = append(c.requests, req)
+	return retrieveInfoBatch(c.client, req)
+}
+
+func TestRedisScraperInfoBatch(t *testing.T) {
+	c := &batchingClient{client: clusterEnabledClient{newFakeClient()}}
+	scraper := newTestScraper(staticNodes{newRedisNode(c, nil)}, "", nil)
+	scraper.dbSizeDatabases = []int{0, 1, 2}
+	scrapeMetrics(t, scraper)
+	rms := scrapeMetrics(t, scraper)
+
+	// CLUSTER INFO is only sent along with INFO once INFO reported cluster mode.
+	require.Len(t, c.requests, 2)
+	assert.Equal(t, infoBatchRequest{section: "everything", dbs: []int{0, 1, 2}}, c.requests[0])
+	assert.Equal(t, infoBatchRequest{section: "everything", clusterInfo: true, dbs: []int{0, 1, 2}}, c.requests[1])
+
+	names := map[string]int{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		names[ms.At(i).Name()]++
+	}
+	assert.Equal(t, 1, names["redis/cluster/state"])
+	// Databases 0 and 1 are in the keyspace section of INFO, 2 only in DBSIZE.
+	assert.Equal(t, 3, names["redis/db/keys"])
+}
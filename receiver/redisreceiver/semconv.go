@@ -0,0 +1,122 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The names of the metrics whose semantic conventions name is not derived
+// from their name, see semanticConventionsName.
+var semanticConventionsNames = map[string]string{
+	"redis/slaves/connected":         "redis.replicas.connected",
+	"redis/replication/slave_offset": "redis.replication.replica_offset",
+}
+
+// The gauges counting or measuring something that are not additive, and so
+// are not converted to sums: offsets are positions in the replication stream,
+// and the other ones are maximums, averages or per collection.
+var semanticConventionsGauges = map[string]bool{
+	"redis/clients/max_input_buffer":              true,
+	"redis/clients/max_output_buffer":             true,
+	"redis/replication/backlog_first_byte_offset": true,
+	"redis/replication/offset":                    true,
+	"redis/replication/replica/offset":            true,
+	"redis/replication/slave_offset":              true,
+	"redis/memory/stats/bytes_per_key":            true,
+	"redis/slowlog/entries":                       true,
+}
+
+// Returns the name of a metric following the semantic conventions, with
+// dot-separated namespaces, e.g. redis.memory.used for redis/memory/used.
+func semanticConventionsName(name string) string {
+	if n, ok := semanticConventionsNames[name]; ok {
+		return n
+	}
+	return strings.ReplaceAll(name, "/", ".")
+}
+
+// Converts metrics to the semantic conventions: renames them, converts
+// durations in milliseconds and microseconds to seconds, and reports the
+// gauges of additive quantities, e.g. numbers of keys or bytes, as
+// non-monotonic cumulative sums starting at start.
+func toSemanticConventions(ms pdata.MetricSlice, start time.Time) {
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		switch m.Unit() {
+		case "ms":
+			toSeconds(m, float64(time.Millisecond))
+		case "us":
+			toSeconds(m, float64(time.Microsecond))
+		default:
+			if m.DataType() == pdata.MetricDataTypeIntGauge && isAdditiveUnit(m.Unit()) && !semanticConventionsGauges[m.Name()] {
+				toUpDownCounter(m, pdata.TimestampFromTime(start))
+			}
+		}
+		m.SetName(semanticConventionsName(m.Name()))
+	}
+}
+
+// Returns whether a unit is that of an additive quantity: bytes, or a count
+// such as {keys}, but not a rate such as {keys}/s.
+func isAdditiveUnit(unit string) bool {
+	if unit == "By" {
+		return true
+	}
+	return strings.HasPrefix(unit, "{") && strings.HasSuffix(unit, "}")
+}
+
+// Converts a gauge in the given unit, in nanoseconds, to a double gauge in
+// seconds.
+func toSeconds(m pdata.Metric, unit float64) {
+	points := pdata.NewDoubleDataPointSlice()
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		dps := m.IntGauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp, pt := dps.At(i), points.AppendEmpty()
+			pt.SetTimestamp(dp.Timestamp())
+			pt.SetValue(float64(dp.Value()) * unit / float64(time.Second))
+			dp.LabelsMap().CopyTo(pt.LabelsMap())
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		m.DoubleGauge().DataPoints().MoveAndAppendTo(points)
+		for i := 0; i < points.Len(); i++ {
+			points.At(i).SetValue(points.At(i).Value() * unit / float64(time.Second))
+		}
+	default:
+		return
+	}
+	m.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	points.MoveAndAppendTo(m.DoubleGauge().DataPoints())
+	m.SetUnit("s")
+}
+
+// Converts an int gauge to a non-monotonic cumulative int sum.
+func toUpDownCounter(m pdata.Metric, start pdata.Timestamp) {
+	points := pdata.NewIntDataPointSlice()
+	m.IntGauge().DataPoints().MoveAndAppendTo(points)
+	m.SetDataType(pdata.MetricDataTypeIntSum)
+	sum := m.IntSum()
+	sum.SetIsMonotonic(false)
+	sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	for i := 0; i < points.Len(); i++ {
+		points.At(i).SetStartTimestamp(start)
+	}
+	points.MoveAndAppendTo(sum.DataPoints())
+}
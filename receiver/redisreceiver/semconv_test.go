@@ -0,0 +1,101 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSemanticConventionsName(t *testing.T) {
+	assert.Equal(t, "redis.memory.used", semanticConventionsName("redis/memory/used"))
+	assert.Equal(t, "redis.replication.replica.offset", semanticConventionsName("redis/replication/replica/offset"))
+	assert.Equal(t, "redis.replication.replica_offset", semanticConventionsName("redis/replication/slave_offset"))
+	assert.Equal(t, "redis.replicas.connected", semanticConventionsName("redis/slaves/connected"))
+}
+
+func TestToSemanticConventions(t *testing.T) {
+	start := time.Unix(1622505600, 0)
+	tb := newTimeBundle(start.Add(time.Hour), 3600)
+	ms := pdata.NewMetricSlice()
+	initIntMetric(&redisMetric{name: "redis/memory/used"}, 1024, tb, ms.AppendEmpty())
+	initIntMetric(&redisMetric{name: "redis/db/avg_ttl", labels: map[string]string{"db": "0"}}, 1500, tb, ms.AppendEmpty())
+	initDoubleMetric(&redisMetric{name: "redis/commands/latency"}, 250, tb, ms.AppendEmpty())
+	initIntMetric(&redisMetric{name: "redis/replication/offset"}, 2048, tb, ms.AppendEmpty())
+	initIntMetric(&redisMetric{name: "redis/keys/expired"}, 7, tb, ms.AppendEmpty())
+	initDoubleMetric(&redisMetric{name: "redis/memory/fragmentation_ratio"}, 1.5, tb, ms.AppendEmpty())
+
+	toSemanticConventions(ms, start)
+
+	m := ms.At(0)
+	assert.Equal(t, "redis.memory.used", m.Name())
+	assert.Equal(t, "By", m.Unit())
+	require.Equal(t, pdata.MetricDataTypeIntSum, m.DataType())
+	assert.False(t, m.IntSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, m.IntSum().AggregationTemporality())
+	pt := m.IntSum().DataPoints().At(0)
+	assert.EqualValues(t, 1024, pt.Value())
+	assert.Equal(t, pdata.TimestampFromTime(start), pt.StartTimestamp())
+	assert.Equal(t, pdata.TimestampFromTime(tb.current), pt.Timestamp())
+
+	m = ms.At(1)
+	assert.Equal(t, "redis.db.avg_ttl", m.Name())
+	assert.Equal(t, "s", m.Unit())
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, m.DataType())
+	dp := m.DoubleGauge().DataPoints().At(0)
+	assert.Equal(t, 1.5, dp.Value())
+	db, _ := dp.LabelsMap().Get("db")
+	assert.Equal(t, "0", db)
+
+	m = ms.At(2)
+	assert.Equal(t, "s", m.Unit())
+	assert.InDelta(t, 0.00025, m.DoubleGauge().DataPoints().At(0).Value(), 1e-12)
+
+	m = ms.At(3)
+	assert.Equal(t, "redis.replication.offset", m.Name())
+	assert.Equal(t, pdata.MetricDataTypeIntGauge, m.DataType(), "offsets are not additive")
+
+	m = ms.At(4)
+	assert.Equal(t, pdata.MetricDataTypeIntSum, m.DataType())
+	assert.True(t, m.IntSum().IsMonotonic())
+
+	m = ms.At(5)
+	assert.Equal(t, "redis.memory.fragmentation_ratio", m.Name())
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, m.DataType())
+}
+
+func TestRedisScraperSemanticConventions(t *testing.T) {
+	scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "my-redis", nil)
+	scraper.config.SemanticConventions = true
+	ms := scrapeMetrics(t, scraper).At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Greater(t, ms.Len(), 0)
+	types := map[string]pdata.MetricDataType{}
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		assert.True(t, strings.HasPrefix(m.Name(), "redis."), m.Name())
+		assert.NotContains(t, m.Name(), "/")
+		assert.NotContains(t, []string{"ms", "us"}, m.Unit(), m.Name())
+		types[m.Name()] = m.DataType()
+	}
+	assert.Equal(t, pdata.MetricDataTypeIntSum, types["redis.memory.used"])
+	assert.Equal(t, pdata.MetricDataTypeIntSum, types["redis.db.keys"])
+	assert.Equal(t, pdata.MetricDataTypeIntGauge, types["redis.clients.max_input_buffer"])
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, types["redis.db.avg_ttl"])
+}
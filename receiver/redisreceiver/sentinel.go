@@ -0,0 +1,401 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Queries Sentinel for the servers of a master. Can be faked for testing.
+type sentinel interface {
+	// Returns the address of the current master.
+	masterAddr(masterName string) (string, error)
+	// Returns the addresses of the replicas of the master that are up.
+	replicaAddrs(masterName string) ([]string, error)
+	// Returns the state of each Sentinel instance that answers, and the last
+	// error if any did not.
+	instances() ([]sentinelInstance, error)
+	close() error
+}
+
+// The state of a Sentinel instance, from INFO sentinel and SENTINEL MASTERS.
+type sentinelInstance struct {
+	addr    string
+	info    info
+	masters []sentinelMaster
+}
+
+// A master monitored by a Sentinel instance.
+type sentinelMaster struct {
+	name      string
+	flags     []string
+	replicas  int64
+	sentinels int64
+	quorum    int64
+}
+
+// Queries the first Sentinel instance that answers.
+type redisSentinel struct {
+	addrs   []string
+	clients []*redis.SentinelClient
+}
+
+var _ sentinel = (*redisSentinel)(nil)
+
+func newRedisSentinel(cfg *SentinelConfig, timeout time.Duration) *redisSentinel {
+	s := &redisSentinel{}
+	for _, addr := range cfg.Addresses {
+		s.addrs = append(s.addrs, addr)
+		s.clients = append(s.clients, redis.NewSentinelClient(&redis.Options{
+			Addr:         addr,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			DialTimeout:  timeout,
+			ReadTimeout:  timeout,
+			WriteTimeout: timeout,
+		}))
+	}
+	return s
+}
+
+func (s *redisSentinel) masterAddr(masterName string) (string, error) {
+	var addr string
+	err := s.query(func(c *redis.SentinelClient) error {
+		res, err := c.GetMasterAddrByName(masterName).Result()
+		if err != nil {
+			return err
+		}
+		if len(res) != 2 {
+			return fmt.Errorf("unexpected master address %v", res)
+		}
+		addr = net.JoinHostPort(res[0], res[1])
+		return nil
+	})
+	return addr, err
+}
+
+func (s *redisSentinel) replicaAddrs(masterName string) ([]string, error) {
+	var addrs []string
+	err := s.query(func(c *redis.SentinelClient) error {
+		res, err := c.Slaves(masterName).Result()
+		if err != nil {
+			return err
+		}
+		addrs = parseReplicaAddrs(res)
+		return nil
+	})
+	return addrs, err
+}
+
+func (s *redisSentinel) instances() ([]sentinelInstance, error) {
+	var instances []sentinelInstance
+	var err error
+	for i, c := range s.clients {
+		infoCmd := redis.NewStringCmd("info", "sentinel")
+		if pErr := c.Process(infoCmd); pErr != nil {
+			err = pErr
+			continue
+		}
+		res, mErr := c.Masters().Result()
+		if mErr != nil {
+			err = mErr
+			continue
+		}
+		instances = append(instances, sentinelInstance{
+			addr:    s.addrs[i],
+			info:    parseSentinelInfo(infoCmd.Val()),
+			masters: parseSentinelMasters(res),
+		})
+	}
+	return instances, err
+}
+
+func (s *redisSentinel) query(f func(c *redis.SentinelClient) error) error {
+	if len(s.clients) == 0 {
+		return errors.New("no sentinel addresses")
+	}
+	var err error
+	for _, c := range s.clients {
+		if err = f(c); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (s *redisSentinel) close() error {
+	var err error
+	for _, c := range s.clients {
+		if cErr := c.Close(); cErr != nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// Parses a flat key/value list of a SENTINEL reply into a map.
+func parseSentinelFields(fields []interface{}) map[string]string {
+	m := make(map[string]string, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		k, _ := fields[i].(string)
+		v, _ := fields[i+1].(string)
+		m[k] = v
+	}
+	return m
+}
+
+// Parses the reply of SENTINEL SLAVES, a list of flat key/value lists, into the
+// addresses of the replicas that are neither down nor disconnected.
+func parseReplicaAddrs(res []interface{}) []string {
+	var addrs []string
+	for _, r := range res {
+		fields, ok := r.([]interface{})
+		if !ok {
+			continue
+		}
+		replica := parseSentinelFields(fields)
+		if replica["ip"] == "" || replica["port"] == "" {
+			continue
+		}
+
+		down := false
+		for _, flag := range strings.Split(replica["flags"], ",") {
+			if flag == "s_down" || flag == "o_down" || flag == "disconnected" {
+				down = true
+			}
+		}
+		if !down {
+			addrs = append(addrs, net.JoinHostPort(replica["ip"], replica["port"]))
+		}
+	}
+	return addrs
+}
+
+// Parses the reply of SENTINEL MASTERS, a list of flat key/value lists.
+// Numeric fields that cannot be parsed are left as 0.
+func parseSentinelMasters(res []interface{}) []sentinelMaster {
+	var masters []sentinelMaster
+	for _, r := range res {
+		fields, ok := r.([]interface{})
+		if !ok {
+			continue
+		}
+		m := parseSentinelFields(fields)
+		if m["name"] == "" {
+			continue
+		}
+		master := sentinelMaster{name: m["name"], flags: strings.Split(m["flags"], ",")}
+		master.replicas, _ = strconv.ParseInt(m["num-slaves"], 10, 64)
+		master.sentinels, _ = strconv.ParseInt(m["num-other-sentinels"], 10, 64)
+		// The other Sentinel instances do not include this one.
+		master.sentinels++
+		master.quorum, _ = strconv.ParseInt(m["quorum"], 10, 64)
+		masters = append(masters, master)
+	}
+	return masters
+}
+
+// Parses INFO sentinel, e.g. "sentinel_masters:1\r\nsentinel_tilt:0\r\n".
+func parseSentinelInfo(str string) info {
+	return parseInfoLines(str)
+}
+
+// The names of the metrics of Sentinel instances.
+var sentinelMetricNames = []string{
+	"redis/sentinel/masters",
+	"redis/sentinel/tilt",
+	"redis/sentinel/master/replicas",
+	"redis/sentinel/master/sentinels",
+	"redis/sentinel/master/quorum",
+	"redis/sentinel/master/quorum_ok",
+	"redis/sentinel/master/sdown",
+	"redis/sentinel/master/odown",
+}
+
+// Builds the metrics of a Sentinel instance, and of each master it monitors,
+// labeled with the master name. Returns metrics and parsing errors, to be
+// treated as warnings, if there were any.
+func (s *sentinelInstance) buildMetrics(t *timeBundle) (outMS pdata.MetricSlice, warnings []error) {
+	outMS = pdata.NewMetricSlice()
+	for _, m := range []*redisMetric{
+		{
+			key:  "sentinel_masters",
+			name: sentinelMetricNames[0],
+		},
+		{
+			key:  "sentinel_tilt",
+			name: sentinelMetricNames[1],
+		},
+	} {
+		str, ok := s.info[m.key]
+		if !ok {
+			continue
+		}
+		pdm, err := m.parseMetric(str, t)
+		if err != nil {
+			warnings = append(warnings, err)
+			continue
+		}
+		outMS.Append(pdm)
+	}
+
+	for _, master := range s.masters {
+		labels := map[string]string{"master": master.name}
+		var quorumOK, sdown, odown int64
+		if master.sentinels >= master.quorum {
+			quorumOK = 1
+		}
+		for _, flag := range master.flags {
+			switch flag {
+			case "s_down":
+				sdown = 1
+			case "o_down":
+				odown = 1
+			}
+		}
+		for _, v := range []struct {
+			name  string
+			value int64
+		}{
+			{sentinelMetricNames[2], master.replicas},
+			{sentinelMetricNames[3], master.sentinels},
+			{sentinelMetricNames[4], master.quorum},
+			{sentinelMetricNames[5], quorumOK},
+			{sentinelMetricNames[6], sdown},
+			{sentinelMetricNames[7], odown},
+		} {
+			initIntMetric(&redisMetric{
+				name:   v.name,
+				labels: labels,
+			}, v.value, t, outMS.AppendEmpty())
+		}
+	}
+	return outMS, warnings
+}
+
+// Implemented by node sources that also report the state of Sentinel
+// instances.
+type sentinelInstanceSource interface {
+	sentinelInstances() ([]sentinelInstance, error)
+}
+
+// Scrapes the master found through Sentinel and, optionally, its replicas.
+type sentinelNodes struct {
+	masterName string
+	sentinel   sentinel
+	master     *redisNode
+	// Creates the client of a replica, nil if replicas are not scraped.
+	newReplicaClient func(addr string) client
+	// Whether the Sentinel instances are monitored too.
+	monitorSentinels bool
+
+	mu       sync.Mutex
+	replicas map[string]*redisNode
+}
+
+var (
+	_ nodeSource             = (*sentinelNodes)(nil)
+	_ sentinelInstanceSource = (*sentinelNodes)(nil)
+)
+
+func newSentinelNodes(masterName string, sentinel sentinel, master client, newReplicaClient func(addr string) client) *sentinelNodes {
+	return &sentinelNodes{
+		masterName:       masterName,
+		sentinel:         sentinel,
+		master:           newRedisNode(master, map[string]string{nodeRoleAttribute: roleMaster}),
+		newReplicaClient: newReplicaClient,
+		replicas:         make(map[string]*redisNode),
+	}
+}
+
+func (s *sentinelNodes) nodes() ([]*redisNode, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The master client follows failovers on its own, the address only
+	// identifies the current master in the metrics.
+	if addr, err := s.sentinel.masterAddr(s.masterName); err == nil {
+		s.master.attributes[nodeAddressAttribute] = addr
+	}
+	nodes := []*redisNode{s.master}
+
+	if s.newReplicaClient == nil {
+		return nodes, nil
+	}
+
+	addrs, err := s.sentinel.replicaAddrs(s.masterName)
+	if err != nil {
+		// The master can still be scraped.
+		return nodes, nil
+	}
+
+	current := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		current[addr] = true
+		replica, ok := s.replicas[addr]
+		if !ok {
+			replica = newRedisNode(s.newReplicaClient(addr), map[string]string{
+				nodeAddressAttribute: addr,
+				nodeRoleAttribute:    roleReplica,
+			})
+			s.replicas[addr] = replica
+		}
+		nodes = append(nodes, replica)
+	}
+
+	// Forget replicas that are gone, e.g. promoted to master or removed.
+	for addr, replica := range s.replicas {
+		if !current[addr] {
+			_ = replica.client.close()
+			delete(s.replicas, addr)
+		}
+	}
+	return nodes, nil
+}
+
+// Returns the state of the Sentinel instances if they are monitored, nil
+// otherwise.
+func (s *sentinelNodes) sentinelInstances() ([]sentinelInstance, error) {
+	if !s.monitorSentinels {
+		return nil, nil
+	}
+	return s.sentinel.instances()
+}
+
+func (s *sentinelNodes) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.master.client.close()
+	for addr, replica := range s.replicas {
+		if cErr := replica.client.close(); cErr != nil {
+			err = cErr
+		}
+		delete(s.replicas, addr)
+	}
+	if cErr := s.sentinel.close(); cErr != nil {
+		err = cErr
+	}
+	return err
+}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReplicaAddrs(t *testing.T) {
+	replica := func(ip, port, flags string) interface{} {
+		return []interface{}{"name", ip + ":" + port, "ip", ip, "port", port, "flags", flags}
+	}
+	res := []interface{}{
+		replica("10.0.0.2", "6379", "slave"),
+		replica("10.0.0.3", "6379", "s_down,slave"),
+		replica("10.0.0.4", "6379", "slave,disconnected"),
+		replica("fd00::5", "6380", "slave"),
+		[]interface{}{"name", "incomplete"},
+		"unexpected",
+	}
+	assert.Equal(t, []string{"10.0.0.2:6379", "[fd00::5]:6380"}, parseReplicaAddrs(res))
+}
+
+type fakeSentinel struct {
+	master    string
+	replicas  []string
+	sentinels []sentinelInstance
+	err       error
+	closed    bool
+}
+
+func (s *fakeSentinel) masterAddr(string) (string, error) {
+	return s.master, s.err
+}
+
+func (s *fakeSentinel) replicaAddrs(string) ([]string, error) {
+	return s.replicas, s.err
+}
+
+func (s *fakeSentinel) instances() ([]sentinelInstance, error) {
+	return s.sentinels, s.err
+}
+
+func (s *fakeSentinel) close() error {
+	s.closed = true
+	return nil
+}
+
+type closeTrackingClient struct {
+	fakeClient
+	closed bool
+}
+
+func (c *closeTrackingClient) close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSentinelNodes(t *testing.T) {
+	sentinel := &fakeSentinel{master: "10.0.0.1:6379", replicas: []string{"10.0.0.2:6379", "10.0.0.3:6379"}}
+	clients := map[string]*closeTrackingClient{}
+	source := newSentinelNodes("mymaster", sentinel, newFakeClient(), func(addr string) client {
+		c := &closeTrackingClient{}
+		clients[addr] = c
+		return c
+	})
+
+	nodes, err := source.nodes()
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+	assert.Equal(t, map[string]string{nodeRoleAttribute: roleMaster, nodeAddressAttribute: "10.0.0.1:6379"}, nodes[0].attributes)
+	assert.Equal(t, map[string]string{nodeRoleAttribute: roleReplica, nodeAddressAttribute: "10.0.0.2:6379"}, nodes[1].attributes)
+	replica := nodes[2]
+
+	// After a failover, the former master is a replica and a replica the new master.
+	sentinel.master = "10.0.0.2:6379"
+	sentinel.replicas = []string{"10.0.0.3:6379", "10.0.0.1:6379"}
+	nodes, err = source.nodes()
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+	assert.Equal(t, "10.0.0.2:6379", nodes[0].attributes[nodeAddressAttribute])
+	assert.Same(t, replica, nodes[1], "replicas keep their state")
+	assert.Equal(t, "10.0.0.1:6379", nodes[2].attributes[nodeAddressAttribute])
+	assert.True(t, clients["10.0.0.2:6379"].closed)
+
+	// If Sentinel cannot be reached, the master is still scraped.
+	sentinel.err = errors.New("unreachable")
+	nodes, err = source.nodes()
+	require.NoError(t, err)
+	assert.Len(t, nodes, 1)
+
+	require.NoError(t, source.close())
+	assert.True(t, clients["10.0.0.3:6379"].closed)
+	assert.True(t, sentinel.closed)
+}
+
+func TestSentinelNodesWithoutReplicas(t *testing.T) {
+	sentinel := &fakeSentinel{master: "10.0.0.1:6379", replicas: []string{"10.0.0.2:6379"}}
+	nodes, err := newSentinelNodes("mymaster", sentinel, newFakeClient(), nil).nodes()
+	require.NoError(t, err)
+	assert.Len(t, nodes, 1)
+}
+
+func TestParseSentinelMasters(t *testing.T) {
+	res := []interface{}{
+		[]interface{}{"name", "mymaster", "ip", "10.0.0.1", "port", "6379", "flags", "master",
+			"num-slaves", "2", "num-other-sentinels", "2", "quorum", "2"},
+		[]interface{}{"name", "other", "flags", "s_down,o_down,master", "num-slaves", "0",
+			"num-other-sentinels", "0", "quorum", "2"},
+		[]interface{}{"ip", "10.0.0.3"},
+		"unexpected",
+	}
+	assert.Equal(t, []sentinelMaster{
+		{name: "mymaster", flags: []string{"master"}, replicas: 2, sentinels: 3, quorum: 2},
+		{name: "other", flags: []string{"s_down", "o_down", "master"}, sentinels: 1, quorum: 2},
+	}, parseSentinelMasters(res))
+}
+
+func TestSentinelInstanceMetrics(t *testing.T) {
+	instance := sentinelInstance{
+		addr: "10.0.0.10:26379",
+		info: parseSentinelInfo("# Sentinel\r\nsentinel_masters:2\r\nsentinel_tilt:0\r\n"),
+		masters: []sentinelMaster{
+			{name: "mymaster", flags: []string{"master"}, replicas: 2, sentinels: 3, quorum: 2},
+			{name: "other", flags: []string{"s_down", "o_down", "master"}, sentinels: 1, quorum: 2},
+		},
+	}
+	ms, warnings := instance.buildMetrics(testTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, 2+2*6, ms.Len())
+
+	values := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		pt := ms.At(i).IntGauge().DataPoints().At(0)
+		name := ms.At(i).Name()
+		if master, ok := pt.LabelsMap().Get("master"); ok {
+			name += "{" + master + "}"
+		}
+		values[name] = pt.Value()
+	}
+	assert.Equal(t, map[string]int64{
+		"redis/sentinel/masters":                    2,
+		"redis/sentinel/tilt":                       0,
+		"redis/sentinel/master/replicas{mymaster}":  2,
+		"redis/sentinel/master/sentinels{mymaster}": 3,
+		"redis/sentinel/master/quorum{mymaster}":    2,
+		"redis/sentinel/master/quorum_ok{mymaster}": 1,
+		"redis/sentinel/master/sdown{mymaster}":     0,
+		"redis/sentinel/master/odown{mymaster}":     0,
+		"redis/sentinel/master/replicas{other}":     0,
+		"redis/sentinel/master/sentinels{other}":    1,
+		"redis/sentinel/master/quorum{other}":       2,
+		"redis/sentinel/master/quorum_ok{other}":    0,
+		"redis/sentinel/master/sdown{other}":        1,
+		"redis/sentinel/master/odown{other}":        1,
+	}, values)
+}
+
+func TestRedisScraperSentinels(t *testing.T) {
+	sentinel := &fakeSentinel{
+		master: "10.0.0.1:6379",
+		sentinels: []sentinelInstance{{
+			addr:    "10.0.0.10:26379",
+			info:    info{"sentinel_masters": "1"},
+			masters: []sentinelMaster{{name: "mymaster", flags: []string{"master"}, sentinels: 3, quorum: 2}},
+		}},
+	}
+	for _, monitor := range []bool{false, true} {
+		source := newSentinelNodes("mymaster", sentinel, newFakeClient(), nil)
+		source.monitorSentinels = monitor
+		scraper := newTestScraper(source, "my-redis", nil)
+		rms := scrapeMetrics(t, scraper)
+
+		if !monitor {
+			assert.Equal(t, 1, rms.Len())
+			continue
+		}
+		require.Equal(t, 2, rms.Len())
+		attrs := rms.At(1).Resource().Attributes()
+		v, ok := attrs.Get(nodeRoleAttribute)
+		require.True(t, ok)
+		assert.Equal(t, roleSentinel, v.StringVal())
+		v, ok = attrs.Get(nodeAddressAttribute)
+		require.True(t, ok)
+		assert.Equal(t, "10.0.0.10:26379", v.StringVal())
+		assert.Equal(t, 1+6, rms.At(1).InstrumentationLibraryMetrics().At(0).Metrics().Len())
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/interval"
+)
+
+// An entry of the reply of SLOWLOG GET.
+type slowLogEntry struct {
+	id       int64
+	time     time.Time
+	duration time.Duration
+	args     []string
+	// Only reported by Redis 4 and later.
+	clientAddr string
+	clientName string
+}
+
+// Parses the reply of SLOWLOG GET, a list of entries, each a list of the id,
+// the unix timestamp, the duration in microseconds, the command arguments and,
+// since Redis 4, the client address and name.
+func parseSlowLog(res interface{}) ([]slowLogEntry, error) {
+	items, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected slowlog reply %T", res)
+	}
+	entries := make([]slowLogEntry, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected slowlog entry %v", item)
+		}
+		id, ok1 := fields[0].(int64)
+		ts, ok2 := fields[1].(int64)
+		us, ok3 := fields[2].(int64)
+		rawArgs, ok4 := fields[3].([]interface{})
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, fmt.Errorf("unexpected slowlog entry %v", item)
+		}
+		entry := slowLogEntry{
+			id:       id,
+			time:     time.Unix(ts, 0),
+			duration: time.Duration(us) * time.Microsecond,
+		}
+		for _, a := range rawArgs {
+			s, _ := a.(string)
+			entry.args = append(entry.args, s)
+		}
+		if len(fields) >= 6 {
+			entry.clientAddr, _ = fields[4].(string)
+			entry.clientName, _ = fields[5].(string)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Returns the entries, most recent first, that are newer than those passed in
+// the previous call for the node, or all entries on the first call.
+func (n *redisNode) newSlowLogEntries(entries []slowLogEntry) []slowLogEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	latest := entries[0].id
+	// Ids start again from 0 when the server restarts.
+	if n.seenSlowLogIDs && latest < n.lastSlowLogID {
+		n.seenSlowLogIDs = false
+	}
+	newEntries := entries
+	if n.seenSlowLogIDs {
+		for i, e := range entries {
+			if e.id <= n.lastSlowLogID {
+				newEntries = entries[:i]
+				break
+			}
+		}
+	}
+	n.lastSlowLogID = latest
+	n.seenSlowLogIDs = true
+	return newEntries
+}
+
+var _ interval.Runnable = (*slowLogRunnable)(nil)
+
+const transport = "http" // todo verify this
+
+// Runs intermittently, fetching the slow log of each Redis node and feeding
+// the entries that were not seen before to a logsConsumer.
+type slowLogRunnable struct {
+	id           config.ComponentID
+	ctx          context.Context
+	logsConsumer consumer.Logs
+	nodes        nodeSource
+	maxEntries   int64
+	logger       *zap.Logger
+	// The attributes added to the resource of every node.
+	resourceAttributes map[string]string
+	obsrecv            *obsreport.Receiver
+}
+
+func newSlowLogRunnable(
+	ctx context.Context,
+	id config.ComponentID,
+	nodes nodeSource,
+	resourceAttributes map[string]string,
+	maxEntries int64,
+	logsConsumer consumer.Logs,
+	logger *zap.Logger,
+) *slowLogRunnable {
+	return &slowLogRunnable{
+		id:                 id,
+		ctx:                ctx,
+		resourceAttributes: resourceAttributes,
+		nodes:              nodes,
+		maxEntries:         maxEntries,
+		logsConsumer:       logsConsumer,
+		logger:             logger,
+		obsrecv:            obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: id, Transport: transport}),
+	}
+}
+
+func (r *slowLogRunnable) Setup() error {
+	return nil
+}
+
+// Run is called periodically, querying the slow log of each Redis node and
+// sending new entries to the next consumer as log records, with one
+// ResourceLogs per node.
+func (r *slowLogRunnable) Run() error {
+	const dataFormat = "redis_slowlog"
+	ctx := r.obsrecv.StartLogsReceiveOp(r.ctx)
+
+	nodes, err := r.nodes.nodes()
+	if err != nil {
+		r.obsrecv.EndLogsReceiveOp(ctx, dataFormat, 0, err)
+		return nil
+	}
+
+	ld := pdata.NewLogs()
+	var scrapeErr error
+	for _, node := range nodes {
+		if node.commandUnavailable("slowlog") {
+			continue
+		}
+		if err = r.scrapeNode(node, ld.ResourceLogs()); err != nil {
+			scrapeErr = err
+			node.commandFailed(r.logger, "slowlog", "failed to fetch redis slowlog", err)
+		}
+	}
+	if ld.LogRecordCount() == 0 {
+		r.obsrecv.EndLogsReceiveOp(ctx, dataFormat, 0, scrapeErr)
+		return nil
+	}
+
+	err = r.logsConsumer.ConsumeLogs(r.ctx, ld)
+	r.obsrecv.EndLogsReceiveOp(ctx, dataFormat, ld.LogRecordCount(), err)
+
+	return nil
+}
+
+// Appends the entries of the slow log of a node that are newer than the ones
+// emitted before. SLOWLOG GET returns the most recent entries first.
+func (r *slowLogRunnable) scrapeNode(node *redisNode, rls pdata.ResourceLogsSlice) error {
+	entries, err := node.client.retrieveSlowLog(r.maxEntries)
+	if err != nil {
+		return err
+	}
+
+	newEntries := node.newSlowLogEntries(entries)
+	if len(newEntries) == 0 {
+		return nil
+	}
+
+	rl := rls.AppendEmpty()
+	rattrs := rl.Resource().Attributes()
+	for k, v := range r.resourceAttributes {
+		rattrs.InsertString(k, v)
+	}
+	for k, v := range node.attributes {
+		rattrs.UpsertString(k, v)
+	}
+	logs := rl.InstrumentationLibraryLogs().AppendEmpty().Logs()
+	// Oldest first.
+	for i := len(newEntries) - 1; i >= 0; i-- {
+		slowLogEntryToLogRecord(newEntries[i], logs.AppendEmpty())
+	}
+	return nil
+}
+
+func slowLogEntryToLogRecord(e slowLogEntry, lr pdata.LogRecord) {
+	command := strings.Join(e.args, " ")
+	lr.SetName("redis.slowlog")
+	lr.SetTimestamp(pdata.TimestampFromTime(e.time))
+	lr.Body().SetStringVal(command)
+
+	attrs := lr.Attributes()
+	attrs.InsertString("db.system", "redis")
+	attrs.InsertString("db.statement", command)
+	if len(e.args) > 0 {
+		attrs.InsertString("db.operation", strings.ToUpper(e.args[0]))
+	}
+	attrs.InsertInt("redis.slowlog.id", e.id)
+	attrs.InsertInt("redis.slowlog.duration_us", e.duration.Microseconds())
+	if e.clientAddr != "" {
+		attrs.InsertString("redis.client.address", e.clientAddr)
+	}
+	if e.clientName != "" {
+		attrs.InsertString("redis.client.name", e.clientName)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// A SLOWLOG GET reply, most recent entry first. The oldest entry is from a
+// Redis version before 4, without client information.
+var testSlowLog = []interface{}{
+	[]interface{}{int64(3), int64(1622505600), int64(25000), []interface{}{"KEYS", "*"}, "127.0.0.1:58217", "worker"},
+	[]interface{}{int64(2), int64(1622505590), int64(12000), []interface{}{"HGETALL", "user:1"}, "127.0.0.1:58218", ""},
+	[]interface{}{int64(1), int64(1622505580), int64(11000), []interface{}{"SMEMBERS", "tags"}},
+}
+
+func TestParseSlowLog(t *testing.T) {
+	entries, err := parseSlowLog(testSlowLog)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, slowLogEntry{
+		id:         3,
+		time:       time.Unix(1622505600, 0),
+		duration:   25 * time.Millisecond,
+		args:       []string{"KEYS", "*"},
+		clientAddr: "127.0.0.1:58217",
+		clientName: "worker",
+	}, entries[0])
+	assert.Equal(t, "", entries[2].clientAddr)
+
+	_, err = parseSlowLog("OK")
+	assert.Error(t, err)
+	_, err = parseSlowLog([]interface{}{[]interface{}{"1", int64(2), int64(3), []interface{}{}}})
+	assert.Error(t, err)
+}
+
+// Returns a slow log that can be changed between runs.
+type slowLogClient struct {
+	fakeClient
+	entries []interface{}
+	// The number of runs of SLOWLOG GET.
+	gets int
+}
+
+func (c *slowLogClient) retrieveSlowLog(int64) ([]slowLogEntry, error) {
+	c.gets++
+	return parseSlowLog(c.entries)
+}
+
+func TestSlowLogRunnable(t *testing.T) {
+	c := &slowLogClient{entries: testSlowLog[1:]}
+	consumer := new(consumertest.LogsSink)
+	nodes := staticNodes{newRedisNode(c, map[string]string{nodeAddressAttribute: "localhost:6379"})}
+	runner := newSlowLogRunnable(context.Background(), config.NewID(typeStr), nodes, map[string]string{serviceNameAttribute: "my-redis"}, 128, consumer, zap.NewNop())
+
+	require.NoError(t, runner.Run())
+	require.Len(t, consumer.AllLogs(), 1)
+	rl := consumer.AllLogs()[0].ResourceLogs().At(0)
+	v, ok := rl.Resource().Attributes().Get(nodeAddressAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "localhost:6379", v.StringVal())
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, "SMEMBERS tags", logs.At(0).Body().StringVal(), "oldest entries first")
+
+	lr := logs.At(1)
+	assert.Equal(t, "HGETALL user:1", lr.Body().StringVal())
+	assert.Equal(t, pdata.TimestampFromTime(time.Unix(1622505590, 0)), lr.Timestamp())
+	attrs := lr.Attributes()
+	v, _ = attrs.Get("db.operation")
+	assert.Equal(t, "HGETALL", v.StringVal())
+	v, _ = attrs.Get("redis.slowlog.duration_us")
+	assert.EqualValues(t, 12000, v.IntVal())
+	v, _ = attrs.Get("redis.client.address")
+	assert.Equal(t, "127.0.0.1:58218", v.StringVal())
+	_, ok = attrs.Get("redis.client.name")
+	assert.False(t, ok)
+
+	// Only entries that were not seen before are emitted.
+	require.NoError(t, runner.Run())
+	assert.Len(t, consumer.AllLogs(), 1)
+
+	c.entries = testSlowLog
+	require.NoError(t, runner.Run())
+	require.Len(t, consumer.AllLogs(), 2)
+	assert.Equal(t, 1, consumer.AllLogs()[1].LogRecordCount())
+
+	// After a restart, ids start again.
+	c.entries = []interface{}{
+		[]interface{}{int64(0), int64(1622509200), int64(15000), []interface{}{"FLUSHALL"}, "127.0.0.1:58300", ""},
+	}
+	require.NoError(t, runner.Run())
+	require.Len(t, consumer.AllLogs(), 3)
+	assert.Equal(t, 1, consumer.AllLogs()[2].LogRecordCount())
+}
+
+func TestRedisScraperSlowLogMetrics(t *testing.T) {
+	c := &slowLogClient{entries: testSlowLog[1:]}
+	nodes := staticNodes{newRedisNode(c, nil)}
+	scraper := newTestScraper(nodes, "my-redis", map[string]MetricSettings{
+		"redis/slowlog/length":       {Enabled: true},
+		"redis/slowlog/entries":      {Enabled: true},
+		"redis/slowlog/max_duration": {Enabled: true},
+	})
+
+	slowLogMetrics := func() map[string]int64 {
+		values := map[string]int64{}
+		ms := scrapeMetrics(t, scraper).At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for i := 0; i < ms.Len(); i++ {
+			if m := ms.At(i); strings.HasPrefix(m.Name(), "redis/slowlog/") {
+				values[m.Name()] = m.IntGauge().DataPoints().At(0).Value()
+			}
+		}
+		return values
+	}
+
+	// Entries logged before the first run are not counted as new.
+	assert.Equal(t, map[string]int64{
+		"redis/slowlog/length":       3,
+		"redis/slowlog/entries":      0,
+		"redis/slowlog/max_duration": 0,
+	}, slowLogMetrics())
+
+	c.entries = testSlowLog
+	assert.Equal(t, map[string]int64{
+		"redis/slowlog/length":       3,
+		"redis/slowlog/entries":      1,
+		"redis/slowlog/max_duration": 25000,
+	}, slowLogMetrics())
+}
+
+func TestRedisScraperSlowLogOptIn(t *testing.T) {
+	c := &batchingClient{client: &slowLogClient{entries: testSlowLog}}
+	scraper := newTestScraper(staticNodes{newRedisNode(c, nil)}, "", nil)
+	names := metricNames(scrapeMetrics(t, scraper))
+	assert.False(t, names["redis/slowlog/length"])
+	require.Len(t, c.requests, 1)
+	assert.False(t, c.requests[0].slowLogLen, "SLOWLOG LEN is not run by default")
+	assert.Zero(t, c.client.(*slowLogClient).gets, "SLOWLOG GET is not run by default")
+
+	// The length alone only needs SLOWLOG LEN.
+	scraper = newTestScraper(staticNodes{newRedisNode(c, nil)}, "", map[string]MetricSettings{
+		"redis/slowlog/length": {Enabled: true},
+	})
+	names = metricNames(scrapeMetrics(t, scraper))
+	assert.True(t, names["redis/slowlog/length"])
+	assert.False(t, names["redis/slowlog/max_duration"])
+	require.Len(t, c.requests, 2)
+	assert.True(t, c.requests[1].slowLogLen)
+	assert.Zero(t, c.client.(*slowLogClient).gets)
+}
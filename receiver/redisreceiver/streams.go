@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The names of the metrics built from XINFO STREAM and XINFO GROUPS.
+var streamMetricNames = []string{
+	"redis/stream/length",
+	"redis/stream/last_entry_age",
+	"redis/stream/group/consumers",
+	"redis/stream/group/pending",
+	"redis/stream/group/lag",
+	"redis/stream/group/delivery_lag",
+}
+
+// A stream described by XINFO STREAM and XINFO GROUPS.
+type streamInfo struct {
+	length          int64
+	lastGeneratedID string
+	groups          []streamGroup
+}
+
+// A consumer group of a stream. The lag, the number of entries not delivered
+// to the group yet, is only reported by Redis 7 and later.
+type streamGroup struct {
+	name            string
+	consumers       int64
+	pending         int64
+	lastDeliveredID string
+	lag             int64
+	hasLag          bool
+}
+
+// Parses the field-value pairs of an XINFO reply into a map.
+func parseXInfoFields(res interface{}) (map[string]interface{}, error) {
+	items, ok := res.([]interface{})
+	if !ok || len(items)%2 != 0 {
+		return nil, fmt.Errorf("unexpected xinfo reply %v", res)
+	}
+	fields := make(map[string]interface{}, len(items)/2)
+	for i := 0; i < len(items); i += 2 {
+		name, ok := items[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected xinfo field %v", items[i])
+		}
+		fields[name] = items[i+1]
+	}
+	return fields, nil
+}
+
+// Parses the replies of XINFO STREAM and XINFO GROUPS of a stream.
+func parseStreamInfo(streamRes, groupsRes interface{}) (streamInfo, error) {
+	fields, err := parseXInfoFields(streamRes)
+	if err != nil {
+		return streamInfo{}, err
+	}
+	var info streamInfo
+	info.length, _ = fields["length"].(int64)
+	info.lastGeneratedID, _ = fields["last-generated-id"].(string)
+
+	groups, ok := groupsRes.([]interface{})
+	if !ok {
+		return streamInfo{}, fmt.Errorf("unexpected xinfo groups reply %v", groupsRes)
+	}
+	for _, g := range groups {
+		fields, err := parseXInfoFields(g)
+		if err != nil {
+			return streamInfo{}, err
+		}
+		group := streamGroup{}
+		group.name, _ = fields["name"].(string)
+		group.consumers, _ = fields["consumers"].(int64)
+		group.pending, _ = fields["pending"].(int64)
+		group.lastDeliveredID, _ = fields["last-delivered-id"].(string)
+		// nil when Redis cannot tell, e.g. after entries were deleted.
+		group.lag, group.hasLag = fields["lag"].(int64)
+		info.groups = append(info.groups, group)
+	}
+	return info, nil
+}
+
+// Returns the milliseconds part of a stream entry id, e.g. 1526569495631 for
+// 1526569495631-0.
+func streamIDMillis(id string) (int64, error) {
+	ms := id
+	if i := strings.IndexByte(id, '-'); i >= 0 {
+		ms = id[:i]
+	}
+	return strconv.ParseInt(ms, 10, 64)
+}
+
+// Builds the metrics of each of the streams that exist, in the order of
+// streams. The age of the last entry and the delivery lag of groups are
+// derived from the time part of entry ids, and left out for empty streams.
+func buildStreamMetrics(streams []string, infos map[string]streamInfo, t *timeBundle) (pdata.MetricSlice, []error) {
+	ms := pdata.NewMetricSlice()
+	var errs []error
+	for _, stream := range streams {
+		info, ok := infos[stream]
+		if !ok {
+			continue
+		}
+		labels := map[string]string{"stream": stream}
+		initIntMetric(&redisMetric{
+			name:   streamMetricNames[0],
+			labels: labels,
+		}, info.length, t, ms.AppendEmpty())
+
+		lastMillis, err := streamIDMillis(info.lastGeneratedID)
+		if err != nil {
+			errs = append(errs, err)
+		} else if lastMillis > 0 {
+			age := t.current.Sub(time.Unix(0, lastMillis*int64(time.Millisecond)))
+			initIntMetric(&redisMetric{
+				name:   streamMetricNames[1],
+				labels: labels,
+			}, age.Milliseconds(), t, ms.AppendEmpty())
+		}
+
+		for _, g := range info.groups {
+			groupLabels := map[string]string{"stream": stream, "group": g.name}
+			initIntMetric(&redisMetric{
+				name:   streamMetricNames[2],
+				labels: groupLabels,
+			}, g.consumers, t, ms.AppendEmpty())
+			initIntMetric(&redisMetric{
+				name:   streamMetricNames[3],
+				labels: groupLabels,
+			}, g.pending, t, ms.AppendEmpty())
+			if g.hasLag {
+				initIntMetric(&redisMetric{
+					name:   streamMetricNames[4],
+					labels: groupLabels,
+				}, g.lag, t, ms.AppendEmpty())
+			}
+			deliveredMillis, err := streamIDMillis(g.lastDeliveredID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if lastMillis > 0 {
+				initIntMetric(&redisMetric{
+					name:   streamMetricNames[5],
+					labels: groupLabels,
+				}, lastMillis-deliveredMillis, t, ms.AppendEmpty())
+			}
+		}
+	}
+	return ms, errs
+}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStreamInfo(t *testing.T) {
+	streamRes := []interface{}{
+		"length", int64(10),
+		"radix-tree-keys", int64(1),
+		"radix-tree-nodes", int64(2),
+		"last-generated-id", "1526569495631-0",
+		"groups", int64(2),
+		"first-entry", []interface{}{"1526569495000-0", []interface{}{"a", "1"}},
+		"last-entry", []interface{}{"1526569495631-0", []interface{}{"a", "2"}},
+	}
+	groupsRes := []interface{}{
+		// Redis 6
+		[]interface{}{"name", "workers", "consumers", int64(2), "pending", int64(3), "last-delivered-id", "1526569495000-1"},
+		// Redis 7, with an unknown lag
+		[]interface{}{"name", "audit", "consumers", int64(1), "pending", int64(0), "last-delivered-id", "1526569495631-0",
+			"entries-read", int64(10), "lag", nil},
+		[]interface{}{"name", "billing", "consumers", int64(0), "pending", int64(0), "last-delivered-id", "0-0",
+			"entries-read", nil, "lag", int64(10)},
+	}
+	info, err := parseStreamInfo(streamRes, groupsRes)
+	require.NoError(t, err)
+	assert.Equal(t, streamInfo{
+		length:          10,
+		lastGeneratedID: "1526569495631-0",
+		groups: []streamGroup{
+			{name: "workers", consumers: 2, pending: 3, lastDeliveredID: "1526569495000-1"},
+			{name: "audit", consumers: 1, lastDeliveredID: "1526569495631-0"},
+			{name: "billing", lastDeliveredID: "0-0", lag: 10, hasLag: true},
+		},
+	}, info)
+
+	_, err = parseStreamInfo([]interface{}{"length"}, groupsRes)
+	assert.Error(t, err)
+	_, err = parseStreamInfo(streamRes, "OK")
+	assert.Error(t, err)
+}
+
+func TestBuildStreamMetrics(t *testing.T) {
+	infos := map[string]streamInfo{
+		"events": {
+			length:          10,
+			lastGeneratedID: "999000-0",
+			groups: []streamGroup{
+				{name: "workers", consumers: 2, pending: 3, lastDeliveredID: "998500-1", lag: 4, hasLag: true},
+			},
+		},
+		"empty": {lastGeneratedID: "0-0"},
+	}
+	ms, errs := buildStreamMetrics([]string{"events", "missing", "empty"}, infos, newTimeBundle(time.Unix(1000, 0), 100))
+	require.Nil(t, errs)
+
+	expected := []struct {
+		name  string
+		value int64
+	}{
+		{"redis/stream/length", 10},
+		{"redis/stream/last_entry_age", 1000},
+		{"redis/stream/group/consumers", 2},
+		{"redis/stream/group/pending", 3},
+		{"redis/stream/group/lag", 4},
+		{"redis/stream/group/delivery_lag", 500},
+		{"redis/stream/length", 0},
+	}
+	require.Equal(t, len(expected), ms.Len())
+	for i, e := range expected {
+		assert.Equal(t, e.name, ms.At(i).Name())
+		assert.Equal(t, e.value, ms.At(i).IntGauge().DataPoints().At(0).Value())
+	}
+	group, _ := ms.At(2).IntGauge().DataPoints().At(0).LabelsMap().Get("group")
+	assert.Equal(t, "workers", group)
+
+	_, errs = buildStreamMetrics([]string{"events"}, map[string]streamInfo{"events": {lastGeneratedID: "x-0"}}, testTimeBundle())
+	assert.Len(t, errs, 1)
+}
+
+func TestRedisScraperStreams(t *testing.T) {
+	scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "", nil)
+	scraper.streams = []string{"events", "missing"}
+	rms := scrapeMetrics(t, scraper)
+
+	names := map[string]bool{}
+	ms := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		names[ms.At(i).Name()] = true
+	}
+	for _, name := range streamMetricNames {
+		assert.True(t, names[name], name)
+	}
+}
@@ -14,40 +14,43 @@
 
 package redisreceiver
 
-import "time"
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/uptime"
+)
 
 // Provides a server start time for cumulative metrics and the current time
-// for all metrics. The server start time is calculated by subtracting the
-// uptime from the current time. This is done during construction or if a server
-// restart is detected. Server restarts are detected by testing for a lower
-// uptime value than the most recent uptime. Provides the current (server) time
-// by adding the uptime value to the server start time.
+// for all metrics, from the uptime of the server. See uptime.Tracker for how
+// restarts of the server and jumps of the clock are handled.
 type timeBundle struct {
-	serverStart       time.Time
-	current           time.Time
-	lastUptimeSeconds int
+	serverStart time.Time
+	current     time.Time
+	// The time of the first scrape, the start of the count of restarts.
+	created time.Time
+	tracker *uptime.Tracker
 }
 
 func newTimeBundle(now time.Time, uptimeSeconds int) *timeBundle {
-	serverStart := calculateServerStart(now, uptimeSeconds)
+	tracker := uptime.NewTracker(now, time.Duration(uptimeSeconds)*time.Second)
 	return &timeBundle{
-		serverStart:       serverStart,
-		lastUptimeSeconds: uptimeSeconds,
-		current:           now,
+		serverStart: tracker.Start(),
+		current:     tracker.Current(),
+		created:     now,
+		tracker:     tracker,
 	}
 }
 
-// Calculates the current time by adding the server uptime to the server start
-// time. If a server restart is detected, updates the server start time.
-func (t *timeBundle) update(now time.Time, uptimeSeconds int) {
-	if uptimeSeconds < t.lastUptimeSeconds {
-		// assume a server restart
-		t.lastUptimeSeconds = uptimeSeconds
-		t.serverStart = calculateServerStart(now, uptimeSeconds)
-	}
-	t.current = t.serverStart.Add(time.Duration(uptimeSeconds) * time.Second)
+// Updates the server start time and the current time, and returns whether a
+// server restart was detected.
+func (t *timeBundle) update(now time.Time, uptimeSeconds int) bool {
+	restarted := t.tracker.Update(now, time.Duration(uptimeSeconds)*time.Second)
+	t.serverStart = t.tracker.Start()
+	t.current = t.tracker.Current()
+	return restarted
 }
 
-func calculateServerStart(now time.Time, uptimeSeconds int) time.Time {
-	return now.Add(time.Duration(-uptimeSeconds) * time.Second)
+// Returns the number of server restarts detected since the first scrape.
+func (t *timeBundle) restarts() int64 {
+	return t.tracker.Restarts()
 }
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
 func TestStartTime(t *testing.T) {
@@ -40,3 +41,22 @@ func TestStartTime(t *testing.T) {
 	startTime.update(time.Unix(1050, 0), 25)
 	require.Equal(t, time.Unix(1045, 0), startTime.current)
 }
+
+func TestStartTimeRestarts(t *testing.T) {
+	startTime := newTimeBundle(time.Unix(1000, 0), 60)
+	require.False(t, startTime.update(time.Unix(1010, 0), 70))
+
+	// A restart after the first one is detected too.
+	require.True(t, startTime.update(time.Unix(1020, 0), 5))
+	require.False(t, startTime.update(time.Unix(1030, 0), 15))
+	require.True(t, startTime.update(time.Unix(1040, 0), 10))
+	require.Equal(t, time.Unix(1030, 0), startTime.serverStart)
+	require.EqualValues(t, 2, startTime.restarts())
+
+	ms := pdata.NewMetricSlice()
+	buildRestartsMetric(startTime, ms.AppendEmpty())
+	pt := ms.At(0).IntSum().DataPoints().At(0)
+	require.EqualValues(t, 2, pt.Value())
+	require.Equal(t, pdata.TimestampFromTime(time.Unix(1000, 0)), pt.StartTimestamp())
+	require.Equal(t, pdata.TimestampFromTime(time.Unix(1040, 0)), pt.Timestamp())
+}
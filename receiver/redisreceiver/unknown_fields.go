@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"sort"
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The prefix of the names of the metrics built from the fields of INFO that
+// the receiver does not know, e.g. added by a newer Redis version.
+const unknownFieldMetricPrefix = "redis/info/"
+
+// The keys of INFO that metrics are built from other than with the
+// redisMetrics of getRedisMetrics, getAllocatorMetrics and getTrackingMetrics.
+var infoKeysBuiltElsewhere = []string{
+	// Eviction and expiration.
+	"maxmemory", "used_memory", "evicted_keys", "expired_keys",
+	// Client saturation.
+	"connected_clients", "maxclients",
+	// Persistence.
+	"rdb_last_save_time", "rdb_bgsave_in_progress", "rdb_last_bgsave_time_sec",
+	"rdb_current_bgsave_time_sec", "aof_enabled", "aof_rewrite_in_progress",
+	"aof_last_rewrite_time_sec", "aof_current_size", "aof_base_size",
+	// Replication.
+	"master_repl_offset", "master_last_io_seconds_ago", "slave_repl_offset",
+	// Cluster mode.
+	"cluster_enabled",
+}
+
+// Returns the keys of INFO that the receiver builds metrics from.
+func knownInfoKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, metrics := range [][]*redisMetric{getRedisMetrics(), getAllocatorMetrics(), getTrackingMetrics()} {
+		for _, m := range metrics {
+			keys[m.key] = true
+		}
+	}
+	for _, key := range infoKeysBuiltElsewhere {
+		keys[key] = true
+	}
+	return keys
+}
+
+// Builds a gauge named after the field for each numeric field of INFO that is
+// not in known, in the order of the fields. Fields with other values, e.g. the
+// keyspace lines, or the version and mode of the server, are left out.
+func (i info) buildUnknownFieldMetrics(known map[string]bool, t *timeBundle) pdata.MetricSlice {
+	var fields []string
+	for key := range i {
+		if !known[key] {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+
+	ms := pdata.NewMetricSlice()
+	for _, key := range fields {
+		value, err := strconv.ParseFloat(i[key], 64)
+		if err != nil {
+			continue
+		}
+		m := ms.AppendEmpty()
+		m.SetName(unknownFieldMetricPrefix + key)
+		m.SetDescription("The " + key + " field of INFO")
+		m.SetDataType(pdata.MetricDataTypeDoubleGauge)
+		pt := m.DoubleGauge().DataPoints().AppendEmpty()
+		pt.SetValue(value)
+		pt.SetTimestamp(pdata.TimestampFromTime(t.current))
+	}
+	return ms
+}
@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestBuildUnknownFieldMetrics(t *testing.T) {
+	inf := info{
+		"used_memory":     "1024",
+		"hz":              "10",
+		"rss_ratio":       "1.5",
+		"redis_version":   "7.0.0",
+		"maxmemory_human": "0B",
+	}
+	ms := inf.buildUnknownFieldMetrics(knownInfoKeys(), newTimeBundle(time.Now(), 100))
+
+	require.Equal(t, 2, ms.Len())
+	assert.Equal(t, "redis/info/hz", ms.At(0).Name())
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, ms.At(0).DataType())
+	assert.Equal(t, 10.0, ms.At(0).DoubleGauge().DataPoints().At(0).Value())
+	assert.Equal(t, "redis/info/rss_ratio", ms.At(1).Name())
+	assert.Equal(t, 1.5, ms.At(1).DoubleGauge().DataPoints().At(0).Value())
+}
+
+func TestRedisScraperUnknownFields(t *testing.T) {
+	names := func(unknownFields bool) map[string]bool {
+		scraper := newTestScraper(staticNodes{newRedisNode(newFakeClient(), nil)}, "", nil)
+		if unknownFields {
+			scraper.knownInfoKeys = knownInfoKeys()
+		}
+		ms := scrapeMetrics(t, scraper).At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		names := map[string]bool{}
+		for i := 0; i < ms.Len(); i++ {
+			names[ms.At(i).Name()] = true
+		}
+		return names
+	}
+
+	assert.False(t, names(false)["redis/info/active_defrag_hits"])
+	enabled := names(true)
+	assert.True(t, enabled["redis/info/active_defrag_hits"])
+	// Fields the receiver knows are not reported again.
+	assert.False(t, enabled["redis/info/used_memory"])
+	assert.False(t, enabled["redis/info/master_repl_offset"])
+}
@@ -0,0 +1,205 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datareceivers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testbed/testbed"
+)
+
+// SentryDataReceiver implements a stub Sentry backend, which accepts the envelopes sent
+// by the Sentry exporter and converts the transactions in them back to traces.
+type SentryDataReceiver struct {
+	testbed.DataReceiverBase
+	server *http.Server
+}
+
+// Ensure SentryDataReceiver implements DataReceiver.
+var _ testbed.DataReceiver = (*SentryDataReceiver)(nil)
+
+// NewSentryDataReceiver creates a new SentryDataReceiver that will listen on the
+// specified port after Start is called.
+func NewSentryDataReceiver(port int) *SentryDataReceiver {
+	return &SentryDataReceiver{DataReceiverBase: testbed.DataReceiverBase{Port: port}}
+}
+
+// Start the receiver.
+func (sr *SentryDataReceiver) Start(tc consumer.Traces, _ consumer.Metrics, _ consumer.Logs) error {
+	ln, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", sr.Port))
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/1/envelope/", func(w http.ResponseWriter, r *http.Request) {
+		td, err := sentryEnvelopeToTraces(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err = tc.ConsumeTraces(r.Context(), td); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	sr.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := sr.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			sr.ReportFatalError(err)
+		}
+	}()
+	return nil
+}
+
+// Stop the receiver.
+func (sr *SentryDataReceiver) Stop() error {
+	return sr.server.Shutdown(context.Background())
+}
+
+// GenConfigYAMLStr returns exporter config for the agent.
+func (sr *SentryDataReceiver) GenConfigYAMLStr() string {
+	// Note that this generates an exporter config for agent.
+	return fmt.Sprintf(`
+    sentry:
+      dsn: "http://key@localhost:%d/1"`, sr.Port)
+}
+
+// ProtocolName returns protocol name as it is specified in Collector config.
+func (sr *SentryDataReceiver) ProtocolName() string {
+	return "sentry"
+}
+
+type sentryEnvelopeItemHeader struct {
+	Type   string `json:"type"`
+	Length *int   `json:"length"`
+}
+
+type sentryTraceContext struct {
+	TraceID      string `json:"trace_id"`
+	SpanID       string `json:"span_id"`
+	ParentSpanID string `json:"parent_span_id"`
+	Op           string `json:"op"`
+}
+
+type sentrySpan struct {
+	sentryTraceContext
+	Description    string    `json:"description"`
+	StartTimestamp time.Time `json:"start_timestamp"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+type sentryTransaction struct {
+	Transaction    string    `json:"transaction"`
+	StartTimestamp time.Time `json:"start_timestamp"`
+	Timestamp      time.Time `json:"timestamp"`
+	Contexts       struct {
+		Trace sentryTraceContext `json:"trace"`
+	} `json:"contexts"`
+	Spans []sentrySpan `json:"spans"`
+}
+
+// sentryEnvelopeToTraces converts the transaction items of an envelope to traces,
+// with one span for the transaction itself and one for each of its spans.
+func sentryEnvelopeToTraces(r io.Reader) (pdata.Traces, error) {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+
+	br := bufio.NewReader(r)
+	// Skip the envelope header.
+	if _, err := br.ReadBytes('\n'); err != nil {
+		return td, err
+	}
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(bytes.TrimSpace(line)) == 0 {
+			return td, nil
+		}
+		if err != nil {
+			return td, err
+		}
+
+		var header sentryEnvelopeItemHeader
+		if err = json.Unmarshal(line, &header); err != nil {
+			return td, err
+		}
+
+		var payload []byte
+		if header.Length != nil {
+			payload = make([]byte, *header.Length)
+			if _, err = io.ReadFull(br, payload); err != nil {
+				return td, err
+			}
+			// Skip the newline following the payload.
+			if _, err = br.ReadBytes('\n'); err != nil && !errors.Is(err, io.EOF) {
+				return td, err
+			}
+		} else if payload, err = br.ReadBytes('\n'); err != nil && !errors.Is(err, io.EOF) {
+			return td, err
+		}
+
+		if header.Type != "transaction" {
+			continue
+		}
+
+		var transaction sentryTransaction
+		if err = json.Unmarshal(payload, &transaction); err != nil {
+			return td, err
+		}
+		appendSentrySpan(spans, transaction.Contexts.Trace, transaction.Transaction, transaction.StartTimestamp, transaction.Timestamp)
+		for _, span := range transaction.Spans {
+			appendSentrySpan(spans, span.sentryTraceContext, span.Description, span.StartTimestamp, span.Timestamp)
+		}
+	}
+}
+
+func appendSentrySpan(spans pdata.SpanSlice, trace sentryTraceContext, name string, start, end time.Time) {
+	span := spans.AppendEmpty()
+	span.SetName(name)
+	span.SetStartTimestamp(pdata.TimestampFromTime(start))
+	span.SetEndTimestamp(pdata.TimestampFromTime(end))
+	span.Attributes().InsertString("sentry.op", trace.Op)
+
+	var traceID [16]byte
+	if b, err := hex.DecodeString(trace.TraceID); err == nil && len(b) == len(traceID) {
+		copy(traceID[:], b)
+		span.SetTraceID(pdata.NewTraceID(traceID))
+	}
+	var spanID [8]byte
+	if b, err := hex.DecodeString(trace.SpanID); err == nil && len(b) == len(spanID) {
+		copy(spanID[:], b)
+		span.SetSpanID(pdata.NewSpanID(spanID))
+	}
+	var parentSpanID [8]byte
+	if b, err := hex.DecodeString(trace.ParentSpanID); err == nil && len(b) == len(parentSpanID) {
+		copy(parentSpanID[:], b)
+		span.SetParentSpanID(pdata.NewSpanID(parentSpanID))
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testbed/testbed"
+	"go.uber.org/atomic"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/testbed/datareceivers"
+)
+
+// TestSentryExporter measures the Sentry exporter with traces spanning several services,
+// which exercises the grouping of spans into transactions as well as the transport.
+func TestSentryExporter(t *testing.T) {
+	tests := []struct {
+		name          string
+		spansPerSec   int
+		spansPerBatch int
+		resourceSpec  testbed.ResourceSpec
+	}{
+		{
+			name:          "1kSPS",
+			spansPerSec:   1_000,
+			spansPerBatch: 70,
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 30,
+				ExpectedMaxRAM: 90,
+			},
+		},
+		{
+			name:          "10kSPS",
+			spansPerSec:   10_000,
+			spansPerBatch: 700,
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 90,
+				ExpectedMaxRAM: 120,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resultDir, err := filepath.Abs(path.Join("results", t.Name()))
+			require.NoError(t, err)
+
+			options := testbed.LoadOptions{
+				DataItemsPerSecond: test.spansPerSec,
+				ItemsPerBatch:      test.spansPerBatch,
+				Parallel:           1,
+			}
+			sender := testbed.NewOTLPTraceDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t))
+			receiver := datareceivers.NewSentryDataReceiver(testbed.GetAvailablePort(t))
+
+			agentProc := &testbed.ChildProcess{}
+			configCleanup, err := agentProc.PrepareConfig(fmt.Sprintf(`
+receivers:%v
+exporters:%v
+processors:
+  batch:
+
+extensions:
+  pprof:
+    save_to_file: %v/cpu.prof
+
+service:
+  extensions: [pprof]
+  pipelines:
+    traces:
+      receivers: [%v]
+      processors: [batch]
+      exporters: [%v]
+`,
+				sender.GenConfigYAMLStr(),
+				receiver.GenConfigYAMLStr(),
+				resultDir,
+				sender.ProtocolName(),
+				receiver.ProtocolName(),
+			))
+			require.NoError(t, err)
+			defer configCleanup()
+
+			tc := testbed.NewTestCase(
+				t,
+				newMultiServiceDataProvider(options),
+				sender,
+				receiver,
+				agentProc,
+				&testbed.PerfTestValidator{},
+				contribPerfResultsSummary,
+			)
+			defer tc.Stop()
+
+			tc.SetResourceLimits(test.resourceSpec)
+			tc.StartBackend()
+			tc.StartAgent()
+
+			tc.StartLoad(options)
+			tc.Sleep(tc.Duration)
+			tc.StopLoad()
+
+			tc.WaitFor(func() bool { return tc.LoadGenerator.DataItemsSent() > 0 }, "load generator started")
+			tc.WaitFor(func() bool { return tc.LoadGenerator.DataItemsSent() == tc.MockBackend.DataItemsReceived() },
+				"all data items received")
+
+			tc.StopAgent()
+			tc.ValidateData()
+		})
+	}
+}
+
+// multiServiceSpan describes a span of the traces generated by multiServiceDataProvider.
+type multiServiceSpan struct {
+	service  string
+	name     string
+	kind     pdata.SpanKind
+	parent   int
+	attrs    map[string]string
+	duration time.Duration
+}
+
+// multiServiceTrace is the shape of every generated trace: a frontend request calling
+// two backend services, which in turn query their databases. Parents refer to the index of
+// the parent span, -1 marks the root span.
+var multiServiceTrace = []multiServiceSpan{
+	{service: "frontend", name: "GET /checkout", kind: pdata.SpanKindServer, parent: -1, duration: 40 * time.Millisecond,
+		attrs: map[string]string{"http.method": "GET", "http.route": "/checkout", "http.status_code": "200"}},
+	{service: "frontend", name: "HTTP GET", kind: pdata.SpanKindClient, parent: 0, duration: 15 * time.Millisecond,
+		attrs: map[string]string{"http.method": "GET", "http.url": "http://cart/cart"}},
+	{service: "cart", name: "GET /cart", kind: pdata.SpanKindServer, parent: 1, duration: 12 * time.Millisecond,
+		attrs: map[string]string{"http.method": "GET", "http.route": "/cart", "http.status_code": "200"}},
+	{service: "cart", name: "SELECT", kind: pdata.SpanKindClient, parent: 2, duration: 5 * time.Millisecond,
+		attrs: map[string]string{"db.system": "postgresql", "db.statement": "SELECT * FROM cart_items WHERE cart_id = $1"}},
+	{service: "frontend", name: "HTTP POST", kind: pdata.SpanKindClient, parent: 0, duration: 20 * time.Millisecond,
+		attrs: map[string]string{"http.method": "POST", "http.url": "http://payment/charge"}},
+	{service: "payment", name: "POST /charge", kind: pdata.SpanKindServer, parent: 4, duration: 18 * time.Millisecond,
+		attrs: map[string]string{"http.method": "POST", "http.route": "/charge", "http.status_code": "201"}},
+	{service: "payment", name: "INSERT", kind: pdata.SpanKindClient, parent: 5, duration: 6 * time.Millisecond,
+		attrs: map[string]string{"db.system": "mysql", "db.statement": "INSERT INTO charges (amount) VALUES (?)"}},
+}
+
+// multiServiceDataProvider is a testbed.DataProvider generating complete traces shaped like
+// multiServiceTrace, with a resource per service. A batch holds as many traces as fit in
+// the configured number of items per batch, but at least one.
+type multiServiceDataProvider struct {
+	options            testbed.LoadOptions
+	batchesGenerated   *atomic.Uint64
+	dataItemsGenerated *atomic.Uint64
+}
+
+var _ testbed.DataProvider = (*multiServiceDataProvider)(nil)
+
+func newMultiServiceDataProvider(options testbed.LoadOptions) *multiServiceDataProvider {
+	return &multiServiceDataProvider{options: options}
+}
+
+func (dp *multiServiceDataProvider) SetLoadGeneratorCounters(batchesGenerated *atomic.Uint64, dataItemsGenerated *atomic.Uint64) {
+	dp.batchesGenerated = batchesGenerated
+	dp.dataItemsGenerated = dataItemsGenerated
+}
+
+func (dp *multiServiceDataProvider) GenerateTraces() (pdata.Traces, bool) {
+	td := pdata.NewTraces()
+
+	services := make(map[string]pdata.SpanSlice)
+	for _, span := range multiServiceTrace {
+		if _, ok := services[span.service]; ok {
+			continue
+		}
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString("service.name", span.service)
+		rs.Resource().Attributes().InsertString("deployment.environment", "load-test")
+		services[span.service] = rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	}
+
+	traces := dp.options.ItemsPerBatch / len(multiServiceTrace)
+	if traces == 0 {
+		traces = 1
+	}
+
+	dp.batchesGenerated.Inc()
+	now := time.Now()
+	for i := 0; i < traces; i++ {
+		traceID := testbed.GenerateSequentialTraceID(dp.dataItemsGenerated.Load() + 1)
+		spanIDs := make([]pdata.SpanID, len(multiServiceTrace))
+
+		for j, s := range multiServiceTrace {
+			spanIDs[j] = testbed.GenerateSequentialSpanID(dp.dataItemsGenerated.Inc())
+
+			span := services[s.service].AppendEmpty()
+			span.SetTraceID(traceID)
+			span.SetSpanID(spanIDs[j])
+			if s.parent >= 0 {
+				span.SetParentSpanID(spanIDs[s.parent])
+			}
+			span.SetName(s.name)
+			span.SetKind(s.kind)
+			span.SetStartTimestamp(pdata.TimestampFromTime(now.Add(-s.duration)))
+			span.SetEndTimestamp(pdata.TimestampFromTime(now))
+			for k, v := range s.attrs {
+				span.Attributes().InsertString(k, v)
+			}
+		}
+	}
+	return td, false
+}
+
+func (dp *multiServiceDataProvider) GenerateMetrics() (pdata.Metrics, bool) {
+	return pdata.NewMetrics(), true
+}
+
+func (dp *multiServiceDataProvider) GenerateLogs() (pdata.Logs, bool) {
+	return pdata.NewLogs(), true
+}